@@ -0,0 +1,317 @@
+// Package bot provides an embeddable github-reminder bot, wiring together the
+// webhook handler, the scheduler running periodic updates, the storage, and
+// the notifiers.
+package bot
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/handler"
+	"github.com/src-d/github-reminder/notify"
+	"github.com/src-d/github-reminder/reminder"
+	"github.com/src-d/github-reminder/storage"
+)
+
+// Config contains the GitHub App credentials and scheduling settings of a Bot.
+type Config struct {
+	// AppID, PrivateKey, and Secret identify the GitHub App.
+	// Secret can be empty if webhooks are not signed.
+	AppID      int
+	PrivateKey []byte
+	Secret     []byte
+
+	// Endpoints, if not empty, replaces AppID, PrivateKey, and Secret to
+	// serve several GitHub deployments at once.
+	Endpoints []handler.Endpoint
+
+	// CronInterval is the time between updates of all of the installations.
+	// If zero no updates are scheduled, and /cron needs to be called externally.
+	CronInterval time.Duration
+}
+
+// A Bot is an http.Handler serving the github-reminder endpoints that can also
+// schedule the periodic updates of all of its installations.
+type Bot struct {
+	current  atomic.Value // *instance
+	reloaded chan struct{}
+}
+
+// instance is the handler and settings built from a given configuration.
+type instance struct {
+	handler.Handler
+	interval time.Duration
+	settings settings
+	digester *notify.Digester
+}
+
+// An Option modifies the default behavior of a Bot.
+type Option func(*settings)
+
+type settings struct {
+	transport   http.RoundTripper
+	store       storage.Store
+	notifiers   notify.Multi
+	deadlines   notify.Multi
+	clientOpts  []reminder.Option
+	handlerOpts []handler.Option
+
+	digestHour  int
+	digestUsers []string
+
+	safeRestarts int
+	safeWindow   time.Duration
+	safeStable   time.Duration
+}
+
+// WithTransport sets the transport used to talk to GitHub, http.DefaultTransport by default.
+func WithTransport(t http.RoundTripper) Option {
+	return func(s *settings) { s.transport = t }
+}
+
+// WithStore sets the store used to persist state, by default kept in memory.
+func WithStore(store storage.Store) Option {
+	return func(s *settings) { s.store = store }
+}
+
+// WithNotifiers adds notifiers receiving events about reminders and deadlines.
+func WithNotifiers(ns ...notify.Notifier) Option {
+	return func(s *settings) { s.notifiers = append(s.notifiers, ns...) }
+}
+
+// WithDeadlineNotifiers adds notifiers receiving the events of the deadlines
+// set, changed or cleared, like webhooks of other systems tracking them. The
+// rest of the notifiers don't get these events.
+func WithDeadlineNotifiers(ns ...notify.Notifier) Option {
+	return func(s *settings) { s.deadlines = append(s.deadlines, ns...) }
+}
+
+// WithDigests makes the given users receive their notifications in a single
+// digest sent every day at the given hour, in UTC, instead of one by one.
+func WithDigests(hour int, users ...string) Option {
+	return func(s *settings) { s.digestHour, s.digestUsers = hour, append(s.digestUsers, users...) }
+}
+
+// WithSafeMode makes the bot start in safe mode if it started more than the
+// given number of times within the window, each time crashing or being stopped
+// before running for the stable duration. In safe mode the status endpoints are
+// served and webhooks are queued, but nothing is changed in GitHub until it's
+// cleared with a POST to /safemode/clear.
+func WithSafeMode(restarts int, window, stable time.Duration) Option {
+	return func(s *settings) { s.safeRestarts, s.safeWindow, s.safeStable = restarts, window, stable }
+}
+
+// WithClientOptions adds options used for every GitHub client created by the bot.
+func WithClientOptions(opts ...reminder.Option) Option {
+	return func(s *settings) { s.clientOpts = append(s.clientOpts, opts...) }
+}
+
+// WithHandlerOptions adds options used to create the webhook handler.
+func WithHandlerOptions(opts ...handler.Option) Option {
+	return func(s *settings) { s.handlerOpts = append(s.handlerOpts, opts...) }
+}
+
+// New returns a new Bot with the given configuration.
+func New(config Config, opts ...Option) (*Bot, error) {
+	b := &Bot{reloaded: make(chan struct{}, 1)}
+	if err := b.Reload(config, opts...); err != nil {
+		return nil, err
+	}
+
+	if s := b.instance().settings; s.safeRestarts > 0 {
+		if _, err := handler.RecordStart(context.Background(), s.store, s.safeRestarts, s.safeWindow); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// Reload replaces the configuration and options of the bot. Requests being
+// served keep using the previous credentials until they finish, while new
+// ones use the new configuration. The store is kept unless another is given.
+func (b *Bot) Reload(config Config, opts ...Option) error {
+	var s settings
+	for _, opt := range opts {
+		opt(&s)
+	}
+	if s.store == nil {
+		if old := b.instance(); old != nil {
+			s.store = old.settings.store
+		} else {
+			s.store = storage.NewMemory()
+		}
+	}
+
+	clientOpts := s.clientOpts
+	var digester *notify.Digester
+	if len(s.notifiers) > 0 {
+		var n notify.Notifier = s.notifiers
+		if len(s.digestUsers) > 0 {
+			digester = notify.NewDigester(s.store, s.notifiers, s.digestHour, s.digestUsers...)
+			n = digester
+		}
+		clientOpts = append(clientOpts, reminder.WithNotifier(n))
+	}
+	if len(s.deadlines) > 0 {
+		clientOpts = append(clientOpts, reminder.WithDeadlineNotifier(s.deadlines))
+	}
+	handlerOpts := append([]handler.Option{
+		handler.WithStore(s.store),
+		handler.WithClientOptions(clientOpts...),
+	}, s.handlerOpts...)
+
+	var h handler.Handler
+	var err error
+	if len(config.Endpoints) > 0 {
+		h, err = handler.NewComposite(config.Endpoints, s.transport, handlerOpts...)
+	} else {
+		if config.AppID == 0 {
+			return errors.New("missing app id")
+		}
+		h, err = handler.New(config.AppID, config.PrivateKey, config.Secret, s.transport, handlerOpts...)
+	}
+	if err != nil {
+		return errors.Wrap(err, "could not create handler")
+	}
+
+	b.current.Store(&instance{Handler: h, interval: config.CronInterval, settings: s, digester: digester})
+	select {
+	case b.reloaded <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (b *Bot) instance() *instance {
+	i, _ := b.current.Load().(*instance)
+	return i
+}
+
+// RegisterCommand adds a custom slash command, like "/triage", to every bot.
+// It's run for the comments starting with it, with the client of the
+// installation where it was written, giving access to GitHub and the store.
+// It panics if the name doesn't start with a slash or is already registered,
+// so it's usually called during initialization.
+func RegisterCommand(name string, fn reminder.CommandFunc) {
+	reminder.RegisterCommand(name, fn)
+}
+
+// ServeHTTP serves the bot endpoints using the current configuration.
+func (b *Bot) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.instance().ServeHTTP(w, r)
+}
+
+// Cron updates all of the installations of the bot.
+func (b *Bot) Cron(ctx context.Context) error {
+	return b.instance().Cron(ctx)
+}
+
+// Store returns the store used by the bot.
+func (b *Bot) Store() storage.Store { return b.instance().settings.store }
+
+// Run schedules the periodic updates of all installations, returning once the
+// context is done. If no cron interval is configured it only sends the scheduled
+// reminders and digests. Reloading the configuration triggers an update and restarts the schedule.
+func (b *Bot) Run(ctx context.Context) error {
+	go b.fireReminders(ctx)
+	go b.recordStable(ctx)
+
+	// drop the notification of the initial configuration.
+	select {
+	case <-b.reloaded:
+	default:
+	}
+
+	for {
+		var timer *time.Timer
+		var next <-chan time.Time
+		if interval := b.instance().interval; interval > 0 {
+			logrus.Infof("running scheduled update")
+			if err := b.Cron(ctx); err != nil {
+				logrus.Errorf("scheduled update failed: %v", err)
+			}
+			timer = time.NewTimer(interval)
+			next = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-next:
+		case <-b.reloaded:
+			if timer != nil {
+				timer.Stop()
+			}
+		}
+	}
+}
+
+// recordStable records that the bot isn't crashing once it has been running
+// for the stable duration of the safe mode.
+func (b *Bot) recordStable(ctx context.Context) {
+	s := b.instance().settings
+	if s.safeRestarts <= 0 {
+		return
+	}
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(s.safeStable):
+	}
+	if err := handler.RecordStable(ctx, s.store); err != nil {
+		logrus.Errorf("could not record stable run: %v", err)
+	}
+}
+
+// reminderResolution is how often the scheduled reminders are checked.
+var reminderResolution = time.Minute
+
+// fireReminders sends the scheduled reminders as they become due, and the
+// digests once it's their time, until the context is done.
+func (b *Bot) fireReminders(ctx context.Context) {
+	ticker := time.NewTicker(reminderResolution)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		i := b.instance()
+		if err := i.FireReminders(ctx); err != nil {
+			logrus.Errorf("could not send scheduled reminders: %v", err)
+		}
+		if i.digester != nil {
+			if err := i.digester.Flush(ctx, time.Now()); err != nil {
+				logrus.Errorf("could not send digests: %v", err)
+			}
+		}
+	}
+}
+
+// ListenAndServe serves the bot endpoints on the given address while running
+// the scheduler, until the context is done.
+func (b *Bot) ListenAndServe(ctx context.Context, addr string) error {
+	srv := &http.Server{Addr: addr, Handler: b}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go b.Run(ctx)
+
+	errc := make(chan error, 1)
+	go func() { errc <- srv.ListenAndServe() }()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+		shutdown, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		return srv.Shutdown(shutdown)
+	}
+}
@@ -0,0 +1,109 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/src-d/github-reminder/handler"
+	"github.com/src-d/github-reminder/handler/handlertest"
+	"github.com/src-d/github-reminder/notify"
+	"github.com/src-d/github-reminder/storage"
+)
+
+func TestReload(t *testing.T) {
+	p := handlertest.Issues(handlertest.Repo{Installation: 43, Owner: "foo", Name: "bar"}, 1, "opened")
+	status := func(b *Bot, secret string) int {
+		rec := httptest.NewRecorder()
+		b.ServeHTTP(rec, p.Request("/hook", []byte(secret)))
+		return rec.Code
+	}
+
+	b, err := New(Config{AppID: 42, PrivateKey: []byte("not a key"), Secret: []byte("old")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	store := b.Store()
+	if code := status(b, "old"); code == http.StatusForbidden {
+		t.Fatalf("expected old secret to be accepted before reloading")
+	}
+
+	if err := b.Reload(Config{AppID: 42, PrivateKey: []byte("not a key"), Secret: []byte("new")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if code := status(b, "old"); code != http.StatusForbidden {
+		t.Errorf("expected old secret to be rejected after reloading; got %d", code)
+	}
+	if code := status(b, "new"); code == http.StatusForbidden {
+		t.Errorf("expected new secret to be accepted after reloading")
+	}
+	if b.Store() != store {
+		t.Errorf("expected store to be kept across reloads")
+	}
+}
+
+// fakeHandler is a handler.Handler whose scheduled reminders are sent by fire.
+type fakeHandler struct {
+	handler.Handler
+	fire func(ctx context.Context) error
+}
+
+func (h fakeHandler) FireReminders(ctx context.Context) error { return h.fire(ctx) }
+
+func TestFireReminders(t *testing.T) {
+	defer func(d time.Duration) { reminderResolution = d }(reminderResolution)
+	reminderResolution = time.Millisecond
+
+	fired := make(chan struct{}, 10)
+	digests := make(chan notify.Event, 10)
+	digester := notify.NewDigester(storage.NewMemory(), notify.NotifierFunc(func(ctx context.Context, e notify.Event) error {
+		digests <- e
+		return nil
+	}), 0, "alice")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := digester.Notify(ctx, notify.Event{Kind: notify.Reminder, User: "alice", Message: "ship it"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	b := &Bot{}
+	b.current.Store(&instance{Handler: fakeHandler{fire: func(ctx context.Context) error {
+		fired <- struct{}{}
+		// a failure doesn't stop the next reminders from being sent.
+		return errors.New("GitHub is down")
+	}}, digester: digester})
+	done := make(chan struct{})
+	go func() {
+		b.fireReminders(ctx)
+		close(done)
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-fired:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected the scheduled reminders to be sent on every tick")
+		}
+	}
+	select {
+	case e := <-digests:
+		if e.Kind != notify.Digest || e.User != "alice" {
+			t.Errorf("expected the digest of alice; got %+v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the pending digests to be sent")
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the reminders to stop once the context is done")
+	}
+	if len(digests) != 0 {
+		t.Errorf("expected a single digest a day; got %d more", len(digests))
+	}
+}
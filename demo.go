@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/notify"
+	"github.com/src-d/github-reminder/reminder"
+)
+
+// runDemo scans the built-in demo dataset and prints what the bot did.
+func runDemo() {
+	logrus.SetLevel(logrus.WarnLevel)
+	ctx := context.Background()
+
+	fmt.Println("Scanning the demo repository acme/rocket, no GitHub access needed.")
+	fmt.Println()
+	client := reminder.NewDemoClient(os.Stdout,
+		reminder.WithUrgencyColors(reminder.DefaultUrgencyColors),
+		reminder.WithNotifier(notify.NotifierFunc(func(ctx context.Context, e notify.Event) error {
+			fmt.Printf("%s/%s#%d: notify %s %s\n", e.Owner, e.Repo, e.Number, e.Kind, e.Message)
+			return nil
+		})))
+	if err := client.UpdateInstallation(ctx); err != nil {
+		logrus.Fatal(err)
+	}
+
+	deadlines, err := client.Deadlines(ctx)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	fmt.Println()
+	fmt.Println("Tracked deadlines:")
+	for _, d := range deadlines {
+		fmt.Printf("  %s/%s#%d %-32q due %s\n", d.Owner, d.Repo, d.Number, d.Title, d.Deadline.Format("2006-01-02"))
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+
+	"github.com/src-d/github-reminder/handler"
+)
+
+// endpoint is the JSON representation of a handler.Endpoint.
+type endpoint struct {
+	Host           string `json:"host"`
+	BaseURL        string `json:"base_url"`
+	AppID          int    `json:"app_id"`
+	PrivateKey     string `json:"private_key"`
+	PrivateKeyFile string `json:"private_key_file"`
+	Secret         string `json:"secret"`
+}
+
+// readEndpoints parses the list of endpoints in the given JSON file.
+func readEndpoints(path string) ([]handler.Endpoint, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read endpoints file")
+	}
+	var es []endpoint
+	if err := json.Unmarshal(data, &es); err != nil {
+		return nil, errors.Wrap(err, "could not parse endpoints file")
+	}
+
+	var res []handler.Endpoint
+	for _, e := range es {
+		key := []byte(e.PrivateKey)
+		if e.PrivateKeyFile != "" {
+			if key, err = ioutil.ReadFile(e.PrivateKeyFile); err != nil {
+				return nil, errors.Wrapf(err, "could not read private key for %s", e.Host)
+			}
+		}
+		res = append(res, handler.Endpoint{
+			Host:    e.Host,
+			BaseURL: e.BaseURL,
+			AppID:   e.AppID,
+			Key:     key,
+			Secret:  []byte(e.Secret),
+		})
+	}
+	return res, nil
+}
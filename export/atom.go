@@ -0,0 +1,84 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/src-d/github-reminder/reminder"
+)
+
+// A Feed describes an Atom feed of deadlines.
+type Feed struct {
+	// ID is the permanent, unique identifier of the feed, like a tag URI.
+	ID    string
+	Title string
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	ID      string    `xml:"id"`
+	Title   string    `xml:"title"`
+	Link    *atomLink `xml:"link,omitempty"`
+	Updated string    `xml:"updated"`
+	Summary string    `xml:"summary"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  string      `xml:"author>name"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+// WriteAtom writes the deadlines as an Atom feed at now, with an entry for
+// each of them in the order given, so feed readers and dashboards can follow
+// what's coming due.
+func WriteAtom(w io.Writer, f Feed, ds []reminder.Deadline, now time.Time) error {
+	feed := atomFeed{
+		ID:      f.ID,
+		Title:   f.Title,
+		Updated: now.UTC().Format(time.RFC3339),
+		Author:  "github-reminder",
+	}
+	for _, d := range ds {
+		title := fmt.Sprintf("%s/%s#%d %s", d.Owner, d.Repo, d.Number, d.Title)
+		if d.Checkpoint != "" {
+			title += " (" + d.Checkpoint + ")"
+		}
+		updated := d.Updated
+		if updated.IsZero() {
+			updated = now
+		}
+		e := atomEntry{
+			ID: fmt.Sprintf("tag:github-reminder,2018:%s/%s/%d",
+				strings.ToLower(d.Owner), strings.ToLower(d.Repo), d.Number),
+			Title:   title,
+			Updated: updated.UTC().Format(time.RFC3339),
+			Summary: fmt.Sprintf("Due %s, %s.", d.Deadline.Format("Mon, Jan 2 2006"), d.TimeLeft(now)),
+		}
+		if d.Checkpoint != "" {
+			e.ID += "/" + strings.ToLower(d.Checkpoint)
+		}
+		if d.URL != "" {
+			e.Link = &atomLink{Href: d.URL}
+		}
+		feed.Entries = append(feed.Entries, e)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return errors.Wrap(err, "could not write feed")
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return errors.Wrap(enc.Encode(feed), "could not write feed")
+}
@@ -0,0 +1,54 @@
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/src-d/github-reminder/reminder"
+)
+
+func TestWriteAtom(t *testing.T) {
+	now := time.Date(2018, 8, 1, 12, 0, 0, 0, time.UTC)
+	ds := []reminder.Deadline{
+		{Owner: "foo", Repo: "bar", Number: 1, Title: "Ship <it> & go", URL: "https://github.com/foo/bar/issues/1",
+			Deadline: time.Date(2018, 7, 28, 0, 0, 0, 0, time.UTC), Updated: time.Date(2018, 7, 20, 8, 0, 0, 0, time.UTC)},
+		{Owner: "Foo", Repo: "bar", Number: 2, Title: "Docs", Checkpoint: "review",
+			Deadline: time.Date(2018, 8, 5, 18, 0, 0, 0, time.UTC)},
+	}
+	var buf bytes.Buffer
+	feed := Feed{ID: "tag:github-reminder,2018:42/43", Title: "Upcoming deadlines"}
+	if err := WriteAtom(&buf, feed, ds, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got atomFeed
+	if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("expected a valid feed; got %v: %s", err, buf.String())
+	}
+	if got.ID != feed.ID || got.Title != feed.Title || got.Updated != "2018-08-01T12:00:00Z" {
+		t.Errorf("unexpected feed %+v", got)
+	}
+	expected := []atomEntry{
+		{ID: "tag:github-reminder,2018:foo/bar/1", Title: "foo/bar#1 Ship <it> & go",
+			Link: &atomLink{Href: "https://github.com/foo/bar/issues/1"}, Updated: "2018-07-20T08:00:00Z",
+			Summary: "Due Sat, Jul 28 2018, overdue by 4 days."},
+		{ID: "tag:github-reminder,2018:foo/bar/2/review", Title: "Foo/bar#2 Docs (review)",
+			Updated: "2018-08-01T12:00:00Z", Summary: "Due Sun, Aug 5 2018, 4 days left."},
+	}
+	if len(got.Entries) != len(expected) {
+		t.Fatalf("expected %d entries; got %+v", len(expected), got.Entries)
+	}
+	for i, e := range expected {
+		g := got.Entries[i]
+		if g.ID != e.ID || g.Title != e.Title || g.Updated != e.Updated || g.Summary != e.Summary ||
+			(g.Link == nil) != (e.Link == nil) || (g.Link != nil && g.Link.Href != e.Link.Href) {
+			t.Errorf("expected entry %d to be %+v; got %+v", i, e, g)
+		}
+	}
+	if !strings.HasPrefix(buf.String(), xml.Header) {
+		t.Errorf("expected an XML header; got %q", buf.String())
+	}
+}
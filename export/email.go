@@ -0,0 +1,144 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/src-d/github-reminder/reminder"
+)
+
+// An EmailDigest emails a summary of the issues of an installation
+// approaching their deadlines, grouped by repository and sorted by urgency,
+// to a list of recipients once a day or once a week.
+type EmailDigest struct {
+	// Addr is the address of the SMTP server, like "smtp.example.com:587".
+	Addr string
+	// Username and Password authenticate with the SMTP server, if set.
+	Username string
+	Password string
+	// From is the sender of the emails.
+	From string
+	// To are the recipients of the emails.
+	To []string
+	// Hour is the hour of the day, in UTC, when the digests are sent.
+	Hour int
+	// Weekly sends the digests on Mondays only.
+	Weekly bool
+	// Days is how many days ahead the digests look, 7 if zero. Issues past
+	// their deadline are always included.
+	Days int
+
+	// sendMail sends the emails, smtp.SendMail if nil.
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmailDigest returns an EmailDigest sending the emails from the given
+// address to the recipients through the SMTP server at addr.
+func NewEmailDigest(addr, from string, to ...string) (*EmailDigest, error) {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return nil, errors.Wrapf(err, "invalid SMTP address %q", addr)
+	}
+	if from == "" || len(to) == 0 {
+		return nil, errors.New("the sender and the recipients of the digests are required")
+	}
+	return &EmailDigest{Addr: addr, From: from, To: to}, nil
+}
+
+// Due reports whether a digest must be sent at now, given the last time one
+// was sent, zero if never.
+func (d *EmailDigest) Due(last, now time.Time) bool {
+	now = now.UTC()
+	scheduled := time.Date(now.Year(), now.Month(), now.Day(), d.Hour, 0, 0, 0, time.UTC)
+	if scheduled.After(now) {
+		scheduled = scheduled.AddDate(0, 0, -1)
+	}
+	if d.Weekly {
+		// Mondays are 1, Sundays 0.
+		scheduled = scheduled.AddDate(0, 0, -(int(scheduled.Weekday())+6)%7)
+	}
+	return last.Before(scheduled)
+}
+
+// Send emails the digest of the deadlines of an installation, sorted by due
+// date, unless none of them is close enough.
+func (d *EmailDigest) Send(installationID int, ds []reminder.Deadline, now time.Time) error {
+	msg := d.message(installationID, ds, now)
+	if msg == nil {
+		return nil
+	}
+	var auth smtp.Auth
+	if d.Username != "" {
+		host, _, _ := net.SplitHostPort(d.Addr)
+		auth = smtp.PlainAuth("", d.Username, d.Password, host)
+	}
+	send := d.sendMail
+	if send == nil {
+		send = smtp.SendMail
+	}
+	return errors.Wrap(send(d.Addr, auth, d.From, d.To, msg), "could not send digest")
+}
+
+// message returns the email with the digest of the deadlines, sorted by due
+// date, or nil if none of them is close enough.
+func (d *EmailDigest) message(installationID int, ds []reminder.Deadline, now time.Time) []byte {
+	days := d.Days
+	if days <= 0 {
+		days = 7
+	}
+	until := now.AddDate(0, 0, days)
+
+	// repositories are listed in the order of their most urgent deadline.
+	var repos []string
+	byRepo := make(map[string][]reminder.Deadline)
+	count := 0
+	for _, dl := range ds {
+		if dl.Deadline.After(until) {
+			continue
+		}
+		repo := dl.Owner + "/" + dl.Repo
+		if _, ok := byRepo[repo]; !ok {
+			repos = append(repos, repo)
+		}
+		byRepo[repo] = append(byRepo[repo], dl)
+		count++
+	}
+	if count == 0 {
+		return nil
+	}
+
+	var body bytes.Buffer
+	issues := "issues are"
+	if count == 1 {
+		issues = "issue is"
+	}
+	fmt.Fprintf(&body, "%d %s due in the next %d days or overdue.\n", count, issues, days)
+	for _, repo := range repos {
+		fmt.Fprintf(&body, "\n%s\n", repo)
+		for _, dl := range byRepo[repo] {
+			title := dl.Title
+			if dl.Checkpoint != "" {
+				title += " (" + dl.Checkpoint + ")"
+			}
+			fmt.Fprintf(&body, "- #%d %s: %s, %s\n", dl.Number, title, dl.TimeLeft(now), dl.Deadline.Format("2006-01-02"))
+			if dl.URL != "" {
+				fmt.Fprintf(&body, "  %s\n", dl.URL)
+			}
+		}
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", d.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(d.To, ", "))
+	fmt.Fprintf(&msg, "Subject: Upcoming deadlines of installation %d\r\n", installationID)
+	fmt.Fprintf(&msg, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(strings.Replace(body.String(), "\n", "\r\n", -1))
+	return msg.Bytes()
+}
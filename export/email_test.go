@@ -0,0 +1,97 @@
+package export
+
+import (
+	"net/smtp"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/src-d/github-reminder/reminder"
+)
+
+func TestEmailDigestDue(t *testing.T) {
+	// 2018-08-01 was a Wednesday.
+	at := func(day, hour int) time.Time { return time.Date(2018, 8, day, hour, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		weekly    bool
+		last, now time.Time
+		due       bool
+	}{
+		{false, time.Time{}, at(1, 7), true},
+		{false, at(1, 7), at(1, 7), false},
+		{false, at(1, 7), at(1, 8), true},
+		{false, at(1, 8), at(1, 20), false},
+		{false, at(1, 8), at(2, 7), false},
+		{false, at(1, 8), at(2, 9), true},
+		{true, at(1, 8), at(5, 23), false},
+		{true, at(1, 8), at(6, 8), true},
+		{true, at(6, 8), at(7, 8), false},
+		{true, at(6, 8), at(13, 9), true},
+	}
+	for _, tt := range tests {
+		d := &EmailDigest{Hour: 8, Weekly: tt.weekly}
+		if due := d.Due(tt.last, tt.now); due != tt.due {
+			t.Errorf("expected due %v at %v, last sent at %v, weekly %v; got %v", tt.due, tt.now, tt.last, tt.weekly, due)
+		}
+	}
+}
+
+func TestEmailDigestSend(t *testing.T) {
+	now := time.Date(2018, 8, 1, 8, 0, 0, 0, time.UTC)
+	ds := []reminder.Deadline{
+		{Owner: "src-d", Repo: "go-git", Number: 1, Title: "Fix it", URL: "https://github.com/src-d/go-git/issues/1", Deadline: now.AddDate(0, 0, -3)},
+		{Owner: "src-d", Repo: "hercules", Number: 2, Title: "Ship it", Deadline: now.Add(12 * time.Hour)},
+		{Owner: "src-d", Repo: "go-git", Number: 3, Title: "Test it", Checkpoint: "docs", Deadline: now.AddDate(0, 0, 5).Add(time.Hour)},
+		{Owner: "src-d", Repo: "go-git", Number: 4, Title: "Later", Deadline: now.AddDate(0, 0, 20)},
+	}
+
+	d, err := NewEmailDigest("smtp.example.com:587", "bot@example.com", "alice@example.com", "bob@example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d.Username, d.Password = "bot", "secret"
+	var sent []string
+	d.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		if addr != "smtp.example.com:587" || a == nil || from != "bot@example.com" ||
+			!reflect.DeepEqual(to, []string{"alice@example.com", "bob@example.com"}) {
+			t.Errorf("unexpected email sent through %s from %s to %v", addr, from, to)
+		}
+		sent = append(sent, string(msg))
+		return nil
+	}
+
+	if err := d.Send(42, ds, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sent) != 1 {
+		t.Fatalf("expected a single email; got %d", len(sent))
+	}
+	parts := strings.SplitN(sent[0], "\r\n\r\n", 2)
+	if len(parts) != 2 || !strings.Contains(parts[0], "Subject: Upcoming deadlines of installation 42\r\n") ||
+		!strings.Contains(parts[0], "To: alice@example.com, bob@example.com\r\n") {
+		t.Fatalf("unexpected email:\n%s", sent[0])
+	}
+	expected := strings.Replace(`3 issues are due in the next 7 days or overdue.
+
+src-d/go-git
+- #1 Fix it: overdue by 3 days, 2018-07-29
+  https://github.com/src-d/go-git/issues/1
+- #3 Test it (docs): 5 days left, 2018-08-06
+
+src-d/hercules
+- #2 Ship it: due in less than a day, 2018-08-01
+`, "\n", "\r\n", -1)
+	if parts[1] != expected {
+		t.Errorf("expected the deadlines grouped by repository:\n%s\ngot:\n%s", expected, parts[1])
+	}
+
+	sent = nil
+	if err := d.Send(42, ds[3:], now); err != nil || len(sent) != 0 {
+		t.Errorf("expected no email without upcoming deadlines; got %v (%v)", sent, err)
+	}
+
+	if _, err := NewEmailDigest("smtp.example.com", "bot@example.com", "alice@example.com"); err == nil {
+		t.Errorf("expected an error for an address without port")
+	}
+}
@@ -0,0 +1,46 @@
+// Package export provides ways to export the deadline inventory of an
+// installation as spreadsheets.
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/src-d/github-reminder/reminder"
+)
+
+// An Exporter publishes the deadline inventory of an installation somewhere.
+type Exporter interface {
+	Export(ctx context.Context, installationID int, ds []reminder.Deadline) error
+}
+
+// header contains the column names of the exported tables.
+var header = []string{"repository", "number", "title", "url", "deadline", "days left"}
+
+// rows returns the inventory as a table, including the header.
+func rows(ds []reminder.Deadline) [][]string {
+	res := [][]string{header}
+	for _, d := range ds {
+		res = append(res, []string{
+			d.Owner + "/" + d.Repo,
+			strconv.Itoa(d.Number),
+			d.Title,
+			d.URL,
+			d.Deadline.Format("2006-01-02"),
+			strconv.Itoa(int(time.Until(d.Deadline).Hours() / 24)),
+		})
+	}
+	return res
+}
+
+// WriteCSV writes the inventory as CSV, including a header line.
+func WriteCSV(w io.Writer, ds []reminder.Deadline) error {
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(rows(ds)); err != nil {
+		return err
+	}
+	return cw.Error()
+}
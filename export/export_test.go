@@ -0,0 +1,33 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/src-d/github-reminder/reminder"
+)
+
+func TestWriteCSV(t *testing.T) {
+	ds := []reminder.Deadline{{
+		Owner:    "src-d",
+		Repo:     "github-reminder",
+		Number:   42,
+		Title:    "Ship it, finally",
+		URL:      "https://github.com/src-d/github-reminder/issues/42",
+		Deadline: time.Date(2018, 6, 20, 0, 0, 0, 0, time.UTC),
+	}}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, ds); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected header and one row; got %q", buf.String())
+	}
+	if !strings.HasPrefix(lines[1], `src-d/github-reminder,42,"Ship it, finally",https://`) {
+		t.Errorf("unexpected row %q", lines[1])
+	}
+}
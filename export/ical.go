@@ -0,0 +1,65 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/src-d/github-reminder/reminder"
+)
+
+// icsEscaper escapes the text values of iCalendar properties.
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`, "\r", "")
+
+// WriteICS writes the deadlines as an iCalendar feed named after the given
+// name, with an event for each of them at its due time, or lasting the whole
+// day for those without a time of day, so calendar apps can subscribe to it.
+func WriteICS(w io.Writer, name string, ds []reminder.Deadline, now time.Time) error {
+	bw := bufio.NewWriter(w)
+	line := func(format string, args ...interface{}) {
+		// lines are folded at 75 octets, continuing after a space.
+		s, limit := fmt.Sprintf(format, args...), 75
+		for len(s) > limit {
+			cut := limit
+			for cut > 1 && s[cut]&0xc0 == 0x80 {
+				// don't split UTF-8 sequences.
+				cut--
+			}
+			bw.WriteString(s[:cut] + "\r\n ")
+			s, limit = s[cut:], 74
+		}
+		bw.WriteString(s + "\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//src-d//github-reminder//EN")
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:%s", icsEscaper.Replace(name))
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, d := range ds {
+		line("BEGIN:VEVENT")
+		line("UID:%s-%s-%d@github-reminder", strings.ToLower(d.Owner), strings.ToLower(d.Repo), d.Number)
+		line("DTSTAMP:%s", stamp)
+		if t := d.Deadline; t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+			line("DTSTART;VALUE=DATE:%s", t.Format("20060102"))
+			line("DTEND;VALUE=DATE:%s", t.AddDate(0, 0, 1).Format("20060102"))
+		} else {
+			line("DTSTART:%s", t.UTC().Format("20060102T150405Z"))
+		}
+		summary := fmt.Sprintf("%s/%s#%d %s", d.Owner, d.Repo, d.Number, d.Title)
+		if d.Checkpoint != "" {
+			summary += " (" + d.Checkpoint + ")"
+		}
+		line("SUMMARY:%s", icsEscaper.Replace(summary))
+		if d.URL != "" {
+			line("URL:%s", d.URL)
+			line("DESCRIPTION:%s", icsEscaper.Replace(d.URL))
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return bw.Flush()
+}
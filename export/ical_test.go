@@ -0,0 +1,65 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/src-d/github-reminder/reminder"
+)
+
+func TestWriteICS(t *testing.T) {
+	ds := []reminder.Deadline{
+		{Owner: "foo", Repo: "bar", Number: 1, Title: "Ship it, now; please", URL: "https://github.com/foo/bar/issues/1",
+			Deadline: time.Date(2018, 8, 3, 0, 0, 0, 0, time.UTC)},
+		{Owner: "foo", Repo: "bar", Number: 2, Title: strings.Repeat("ñ", 50), Checkpoint: "docs",
+			Deadline: time.Date(2018, 8, 5, 17, 30, 0, 0, time.FixedZone("CEST", 2*3600))},
+	}
+	var buf bytes.Buffer
+	if err := WriteICS(&buf, "Deadlines of foo/bar", ds, time.Date(2018, 8, 1, 12, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+
+	for _, s := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"X-WR-CALNAME:Deadlines of foo/bar\r\n",
+		"UID:foo-bar-1@github-reminder\r\n",
+		"DTSTAMP:20180801T120000Z\r\n",
+		"DTSTART;VALUE=DATE:20180803\r\nDTEND;VALUE=DATE:20180804\r\n",
+		`SUMMARY:foo/bar#1 Ship it\, now\; please` + "\r\n",
+		"URL:https://github.com/foo/bar/issues/1\r\n",
+		"DTSTART:20180805T153000Z\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("expected %q in the feed; got %q", s, out)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+	var unfolded []string
+	for _, l := range lines {
+		if len(l) > 75 {
+			t.Errorf("expected lines of at most 75 octets; got %q", l)
+		}
+		if strings.HasPrefix(l, " ") {
+			unfolded[len(unfolded)-1] += l[1:]
+		} else {
+			unfolded = append(unfolded, l)
+		}
+	}
+	if expected := "SUMMARY:foo/bar#2 " + strings.Repeat("ñ", 50) + " (docs)"; !contains(unfolded, expected) {
+		t.Errorf("expected %q once unfolded; got %q", expected, unfolded)
+	}
+}
+
+func contains(ss []string, s string) bool {
+	for _, x := range ss {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,203 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/pkg/errors"
+
+	"github.com/src-d/github-reminder/reminder"
+)
+
+const (
+	sheetsURL   = "https://sheets.googleapis.com/v4/spreadsheets/"
+	sheetsScope = "https://www.googleapis.com/auth/spreadsheets"
+)
+
+// Sheets exports the inventory of each installation to a sheet, named after
+// the installation, of a Google Spreadsheet, authenticated as a service account.
+// The spreadsheet needs to be shared with the service account's email.
+type Sheets struct {
+	spreadsheet string
+	email       string
+	tokenURL    string
+	key         []byte
+	client      *http.Client
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// serviceAccount contains the fields used from a service account credentials file.
+type serviceAccount struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// NewSheets returns an Exporter writing to the given spreadsheet using the
+// JSON credentials of a Google service account.
+// If transport is nil http.DefaultTransport will be used.
+func NewSheets(credentials []byte, spreadsheetID string, transport http.RoundTripper) (*Sheets, error) {
+	var sa serviceAccount
+	if err := json.Unmarshal(credentials, &sa); err != nil {
+		return nil, errors.Wrap(err, "could not parse service account credentials")
+	}
+	if sa.ClientEmail == "" || sa.PrivateKey == "" {
+		return nil, errors.New("service account credentials miss client_email or private_key")
+	}
+	if sa.TokenURI == "" {
+		sa.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+	if _, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(sa.PrivateKey)); err != nil {
+		return nil, errors.Wrap(err, "could not parse service account private key")
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	return &Sheets{
+		spreadsheet: spreadsheetID,
+		email:       sa.ClientEmail,
+		tokenURL:    sa.TokenURI,
+		key:         []byte(sa.PrivateKey),
+		client:      &http.Client{Transport: transport},
+	}, nil
+}
+
+// accessToken returns a valid OAuth2 access token, requesting a new one if needed.
+func (s *Sheets) accessToken(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && time.Now().Before(s.expires) {
+		return s.token, nil
+	}
+
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(s.key)
+	if err != nil {
+		return "", errors.Wrap(err, "could not parse private key")
+	}
+	now := time.Now()
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   s.email,
+		"scope": sheetsScope,
+		"aud":   s.tokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(key)
+	if err != nil {
+		return "", errors.Wrap(err, "could not sign assertion")
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequest("POST", s.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", errors.Wrap(err, "could not create token request")
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := s.do(req.WithContext(ctx), &tok); err != nil {
+		return "", errors.Wrap(err, "could not fetch access token")
+	}
+	s.token = tok.AccessToken
+	// renew the token a minute before it expires.
+	s.expires = now.Add(time.Duration(tok.ExpiresIn)*time.Second - time.Minute)
+	return s.token, nil
+}
+
+// do performs the request, decoding the JSON response into v if not nil.
+func (s *Sheets) do(req *http.Request, v interface{}) error {
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return errors.Wrap(err, "could not read response")
+	}
+	if res.StatusCode >= 300 {
+		return &apiError{res.StatusCode, strings.TrimSpace(string(body))}
+	}
+	if v == nil {
+		return nil
+	}
+	return errors.Wrap(json.Unmarshal(body, v), "could not decode response")
+}
+
+type apiError struct {
+	code int
+	body string
+}
+
+func (e *apiError) Error() string { return fmt.Sprintf("unexpected status %d: %s", e.code, e.body) }
+
+// call performs an authenticated request to the Sheets API.
+func (s *Sheets) call(ctx context.Context, method, path string, payload interface{}) error {
+	token, err := s.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	if payload != nil {
+		if err := json.NewEncoder(&body).Encode(payload); err != nil {
+			return errors.Wrap(err, "could not encode request")
+		}
+	}
+	req, err := http.NewRequest(method, sheetsURL+url.PathEscape(s.spreadsheet)+path, &body)
+	if err != nil {
+		return errors.Wrap(err, "could not create request")
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	return s.do(req.WithContext(ctx), nil)
+}
+
+// Export replaces the contents of the installation's sheet with the inventory,
+// creating the sheet if it doesn't exist yet.
+func (s *Sheets) Export(ctx context.Context, installationID int, ds []reminder.Deadline) error {
+	sheet := fmt.Sprintf("installation %d", installationID)
+	rng := url.PathEscape(fmt.Sprintf("'%s'", sheet))
+
+	err := s.call(ctx, "POST", "/values/"+rng+":clear", struct{}{})
+	if e, ok := err.(*apiError); ok && e.code == http.StatusBadRequest {
+		// the sheet does not exist yet.
+		err = s.call(ctx, "POST", ":batchUpdate", map[string]interface{}{
+			"requests": []interface{}{
+				map[string]interface{}{
+					"addSheet": map[string]interface{}{
+						"properties": map[string]string{"title": sheet},
+					},
+				},
+			},
+		})
+	}
+	if err != nil {
+		return errors.Wrapf(err, "could not prepare sheet %s", sheet)
+	}
+
+	err = s.call(ctx, "PUT", "/values/"+rng+"?valueInputOption=RAW", map[string]interface{}{
+		"range":  fmt.Sprintf("'%s'", sheet),
+		"values": rows(ds),
+	})
+	return errors.Wrapf(err, "could not write sheet %s", sheet)
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/reminder"
+)
+
+// WithAdminToken enables the administration endpoints, protected by the given bearer token.
+func WithAdminToken(token string) Option {
+	return func(s *server) { s.adminToken = token }
+}
+
+// admin only lets requests authenticated with the admin token reach h.
+func (s *server) admin(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.adminToken == "" {
+			http.NotFound(w, r)
+			return
+		}
+		got := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(got), []byte(s.adminToken)) != 1 {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		h(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logrus.Warnf("could not encode response: %v", err)
+	}
+}
+
+func (s *server) listChangesetsHandler(w http.ResponseWriter, r *http.Request) {
+	css, err := reminder.Changesets(r.Context(), s.store)
+	if err != nil {
+		logrus.Errorf("could not list changesets: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	if css == nil {
+		css = []reminder.Changeset{}
+	}
+	writeJSON(w, css)
+}
+
+func (s *server) changesetHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	inst, err := strconv.Atoi(vars["installation"])
+	if err != nil {
+		http.Error(w, "bad installation id", http.StatusBadRequest)
+		return
+	}
+	owner, repo := vars["owner"], vars["repo"]
+	// rejecting only forgets the changes, so it's allowed in safe mode.
+	if vars["action"] == "approve" && s.pausedInSafeMode(w, r) {
+		return
+	}
+
+	client, err := reminder.NewInstallationClient(s.appID, inst, s.key, s.transport, s.opts...)
+	if err != nil {
+		logrus.Errorf("could not create authenticated client: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	ctx := r.Context()
+	switch vars["action"] {
+	case "approve":
+		var n int
+		n, err = client.ApproveChangeset(ctx, owner, repo)
+		logrus.Infof("approved changeset with %d mutations for %s/%s", n, owner, repo)
+	case "reject":
+		err = client.RejectChangeset(ctx, owner, repo)
+		logrus.Infof("rejected changeset for %s/%s", owner, repo)
+	}
+	if err != nil {
+		logrus.Errorf("could not %s changeset: %v", vars["action"], err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
+
+func (s *server) listReadOnlyHandler(w http.ResponseWriter, r *http.Request) {
+	ros, err := reminder.ReadOnlyInstallations(r.Context(), s.store)
+	if err != nil {
+		logrus.Errorf("could not list read-only installations: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	if ros == nil {
+		ros = []reminder.ReadOnlyInstallation{}
+	}
+	writeJSON(w, ros)
+}
+
+// importResult is the response of the import endpoint.
+type importResult struct {
+	Imported int    `json:"imported"`
+	Error    string `json:"error,omitempty"`
+}
+
+func (s *server) importHandler(w http.ResponseWriter, r *http.Request) {
+	inst, err := strconv.Atoi(mux.Vars(r)["installation"])
+	if err != nil {
+		http.Error(w, "bad installation id", http.StatusBadRequest)
+		return
+	}
+	if s.pausedInSafeMode(w, r) {
+		return
+	}
+	mode := reminder.ImportMode(r.URL.Query().Get("mode"))
+	if mode == "" {
+		mode = reminder.ImportComment
+	}
+	if mode != reminder.ImportComment && mode != reminder.ImportMetadata {
+		http.Error(w, "bad import mode", http.StatusBadRequest)
+		return
+	}
+
+	ds, err := reminder.ReadDeadlinesCSV(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	client, err := reminder.NewInstallationClient(s.appID, inst, s.key, s.transport, s.opts...)
+	if err != nil {
+		logrus.Errorf("could not create authenticated client: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	n, err := client.ImportDeadlines(r.Context(), ds, mode)
+	logrus.Infof("imported %d of %d deadlines into installation %d", n, len(ds), inst)
+	res := importResult{Imported: n}
+	if err != nil {
+		logrus.Errorf("could not import deadlines: %v", err)
+		res.Error = err.Error()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+	writeJSON(w, res)
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/reminder"
+)
+
+// An Endpoint is a GitHub deployment, either github.com or a GitHub Enterprise
+// Server, together with the credentials of the app registered in it.
+type Endpoint struct {
+	// Host is the hostname webhooks are routed by, e.g. github.example.com.
+	Host string
+	// BaseURL is the API URL, e.g. https://github.example.com/api/v3/.
+	// Leave it empty for github.com.
+	BaseURL string
+	AppID   int
+	Key     []byte
+	Secret  []byte
+}
+
+type composite struct {
+	routers  map[string]http.Handler
+	fallback http.Handler
+	// github is the router of github.com, if it's one of the endpoints.
+	github http.Handler
+	all    []*server
+	status *status
+}
+
+// NewComposite returns a new http.Handler serving github-reminder endpoints for
+// several GitHub deployments at once.
+// Requests whose path starts with /ghe/{host}, like /ghe/github.example.com/feed/43.atom,
+// go to the endpoint of that host with the prefix removed. Webhooks are routed
+// by the X-GitHub-Enterprise-Host header too. The rest go to github.com, if
+// it's one of the endpoints, falling back to the first endpoint otherwise.
+// The cron endpoint updates the installations of all endpoints.
+func NewComposite(endpoints []Endpoint, transport http.RoundTripper, opts ...Option) (Handler, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("no endpoints given")
+	}
+
+	c := &composite{routers: make(map[string]http.Handler), status: newStatus()}
+	for _, e := range endpoints {
+		host := normalizeHost(e.Host)
+		if _, ok := c.routers[host]; ok {
+			return nil, errors.Errorf("duplicated endpoint for host %s", e.Host)
+		}
+
+		eopts := opts
+		if e.BaseURL != "" {
+			eopts = append(append([]Option(nil), opts...), WithClientOptions(reminder.WithBaseURL(e.BaseURL)))
+		}
+		s := newServer(e.AppID, e.Key, e.Secret, transport, c.status, eopts)
+		r := mux.NewRouter()
+		s.routes(r)
+		c.routers[host] = r
+		c.all = append(c.all, s)
+		if c.fallback == nil {
+			c.fallback = r
+		}
+		if e.BaseURL == "" {
+			c.github = r
+		}
+	}
+
+	r := mux.NewRouter()
+	r.HandleFunc("/cron", c.status.track(c.cronHandler))
+	r.HandleFunc("/status", c.status.handler)
+	r.PathPrefix("/").HandlerFunc(c.route)
+	return &app{r, c.cron, c.fireReminders, c.status}, nil
+}
+
+func normalizeHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(host)
+}
+
+// hostPrefix starts the paths of the requests routed to the endpoint of a host.
+const hostPrefix = "/ghe/"
+
+type prefixKey struct{}
+
+// pathPrefix returns the prefix removed from the path of r when routing it to
+// the endpoint of a host, if any, so the URLs given back can keep it.
+func pathPrefix(r *http.Request) string {
+	prefix, _ := r.Context().Value(prefixKey{}).(string)
+	return prefix
+}
+
+// route forwards the request to the server of the host named by its path, or
+// of the GitHub Enterprise Server it comes from, or to github.com, since its
+// deliveries don't name their host.
+func (c *composite) route(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, hostPrefix) {
+		host := strings.SplitN(strings.TrimPrefix(r.URL.Path, hostPrefix), "/", 2)[0]
+		router, ok := c.routers[normalizeHost(host)]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		prefix := hostPrefix + host
+		r = r.WithContext(context.WithValue(r.Context(), prefixKey{}, prefix))
+		http.StripPrefix(prefix, router).ServeHTTP(w, r)
+		return
+	}
+
+	router, ok := c.routers[normalizeHost(r.Header.Get("X-GitHub-Enterprise-Host"))]
+	if !ok && c.github != nil {
+		router, ok = c.github, true
+	}
+	if !ok {
+		router = c.fallback
+	}
+	router.ServeHTTP(w, r)
+}
+
+func (c *composite) cronHandler(w http.ResponseWriter, r *http.Request) {
+	if err := c.cron(r.Context()); err != nil {
+		logrus.Errorf("cron failed: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	c.status.cronSucceeded(time.Now())
+}
+
+func (c *composite) cron(ctx context.Context) error {
+	failed := 0
+	for _, s := range c.all {
+		if err := s.cron(ctx); err != nil {
+			logrus.Errorf("could not update app %d: %v", s.appID, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return errors.Errorf("%d out of %d endpoints failed", failed, len(c.all))
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/src-d/github-reminder/reminder"
+	"github.com/src-d/github-reminder/storage"
+)
+
+func TestCompositeRouting(t *testing.T) {
+	var got string
+	router := func(name string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { got = name })
+	}
+	c := &composite{
+		routers:  map[string]http.Handler{"ghes.example.com": router("ghes"), "github.com": router("github")},
+		fallback: router("ghes"),
+		github:   router("github"),
+	}
+	for header, expected := range map[string]string{"GHES.example.com:443": "ghes", "": "github", "other.example.com": "github"} {
+		r := httptest.NewRequest("POST", "/", nil)
+		r.Host = "ghes.example.com"
+		if header != "" {
+			r.Header.Set("X-GitHub-Enterprise-Host", header)
+		}
+		c.route(httptest.NewRecorder(), r)
+		if got != expected {
+			t.Errorf("expected deliveries from %q to go to %s; got %s", header, expected, got)
+		}
+	}
+}
+
+func TestCompositePaths(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemory()
+	d := reminder.Deadline{Owner: "foo", Repo: "bar", Number: 1, Title: "Ship it", Deadline: time.Date(2018, 8, 3, 0, 0, 0, 0, time.UTC)}
+	if err := store.Put(ctx, storage.Key("deadline", 12, 43, d.Owner, d.Repo, d.Number), d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	h, err := NewComposite([]Endpoint{
+		{Host: "github.com", AppID: 42, Key: []byte("not a key")},
+		{Host: "github.example.com", BaseURL: "https://github.example.com/api/v3/", AppID: 12, Key: []byte("not a key")},
+	}, nil, WithStore(store), WithFeedToken("s3cr3t"), WithAdminToken("t0k3n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	get := func(url string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", url, nil)
+		r.Header.Set("Authorization", "Bearer t0k3n")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+	feeds := func(url string) (urls feedURLs) {
+		w := get(url)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected the feeds of %s; got status %d", url, w.Code)
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &urls); err != nil {
+			t.Fatalf("could not decode feeds: %v", err)
+		}
+		return urls
+	}
+
+	if urls := feeds("/feeds/43"); len(urls.Calendars) != 0 {
+		t.Errorf("expected requests without a prefix to go to github.com; got %v", urls.Calendars)
+	}
+	urls := feeds("/ghe/GitHub.example.com/feeds/43")
+	cal := urls.Calendars["foo/bar"]
+	if !strings.HasPrefix(cal, "/ghe/GitHub.example.com/calendar/43/foo/bar.ics?token=") {
+		t.Fatalf("expected the calendars of the host to keep its prefix; got %v", urls.Calendars)
+	}
+	if w := get(cal); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "foo/bar#1 Ship it") {
+		t.Errorf("expected the calendar of the host to open; got status %d, %q", w.Code, w.Body.String())
+	}
+	if w := get(urls.Atom); w.Code != http.StatusOK {
+		t.Errorf("expected the feed of the host to open; got status %d", w.Code)
+	}
+	if w := get("/ghe/other.example.com/feeds/43"); w.Code != http.StatusNotFound {
+		t.Errorf("expected unknown hosts not to be routed; got status %d", w.Code)
+	}
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// A Webhook is a GitHub webhook delivery extracted from an envelope.
+type Webhook struct {
+	Header http.Header
+	Body   []byte
+	// Trusted is set when the envelope itself was authenticated, for envelopes
+	// that can't keep the original body and thus its signature.
+	Trusted bool
+}
+
+// An Envelope extracts the original GitHub webhooks from the payloads sent by
+// middlewares forwarding them, such as API gateways or event buses.
+type Envelope interface {
+	Unwrap(r *http.Request, body []byte) (*Webhook, error)
+}
+
+// WithEnvelope makes the handler unwrap every webhook with the given envelope.
+func WithEnvelope(e Envelope) Option {
+	return func(s *server) { s.envelope = e }
+}
+
+// HTTPEnvelope unwraps requests serialized as JSON with their headers and body,
+// as done by AWS API Gateway proxy integrations:
+//
+//	{"headers": {"X-GitHub-Event": "issues", ...}, "body": "...", "isBase64Encoded": false}
+//
+// The original body is kept, so signatures are verified as usual.
+type HTTPEnvelope struct{}
+
+// Unwrap implements the Envelope interface.
+func (HTTPEnvelope) Unwrap(r *http.Request, body []byte) (*Webhook, error) {
+	var env struct {
+		Headers         map[string]string `json:"headers"`
+		Body            string            `json:"body"`
+		IsBase64Encoded bool              `json:"isBase64Encoded"`
+	}
+	if err := json.Unmarshal(body, &env); err != nil {
+		return nil, errors.Wrap(err, "could not decode envelope")
+	}
+	if env.Headers == nil {
+		return nil, errors.New("envelope has no headers")
+	}
+
+	wh := &Webhook{Header: make(http.Header), Body: []byte(env.Body)}
+	for k, v := range env.Headers {
+		wh.Header.Set(k, v)
+	}
+	if env.IsBase64Encoded {
+		var err error
+		if wh.Body, err = base64.StdEncoding.DecodeString(env.Body); err != nil {
+			return nil, errors.Wrap(err, "could not decode envelope body")
+		}
+	}
+	return wh, nil
+}
+
+// EventBridgeEnvelope unwraps AWS EventBridge events delivered through an API
+// destination, where the detail-type is the GitHub event and the detail its payload:
+//
+//	{"detail-type": "issues", "source": "github.com", "detail": {...}}
+//
+// The original body, and thus its signature, is lost. Events are only trusted
+// if the request carries the API key configured in the EventBridge connection.
+type EventBridgeEnvelope struct {
+	// Header is the header carrying the API key, X-Api-Key if empty.
+	Header string
+	// APIKey is the expected value of the header.
+	APIKey string
+}
+
+// Unwrap implements the Envelope interface.
+func (e EventBridgeEnvelope) Unwrap(r *http.Request, body []byte) (*Webhook, error) {
+	header := e.Header
+	if header == "" {
+		header = "X-Api-Key"
+	}
+	if e.APIKey == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get(header)), []byte(e.APIKey)) != 1 {
+		return nil, errors.New("missing or wrong EventBridge API key")
+	}
+
+	var env struct {
+		DetailType string          `json:"detail-type"`
+		Detail     json.RawMessage `json:"detail"`
+	}
+	if err := json.Unmarshal(body, &env); err != nil {
+		return nil, errors.Wrap(err, "could not decode envelope")
+	}
+	if env.DetailType == "" || len(env.Detail) == 0 {
+		return nil, errors.New("envelope has no detail")
+	}
+
+	wh := &Webhook{Header: make(http.Header), Body: env.Detail, Trusted: true}
+	wh.Header.Set("X-GitHub-Event", env.DetailType)
+	return wh, nil
+}
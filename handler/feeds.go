@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/export"
+	"github.com/src-d/github-reminder/reminder"
+)
+
+// WithFeedToken enables the feeds of the deadlines, as iCalendar feeds of
+// every repository and Atom feeds of every installation. Each feed is
+// protected by its own token, derived from secret with FeedToken, so the
+// token of a feed doesn't give access to any other. Calendar apps and feed
+// readers can't send headers, so it's given as the token parameter of the URL,
+// as in /calendar/43/owner/repo.ics?token=t0k3n.
+func WithFeedToken(secret string) Option {
+	return func(s *server) { s.feedToken = secret }
+}
+
+// FeedToken returns the token of the feed at path, like
+// /calendar/43/owner/repo.ics, derived from secret. Paths differing only in
+// their case have the same token.
+func FeedToken(secret, path string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strings.ToLower(path)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// feed only lets requests with the token of their feed reach h.
+func (s *server) feed(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.feedToken == "" {
+			http.NotFound(w, r)
+			return
+		}
+		got := r.URL.Query().Get("token")
+		if got == "" {
+			got = strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		}
+		if subtle.ConstantTimeCompare([]byte(got), []byte(FeedToken(s.feedToken, r.URL.Path))) != 1 {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		h(w, r)
+	}
+}
+
+func (s *server) calendarHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	inst, err := strconv.Atoi(vars["installation"])
+	if err != nil {
+		http.Error(w, "bad installation id", http.StatusBadRequest)
+		return
+	}
+	owner, repo := vars["owner"], vars["repo"]
+	ds, err := reminder.RepoDeadlines(r.Context(), s.store, s.appID, inst, owner, repo)
+	if err != nil {
+		logrus.Errorf("could not list deadlines: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%s.ics", repo))
+	if err := export.WriteICS(w, fmt.Sprintf("Deadlines of %s/%s", owner, repo), ds, time.Now()); err != nil {
+		logrus.Warnf("could not write calendar: %v", err)
+	}
+}
+
+func (s *server) atomHandler(w http.ResponseWriter, r *http.Request) {
+	inst, err := strconv.Atoi(mux.Vars(r)["installation"])
+	if err != nil {
+		http.Error(w, "bad installation id", http.StatusBadRequest)
+		return
+	}
+
+	ds, err := reminder.Deadlines(r.Context(), s.store, s.appID, inst)
+	if err != nil {
+		logrus.Errorf("could not list deadlines: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	feed := export.Feed{
+		ID:    fmt.Sprintf("tag:github-reminder,2018:%d/%d", s.appID, inst),
+		Title: fmt.Sprintf("Upcoming deadlines of installation %d", inst),
+	}
+	if err := export.WriteAtom(w, feed, ds, time.Now()); err != nil {
+		logrus.Warnf("could not write feed: %v", err)
+	}
+}
+
+// feedURLs are the feeds of an installation, as given to the administrators.
+type feedURLs struct {
+	Atom      string            `json:"atom"`
+	Calendars map[string]string `json:"calendars"`
+}
+
+func (s *server) feedsHandler(w http.ResponseWriter, r *http.Request) {
+	if s.feedToken == "" {
+		http.NotFound(w, r)
+		return
+	}
+	inst, err := strconv.Atoi(mux.Vars(r)["installation"])
+	if err != nil {
+		http.Error(w, "bad installation id", http.StatusBadRequest)
+		return
+	}
+
+	ds, err := reminder.Deadlines(r.Context(), s.store, s.appID, inst)
+	if err != nil {
+		logrus.Errorf("could not list deadlines: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// the token is derived from the path the server sees, without the prefix
+	// of the host it was routed by.
+	prefix := pathPrefix(r)
+	withToken := func(path string) string { return prefix + path + "?token=" + FeedToken(s.feedToken, path) }
+	urls := feedURLs{
+		Atom:      withToken(fmt.Sprintf("/feed/%d.atom", inst)),
+		Calendars: map[string]string{},
+	}
+	for _, d := range ds {
+		repo := strings.ToLower(d.Owner + "/" + d.Repo)
+		urls.Calendars[repo] = withToken(fmt.Sprintf("/calendar/%d/%s.ics", inst, repo))
+	}
+	writeJSON(w, urls)
+}
@@ -2,20 +2,22 @@
 package handler
 
 import (
-	"crypto/hmac"
-	"crypto/sha1"
-	"encoding/hex"
+	"bytes"
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/google/go-github/github"
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
+	"github.com/src-d/github-reminder/export"
 	"github.com/src-d/github-reminder/reminder"
+	"github.com/src-d/github-reminder/storage"
 )
 
 type server struct {
@@ -23,56 +25,173 @@ type server struct {
 	key       []byte
 	secret    []byte
 	transport http.RoundTripper
+	status    *status
+	store     storage.Store
+	opts      []reminder.Option
+
+	adminToken string
+	feedToken  string
+	exporter   export.Exporter
+	digest     *export.EmailDigest
+	envelope   Envelope
+	relays     []Relay
+	queue      Queue
+}
+
+// An Option modifies the default behavior of the handler.
+type Option func(*server)
+
+// WithStore sets the store used to persist state across requests.
+// By default all state is kept in memory.
+func WithStore(store storage.Store) Option {
+	return func(s *server) { s.store = store }
+}
+
+// WithClientOptions sets the options used for every client created by the handler.
+func WithClientOptions(opts ...reminder.Option) Option {
+	return func(s *server) { s.opts = append(s.opts, opts...) }
 }
 
 // New returns a new http.Handler serving github-reminder endpoints.
 // key should contain the app's private key for authentication.
 // secret can be empty or contain the application's secret used for hook authentication.
 // You can read more about secret's here: https://developer.github.com/webhooks/#delivery-headers.
-func New(appID int, key, secret []byte, transport http.RoundTripper) (http.Handler, error) {
+func New(appID int, key, secret []byte, transport http.RoundTripper, opts ...Option) (Handler, error) {
+	s := newServer(appID, key, secret, transport, newStatus(), opts)
+
+	r := mux.NewRouter()
+	r.HandleFunc("/cron", s.status.track(s.cronHandler))
+	r.HandleFunc("/status", s.status.handler)
+	s.routes(r)
+	return &app{r, s.cron, s.fireReminders, s.status}, nil
+}
+
+// A Handler serves the github-reminder endpoints.
+// Its Cron method performs the same work as the /cron endpoint, which is
+// useful to schedule it without an external cron job.
+// FireReminders sends the reminders scheduled for now or earlier, and is
+// meant to be called frequently so reminders are sent on time.
+type Handler interface {
+	http.Handler
+	Cron(ctx context.Context) error
+	FireReminders(ctx context.Context) error
+}
+
+type app struct {
+	http.Handler
+	cron          func(ctx context.Context) error
+	fireReminders func(ctx context.Context) error
+	status        *status
+}
+
+func (a *app) FireReminders(ctx context.Context) error {
+	return a.fireReminders(ctx)
+}
+
+func (a *app) Cron(ctx context.Context) error {
+	err := a.cron(ctx)
+	a.status.record(err != nil)
+	if err == nil {
+		a.status.cronSucceeded(time.Now())
+	}
+	return err
+}
+
+func newServer(appID int, key, secret []byte, transport http.RoundTripper, st *status, opts []Option) *server {
 	if transport == nil {
 		transport = http.DefaultTransport
 	}
 
-	s := &server{appID, key, secret, transport}
-	r := mux.NewRouter()
-	r.HandleFunc("/hook", s.hookHandler)
-	r.HandleFunc("/cron", s.cronHandler)
-	return r, nil
+	s := &server{appID: appID, key: key, secret: secret, transport: st.transport(transport), status: st}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.store == nil {
+		s.store = storage.NewMemory()
+	}
+	if s.queue != nil {
+		st.watch(s.queue)
+	}
+	s.opts = append([]reminder.Option{reminder.WithStore(s.store)}, s.opts...)
+	return s
+}
+
+// routes registers the endpoints specific to the server's GitHub App in r.
+func (s *server) routes(r *mux.Router) {
+	r.HandleFunc("/hook", s.status.track(s.hookHandler))
+	r.HandleFunc("/changesets", s.admin(s.listChangesetsHandler)).Methods("GET")
+	r.HandleFunc("/readonly", s.admin(s.listReadOnlyHandler)).Methods("GET")
+	r.HandleFunc("/changesets/{installation:[0-9]+}/{owner}/{repo}/{action:approve|reject}",
+		s.admin(s.changesetHandler)).Methods("POST")
+	r.HandleFunc("/inventory/{installation:[0-9]+}.csv", s.admin(s.inventoryHandler)).Methods("GET")
+	r.HandleFunc("/history/{installation:[0-9]+}", s.admin(s.historyHandler)).Methods("GET")
+	r.HandleFunc("/fallbacks/{installation:[0-9]+}", s.admin(s.fallbacksHandler)).Methods("GET")
+	r.HandleFunc("/import/{installation:[0-9]+}", s.admin(s.importHandler)).Methods("POST")
+	r.HandleFunc("/settings/{installation:[0-9]+}.yaml", s.admin(s.settingsHandler)).Methods("GET", "PUT")
+	r.HandleFunc("/safemode", s.admin(s.safeModeHandler)).Methods("GET")
+	r.HandleFunc("/safemode/clear", s.admin(s.clearSafeModeHandler)).Methods("POST")
+	r.HandleFunc("/calendar/{installation:[0-9]+}/{owner}/{repo}.ics", s.feed(s.calendarHandler)).Methods("GET")
+	r.HandleFunc("/feed/{installation:[0-9]+}.atom", s.feed(s.atomHandler)).Methods("GET")
+	r.HandleFunc("/feeds/{installation:[0-9]+}", s.admin(s.feedsHandler)).Methods("GET")
 }
 
 func (s *server) cronHandler(w http.ResponseWriter, r *http.Request) {
-	client, err := reminder.NewApplicationClient(s.appID, s.key, s.transport)
-	if err != nil {
-		logrus.Errorf("could not create authenticated client: %v", err)
+	if err := s.cron(r.Context()); err != nil {
+		logrus.Errorf("cron failed: %v", err)
 		http.Error(w, "internal server error", http.StatusInternalServerError)
 		return
 	}
+	s.status.cronSucceeded(time.Now())
+}
+
+// cron updates all of the installations of the application.
+func (s *server) cron(ctx context.Context) error {
+	if active, err := s.inSafeMode(ctx); err != nil || active {
+		if active {
+			logrus.Warnf("safe mode, skipping update of app %d", s.appID)
+		}
+		return err
+	}
+	if n, err := s.replayQueue(ctx); err != nil {
+		return err
+	} else if n > 0 {
+		logrus.Infof("processed %d webhooks queued during safe mode", n)
+	}
+
+	client, err := reminder.NewApplicationClient(s.appID, s.key, s.transport, s.opts...)
+	if err != nil {
+		return errors.Wrap(err, "could not create authenticated client")
+	}
 
-	ctx := r.Context()
 	instIDs, err := client.Installations(ctx)
 	if err != nil {
-		logrus.Errorf("could not fetch installations: %v", err)
-		http.Error(w, "internal server error", http.StatusInternalServerError)
-		return
+		return errors.Wrap(err, "could not fetch installations")
 	}
 
-	failed := false
+	failed := 0
 	for _, instID := range instIDs {
-		client, err := reminder.NewInstallationClient(s.appID, instID, s.key, s.transport)
+		client, err := reminder.NewInstallationClient(s.appID, instID, s.key, s.transport, s.opts...)
 		if err != nil {
 			logrus.Errorf("could not create authenticated client: %v", err)
-			failed = true
+			failed++
 			continue
 		}
 		if err = client.UpdateInstallation(ctx); err != nil {
-			failed = true
+			failed++
 			logrus.Errorf("could not update installation: %v", err)
+			continue
+		}
+		if err = s.export(ctx, client, instID); err != nil {
+			logrus.Errorf("could not export inventory of installation %d: %v", instID, err)
+		}
+		if err = s.sendDigest(ctx, client, instID); err != nil {
+			logrus.Errorf("could not send digest of installation %d: %v", instID, err)
 		}
 	}
-	if failed {
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+	if failed > 0 {
+		return errors.Errorf("%d out of %d installations failed", failed, len(instIDs))
 	}
+	return nil
 }
 
 func (s *server) hookHandler(w http.ResponseWriter, r *http.Request) {
@@ -83,108 +202,421 @@ func (s *server) hookHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := checkSignature(r.Header.Get("X-Hub-Signature"), body, s.secret); err != nil {
-		logrus.Warnf("bad signature: %v", err)
-		http.Error(w, "forbidden", http.StatusForbidden)
-		return
+	header, trusted := r.Header, false
+	if s.envelope != nil {
+		wh, err := s.envelope.Unwrap(r, body)
+		if err != nil {
+			logrus.Warnf("could not unwrap webhook: %v", err)
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		header, body, trusted = wh.Header, wh.Body, wh.Trusted
 	}
 
-	inst, owner, repo, issue, err := extractIssueInfo(r.Header.Get("X-Github-Event"), body)
-	if err != nil {
-		logrus.Warnf("could not extract issue info: %v", err)
+	payload := body
+	if !trusted {
+		if payload, err = validatePayload(header, body, s.secret); err != nil {
+			logrus.Warnf("bad signature: %v", err)
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+	}
+
+	if err := checkPayload(header, payload); err != nil {
+		logrus.Warnf("bad payload: %v", err)
 		http.Error(w, "bad request", http.StatusBadRequest)
 		return
 	}
+	// the original body is relayed, so its signature can still be verified.
+	relay := func() { s.relay(header, body) }
 
-	client, err := reminder.NewInstallationClient(s.appID, inst, s.key, s.transport)
+	queued, err := s.queueInSafeMode(r.Context(), header, payload)
 	if err != nil {
-		logrus.Errorf("could not create authenticated client: %v", err)
+		logrus.Errorf("could not queue webhook: %v", err)
 		http.Error(w, "internal server error", http.StatusInternalServerError)
 		return
 	}
+	if queued {
+		// its headers are not kept to relay it once replayed.
+		relay()
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	if s.queue != nil {
+		if err := s.queue.Push(s.job(header, payload, relay)); err != nil {
+			logrus.Errorf("could not queue webhook: %v", err)
+			http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	code := s.process(r.Context(), header, payload)
+	relay()
+	if code != http.StatusOK {
+		http.Error(w, http.StatusText(code), code)
+	}
+}
+
+// checkPayload checks the webhook is an event the bot can decode, so the rest
+// are rejected before being queued or relayed.
+func checkPayload(header http.Header, body []byte) error {
+	kind := header.Get("X-Github-Event")
+	if kind == "installation" {
+		_, err := github.ParseWebHook(kind, body)
+		return errors.Wrapf(err, "could not decode %s event", kind)
+	}
+	_, _, _, _, err := extractIssueInfo(kind, body)
+	return err
+}
+
+// job returns the job processing a webhook in the background, which fails
+// only when processing it again could succeed. Its retries skip the steps
+// already done, so commands aren't answered twice, and it's relayed once
+// first processed.
+func (s *server) job(header http.Header, body []byte, relay func()) Job {
+	var done webhookSteps
+	var relayed bool
+	return func(ctx context.Context) error {
+		code := s.processSteps(ctx, header, body, &done)
+		if !relayed {
+			relay()
+			relayed = true
+		}
+		if code < http.StatusInternalServerError {
+			return nil
+		}
+		s.status.record(true)
+		return errors.Errorf("could not process %s webhook %s: %s",
+			header.Get("X-GitHub-Event"), header.Get("X-GitHub-Delivery"), http.StatusText(code))
+	}
+}
+
+// webhookSteps are the steps of processing a webhook already done.
+type webhookSteps struct {
+	// command is set once the command in its comment, if any, is handled.
+	command bool
+}
+
+// process handles a validated webhook, returning the HTTP status code of the result.
+func (s *server) process(ctx context.Context, header http.Header, body []byte) int {
+	return s.processSteps(ctx, header, body, &webhookSteps{})
+}
+
+// processSteps is process skipping the steps done, and recording those it does.
+func (s *server) processSteps(ctx context.Context, header http.Header, body []byte, done *webhookSteps) int {
+	if header.Get("X-Github-Event") == "installation" {
+		return s.processInstallation(ctx, body)
+	}
 
-	if issue == 0 {
+	inst, owner, repo, issue, err := extractIssueInfo(header.Get("X-Github-Event"), body)
+	if err != nil {
+		logrus.Warnf("could not extract issue info: %v", err)
+		return http.StatusBadRequest
+	}
+
+	client, err := reminder.NewInstallationClient(s.appID, inst, s.key, s.transport, s.opts...)
+	if err != nil {
+		logrus.Errorf("could not create authenticated client: %v", err)
+		return http.StatusInternalServerError
+	}
+
+	if author, text, ok := extractComment(header.Get("X-Github-Event"), body); ok && !done.command {
+		if _, err := client.HandleComment(ctx, owner, repo, issue, author, text); err != nil {
+			logrus.Errorf("could not handle command: %v", err)
+			return http.StatusInternalServerError
+		}
+		done.command = true
+	}
+
+	if m, ok := extractMove(header.Get("X-Github-Event"), body); ok {
+		if m.number == 0 {
+			err = client.MoveRepo(ctx, m.fromOwner, m.fromRepo, m.owner, m.repo)
+		} else {
+			// the repositories of the same owner share their installation.
+			dst := client
+			if !strings.EqualFold(m.fromOwner, m.owner) {
+				dst, err = s.repoClient(ctx, client, inst, m.owner, m.repo)
+			}
+			if err == nil {
+				err = client.MoveIssue(ctx, m.fromOwner, m.fromRepo, m.fromNumber, dst, m.owner, m.repo, m.number)
+			}
+		}
+	} else if header.Get("X-Github-Event") == "repository" {
+		// the rest of the repository events don't change its issues.
+		return http.StatusOK
+	} else if name, from, ok := extractLabel(header.Get("X-Github-Event"), body); ok {
+		err = client.UpdateLabel(ctx, owner, repo, name, from)
+	} else if header.Get("X-Github-Event") == "milestone" {
+		err = client.UpdateMilestone(ctx, owner, repo)
+	} else if issue == 0 {
 		logrus.Infof("updating repository %s/%s", owner, repo)
-		err = client.UpdateRepo(r.Context(), owner, repo)
+		err = client.UpdateRepo(ctx, owner, repo)
+	} else if head, ok := extractHead(header.Get("X-Github-Event"), body); ok {
+		logrus.Infof("updating pull request %s/%s#%d at %s", owner, repo, issue, head)
+		err = client.UpdatePullRequest(ctx, owner, repo, issue, head)
 	} else {
 		logrus.Infof("updating issue %s/%s#%d", owner, repo, issue)
-		err = client.UpdateIssue(r.Context(), owner, repo, issue)
+		err = client.UpdateIssue(ctx, owner, repo, issue)
 	}
 
 	if err != nil {
 		logrus.Errorf("could not update issue: %v", err)
-		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return http.StatusInternalServerError
 	}
+	return http.StatusOK
 }
 
+// repoClient returns the client of the installation in owner/repo, client of
+// the installation inst itself if it's the one.
+func (s *server) repoClient(ctx context.Context, client *reminder.InstallationClient, inst int, owner, repo string) (*reminder.InstallationClient, error) {
+	app, err := reminder.NewApplicationClient(s.appID, s.key, s.transport, s.opts...)
+	if err != nil {
+		return nil, err
+	}
+	id, err := app.RepoInstallation(ctx, owner, repo)
+	if err != nil || id == inst {
+		return client, err
+	}
+	return reminder.NewInstallationClient(s.appID, id, s.key, s.transport, s.opts...)
+}
+
+// processInstallation starts welcoming the new installations, returning the
+// HTTP status code of the result. The rest of the installation events are
+// ignored.
+func (s *server) processInstallation(ctx context.Context, body []byte) int {
+	event, err := github.ParseWebHook("installation", body)
+	if err != nil {
+		logrus.Warnf("could not decode installation event: %v", err)
+		return http.StatusBadRequest
+	}
+	e := event.(*github.InstallationEvent)
+	if e.GetAction() != "created" {
+		return http.StatusOK
+	}
+
+	client, err := reminder.NewInstallationClient(s.appID, int(e.GetInstallation().GetID()), s.key, s.transport, s.opts...)
+	if err != nil {
+		logrus.Errorf("could not create authenticated client: %v", err)
+		return http.StatusInternalServerError
+	}
+
+	// opening the issues and scanning every repository takes longer than
+	// GitHub waits for an answer, so the welcome runs in the background.
+	id := e.GetInstallation().GetID()
+	welcome := func(ctx context.Context) error {
+		err := client.Welcome(ctx)
+		return errors.Wrapf(err, "could not welcome installation %d", id)
+	}
+	if s.queue != nil {
+		if err := s.queue.Push(welcome); err != nil {
+			logrus.Errorf("could not queue welcome of installation %d: %v", id, err)
+			return http.StatusServiceUnavailable
+		}
+		return http.StatusOK
+	}
+	go func() {
+		if err := welcome(context.Background()); err != nil {
+			logrus.Error(err)
+		}
+	}()
+	return http.StatusOK
+}
+
+// extractIssueInfo returns the installation, repository, and issue number,
+// zero for events of a whole repository, of a webhook.
 func extractIssueInfo(kind string, body []byte) (inst int, owner, repo string, issue int, err error) {
 	logrus.Debugf("extracting issue info for message of kind %s", kind)
-	switch kind {
-	case "issue_comment":
-		var data github.IssueCommentEvent
-		if err := json.Unmarshal(body, &data); err != nil {
-			return 0, "", "", 0, errors.Wrap(err, "could not decode issue comment event")
-		}
-		repo := data.GetIssue().GetRepository()
-		if data.GetIssue().Repository == nil {
-			repo = data.GetRepo()
-		}
-		return int(data.GetInstallation().GetID()),
-			repo.GetOwner().GetLogin(),
-			repo.GetName(),
-			data.GetIssue().GetNumber(),
-			nil
-	case "issues":
-		var data github.IssuesEvent
-		if err := json.Unmarshal(body, &data); err != nil {
-			return 0, "", "", 0, errors.Wrap(err, "could not decode issue event")
-		}
-		repo := data.GetIssue().GetRepository()
-		if data.GetIssue().Repository == nil {
-			repo = data.GetRepo()
-		}
-		return int(data.GetInstallation().GetID()),
-			repo.GetOwner().GetLogin(),
-			repo.GetName(),
-			data.GetIssue().GetNumber(),
-			nil
-	case "pull_request":
-		var data github.PullRequestEvent
-		if err := json.Unmarshal(body, &data); err != nil {
-			return 0, "", "", 0, errors.Wrap(err, "could not decode pull request event")
-		}
-		return int(data.GetInstallation().GetID()),
-			data.GetPullRequest().GetHead().GetRepo().GetOwner().GetLogin(),
-			data.GetPullRequest().GetHead().GetRepo().GetName(),
-			data.GetPullRequest().GetNumber(),
-			nil
-	case "label":
-		var data github.LabelEvent
-		if err := json.Unmarshal(body, &data); err != nil {
-			return 0, "", "", 0, errors.Wrap(err, "could not decode label event")
-		}
-		return int(data.GetInstallation().GetID()),
-			data.GetRepo().GetOwner().GetLogin(),
-			data.GetRepo().GetName(),
-			0,
-			nil
-	}
-	return 0, "", "", 0, errors.Errorf("unkown event type %s", kind)
-}
-
-func checkSignature(got string, body, secret []byte) error {
-	if secret == nil {
-		return nil
+	event, err := github.ParseWebHook(kind, body)
+	if err != nil {
+		return 0, "", "", 0, errors.Wrapf(err, "could not decode %s event", kind)
 	}
 
-	if !strings.HasPrefix(got, "sha1=") {
-		return errors.Errorf("unknown hashing algorithm")
+	var r *github.Repository
+	switch e := event.(type) {
+	case *github.IssueCommentEvent:
+		inst, r, issue = int(e.GetInstallation().GetID()), e.GetIssue().GetRepository(), e.GetIssue().GetNumber()
+		if e.GetIssue().Repository == nil {
+			r = e.GetRepo()
+		}
+	case *github.IssuesEvent:
+		inst, r, issue = int(e.GetInstallation().GetID()), e.GetIssue().GetRepository(), e.GetIssue().GetNumber()
+		if e.GetIssue().Repository == nil {
+			r = e.GetRepo()
+		}
+	case *github.PullRequestEvent:
+		// the head repository of the pull requests from forks is the fork,
+		// while they belong to the repository of the event.
+		inst, r, issue = int(e.GetInstallation().GetID()), e.GetRepo(), e.GetPullRequest().GetNumber()
+	case *github.PullRequestReviewCommentEvent:
+		inst, r, issue = int(e.GetInstallation().GetID()), e.GetRepo(), e.GetPullRequest().GetNumber()
+	case *github.LabelEvent:
+		inst, r = int(e.GetInstallation().GetID()), e.GetRepo()
+	case *github.MilestoneEvent:
+		inst, r = int(e.GetInstallation().GetID()), e.GetRepo()
+	case *github.RepositoryEvent:
+		inst, r = int(e.GetInstallation().GetID()), e.GetRepo()
+	default:
+		return 0, "", "", 0, errors.Errorf("unknown event type %s", kind)
 	}
-	got = got[5:]
-	mac := hmac.New(sha1.New, secret)
-	mac.Write(body)
-	if wants := hex.EncodeToString(mac.Sum(nil)); wants != got {
-		return errors.Errorf("wrong signature")
+	return inst, r.GetOwner().GetLogin(), r.GetName(), issue, nil
+}
+
+// extractComment returns the author and body of newly created comments.
+func extractComment(kind string, body []byte) (author, text string, ok bool) {
+	if kind != "issue_comment" {
+		return "", "", false
 	}
-	return nil
+	event, err := github.ParseWebHook(kind, body)
+	if err != nil {
+		return "", "", false
+	}
+	e := event.(*github.IssueCommentEvent)
+	if e.GetAction() != "created" {
+		return "", "", false
+	}
+	return e.GetComment().GetUser().GetLogin(), e.GetComment().GetBody(), true
+}
+
+// extractHead returns the SHA of the head commit of the pull requests opened,
+// reopened or pushed to, so their deadline checks are reported on it.
+func extractHead(kind string, body []byte) (sha string, ok bool) {
+	if kind != "pull_request" {
+		return "", false
+	}
+	event, err := github.ParseWebHook(kind, body)
+	if err != nil {
+		return "", false
+	}
+	e := event.(*github.PullRequestEvent)
+	switch e.GetAction() {
+	case "opened", "reopened", "synchronize":
+		sha = e.GetPullRequest().GetHead().GetSHA()
+	}
+	return sha, sha != ""
+}
+
+// extractLabel returns the name of the label of label events, and its former
+// name if it was renamed. The client library doesn't decode former names yet.
+func extractLabel(kind string, body []byte) (name, from string, ok bool) {
+	if kind != "label" {
+		return "", "", false
+	}
+	var data struct {
+		Label struct {
+			Name string `json:"name"`
+		} `json:"label"`
+		Changes struct {
+			Name struct {
+				From string `json:"from"`
+			} `json:"name"`
+		} `json:"changes"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return "", "", false
+	}
+	return data.Label.Name, data.Changes.Name.From, true
+}
+
+// A move is a repository renamed or transferred, or an issue transferred to
+// another repository. The numbers of the issues are zero for repositories.
+type move struct {
+	fromOwner, fromRepo string
+	fromNumber          int
+	owner, repo         string
+	number              int
+}
+
+// extractMove returns the move of the repository events of repositories
+// renamed or transferred, and of the issues events of issues transferred.
+// The client library doesn't decode their changes yet.
+func extractMove(kind string, body []byte) (m move, ok bool) {
+	type repository struct {
+		Name  string `json:"name"`
+		Owner struct {
+			Login string `json:"login"`
+		} `json:"owner"`
+	}
+	var data struct {
+		Action string `json:"action"`
+		Issue  struct {
+			Number int `json:"number"`
+		} `json:"issue"`
+		Repository repository `json:"repository"`
+		Changes    struct {
+			Repository struct {
+				Name struct {
+					From string `json:"from"`
+				} `json:"name"`
+			} `json:"repository"`
+			Owner struct {
+				From struct {
+					User struct {
+						Login string `json:"login"`
+					} `json:"user"`
+					Organization struct {
+						Login string `json:"login"`
+					} `json:"organization"`
+				} `json:"from"`
+			} `json:"owner"`
+			NewIssue struct {
+				Number int `json:"number"`
+			} `json:"new_issue"`
+			NewRepository repository `json:"new_repository"`
+		} `json:"changes"`
+	}
+	if (kind != "repository" && kind != "issues") || json.Unmarshal(body, &data) != nil {
+		return move{}, false
+	}
+	r, ch := data.Repository, data.Changes
+	m = move{fromOwner: r.Owner.Login, fromRepo: r.Name, owner: r.Owner.Login, repo: r.Name}
+	switch {
+	case kind == "repository" && data.Action == "renamed" && ch.Repository.Name.From != "":
+		m.fromRepo = ch.Repository.Name.From
+	case kind == "repository" && data.Action == "transferred":
+		m.fromOwner = ch.Owner.From.User.Login
+		if m.fromOwner == "" {
+			m.fromOwner = ch.Owner.From.Organization.Login
+		}
+		if m.fromOwner == "" {
+			return move{}, false
+		}
+	case kind == "issues" && data.Action == "transferred" && ch.NewIssue.Number != 0:
+		m.fromNumber, m.number = data.Issue.Number, ch.NewIssue.Number
+		m.owner, m.repo = ch.NewRepository.Owner.Login, ch.NewRepository.Name
+	default:
+		return move{}, false
+	}
+	return m, true
+}
+
+// signature returns the signature of a webhook, preferring the SHA-256 one
+// of the X-Hub-Signature-256 header over the SHA-1 one when both are sent.
+func signature(header http.Header) string {
+	if sig := header.Get("X-Hub-Signature-256"); sig != "" {
+		return sig
+	}
+	return header.Get("X-Hub-Signature")
+}
+
+// validatePayload checks the signature of a webhook, returning its JSON
+// payload. The client library only reads the X-Hub-Signature header, so it's
+// given the preferred signature of the webhook in it.
+func validatePayload(header http.Header, body, secret []byte) ([]byte, error) {
+	if secret == nil {
+		return body, nil
+	}
+	r, err := http.NewRequest("POST", "/", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	r.Header.Set("Content-Type", header.Get("Content-Type"))
+	if r.Header.Get("Content-Type") == "" {
+		// envelopes don't always keep the content type.
+		r.Header.Set("Content-Type", "application/json")
+	}
+	r.Header.Set("X-Hub-Signature", signature(header))
+	return github.ValidatePayload(r, secret)
 }
@@ -0,0 +1,477 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/github"
+
+	"github.com/src-d/github-reminder/handler/handlertest"
+	"github.com/src-d/github-reminder/reminder"
+	"github.com/src-d/github-reminder/storage"
+)
+
+func TestSyntheticPayloads(t *testing.T) {
+	secret := []byte("s3cr3t")
+	r := handlertest.Repo{Installation: 43, Owner: "foo", Name: "bar"}
+
+	tests := []handlertest.Payload{
+		handlertest.IssueComment(r, 1, "francesc", "/ooo clear"),
+		handlertest.Issues(r, 1, "labeled"),
+		handlertest.PullRequest(r, 1, "opened"),
+	}
+	for _, p := range tests {
+		t.Run(p.Event, func(t *testing.T) {
+			req := p.Request("http://localhost/hook", secret)
+			if _, err := validatePayload(req.Header, p.Body, secret); err != nil {
+				t.Errorf("unexpected signature error: %v", err)
+			}
+			if _, err := validatePayload(req.Header, p.Body, []byte("wrong")); err == nil {
+				t.Errorf("expected signature with the wrong secret to fail")
+			}
+
+			inst, owner, repo, number, err := extractIssueInfo(req.Header.Get("X-Github-Event"), p.Body)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if inst != 43 || owner != "foo" || repo != "bar" || number != 1 {
+				t.Errorf("expected 43 foo/bar#1; got %d %s/%s#%d", inst, owner, repo, number)
+			}
+		})
+	}
+
+	author, text, ok := extractComment(tests[0].Event, tests[0].Body)
+	if !ok || author != "francesc" || text != "/ooo clear" {
+		t.Errorf("expected comment by francesc; got %q by %q (%v)", text, author, ok)
+	}
+
+	if sha, ok := extractHead(tests[2].Event, tests[2].Body); !ok || sha != handlertest.HeadSHA {
+		t.Errorf("expected head %s of the opened pull request; got %q (%v)", handlertest.HeadSHA, sha, ok)
+	}
+	closed := handlertest.PullRequest(r, 1, "closed")
+	if _, ok := extractHead(closed.Event, closed.Body); ok {
+		t.Errorf("expected no head for closed pull requests")
+	}
+}
+
+func TestSignatures(t *testing.T) {
+	secret, body := []byte("s3cr3t"), []byte(`{"action": "opened"}`)
+	tests := []struct {
+		name   string
+		sha1   string
+		sha256 string
+		ok     bool
+	}{
+		{"sha256", "", handlertest.Sign256(body, secret), true},
+		{"sha1 fallback", handlertest.Sign(body, secret), "", true},
+		{"sha256 preferred", handlertest.Sign(body, []byte("wrong")), handlertest.Sign256(body, secret), true},
+		{"wrong sha256", handlertest.Sign(body, secret), handlertest.Sign256(body, []byte("wrong")), false},
+		{"malformed", "", "sha256=zz", false},
+		{"unknown algorithm", "md5=0a1b", "", false},
+		{"missing", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.sha1 != "" {
+				header.Set("X-Hub-Signature", tt.sha1)
+			}
+			if tt.sha256 != "" {
+				header.Set("X-Hub-Signature-256", tt.sha256)
+			}
+			if _, err := validatePayload(header, body, secret); (err == nil) != tt.ok {
+				t.Errorf("expected valid signature %v; got %v", tt.ok, err)
+			}
+		})
+	}
+}
+
+func TestFormPayloads(t *testing.T) {
+	secret := []byte("s3cr3t")
+	p := handlertest.Issues(handlertest.Repo{Installation: 43, Owner: "foo", Name: "bar"}, 1, "opened")
+	body := []byte(url.Values{"payload": {string(p.Body)}}.Encode())
+	header := http.Header{}
+	header.Set("Content-Type", "application/x-www-form-urlencoded")
+	header.Set("X-Hub-Signature-256", handlertest.Sign256(body, secret))
+
+	payload, err := validatePayload(header, body, secret)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(payload, p.Body) {
+		t.Errorf("expected the payload of the form; got %s", payload)
+	}
+}
+
+func TestForkPullRequests(t *testing.T) {
+	body, _ := json.Marshal(&github.PullRequestEvent{
+		Action: github.String("opened"),
+		PullRequest: &github.PullRequest{
+			Number: github.Int(7),
+			Head:   &github.PullRequestBranch{Repo: &github.Repository{Name: github.String("bar"), Owner: &github.User{Login: github.String("fork")}}},
+		},
+		Repo:         &github.Repository{Name: github.String("bar"), Owner: &github.User{Login: github.String("foo")}},
+		Installation: &github.Installation{ID: github.Int64(43)},
+	})
+	inst, owner, repo, number, err := extractIssueInfo("pull_request", body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if inst != 43 || owner != "foo" || repo != "bar" || number != 7 {
+		t.Errorf("expected 43 foo/bar#7; got %d %s/%s#%d", inst, owner, repo, number)
+	}
+}
+
+func TestMilestoneEvents(t *testing.T) {
+	body, _ := json.Marshal(&github.MilestoneEvent{
+		Action:       github.String("edited"),
+		Milestone:    &github.Milestone{Number: github.Int(3)},
+		Repo:         &github.Repository{Name: github.String("bar"), Owner: &github.User{Login: github.String("foo")}},
+		Installation: &github.Installation{ID: github.Int64(43)},
+	})
+	inst, owner, repo, number, err := extractIssueInfo("milestone", body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if inst != 43 || owner != "foo" || repo != "bar" || number != 0 {
+		t.Errorf("expected 43 foo/bar; got %d %s/%s#%d", inst, owner, repo, number)
+	}
+}
+
+func TestMoves(t *testing.T) {
+	tests := []struct {
+		kind, body string
+		expected   move
+		ok         bool
+	}{
+		{"repository", `{"action": "renamed", "repository": {"name": "baz", "owner": {"login": "foo"}},
+			"changes": {"repository": {"name": {"from": "bar"}}}}`, move{"foo", "bar", 0, "foo", "baz", 0}, true},
+		{"repository", `{"action": "transferred", "repository": {"name": "bar", "owner": {"login": "acme"}},
+			"changes": {"owner": {"from": {"user": {"login": "foo"}}}}}`, move{"foo", "bar", 0, "acme", "bar", 0}, true},
+		{"issues", `{"action": "transferred", "issue": {"number": 1}, "repository": {"name": "bar", "owner": {"login": "foo"}},
+			"changes": {"new_issue": {"number": 7}, "new_repository": {"name": "web", "owner": {"login": "acme"}}}}`,
+			move{"foo", "bar", 1, "acme", "web", 7}, true},
+		{"repository", `{"action": "archived", "repository": {"name": "bar", "owner": {"login": "foo"}}}`, move{}, false},
+		{"issues", `{"action": "opened", "issue": {"number": 1}, "repository": {"name": "bar", "owner": {"login": "foo"}}}`, move{}, false},
+	}
+	for _, tt := range tests {
+		m, ok := extractMove(tt.kind, []byte(tt.body))
+		if ok != tt.ok || m != tt.expected {
+			t.Errorf("expected %+v (%v) for %s; got %+v (%v)", tt.expected, tt.ok, tt.body, m, ok)
+		}
+	}
+}
+
+func TestInstallationEvents(t *testing.T) {
+	body, _ := json.Marshal(&github.InstallationEvent{
+		Action:       github.String("deleted"),
+		Installation: &github.Installation{ID: github.Int64(43)},
+	})
+	s := &server{appID: 42}
+	if code := s.process(context.Background(), http.Header{"X-Github-Event": {"installation"}}, body); code != http.StatusOK {
+		t.Errorf("expected removed installations to be ignored; got status %d", code)
+	}
+	if code := s.process(context.Background(), http.Header{"X-Github-Event": {"installation"}}, []byte("{")); code != http.StatusBadRequest {
+		t.Errorf("expected malformed events to be rejected; got status %d", code)
+	}
+}
+
+func TestLabelEvents(t *testing.T) {
+	body := []byte(`{"action": "edited", "label": {"name": "deadline < 3"}, "changes": {"name": {"from": "deadline < 5"}}}`)
+	name, from, ok := extractLabel("label", body)
+	if !ok || name != "deadline < 3" || from != "deadline < 5" {
+		t.Errorf("expected deadline < 5 renamed to deadline < 3; got %q from %q (%v)", name, from, ok)
+	}
+	if _, _, ok := extractLabel("issues", body); ok {
+		t.Errorf("expected only label events to have labels")
+	}
+}
+
+func TestEnvelopes(t *testing.T) {
+	secret := []byte("s3cr3t")
+	p := handlertest.Issues(handlertest.Repo{Installation: 43, Owner: "foo", Name: "bar"}, 1, "opened")
+
+	wrapped, _ := json.Marshal(map[string]interface{}{
+		"headers": map[string]string{
+			"x-github-event":  p.Event,
+			"x-hub-signature": handlertest.Sign(p.Body, secret),
+		},
+		"body":            base64.StdEncoding.EncodeToString(p.Body),
+		"isBase64Encoded": true,
+	})
+	wh, err := HTTPEnvelope{}.Unwrap(httptest.NewRequest("POST", "/hook", nil), wrapped)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if wh.Trusted || wh.Header.Get("X-Github-Event") != "issues" || !bytes.Equal(wh.Body, p.Body) {
+		t.Errorf("unexpected webhook %+v", wh)
+	}
+	if _, err := validatePayload(wh.Header, wh.Body, secret); err != nil {
+		t.Errorf("unexpected signature error: %v", err)
+	}
+
+	event := []byte(`{"detail-type": "issues", "source": "github.com", "detail": ` + string(p.Body) + `}`)
+	eb := EventBridgeEnvelope{APIKey: "key"}
+	r := httptest.NewRequest("POST", "/hook", nil)
+	if _, err := eb.Unwrap(r, event); err == nil {
+		t.Errorf("expected events without API key to be rejected")
+	}
+	r.Header.Set("X-Api-Key", "key")
+	if wh, err = eb.Unwrap(r, event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !wh.Trusted || wh.Header.Get("X-Github-Event") != "issues" {
+		t.Errorf("unexpected webhook %+v", wh)
+	}
+	if _, _, _, number, err := extractIssueInfo("issues", wh.Body); err != nil || number != 1 {
+		t.Errorf("expected issue 1; got %d (%v)", number, err)
+	}
+}
+
+func TestRelay(t *testing.T) {
+	received := make(chan *http.Request, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r
+	}))
+	defer srv.Close()
+
+	secret := []byte("s3cr3t")
+	h, err := New(42, []byte("not a key"), secret, nil, WithRelay(URLRelay{URLs: []string{srv.URL}}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	p := handlertest.Issues(handlertest.Repo{Installation: 43, Owner: "foo", Name: "bar"}, 1, "opened")
+	h.ServeHTTP(httptest.NewRecorder(), p.Request("/hook", []byte("wrong")))
+	h.ServeHTTP(httptest.NewRecorder(), p.Request("/hook", secret))
+
+	select {
+	case r := <-received:
+		if r.Header.Get("X-GitHub-Event") != "issues" || r.Header.Get("X-Hub-Signature") != handlertest.Sign(p.Body, secret) {
+			t.Errorf("unexpected relayed headers %v", r.Header)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("webhook was not relayed")
+	}
+	select {
+	case <-received:
+		t.Errorf("expected webhooks with bad signatures not to be relayed")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// events that can't be decoded are rejected before being relayed.
+	bad := handlertest.Payload{Event: "issues", Body: []byte("{")}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, bad.Request("/hook", secret))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected malformed events to be rejected; got status %d", w.Code)
+	}
+	select {
+	case <-received:
+		t.Errorf("expected malformed events not to be relayed")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestRelayQueued(t *testing.T) {
+	relayed := make(chan string, 2)
+	var jobs []Job
+	full := false
+	secret := []byte("s3cr3t")
+	h, err := New(42, []byte("not a key"), secret, nil,
+		WithRelay(RelayFunc(func(ctx context.Context, header http.Header, body []byte) error {
+			relayed <- header.Get("X-GitHub-Event")
+			return nil
+		})),
+		WithQueue(queueFunc(func(job Job) error {
+			if full {
+				return errors.New("queue is full")
+			}
+			jobs = append(jobs, job)
+			return nil
+		})))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	none := func(msg string) {
+		select {
+		case <-relayed:
+			t.Error(msg)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	p := handlertest.Issues(handlertest.Repo{Installation: 43, Owner: "foo", Name: "bar"}, 1, "opened")
+	h.ServeHTTP(httptest.NewRecorder(), p.Request("/hook", secret))
+	none("expected queued webhooks not to be relayed before being processed")
+
+	// the key is not valid, so it fails and is retried, but only relayed once.
+	jobs[0](context.Background())
+	jobs[0](context.Background())
+	select {
+	case <-relayed:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the webhook to be relayed once processed")
+	}
+	none("expected the retries not to relay the webhook again")
+
+	full = true
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, p.Request("/hook", secret))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected the webhook not to be queued; got status %d", w.Code)
+	}
+	none("expected webhooks that can't be queued not to be relayed")
+}
+
+func TestSafeMode(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemory()
+	for i, active := range []bool{false, false, true} {
+		sm, err := RecordStart(ctx, store, 2, time.Hour)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sm.Active != active {
+			t.Errorf("start %d: expected active to be %v", i, active)
+		}
+	}
+
+	secret := []byte("s3cr3t")
+	h, err := New(42, []byte("not a key"), secret, nil, WithStore(store), WithAdminToken("t0k3n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := h.Cron(ctx); err != nil {
+		t.Errorf("expected cron to be skipped in safe mode; got %v", err)
+	}
+
+	p := handlertest.Issues(handlertest.Repo{Installation: 43, Owner: "foo", Name: "bar"}, 1, "opened")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, p.Request("/hook", secret))
+	if w.Code != http.StatusAccepted {
+		t.Errorf("expected webhook to be queued; got status %d", w.Code)
+	}
+	queued, _ := store.List(ctx, "safemode/queue/")
+	if len(queued) != 1 {
+		t.Fatalf("expected 1 queued webhook; got %d", len(queued))
+	}
+
+	for _, req := range []struct{ method, path string }{
+		{"POST", "/import/43"},
+		{"POST", "/changesets/43/foo/bar/approve"},
+		{"PUT", "/settings/43.yaml"},
+	} {
+		r := httptest.NewRequest(req.method, req.path, strings.NewReader(""))
+		r.Header.Set("Authorization", "Bearer t0k3n")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != http.StatusServiceUnavailable {
+			t.Errorf("expected %s %s to be refused in safe mode; got status %d", req.method, req.path, w.Code)
+		}
+	}
+
+	r := httptest.NewRequest("POST", "/safemode/clear", nil)
+	r.Header.Set("Authorization", "Bearer t0k3n")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected safe mode to be cleared; got status %d", w.Code)
+	}
+	if queued, _ := store.List(ctx, "safemode/queue/"); len(queued) != 0 {
+		t.Errorf("expected queue to be processed; got %v", queued)
+	}
+	if sm, err := RecordStart(ctx, store, 2, time.Hour); err != nil || sm.Active || len(sm.Starts) != 1 {
+		t.Errorf("expected a clean start after clearing; got %+v (%v)", sm, err)
+	}
+}
+
+func TestFeeds(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemory()
+	for _, d := range []reminder.Deadline{
+		{Owner: "foo", Repo: "bar", Number: 1, Title: "Ship it", Deadline: time.Date(2018, 8, 3, 0, 0, 0, 0, time.UTC)},
+		{Owner: "foo", Repo: "baz", Number: 2, Title: "Other", Deadline: time.Date(2018, 8, 4, 0, 0, 0, 0, time.UTC)},
+	} {
+		if err := store.Put(ctx, storage.Key("deadline", 42, 43, d.Owner, d.Repo, d.Number), d); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	get := func(h http.Handler, url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", url, nil))
+		return w
+	}
+	h, err := New(42, []byte("not a key"), nil, nil, WithStore(store))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if w := get(h, "/calendar/43/foo/bar.ics?token="+FeedToken("s3cr3t", "/calendar/43/foo/bar.ics")); w.Code != http.StatusNotFound {
+		t.Errorf("expected calendars to be disabled without a secret; got status %d", w.Code)
+	}
+
+	h, err = New(42, []byte("not a key"), nil, nil, WithStore(store), WithFeedToken("s3cr3t"), WithAdminToken("t0k3n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if w := get(h, "/calendar/43/foo/bar.ics?token=s3cr3t"); w.Code != http.StatusForbidden {
+		t.Errorf("expected the secret not to be a token; got status %d", w.Code)
+	}
+	if w := get(h, "/calendar/43/foo/baz.ics?token="+FeedToken("s3cr3t", "/calendar/43/foo/bar.ics")); w.Code != http.StatusForbidden {
+		t.Errorf("expected the token of a calendar not to open another; got status %d", w.Code)
+	}
+	if w := get(h, "/calendar/44/foo/bar.ics?token="+FeedToken("s3cr3t", "/calendar/43/foo/bar.ics")); w.Code != http.StatusForbidden {
+		t.Errorf("expected the token of a calendar not to open another installation; got status %d", w.Code)
+	}
+	w := get(h, "/calendar/43/Foo/bar.ics?token="+FeedToken("s3cr3t", "/calendar/43/foo/bar.ics"))
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "text/calendar; charset=utf-8" {
+		t.Fatalf("expected a calendar; got status %d, %s", w.Code, w.Header().Get("Content-Type"))
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, "SUMMARY:foo/bar#1 Ship it") || strings.Contains(body, "foo/baz") {
+		t.Errorf("expected only the deadlines of foo/bar; got %q", body)
+	}
+
+	if w := get(h, "/feed/43.atom"); w.Code != http.StatusForbidden {
+		t.Errorf("expected feeds to require the token; got status %d", w.Code)
+	}
+	if w := get(h, "/feed/44.atom?token="+FeedToken("s3cr3t", "/feed/43.atom")); w.Code != http.StatusForbidden {
+		t.Errorf("expected the token of an installation not to open another; got status %d", w.Code)
+	}
+	w = get(h, "/feed/43.atom?token="+FeedToken("s3cr3t", "/feed/43.atom"))
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/atom+xml; charset=utf-8" {
+		t.Fatalf("expected a feed; got status %d, %s", w.Code, w.Header().Get("Content-Type"))
+	}
+	body = w.Body.String()
+	if i, j := strings.Index(body, "foo/bar#1"), strings.Index(body, "foo/baz#2"); i < 0 || j < 0 || j < i {
+		t.Errorf("expected the deadlines of the installation by due date; got %q", body)
+	}
+
+	req := httptest.NewRequest("GET", "/feeds/43", nil)
+	req.Header.Set("Authorization", "Bearer t0k3n")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	var urls struct {
+		Atom      string
+		Calendars map[string]string
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &urls); err != nil {
+		t.Fatalf("could not decode feeds: %v", err)
+	}
+	if w := get(h, urls.Atom); w.Code != http.StatusOK {
+		t.Errorf("expected the atom feed listed to open; got status %d", w.Code)
+	}
+	if w := get(h, urls.Calendars["foo/baz"]); w.Code != http.StatusOK || len(urls.Calendars) != 2 {
+		t.Errorf("expected the calendars listed to open; got status %d, %v", w.Code, urls.Calendars)
+	}
+}
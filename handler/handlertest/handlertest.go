@@ -0,0 +1,133 @@
+// Package handlertest provides utilities to test integrations with the
+// github-reminder webhook handler, building signed synthetic GitHub payloads.
+package handlertest
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/google/go-github/github"
+)
+
+// A Payload is a webhook delivery, as sent by GitHub.
+type Payload struct {
+	// Event is the kind of event, sent in the X-GitHub-Event header.
+	Event string
+	// Body is the JSON encoded event.
+	Body []byte
+}
+
+// Repo identifies the repository and installation a payload belongs to.
+type Repo struct {
+	Installation int64
+	Owner        string
+	Name         string
+}
+
+func (r Repo) installation() *github.Installation {
+	return &github.Installation{ID: github.Int64(r.Installation)}
+}
+
+func (r Repo) repository() *github.Repository {
+	return &github.Repository{
+		Name:     github.String(r.Name),
+		FullName: github.String(r.Owner + "/" + r.Name),
+		Owner:    &github.User{Login: github.String(r.Owner)},
+	}
+}
+
+func user(login string) *github.User {
+	return &github.User{Login: github.String(login)}
+}
+
+// IssueComment returns an issue_comment payload for a comment with the given
+// author and body created on an issue.
+func IssueComment(r Repo, number int, author, body string) Payload {
+	return payload("issue_comment", &github.IssueCommentEvent{
+		Action:       github.String("created"),
+		Issue:        &github.Issue{Number: github.Int(number), Repository: r.repository()},
+		Comment:      &github.IssueComment{Body: github.String(body), User: user(author)},
+		Repo:         r.repository(),
+		Sender:       user(author),
+		Installation: r.installation(),
+	})
+}
+
+// Issues returns an issues payload with the given action, e.g. opened or labeled.
+func Issues(r Repo, number int, action string) Payload {
+	return payload("issues", &github.IssuesEvent{
+		Action:       github.String(action),
+		Issue:        &github.Issue{Number: github.Int(number), Repository: r.repository()},
+		Repo:         r.repository(),
+		Installation: r.installation(),
+	})
+}
+
+// HeadSHA is the SHA of the head commit of the pull requests of the payloads.
+const HeadSHA = "6dcb09b5b57875f334f61aebed695e2e4193db5e"
+
+// PullRequest returns a pull_request payload with the given action, e.g. opened or synchronize.
+func PullRequest(r Repo, number int, action string) Payload {
+	return payload("pull_request", &github.PullRequestEvent{
+		Action: github.String(action),
+		Number: github.Int(number),
+		PullRequest: &github.PullRequest{
+			Number: github.Int(number),
+			Head:   &github.PullRequestBranch{Repo: r.repository(), SHA: github.String(HeadSHA)},
+			Base:   &github.PullRequestBranch{Repo: r.repository()},
+		},
+		Repo:         r.repository(),
+		Installation: r.installation(),
+	})
+}
+
+func payload(event string, v interface{}) Payload {
+	body, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("could not encode %s payload: %v", event, err))
+	}
+	return Payload{Event: event, Body: body}
+}
+
+var deliveries int64
+
+// Request returns a POST request delivering the payload to url, with the
+// headers GitHub would send, signed with secret unless it is nil.
+func (p Payload) Request(url string, secret []byte) *http.Request {
+	req, err := http.NewRequest("POST", url, bytes.NewReader(p.Body))
+	if err != nil {
+		panic(fmt.Sprintf("could not create request: %v", err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Event", p.Event)
+	req.Header.Set("X-GitHub-Delivery", fmt.Sprintf("handlertest-%d", atomic.AddInt64(&deliveries, 1)))
+	if secret != nil {
+		req.Header.Set("X-Hub-Signature", Sign(p.Body, secret))
+		req.Header.Set("X-Hub-Signature-256", Sign256(p.Body, secret))
+	}
+	return req
+}
+
+// Sign returns the value of the X-Hub-Signature header for body, e.g. sha1=0a1b...
+func Sign(body, secret []byte) string {
+	return "sha1=" + hexMAC(sha1.New, body, secret)
+}
+
+// Sign256 returns the value of the X-Hub-Signature-256 header for body, e.g. sha256=0a1b...
+func Sign256(body, secret []byte) string {
+	return "sha256=" + hexMAC(sha256.New, body, secret)
+}
+
+func hexMAC(h func() hash.Hash, body, secret []byte) string {
+	mac := hmac.New(h, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/export"
+	"github.com/src-d/github-reminder/reminder"
+	"github.com/src-d/github-reminder/storage"
+)
+
+// WithExporter publishes the deadline inventory of every installation after each cron run.
+func WithExporter(e export.Exporter) Option {
+	return func(s *server) { s.exporter = e }
+}
+
+// export publishes the inventory of the installation, if an exporter is configured.
+func (s *server) export(ctx context.Context, client *reminder.InstallationClient, instID int) error {
+	if s.exporter == nil {
+		return nil
+	}
+	ds, err := client.Deadlines(ctx)
+	if err != nil {
+		return err
+	}
+	return s.exporter.Export(ctx, instID, ds)
+}
+
+// WithEmailDigest emails the digest of the upcoming deadlines of every
+// installation once it's due, checked after each cron run.
+func WithEmailDigest(d *export.EmailDigest) Option {
+	return func(s *server) { s.digest = d }
+}
+
+// sendDigest emails the digest of the installation, if configured and due.
+func (s *server) sendDigest(ctx context.Context, client *reminder.InstallationClient, instID int) error {
+	if s.digest == nil {
+		return nil
+	}
+	now := time.Now()
+	key := storage.Key("emaildigest", s.appID, instID)
+	var last time.Time
+	if err := s.store.Get(ctx, key, &last); err != nil && err != storage.ErrNotFound {
+		return errors.Wrap(err, "could not fetch last digest")
+	}
+	if !s.digest.Due(last, now) {
+		return nil
+	}
+
+	ds, err := client.Deadlines(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.digest.Send(instID, ds, now); err != nil {
+		return err
+	}
+	return errors.Wrap(s.store.Put(ctx, key, now), "could not record digest")
+}
+
+func (s *server) inventoryHandler(w http.ResponseWriter, r *http.Request) {
+	inst, err := strconv.Atoi(mux.Vars(r)["installation"])
+	if err != nil {
+		http.Error(w, "bad installation id", http.StatusBadRequest)
+		return
+	}
+
+	ds, err := reminder.Deadlines(r.Context(), s.store, s.appID, inst)
+	if err != nil {
+		logrus.Errorf("could not list deadlines: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=deadlines-%d.csv", inst))
+	if err := export.WriteCSV(w, ds); err != nil {
+		logrus.Warnf("could not write inventory: %v", err)
+	}
+}
+
+func (s *server) historyHandler(w http.ResponseWriter, r *http.Request) {
+	inst, err := strconv.Atoi(mux.Vars(r)["installation"])
+	if err != nil {
+		http.Error(w, "bad installation id", http.StatusBadRequest)
+		return
+	}
+
+	outcomes, err := reminder.History(r.Context(), s.store, s.appID, inst)
+	if err != nil {
+		logrus.Errorf("could not list history: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	if outcomes == nil {
+		outcomes = []reminder.Outcome{}
+	}
+	writeJSON(w, outcomes)
+}
+
+func (s *server) fallbacksHandler(w http.ResponseWriter, r *http.Request) {
+	inst, err := strconv.Atoi(mux.Vars(r)["installation"])
+	if err != nil {
+		http.Error(w, "bad installation id", http.StatusBadRequest)
+		return
+	}
+
+	fs, err := reminder.Fallbacks(r.Context(), s.store, s.appID, inst)
+	if err != nil {
+		logrus.Errorf("could not list fallbacks: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	if fs == nil {
+		fs = []reminder.Fallback{}
+	}
+	writeJSON(w, fs)
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// A Job is the work done for a webhook, failing if it's worth retrying.
+type Job func(ctx context.Context) error
+
+// A Queue runs the jobs of the webhooks in the background, so they're answered
+// before the GitHub API is called and slow issues don't make GitHub time out.
+type Queue interface {
+	// Push queues a job, failing if the queue can't take more.
+	Push(job Job) error
+}
+
+// WithQueue makes the handler answer the webhooks with 202 Accepted as soon as
+// they're validated, processing them later with the jobs pushed to q.
+func WithQueue(q Queue) Option {
+	return func(s *server) { s.queue = q }
+}
+
+// A depther is a Queue reporting how many of its jobs are not done yet, which
+// the status page shows as the depth of the queue.
+type depther interface {
+	Depth() int
+}
+
+// memoryQueue runs the jobs in up to a number of goroutines, started as jobs
+// are pushed and stopped once there are no more.
+type memoryQueue struct {
+	pending int64 // accessed atomically
+
+	jobs     chan queuedJob
+	workers  chan struct{}
+	attempts int
+	// backoff is the wait before the first retry, doubled on every retry.
+	backoff time.Duration
+}
+
+// A queuedJob is a job with the attempts it was already tried, and the wait
+// before its next retry.
+type queuedJob struct {
+	job     Job
+	attempt int
+	wait    time.Duration
+}
+
+// NewMemoryQueue returns a Queue keeping up to size jobs in memory, run by up
+// to the given number of workers. Failed jobs are tried up to attempts times,
+// waiting a second before the first retry and twice as long before each of
+// the rest, queued again once the wait is over so it doesn't keep a worker
+// busy. Queued jobs are lost if the process exits.
+func NewMemoryQueue(workers, size, attempts int) Queue {
+	if workers < 1 {
+		workers = 1
+	}
+	if attempts < 1 {
+		attempts = 1
+	}
+	return &memoryQueue{
+		jobs:     make(chan queuedJob, size),
+		workers:  make(chan struct{}, workers),
+		attempts: attempts,
+		backoff:  time.Second,
+	}
+}
+
+// Push implements the Queue interface.
+func (q *memoryQueue) Push(job Job) error {
+	atomic.AddInt64(&q.pending, 1)
+	if err := q.push(queuedJob{job: job, wait: q.backoff}); err != nil {
+		atomic.AddInt64(&q.pending, -1)
+		return err
+	}
+	return nil
+}
+
+// Depth returns the number of jobs queued, running or waiting to be retried.
+func (q *memoryQueue) Depth() int {
+	return int(atomic.LoadInt64(&q.pending))
+}
+
+func (q *memoryQueue) push(job queuedJob) error {
+	select {
+	case q.jobs <- job:
+	default:
+		return errors.New("queue is full")
+	}
+	select {
+	case q.workers <- struct{}{}:
+		go q.work()
+	default:
+		// every worker is busy, one of them will take it.
+	}
+	return nil
+}
+
+// work runs the queued jobs until there are no more.
+func (q *memoryQueue) work() {
+	for {
+		select {
+		case job := <-q.jobs:
+			q.run(job)
+			continue
+		default:
+		}
+
+		<-q.workers
+		// a job pushed while every worker was busy is left to this one,
+		// unless another worker was started for it.
+		if len(q.jobs) == 0 {
+			return
+		}
+		select {
+		case q.workers <- struct{}{}:
+		default:
+			return
+		}
+	}
+}
+
+// run runs the job, queuing it again after its wait if it fails, up to the
+// number of attempts.
+func (q *memoryQueue) run(job queuedJob) {
+	err := job.job(context.Background())
+	if err == nil {
+		atomic.AddInt64(&q.pending, -1)
+		return
+	}
+	job.attempt++
+	if job.attempt >= q.attempts {
+		logrus.Errorf("giving up on queued webhook after %d attempts: %v", job.attempt, err)
+		atomic.AddInt64(&q.pending, -1)
+		return
+	}
+	logrus.Warnf("retrying queued webhook in %v: %v", job.wait, err)
+	retry := job
+	retry.wait *= 2
+	time.AfterFunc(job.wait, func() {
+		if err := q.push(retry); err != nil {
+			logrus.Errorf("could not queue webhook again: %v", err)
+			atomic.AddInt64(&q.pending, -1)
+		}
+	})
+}
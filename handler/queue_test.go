@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/src-d/github-reminder/handler/handlertest"
+)
+
+func TestMemoryQueue(t *testing.T) {
+	q := NewMemoryQueue(2, 10, 3).(*memoryQueue)
+	q.backoff = time.Millisecond
+
+	var mu sync.Mutex
+	runs := make([]int, 5)
+	last := make(chan int, 5)
+	for i := range runs {
+		i := i
+		err := q.Push(func(ctx context.Context) error {
+			mu.Lock()
+			runs[i]++
+			n := runs[i]
+			mu.Unlock()
+			// the first job succeeds on its last attempt, the second one never does.
+			if n == 3 || (i > 1 && n == 1) {
+				last <- i
+			}
+			if (i == 0 && n < 3) || i == 1 {
+				return errors.New("try again")
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	for range runs {
+		select {
+		case <-last:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("jobs were not run")
+		}
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	if expected := []int{3, 3, 1, 1, 1}; fmt.Sprint(runs) != fmt.Sprint(expected) {
+		t.Errorf("expected runs %v; got %v", expected, runs)
+	}
+}
+
+func TestQueueRetriesFreeWorkers(t *testing.T) {
+	q := NewMemoryQueue(1, 10, 2).(*memoryQueue)
+	q.backoff = time.Hour
+
+	ran := make(chan struct{}, 1)
+	q.Push(func(ctx context.Context) error { return errors.New("try again") })
+	q.Push(func(ctx context.Context) error {
+		ran <- struct{}{}
+		return nil
+	})
+	select {
+	case <-ran:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the only worker to run the next job while the failed one waits")
+	}
+}
+
+func TestFullQueue(t *testing.T) {
+	q := NewMemoryQueue(1, 1, 1)
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	block := func(ctx context.Context) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}
+	if err := q.Push(block); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	<-started
+	if err := q.Push(func(ctx context.Context) error { return nil }); err != nil {
+		t.Fatalf("expected the job to wait for the worker; got %v", err)
+	}
+	if err := q.Push(func(ctx context.Context) error { return nil }); err == nil {
+		t.Errorf("expected a full queue to refuse jobs")
+	}
+}
+
+func TestQueueDepth(t *testing.T) {
+	q := NewMemoryQueue(1, 10, 1)
+	h, err := New(42, []byte("not a key"), nil, nil, WithQueue(q))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	depth := func() int64 {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/status?format=json", nil))
+		var rep statusReport
+		if err := json.NewDecoder(w.Body).Decode(&rep); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return rep.QueueDepth
+	}
+
+	started, release, done := make(chan struct{}), make(chan struct{}), make(chan struct{}, 2)
+	q.Push(func(ctx context.Context) error {
+		started <- struct{}{}
+		<-release
+		done <- struct{}{}
+		return nil
+	})
+	q.Push(func(ctx context.Context) error {
+		done <- struct{}{}
+		return nil
+	})
+	<-started
+	if d := depth(); d != 2 {
+		t.Errorf("expected the running and the queued jobs in the queue depth; got %d", d)
+	}
+
+	close(release)
+	<-done
+	<-done
+	// the job is only done once it returns, right after signaling it.
+	deadline := time.Now().Add(5 * time.Second)
+	for d := depth(); d != 0; d = depth() {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected an empty queue once the jobs are done; got depth %d", d)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// queueFunc allows using ordinary functions as a Queue.
+type queueFunc func(job Job) error
+
+func (f queueFunc) Push(job Job) error { return f(job) }
+
+func TestQueuedWebhooks(t *testing.T) {
+	var jobs []Job
+	secret := []byte("s3cr3t")
+	h, err := New(42, []byte("not a key"), secret, nil, WithQueue(queueFunc(func(job Job) error {
+		jobs = append(jobs, job)
+		return nil
+	})))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	p := handlertest.Issues(handlertest.Repo{Installation: 43, Owner: "foo", Name: "bar"}, 1, "opened")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, p.Request("/hook", secret))
+	if w.Code != http.StatusAccepted || len(jobs) != 1 {
+		t.Fatalf("expected the webhook to be queued; got status %d and %d jobs", w.Code, len(jobs))
+	}
+	// the key is not valid, so it fails as GitHub couldn't be reached.
+	if err := jobs[0](context.Background()); err == nil {
+		t.Errorf("expected the job to fail to be retried")
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, p.Request("/hook", []byte("wrong")))
+	if w.Code != http.StatusForbidden || len(jobs) != 1 {
+		t.Errorf("expected webhooks with bad signatures not to be queued; got status %d", w.Code)
+	}
+}
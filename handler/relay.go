@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// A Relay re-publishes the validated webhooks received by the handler, so
+// other tools can rely on the same GitHub App webhook.
+type Relay interface {
+	Publish(ctx context.Context, header http.Header, body []byte) error
+}
+
+// RelayFunc allows using ordinary functions as a Relay.
+type RelayFunc func(ctx context.Context, header http.Header, body []byte) error
+
+// Publish implements the Relay interface.
+func (f RelayFunc) Publish(ctx context.Context, header http.Header, body []byte) error {
+	return f(ctx, header, body)
+}
+
+// WithRelay publishes every validated webhook with r once it has been processed,
+// or once queued in safe mode. Webhooks with bad signatures or events that can't
+// be decoded, and those that can't be queued, are not published.
+// Webhooks are published in the background, without delaying the responses to GitHub.
+func WithRelay(r Relay) Option {
+	return func(s *server) { s.relays = append(s.relays, r) }
+}
+
+// relayTimeout is the maximum time given to a relay to publish a webhook.
+const relayTimeout = 30 * time.Second
+
+// relayedHeaders are the headers of the original webhook kept when relaying it.
+var relayedHeaders = []string{
+	"Content-Type",
+	"User-Agent",
+	"X-GitHub-Event",
+	"X-GitHub-Delivery",
+	"X-GitHub-Enterprise-Host",
+	"X-Hub-Signature",
+	"X-Hub-Signature-256",
+}
+
+// relay publishes the webhook with every relay.
+func (s *server) relay(header http.Header, body []byte) {
+	if len(s.relays) == 0 {
+		return
+	}
+
+	h := make(http.Header)
+	for _, k := range relayedHeaders {
+		if v := header.Get(k); v != "" {
+			h.Set(k, v)
+		}
+	}
+	for _, r := range s.relays {
+		go func(r Relay) {
+			ctx, cancel := context.WithTimeout(context.Background(), relayTimeout)
+			defer cancel()
+			if err := r.Publish(ctx, h, body); err != nil {
+				logrus.Warnf("could not relay %s webhook: %v", h.Get("X-GitHub-Event"), err)
+			}
+		}(r)
+	}
+}
+
+// URLRelay posts the webhooks as received from GitHub to a list of URLs.
+// The original signature headers are kept, so receivers sharing the
+// webhook secret can validate them.
+type URLRelay struct {
+	URLs   []string
+	Client *http.Client
+}
+
+// Publish implements the Relay interface, failing if any of the URLs failed.
+func (r URLRelay) Publish(ctx context.Context, header http.Header, body []byte) error {
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	var failed error
+	for _, url := range r.URLs {
+		req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+		if err != nil {
+			return errors.Wrapf(err, "could not create request for %s", url)
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+
+		res, err := client.Do(req.WithContext(ctx))
+		if err != nil {
+			failed = errors.Wrapf(err, "could not post to %s", url)
+			continue
+		}
+		res.Body.Close()
+		if res.StatusCode >= 300 {
+			failed = errors.Errorf("%s responded %s", url, res.Status)
+		}
+	}
+	return failed
+}
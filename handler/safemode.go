@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+// SafeMode is the state of the crash loop detection, shared by every instance using the same store.
+type SafeMode struct {
+	// Active is set while mutations are paused, until an operator clears it.
+	Active bool      `json:"active"`
+	Since  time.Time `json:"since,omitempty"`
+	// Starts are the recent starts of the bot that didn't run long enough to be considered stable.
+	Starts []time.Time `json:"starts,omitempty"`
+}
+
+var safeModeKey = storage.Key("safemode", "state")
+
+func getSafeMode(ctx context.Context, store storage.Store) (SafeMode, error) {
+	var sm SafeMode
+	err := store.Get(ctx, safeModeKey, &sm)
+	if err != nil && err != storage.ErrNotFound {
+		return sm, errors.Wrap(err, "could not fetch safe mode")
+	}
+	return sm, nil
+}
+
+// RecordStart records a start of the bot, switching to safe mode if it already
+// started the given number of times within the window without becoming stable.
+func RecordStart(ctx context.Context, store storage.Store, restarts int, window time.Duration) (SafeMode, error) {
+	sm, err := getSafeMode(ctx, store)
+	if err != nil {
+		return sm, err
+	}
+
+	now := time.Now()
+	var starts []time.Time
+	for _, t := range sm.Starts {
+		if now.Sub(t) < window {
+			starts = append(starts, t)
+		}
+	}
+	sm.Starts = append(starts, now)
+	if !sm.Active && len(sm.Starts) > restarts {
+		logrus.Warnf("started %d times in %v, switching to safe mode: GitHub mutations are paused until it's cleared",
+			len(sm.Starts), window)
+		sm.Active, sm.Since = true, now
+	}
+	return sm, errors.Wrap(store.Put(ctx, safeModeKey, sm), "could not record start")
+}
+
+// RecordStable records that the bot has been running long enough not to be in a crash loop.
+func RecordStable(ctx context.Context, store storage.Store) error {
+	sm, err := getSafeMode(ctx, store)
+	if err != nil {
+		return err
+	}
+	sm.Starts = nil
+	return errors.Wrap(store.Put(ctx, safeModeKey, sm), "could not record stable run")
+}
+
+// queuedWebhook is a webhook received in safe mode, processed once it's cleared.
+type queuedWebhook struct {
+	AppID    int    `json:"app_id"`
+	Event    string `json:"event"`
+	Delivery string `json:"delivery"`
+	Body     []byte `json:"body"`
+}
+
+// inSafeMode checks whether mutations are paused.
+func (s *server) inSafeMode(ctx context.Context) (bool, error) {
+	sm, err := getSafeMode(ctx, s.store)
+	return sm.Active, err
+}
+
+// pausedInSafeMode answers the admin requests changing GitHub or the state of
+// the bot with 503 Service Unavailable while in safe mode, returning whether
+// it did.
+func (s *server) pausedInSafeMode(w http.ResponseWriter, r *http.Request) bool {
+	active, err := s.inSafeMode(r.Context())
+	if err != nil {
+		logrus.Errorf("could not check safe mode: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return true
+	}
+	if active {
+		http.Error(w, "safe mode is active, clear it first", http.StatusServiceUnavailable)
+	}
+	return active
+}
+
+// queueInSafeMode keeps the webhook for later if in safe mode, returning whether it did.
+func (s *server) queueInSafeMode(ctx context.Context, header http.Header, body []byte) (bool, error) {
+	if active, err := s.inSafeMode(ctx); err != nil || !active {
+		return false, err
+	}
+	wh := queuedWebhook{AppID: s.appID, Event: header.Get("X-GitHub-Event"), Delivery: header.Get("X-GitHub-Delivery"), Body: body}
+	key := storage.Key("safemode", "queue", fmt.Sprintf("%019d", time.Now().UnixNano()))
+	logrus.Infof("safe mode, queueing %s webhook %s", wh.Event, wh.Delivery)
+	return true, errors.Wrap(s.store.Put(ctx, key, wh), "could not queue webhook")
+}
+
+// replayQueue processes the webhooks of the app queued during safe mode, in order.
+func (s *server) replayQueue(ctx context.Context) (int, error) {
+	keys, err := s.store.List(ctx, storage.Key("safemode", "queue")+"/")
+	if err != nil {
+		return 0, errors.Wrap(err, "could not list queued webhooks")
+	}
+
+	n := 0
+	for _, key := range keys {
+		var wh queuedWebhook
+		if err := s.store.Get(ctx, key, &wh); err != nil {
+			return n, errors.Wrapf(err, "could not fetch queued webhook %s", key)
+		}
+		if wh.AppID != s.appID {
+			continue
+		}
+		header := http.Header{}
+		header.Set("X-GitHub-Event", wh.Event)
+		header.Set("X-GitHub-Delivery", wh.Delivery)
+		if code := s.process(ctx, header, wh.Body); code != http.StatusOK {
+			logrus.Errorf("could not process queued webhook %s: %s", wh.Delivery, http.StatusText(code))
+		}
+		if err := s.store.Delete(ctx, key); err != nil {
+			return n, errors.Wrapf(err, "could not delete queued webhook %s", key)
+		}
+		n++
+	}
+	return n, nil
+}
+
+func (s *server) safeModeHandler(w http.ResponseWriter, r *http.Request) {
+	sm, err := getSafeMode(r.Context(), s.store)
+	if err != nil {
+		logrus.Errorf("could not fetch safe mode: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, sm)
+}
+
+// clearSafeModeHandler leaves safe mode and processes the webhooks queued meanwhile.
+func (s *server) clearSafeModeHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if err := s.store.Put(ctx, safeModeKey, SafeMode{}); err != nil {
+		logrus.Errorf("could not clear safe mode: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	logrus.Infof("safe mode cleared, GitHub mutations are resumed")
+
+	n, err := s.replayQueue(ctx)
+	logrus.Infof("processed %d webhooks queued during safe mode", n)
+	if err != nil {
+		logrus.Errorf("could not process queued webhooks: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
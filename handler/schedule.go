@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/reminder"
+)
+
+// fireReminders sends all of the scheduled reminders that are due.
+func (s *server) fireReminders(ctx context.Context) error {
+	if active, err := s.inSafeMode(ctx); err != nil || active {
+		return err
+	}
+
+	due, err := reminder.DueReminders(ctx, s.store, s.appID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, r := range due {
+		client, err := reminder.NewInstallationClient(s.appID, r.InstallationID, s.key, s.transport, s.opts...)
+		if err == nil {
+			err = client.FireReminder(ctx, r)
+		}
+		if err != nil {
+			logrus.Errorf("could not send reminder on %s/%s#%d: %v", r.Owner, r.Repo, r.Number, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return errors.Errorf("%d out of %d reminders failed", failed, len(due))
+	}
+	return nil
+}
+
+func (c *composite) fireReminders(ctx context.Context) error {
+	failed := 0
+	for _, s := range c.all {
+		if err := s.fireReminders(ctx); err != nil {
+			logrus.Errorf("could not send reminders of app %d: %v", s.appID, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return errors.Errorf("%d out of %d endpoints failed", failed, len(c.all))
+	}
+	return nil
+}
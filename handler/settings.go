@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+
+	"github.com/src-d/github-reminder/reminder"
+)
+
+// settingsHandler exports the settings of an installation as YAML on GET,
+// and imports them on PUT.
+func (s *server) settingsHandler(w http.ResponseWriter, r *http.Request) {
+	inst, err := strconv.Atoi(mux.Vars(r)["installation"])
+	if err != nil {
+		http.Error(w, "bad installation id", http.StatusBadRequest)
+		return
+	}
+	if r.Method == "PUT" && s.pausedInSafeMode(w, r) {
+		return
+	}
+
+	client, err := reminder.NewInstallationClient(s.appID, inst, s.key, s.transport, s.opts...)
+	if err != nil {
+		logrus.Errorf("could not create authenticated client: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if r.Method == "PUT" {
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "could not read settings", http.StatusBadRequest)
+			return
+		}
+		var settings reminder.Settings
+		err = yaml.UnmarshalStrict(data, &settings)
+		if err == nil {
+			err = settings.Validate()
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := client.ImportSettings(r.Context(), settings); err != nil {
+			logrus.Errorf("could not import settings: %v", err)
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			return
+		}
+		logrus.Infof("imported settings into installation %d", inst)
+		return
+	}
+
+	settings, err := client.ExportSettings(r.Context())
+	if err != nil {
+		logrus.Errorf("could not export settings: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	data, err := yaml.Marshal(settings)
+	if err != nil {
+		logrus.Errorf("could not encode settings: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-yaml")
+	w.Write(data)
+}
@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// errorWindow is the period of time considered when computing the error rate.
+const errorWindow = time.Hour
+
+// status keeps track of the health indicators shown in the public status page.
+type status struct {
+	queued int64 // accessed atomically
+
+	mu        sync.Mutex
+	queues    []depther
+	lastCron  time.Time
+	rate      int
+	rateReset time.Time
+	outcomes  []outcome
+}
+
+type outcome struct {
+	at     time.Time
+	failed bool
+}
+
+func newStatus() *status { return &status{rate: -1} }
+
+// watch adds the jobs of q not done yet to the depth of the queue, if it
+// reports them.
+func (s *status) watch(q Queue) {
+	d, ok := q.(depther)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, w := range s.queues {
+		if w == d {
+			return
+		}
+	}
+	s.queues = append(s.queues, d)
+}
+
+// cronSucceeded records the time of the last successful cron run.
+func (s *status) cronSucceeded(t time.Time) {
+	s.mu.Lock()
+	s.lastCron = t
+	s.mu.Unlock()
+}
+
+// record adds the outcome of a request, dropping those older than errorWindow.
+func (s *status) record(failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	i := 0
+	for i < len(s.outcomes) && now.Sub(s.outcomes[i].at) > errorWindow {
+		i++
+	}
+	s.outcomes = append(s.outcomes[i:], outcome{now, failed})
+}
+
+// track wraps h so every request it serves is counted towards the error rate.
+func (s *status) track(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&s.queued, 1)
+		defer atomic.AddInt64(&s.queued, -1)
+
+		sw := &statusWriter{ResponseWriter: w, code: http.StatusOK}
+		h(sw, r)
+		s.record(sw.code >= http.StatusInternalServerError)
+	}
+}
+
+// transport wraps t to keep track of the GitHub API rate limit headers.
+func (s *status) transport(t http.RoundTripper) http.RoundTripper {
+	return &rateTransport{t, s}
+}
+
+type rateTransport struct {
+	http.RoundTripper
+	status *status
+}
+
+func (t *rateTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.RoundTripper.RoundTrip(req)
+	if err != nil {
+		return res, err
+	}
+
+	remaining, err := strconv.Atoi(res.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return res, nil
+	}
+	t.status.mu.Lock()
+	t.status.rate = remaining
+	if reset, err := strconv.ParseInt(res.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		t.status.rateReset = time.Unix(reset, 0)
+	}
+	t.status.mu.Unlock()
+	return res, nil
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	code int
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	w.code = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// statusReport is the information displayed in the status page.
+type statusReport struct {
+	LastCron      *time.Time `json:"last_successful_cron"`
+	QueueDepth    int64      `json:"queue_depth"`
+	RateRemaining *int       `json:"github_rate_remaining"`
+	RateReset     *time.Time `json:"github_rate_reset,omitempty"`
+	Requests      int        `json:"recent_requests"`
+	ErrorRate     float64    `json:"recent_error_rate"`
+}
+
+func (s *status) report() statusReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rep := statusReport{QueueDepth: atomic.LoadInt64(&s.queued)}
+	for _, q := range s.queues {
+		rep.QueueDepth += int64(q.Depth())
+	}
+	if !s.lastCron.IsZero() {
+		t := s.lastCron
+		rep.LastCron = &t
+	}
+	if s.rate >= 0 {
+		rate, reset := s.rate, s.rateReset
+		rep.RateRemaining = &rate
+		rep.RateReset = &reset
+	}
+
+	now := time.Now()
+	failed := 0
+	for _, o := range s.outcomes {
+		if now.Sub(o.at) > errorWindow {
+			continue
+		}
+		rep.Requests++
+		if o.failed {
+			failed++
+		}
+	}
+	if rep.Requests > 0 {
+		rep.ErrorRate = float64(failed) / float64(rep.Requests)
+	}
+	return rep
+}
+
+var statusPage = template.Must(template.New("status").Funcs(template.FuncMap{
+	"percent": func(f float64) float64 { return f * 100 },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head><title>github-reminder status</title></head>
+<body>
+<h1>github-reminder status</h1>
+<table>
+<tr><td>Last successful cron</td><td>{{with .LastCron}}{{.Format "2006-01-02 15:04:05 MST"}}{{else}}never{{end}}</td></tr>
+<tr><td>Queue depth</td><td>{{.QueueDepth}}</td></tr>
+<tr><td>GitHub rate remaining</td><td>{{with .RateRemaining}}{{.}}{{else}}unknown{{end}}</td></tr>
+<tr><td>Error rate (last hour)</td><td>{{printf "%.1f" (percent .ErrorRate)}}% of {{.Requests}} requests</td></tr>
+</table>
+</body>
+</html>
+`))
+
+func (s *status) handler(w http.ResponseWriter, r *http.Request) {
+	rep := s.report()
+
+	if r.URL.Query().Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json") {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(rep); err != nil {
+			logrus.Warnf("could not encode status: %v", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := statusPage.Execute(w, rep); err != nil {
+		logrus.Warnf("could not render status page: %v", err)
+	}
+}
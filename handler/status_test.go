@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/github"
+
+	"github.com/src-d/github-reminder/handler/handlertest"
+)
+
+func TestStatusReport(t *testing.T) {
+	s := newStatus()
+	rep := s.report()
+	if rep.LastCron != nil || rep.RateRemaining != nil || rep.RateReset != nil || rep.Requests != 0 || rep.ErrorRate != 0 {
+		t.Errorf("expected an empty report; got %+v", rep)
+	}
+
+	cron := time.Date(2018, 8, 1, 12, 0, 0, 0, time.UTC)
+	s.cronSucceeded(cron)
+	s.record(false)
+	s.record(true)
+	s.record(false)
+	s.record(false)
+	rep = s.report()
+	if rep.LastCron == nil || !rep.LastCron.Equal(cron) {
+		t.Errorf("expected the last cron at %v; got %v", cron, rep.LastCron)
+	}
+	if rep.Requests != 4 || rep.ErrorRate != 0.25 {
+		t.Errorf("expected 4 requests with a 25%% error rate; got %d with %v", rep.Requests, rep.ErrorRate)
+	}
+}
+
+func TestStatusErrorWindow(t *testing.T) {
+	s := newStatus()
+	old := time.Now().Add(-errorWindow - time.Minute)
+	s.outcomes = []outcome{{old, true}, {old, true}, {time.Now(), false}}
+	if rep := s.report(); rep.Requests != 1 || rep.ErrorRate != 0 {
+		t.Errorf("expected the outcomes older than the window to be ignored; got %d requests, %v", rep.Requests, rep.ErrorRate)
+	}
+
+	s.record(true)
+	if len(s.outcomes) != 2 {
+		t.Errorf("expected the old outcomes to be dropped; got %d", len(s.outcomes))
+	}
+	if rep := s.report(); rep.Requests != 2 || rep.ErrorRate != 0.5 {
+		t.Errorf("expected 2 requests with a 50%% error rate; got %d with %v", rep.Requests, rep.ErrorRate)
+	}
+}
+
+// roundTripperFunc allows using ordinary functions as an http.RoundTripper.
+type roundTripperFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestStatusRateLimit(t *testing.T) {
+	s := newStatus()
+	header := http.Header{}
+	rt := s.transport(roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Header: header}, nil
+	}))
+	req := httptest.NewRequest("GET", "https://api.github.com/", nil)
+
+	rt.RoundTrip(req)
+	if rep := s.report(); rep.RateRemaining != nil {
+		t.Errorf("expected an unknown rate limit without headers; got %d", *rep.RateRemaining)
+	}
+
+	header.Set("X-RateLimit-Remaining", "4321")
+	header.Set("X-RateLimit-Reset", "1533128400")
+	rt.RoundTrip(req)
+	rep := s.report()
+	if rep.RateRemaining == nil || *rep.RateRemaining != 4321 {
+		t.Fatalf("expected 4321 requests remaining; got %v", rep.RateRemaining)
+	}
+	if reset := time.Unix(1533128400, 0); rep.RateReset == nil || !rep.RateReset.Equal(reset) {
+		t.Errorf("expected the rate limit to reset at %v; got %v", reset, rep.RateReset)
+	}
+
+	header.Set("X-RateLimit-Remaining", "not a number")
+	rt.RoundTrip(req)
+	if rep := s.report(); *rep.RateRemaining != 4321 {
+		t.Errorf("expected malformed headers to be ignored; got %d", *rep.RateRemaining)
+	}
+}
+
+func TestStatusHooks(t *testing.T) {
+	secret := []byte("s3cr3t")
+	h, err := New(42, []byte("not a key"), secret, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	hook := func(p handlertest.Payload, expected int) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, p.Request("/hook", secret))
+		if w.Code != expected {
+			t.Fatalf("expected status %d for %s; got %d", expected, p.Event, w.Code)
+		}
+	}
+
+	deleted, _ := json.Marshal(&github.InstallationEvent{
+		Action:       github.String("deleted"),
+		Installation: &github.Installation{ID: github.Int64(43)},
+	})
+	hook(handlertest.Payload{Event: "installation", Body: deleted}, http.StatusOK)
+	// the key is not valid, so it fails as GitHub couldn't be reached.
+	hook(handlertest.Issues(handlertest.Repo{Installation: 43, Owner: "foo", Name: "bar"}, 1, "opened"), http.StatusInternalServerError)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/status?format=json", nil))
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("expected a JSON report; got %s", ct)
+	}
+	var rep map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &rep); err != nil {
+		t.Fatalf("could not decode status: %v", err)
+	}
+	expected := map[string]interface{}{
+		"last_successful_cron":  nil,
+		"queue_depth":           0.0,
+		"github_rate_remaining": nil,
+		"recent_requests":       2.0,
+		"recent_error_rate":     0.5,
+	}
+	if len(rep) != len(expected) {
+		t.Errorf("expected the fields %v; got %v", expected, rep)
+	}
+	for k, v := range expected {
+		if got, ok := rep[k]; !ok || got != v {
+			t.Errorf("expected %s to be %v; got %v", k, v, got)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/status", nil))
+	if body := w.Body.String(); !strings.Contains(body, "50.0% of 2 requests") {
+		t.Errorf("expected the error rate in the status page; got %q", body)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/src-d/github-reminder/reminder"
+)
+
+// runImport comments the deadlines listed in a CSV file on their issues,
+// authenticating with the token in GITHUB_TOKEN.
+func runImport(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	baseURL := flags.String("base-url", "", "GitHub API URL, for GitHub Enterprise Server")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: github-reminder import [-base-url url] deadlines.csv")
+	}
+
+	f, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return errors.Wrap(err, "could not open deadlines")
+	}
+	defer f.Close()
+	ds, err := reminder.ReadDeadlinesCSV(f)
+	if err != nil {
+		return err
+	}
+
+	var opts []reminder.Option
+	if *baseURL != "" {
+		opts = append(opts, reminder.WithBaseURL(*baseURL))
+	}
+	client, err := reminder.NewTokenClient(context.Background(), os.Getenv("GITHUB_TOKEN"), nil, opts...)
+	if err != nil {
+		return errors.Wrap(err, "could not create client, is GITHUB_TOKEN set?")
+	}
+
+	n, err := client.ImportDeadlines(context.Background(), ds, reminder.ImportComment)
+	fmt.Fprintf(out, "imported %d of %d deadlines\n", n, len(ds))
+	return err
+}
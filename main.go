@@ -1,39 +1,587 @@
 package main
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
-	"net/http"
+	"io/ioutil"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
+	_ "github.com/lib/pq"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
+	"github.com/src-d/github-reminder/bot"
+	"github.com/src-d/github-reminder/export"
 	"github.com/src-d/github-reminder/handler"
+	"github.com/src-d/github-reminder/notify"
+	"github.com/src-d/github-reminder/reminder"
+	"github.com/src-d/github-reminder/storage"
 )
 
+// envPrefix is the prefix of the environment variables configuring the bot.
+const envPrefix = "github_reminder"
+
+// configuration is read from the environment. Fields tagged as secret are
+// kept in a Kubernetes Secret by the generated manifests.
+type configuration struct {
+	Address        string `default:":8080" desc:"address where the server will listen to"`
+	AppID          int    `split_words:"true" desc:"GitHub application id"`
+	PrivateKey     string `split_words:"true" secret:"true" desc:"contents of the GitHub application private key"`
+	PrivateKeyFile string `split_words:"true" desc:"file with the GitHub application private key, replaces private key"`
+	Secret         string `secret:"true" desc:"GitHub application's secret value"`
+	Verbose        bool   `desc:"log debugging information"`
+
+	CronInterval time.Duration `split_words:"true" desc:"time between scheduled updates of all installations, 0 to rely on calls to /cron"`
+
+	AnomalyFactor float64 `split_words:"true" desc:"alert when a scan performs this many times more changes than usual, 0 disables it"`
+	AnomalyPause  bool    `split_words:"true" desc:"discard the changes of anomalous scans instead of applying them"`
+
+	AdminToken        string `split_words:"true" secret:"true" desc:"bearer token protecting the administration endpoints, empty disables them"`
+	FeedToken         string `split_words:"true" secret:"true" desc:"secret the tokens of each iCalendar and Atom feed of the deadlines are derived from, empty disables them"`
+	ApprovalThreshold int    `split_words:"true" desc:"hold repository scans with more changes than this until approved, 0 disables it"`
+
+	QuietPeriods []string `split_words:"true" desc:"comma separated periods like 2018-12-20/2019-01-07 during which no comments are posted"`
+	QuietLabels  bool     `split_words:"true" desc:"suppress label changes during quiet periods too"`
+
+	OutOfOffice []string `split_words:"true" desc:"comma separated absences like alice:2018-08-01/2018-08-15:bob"`
+
+	SheetsCredentialsFile string `split_words:"true" desc:"Google service account credentials used to export deadlines to a spreadsheet"`
+	SheetsSpreadsheetID   string `split_words:"true" desc:"id of the Google spreadsheet where deadlines are exported"`
+
+	EmailDigestTo     []string `split_words:"true" desc:"comma separated email addresses receiving the digest of the upcoming deadlines of each installation"`
+	EmailDigestFrom   string   `split_words:"true" desc:"sender of the deadline digests"`
+	EmailDigestHour   int      `split_words:"true" default:"8" desc:"hour of the day, in UTC, when the deadline digests are sent"`
+	EmailDigestWeekly bool     `split_words:"true" desc:"send the deadline digests on Mondays only"`
+	EmailDigestDays   int      `split_words:"true" default:"7" desc:"days ahead the deadline digests look"`
+	SMTPAddr          string   `envconfig:"SMTP_ADDR" desc:"address of the SMTP server sending the deadline digests, like smtp.example.com:587"`
+	SMTPUsername      string   `envconfig:"SMTP_USERNAME" desc:"username of the SMTP server"`
+	SMTPPassword      string   `envconfig:"SMTP_PASSWORD" desc:"password of the SMTP server"`
+
+	Cadences  []string `desc:"comma separated reminder cadences by label like sev1:168h:24h"`
+	FollowUps []string `split_words:"true" desc:"comma separated escalation chain of the overdue issues, like 72h:alice,168h:org/leads"`
+
+	Backfill int `desc:"closed issues walked on each update to backfill the deadline history, 0 disables it"`
+
+	BusinessDays bool `split_words:"true" desc:"count business days instead of calendar days in the deadline labels"`
+
+	UrgencyColors bool `split_words:"true" desc:"color deadline labels green, amber or red depending on their most urgent issue"`
+	LabelGradient bool `split_words:"true" desc:"keep the colors of the deadline labels in a red to green gradient by their days"`
+
+	DigestUsers []string `split_words:"true" desc:"comma separated users notified with a daily digest instead of on every event"`
+	DigestHour  int      `split_words:"true" default:"18" desc:"hour of the day, in UTC, when the digests are sent"`
+
+	SlackWebhooks      []string `split_words:"true" desc:"comma separated Slack webhooks notified of the events, like owner/repo=https://hooks.slack.com/services/...#channel"`
+	TeamsWebhooks      []string `split_words:"true" desc:"comma separated Microsoft Teams webhooks notified of the events, like owner/repo=https://..."`
+	DiscordWebhooks    []string `split_words:"true" desc:"comma separated Discord webhooks notified of the reminder and overdue events, like owner/repo=https://discord.com/api/webhooks/..."`
+	EventWebhooks      []string `split_words:"true" desc:"comma separated URLs receiving every event as JSON, like owner/repo=https://..."`
+	EventWebhookSecret string   `split_words:"true" desc:"secret signing the events posted to the event webhooks"`
+
+	ProjectDateField string `split_words:"true" desc:"date field of GitHub projects, like Due date, read as the deadline of the issues without one"`
+	ProjectSyncField string `split_words:"true" desc:"date field of GitHub projects, like Due date, where the deadlines written in the issues are copied"`
+
+	MilestoneDeadlines bool `split_words:"true" desc:"read the due date of the milestone of the issues without a deadline as their deadline"`
+
+	ProjectBoard       string        `split_words:"true" desc:"project the issues are added to once their deadline is near, like acme/5 or classic:1234 for a classic project"`
+	ProjectBoardWithin time.Duration `split_words:"true" default:"168h" desc:"time left before their deadline when the issues are added to the project board"`
+	ProjectColumns     string        `split_words:"true" desc:"semicolon separated columns of the project board by deadline label, like none=Backlog;deadline < 7=This Week;overdue=Overdue"`
+	ProjectColumnField string        `split_words:"true" desc:"single select field of the project board whose options are its columns, Status by default"`
+
+	Language string `desc:"language dates can also be written in, es, fr, de, or pt, in the repositories that didn't choose one"`
+
+	Timezone string `desc:"timezone of the dates written without one, like Europe/Madrid or CET, UTC by default"`
+	EndOfDay bool   `split_words:"true" desc:"make deadlines written without a time of day due at 23:59 instead of the start of the day"`
+
+	DateFeedback bool `split_words:"true" desc:"reply once to the deadlines that couldn't be read, explaining the formats understood"`
+
+	LabelPrefix   string `split_words:"true" desc:"prefix of the deadline labels in the repositories that didn't choose one, deadline < by default"`
+	OverdueLabel  string `split_words:"true" desc:"label applied to the issues whose deadline has passed, like overdue, none by default"`
+	CleanupClosed bool   `split_words:"true" desc:"remove the deadline labels of the issues once they're closed"`
+	ManualLabels  bool   `split_words:"true" desc:"keep the deadline labels changed by people until the deadline of the issue changes"`
+
+	MentionAssignees bool `split_words:"true" desc:"mention the assignees of the issues in the reminders instead of the author of the reminder"`
+
+	WelcomeIssue bool `split_words:"true" desc:"open a getting started issue in every repository of the new installations"`
+
+	RequiredDeadlineLabels []string `split_words:"true" desc:"comma separated labels of the issues the bot asks a deadline for when they have none, like priority: high"`
+	NeedsDeadlineLabel     string   `split_words:"true" desc:"label applied to the issues missing a required deadline instead of commenting, like needs-deadline"`
+
+	PagerDutyRoutingKey string   `envconfig:"PAGERDUTY_ROUTING_KEY" desc:"integration key of the PagerDuty service where the critical overdue issues open incidents"`
+	PagerDutyLabels     []string `envconfig:"PAGERDUTY_LABELS" desc:"comma separated labels of the critical issues, like severity: critical"`
+	PagerDutySeverity   string   `envconfig:"PAGERDUTY_SEVERITY" default:"critical" desc:"severity of the PagerDuty incidents: critical, error, warning, or info"`
+
+	DateLayouts string `split_words:"true" desc:"semicolon separated extra layouts dates can be written in, like 02.01.2006;2 Jan 06"`
+	StrictDates bool   `split_words:"true" desc:"only read absolute and relative dates, not natural language ones like next friday"`
+
+	Grammar string `desc:"grammar version of the repositories that didn't choose one with /reminder grammar"`
+
+	FocusLabel string `split_words:"true" desc:"label applied to the issues due within the current week, from Monday to Sunday"`
+
+	MinimizeReminders bool `split_words:"true" desc:"hide the previous reminders of an issue as outdated when posting a new one"`
+
+	DeadlineChecks        bool          `split_words:"true" desc:"report the deadline of every pull request as a check run, failing once it's overdue"`
+	DeadlineStatuses      bool          `split_words:"true" desc:"set a commit status on the pull requests with a deadline, failing once it's overdue"`
+	DeadlineStatusPending time.Duration `split_words:"true" default:"72h" desc:"time left before the deadline of a pull request when its commit status turns pending"`
+
+	WeeklySummary      bool     `split_words:"true" desc:"send a weekly summary of the deadlines of every installation to the notifiers"`
+	WeeklySummaryIssue string   `split_words:"true" desc:"issue getting the weekly summaries as comments, like acme/planning#12"`
+	WeeklySummaryHour  int      `split_words:"true" default:"9" desc:"hour of Monday, in UTC, when the weekly summaries are sent"`
+	WeeklySummaryTeams []string `split_words:"true" desc:"comma separated teams of the weekly summaries, like web:acme/site+acme/api"`
+
+	DigestIssue      bool   `split_words:"true" desc:"keep a pinned issue in every repository with a table of the open issues with a deadline"`
+	DigestIssueTitle string `split_words:"true" desc:"title of the digest issues, Upcoming deadlines by default"`
+	DigestIssueOnly  bool   `split_words:"true" desc:"replace the cadence reminders with the digest issues"`
+
+	ReminderTemplate string `split_words:"true" desc:"text/template of the reminder comments, like {{mention .Assignees}}, {{.Title}} is due in {{.DaysLeft}} days"`
+	OverdueTemplate  string `split_words:"true" desc:"text/template of the comment posted once an issue is overdue, none by default"`
+	NagTemplate      string `split_words:"true" desc:"text/template of the comments of the reminder cadences"`
+
+	EndpointsFile string `split_words:"true" desc:"JSON file listing several GitHub endpoints and their app credentials, replaces app id, key and secret"`
+
+	Envelope       string `desc:"format wrapping the webhooks forwarded by a middleware: apigateway or eventbridge"`
+	EnvelopeAPIKey string `split_words:"true" secret:"true" desc:"API key EventBridge must send in the X-Api-Key header"`
+
+	RelayURLs []string `split_words:"true" desc:"comma separated URLs where every validated webhook is posted after being processed"`
+
+	WebhookWorkers   int `split_words:"true" desc:"webhooks processed at once in the background after answering them, 0 processes them before answering"`
+	WebhookQueueSize int `split_words:"true" default:"100" desc:"webhooks waiting to be processed in the background, the rest are refused"`
+	WebhookAttempts  int `split_words:"true" default:"3" desc:"times a webhook processed in the background is tried before giving up"`
+
+	RetryAttempts int           `split_words:"true" default:"3" desc:"times the calls to GitHub failing with server errors or secondary rate limits are tried, 1 never retries them"`
+	RetryBackoff  time.Duration `split_words:"true" default:"1s" desc:"wait before retrying a call to GitHub, doubled before each retry"`
+	RateLimitWait time.Duration `split_words:"true" default:"1m" desc:"longest a call to GitHub waits for a rate limit to reset, 0 fails right away"`
+
+	DatabaseURL        string        `split_words:"true" secret:"true" desc:"Postgres database where the state is stored, kept in memory if empty"`
+	DatabaseReplicaURL string        `split_words:"true" secret:"true" desc:"read replica of the Postgres database used for reads"`
+	CacheSize          int           `split_words:"true" default:"10000" desc:"number of values read from the database kept in memory"`
+	CacheTTL           time.Duration `split_words:"true" desc:"time values read from the database are kept in memory, 0 disables the cache"`
+
+	SafeModeRestarts int           `split_words:"true" desc:"start in safe mode, without changing GitHub, after this many restarts within the window, 0 disables it"`
+	SafeModeWindow   time.Duration `split_words:"true" default:"10m" desc:"time window where restarts are counted for the safe mode"`
+	SafeModeStable   time.Duration `split_words:"true" default:"5m" desc:"time running after which a start doesn't count as a restart"`
+
+	EnvFile string `split_words:"true" desc:"file with KEY=value lines overriding the environment, read again on SIGHUP"`
+}
+
 func main() {
-	var config struct {
-		Address    string `default:":8080" desc:"address where the server will listen to"`
-		AppID      int    `required:"true" split_words:"true" desc:"GitHub application id"`
-		PrivateKey string `split_words:"true" desc:"contents of the GitHub application private key"`
-		Secret     string `desc:"GitHub application's secret value"`
-		Verbose    bool
-	}
-	if err := envconfig.Process("github_reminder", &config); err != nil {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "demo":
+			runDemo()
+			return
+		case "init":
+			if err := runInit(os.Stdin, os.Stdout); err != nil {
+				logrus.Fatal(err)
+			}
+			return
+		case "import":
+			if err := runImport(os.Args[2:], os.Stdout); err != nil {
+				logrus.Fatal(err)
+			}
+			return
+		case "manifest":
+			if err := runManifest(os.Args[2:], os.Stdout); err != nil {
+				logrus.Fatal(err)
+			}
+			return
+		}
+	}
+
+	config, err := loadConfig()
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
-		envconfig.Usage("github_reminder", &config)
+		envconfig.Usage(envPrefix, &config)
 		os.Exit(1)
 	}
+	botConfig, botOpts, err := configure(config)
+	if err != nil {
+		logrus.Fatal(err)
+	}
 
-	if config.Verbose {
-		logrus.SetLevel(logrus.DebugLevel)
+	store, err := openStore(config)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	if store != nil {
+		botOpts = append(botOpts, bot.WithStore(store))
 	}
 
-	h, err := handler.New(config.AppID, []byte(config.PrivateKey), []byte(config.Secret), nil)
+	b, err := bot.New(botConfig, botOpts...)
 	if err != nil {
 		logrus.Fatal(err)
 	}
+	go reloadOnHangup(b)
 
 	logrus.Infof("github-reminder listening on %s", config.Address)
-	logrus.Fatal(http.ListenAndServe(config.Address, h))
+	logrus.Fatal(b.ListenAndServe(context.Background(), config.Address))
+}
+
+// loadConfig reads the configuration from the environment and the env file, if any.
+func loadConfig() (configuration, error) {
+	var config configuration
+	if err := envconfig.Process(envPrefix, &config); err != nil {
+		return config, err
+	}
+	if config.EnvFile != "" {
+		if err := applyEnvFile(config.EnvFile); err != nil {
+			return config, err
+		}
+		if err := envconfig.Process(envPrefix, &config); err != nil {
+			return config, err
+		}
+	}
+	if config.AppID == 0 && config.EndpointsFile == "" {
+		return config, errors.New("either GITHUB_REMINDER_APP_ID or GITHUB_REMINDER_ENDPOINTS_FILE is required")
+	}
+	return config, nil
+}
+
+// openStore opens the database configured, if any. It's not part of configure
+// since the store is kept when the configuration is reloaded.
+func openStore(config configuration) (storage.Store, error) {
+	if config.DatabaseURL == "" {
+		return nil, nil
+	}
+	ctx := context.Background()
+	db, err := sql.Open("postgres", config.DatabaseURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open database")
+	}
+
+	var opts []storage.PostgresOption
+	if config.DatabaseReplicaURL != "" {
+		replica, err := sql.Open("postgres", config.DatabaseReplicaURL)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not open database replica")
+		}
+		opts = append(opts, storage.WithReplica(replica))
+	}
+	store, err := storage.NewPostgres(ctx, db, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if config.CacheTTL > 0 {
+		store = storage.NewCache(store, config.CacheSize, config.CacheTTL)
+	}
+	return store, nil
+}
+
+// configure returns the bot configuration and options corresponding to config,
+// reading the files it references.
+func configure(config configuration) (bot.Config, []bot.Option, error) {
+	if config.Verbose {
+		logrus.SetLevel(logrus.DebugLevel)
+	} else {
+		logrus.SetLevel(logrus.InfoLevel)
+	}
+
+	var clientOpts []reminder.Option
+	if config.AnomalyFactor > 0 {
+		clientOpts = append(clientOpts, reminder.WithAnomalyDetection(reminder.AnomalyPolicy{
+			Factor: config.AnomalyFactor,
+			Pause:  config.AnomalyPause,
+		}))
+	}
+
+	if config.ApprovalThreshold > 0 {
+		clientOpts = append(clientOpts, reminder.WithApprovalThreshold(config.ApprovalThreshold))
+	}
+
+	for _, s := range config.QuietPeriods {
+		p, err := reminder.ParseQuietPeriod(s)
+		if err != nil {
+			return bot.Config{}, nil, err
+		}
+		p.Labels = config.QuietLabels
+		clientOpts = append(clientOpts, reminder.WithQuietPeriods(p))
+	}
+
+	if config.RetryAttempts < 1 {
+		return bot.Config{}, nil, errors.Errorf("bad retry attempts %d, expected at least 1", config.RetryAttempts)
+	}
+	clientOpts = append(clientOpts, reminder.WithRetry(reminder.Retry{Attempts: config.RetryAttempts, Backoff: config.RetryBackoff}))
+	clientOpts = append(clientOpts, reminder.WithRateLimitPolicy(reminder.RateLimitPolicy{MaxWait: config.RateLimitWait}))
+
+	for _, s := range config.OutOfOffice {
+		a, err := reminder.ParseAbsence(s)
+		if err != nil {
+			return bot.Config{}, nil, err
+		}
+		clientOpts = append(clientOpts, reminder.WithAbsences(a))
+	}
+
+	if config.PrivateKeyFile != "" {
+		key, err := ioutil.ReadFile(config.PrivateKeyFile)
+		if err != nil {
+			return bot.Config{}, nil, errors.Wrap(err, "could not read private key")
+		}
+		config.PrivateKey = string(key)
+	}
+
+	botConfig := bot.Config{
+		AppID:        config.AppID,
+		PrivateKey:   []byte(config.PrivateKey),
+		Secret:       []byte(config.Secret),
+		CronInterval: config.CronInterval,
+	}
+	if config.EndpointsFile != "" {
+		endpoints, err := readEndpoints(config.EndpointsFile)
+		if err != nil {
+			return bot.Config{}, nil, err
+		}
+		botConfig.Endpoints = endpoints
+	}
+
+	for _, s := range config.Cadences {
+		c, err := reminder.ParseCadence(s)
+		if err != nil {
+			return bot.Config{}, nil, err
+		}
+		clientOpts = append(clientOpts, reminder.WithCadences(c))
+	}
+	if len(config.FollowUps) > 0 {
+		fs := make([]reminder.FollowUp, len(config.FollowUps))
+		for i, s := range config.FollowUps {
+			f, err := reminder.ParseFollowUp(s)
+			if err != nil {
+				return bot.Config{}, nil, err
+			}
+			fs[i] = f
+		}
+		clientOpts = append(clientOpts, reminder.WithFollowUps(fs...))
+	}
+	if config.Backfill > 0 {
+		clientOpts = append(clientOpts, reminder.WithBackfill(config.Backfill))
+	}
+	if config.BusinessDays {
+		clientOpts = append(clientOpts, reminder.WithBusinessDays())
+	}
+	if config.UrgencyColors {
+		clientOpts = append(clientOpts, reminder.WithUrgencyColors(reminder.DefaultUrgencyColors))
+	}
+	if config.LabelGradient {
+		clientOpts = append(clientOpts, reminder.WithLabelGradient(reminder.DefaultUrgencyColors))
+	}
+	if config.Grammar != "" {
+		g, err := reminder.ParseGrammar(config.Grammar)
+		if err != nil {
+			return bot.Config{}, nil, err
+		}
+		clientOpts = append(clientOpts, reminder.WithGrammar(g))
+	}
+	if config.ProjectDateField != "" {
+		clientOpts = append(clientOpts, reminder.WithProjectDateField(config.ProjectDateField))
+	}
+	if config.ProjectSyncField != "" {
+		clientOpts = append(clientOpts, reminder.WithProjectSync(config.ProjectSyncField))
+	}
+	if config.MilestoneDeadlines {
+		clientOpts = append(clientOpts, reminder.WithMilestoneDeadlines())
+	}
+	if config.ProjectBoard != "" {
+		p, err := reminder.ParseProject(config.ProjectBoard)
+		if err != nil {
+			return bot.Config{}, nil, err
+		}
+		clientOpts = append(clientOpts, reminder.WithProjectBoard(reminder.ProjectBoard{Project: p, Within: config.ProjectBoardWithin}))
+		if config.ProjectColumns != "" {
+			cols, err := reminder.ParseBoardColumns(config.ProjectColumns)
+			if err != nil {
+				return bot.Config{}, nil, err
+			}
+			cols.Field = config.ProjectColumnField
+			clientOpts = append(clientOpts, reminder.WithBoardColumns(cols))
+		}
+	}
+	if config.Language != "" {
+		lang, err := reminder.ParseLanguage(config.Language)
+		if err != nil {
+			return bot.Config{}, nil, err
+		}
+		clientOpts = append(clientOpts, reminder.WithLanguage(lang))
+	}
+	if config.Timezone != "" {
+		loc, err := reminder.ParseTimezone(config.Timezone)
+		if err != nil {
+			return bot.Config{}, nil, err
+		}
+		clientOpts = append(clientOpts, reminder.WithTimezone(loc))
+	}
+	if config.EndOfDay {
+		clientOpts = append(clientOpts, reminder.WithEndOfDay())
+	}
+	if config.DateFeedback {
+		clientOpts = append(clientOpts, reminder.WithDateFeedback())
+	}
+	if config.LabelPrefix != "" {
+		clientOpts = append(clientOpts, reminder.WithLabelPrefix(config.LabelPrefix))
+	}
+	if config.OverdueLabel != "" {
+		clientOpts = append(clientOpts, reminder.WithOverdueLabel(config.OverdueLabel))
+	}
+	if config.CleanupClosed {
+		clientOpts = append(clientOpts, reminder.WithClosedIssueCleanup())
+	}
+	if config.ManualLabels {
+		clientOpts = append(clientOpts, reminder.WithManualLabels())
+	}
+	if config.MentionAssignees {
+		clientOpts = append(clientOpts, reminder.WithAssigneeMentions())
+	}
+	if config.WelcomeIssue {
+		clientOpts = append(clientOpts, reminder.WithWelcomeIssue())
+	}
+	if t := (reminder.Templates{Reminder: config.ReminderTemplate, Overdue: config.OverdueTemplate, Nag: config.NagTemplate}); t != (reminder.Templates{}) {
+		if err := reminder.ParseTemplates(t); err != nil {
+			return bot.Config{}, nil, errors.Wrap(err, "invalid comment templates")
+		}
+		clientOpts = append(clientOpts, reminder.WithTemplates(t))
+	}
+	if len(config.RequiredDeadlineLabels) > 0 {
+		clientOpts = append(clientOpts, reminder.WithRequiredDeadline(reminder.RequiredDeadline{
+			Labels: config.RequiredDeadlineLabels,
+			Label:  config.NeedsDeadlineLabel,
+		}))
+	}
+	if config.PagerDutyRoutingKey != "" && len(config.PagerDutyLabels) > 0 {
+		switch config.PagerDutySeverity {
+		case "critical", "error", "warning", "info":
+		default:
+			return bot.Config{}, nil, errors.Errorf("unknown PagerDuty severity %q", config.PagerDutySeverity)
+		}
+		clientOpts = append(clientOpts, reminder.WithEscalation(reminder.Escalation{
+			Labels: config.PagerDutyLabels,
+			Pager:  &notify.PagerDuty{RoutingKey: config.PagerDutyRoutingKey, Severity: config.PagerDutySeverity},
+		}))
+	}
+	if config.DateLayouts != "" {
+		clientOpts = append(clientOpts, reminder.WithDateLayouts(strings.Split(config.DateLayouts, ";")...))
+	}
+	if config.StrictDates {
+		clientOpts = append(clientOpts, reminder.WithDateParsers(reminder.RelativeDates))
+	}
+	if config.FocusLabel != "" {
+		clientOpts = append(clientOpts, reminder.WithFocusLabel(config.FocusLabel))
+	}
+	if config.MinimizeReminders {
+		clientOpts = append(clientOpts, reminder.WithMinimizedReminders())
+	}
+	if config.DeadlineChecks {
+		clientOpts = append(clientOpts, reminder.WithDeadlineChecks())
+	}
+	if config.DeadlineStatuses {
+		clientOpts = append(clientOpts, reminder.WithDeadlineStatuses(config.DeadlineStatusPending))
+	}
+	if config.WeeklySummary || config.WeeklySummaryIssue != "" {
+		s := reminder.WeeklySummary{Issue: config.WeeklySummaryIssue, Hour: config.WeeklySummaryHour}
+		if s.Issue != "" {
+			if _, _, _, err := reminder.ParseIssueRef(s.Issue); err != nil {
+				return bot.Config{}, nil, err
+			}
+		}
+		for _, t := range config.WeeklySummaryTeams {
+			team, err := reminder.ParseTeam(t)
+			if err != nil {
+				return bot.Config{}, nil, err
+			}
+			s.Teams = append(s.Teams, team)
+		}
+		clientOpts = append(clientOpts, reminder.WithWeeklySummary(s))
+	}
+	if config.DigestIssue {
+		clientOpts = append(clientOpts, reminder.WithDigestIssue(reminder.DigestIssue{
+			Title: config.DigestIssueTitle,
+			Only:  config.DigestIssueOnly,
+		}))
+	}
+
+	handlerOpts := []handler.Option{handler.WithAdminToken(config.AdminToken), handler.WithFeedToken(config.FeedToken)}
+	switch config.Envelope {
+	case "":
+	case "apigateway":
+		handlerOpts = append(handlerOpts, handler.WithEnvelope(handler.HTTPEnvelope{}))
+	case "eventbridge":
+		handlerOpts = append(handlerOpts, handler.WithEnvelope(handler.EventBridgeEnvelope{APIKey: config.EnvelopeAPIKey}))
+	default:
+		return bot.Config{}, nil, errors.Errorf("unknown envelope %q", config.Envelope)
+	}
+	if len(config.RelayURLs) > 0 {
+		handlerOpts = append(handlerOpts, handler.WithRelay(handler.URLRelay{URLs: config.RelayURLs}))
+	}
+	if config.WebhookWorkers > 0 {
+		q := handler.NewMemoryQueue(config.WebhookWorkers, config.WebhookQueueSize, config.WebhookAttempts)
+		handlerOpts = append(handlerOpts, handler.WithQueue(q))
+	}
+	if config.SheetsCredentialsFile != "" {
+		credentials, err := ioutil.ReadFile(config.SheetsCredentialsFile)
+		if err != nil {
+			return bot.Config{}, nil, errors.Wrap(err, "could not read sheets credentials")
+		}
+		sheets, err := export.NewSheets(credentials, config.SheetsSpreadsheetID, nil)
+		if err != nil {
+			return bot.Config{}, nil, err
+		}
+		handlerOpts = append(handlerOpts, handler.WithExporter(sheets))
+	}
+	if len(config.EmailDigestTo) > 0 {
+		digest, err := export.NewEmailDigest(config.SMTPAddr, config.EmailDigestFrom, config.EmailDigestTo...)
+		if err != nil {
+			return bot.Config{}, nil, err
+		}
+		digest.Username, digest.Password = config.SMTPUsername, config.SMTPPassword
+		digest.Hour, digest.Weekly, digest.Days = config.EmailDigestHour, config.EmailDigestWeekly, config.EmailDigestDays
+		handlerOpts = append(handlerOpts, handler.WithEmailDigest(digest))
+	}
+
+	notifiers := []notify.Notifier{notify.Log}
+	if len(config.SlackWebhooks) > 0 {
+		slack, err := notify.ParseRoutes(config.SlackWebhooks, func(webhook string) (notify.Notifier, error) {
+			return notify.NewSlack(webhook)
+		})
+		if err != nil {
+			return bot.Config{}, nil, err
+		}
+		notifiers = append(notifiers, slack)
+	}
+	if len(config.TeamsWebhooks) > 0 {
+		teams, err := notify.ParseRoutes(config.TeamsWebhooks, func(webhook string) (notify.Notifier, error) {
+			return notify.NewTeams(webhook)
+		})
+		if err != nil {
+			return bot.Config{}, nil, err
+		}
+		notifiers = append(notifiers, teams)
+	}
+	if len(config.DiscordWebhooks) > 0 {
+		discord, err := notify.ParseRoutes(config.DiscordWebhooks, func(webhook string) (notify.Notifier, error) {
+			return notify.NewDiscord(webhook)
+		})
+		if err != nil {
+			return bot.Config{}, nil, err
+		}
+		notifiers = append(notifiers, discord)
+	}
+	var deadlines []notify.Notifier
+	if len(config.EventWebhooks) > 0 {
+		webhooks, err := notify.ParseRoutes(config.EventWebhooks, func(url string) (notify.Notifier, error) {
+			return notify.NewWebhook(url, []byte(config.EventWebhookSecret))
+		})
+		if err != nil {
+			return bot.Config{}, nil, err
+		}
+		notifiers = append(notifiers, webhooks)
+		deadlines = append(deadlines, webhooks)
+	}
+
+	return botConfig, []bot.Option{
+		bot.WithNotifiers(notifiers...),
+		bot.WithDeadlineNotifiers(deadlines...),
+		bot.WithDigests(config.DigestHour, config.DigestUsers...),
+		bot.WithSafeMode(config.SafeModeRestarts, config.SafeModeWindow, config.SafeModeStable),
+		bot.WithClientOptions(clientOpts...),
+		bot.WithHandlerOptions(handlerOpts...),
+	}, nil
 }
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/pkg/errors"
+)
+
+// envVar is a configuration variable as rendered in the manifests.
+type envVar struct {
+	Key         string
+	Value       string
+	Description string
+	Secret      bool
+	Set         bool
+}
+
+// varsFormat lists the configuration variables known by envconfig, one per line.
+const varsFormat = "{{range .}}{{usage_key .}}\t{{.Tags.Get \"secret\"}}\t{{usage_default .}}\t{{usage_description .}}\n{{end}}"
+
+// configVars returns all of the configuration variables with their values in the
+// environment, or their defaults, so the manifests always match the configuration.
+func configVars() ([]envVar, error) {
+	var buf bytes.Buffer
+	if err := envconfig.Usagef(envPrefix, &configuration{}, &buf, varsFormat); err != nil {
+		return nil, errors.Wrap(err, "could not list configuration variables")
+	}
+
+	var vars []envVar
+	s := bufio.NewScanner(&buf)
+	for s.Scan() {
+		parts := strings.SplitN(s.Text(), "\t", 4)
+		if len(parts) != 4 {
+			continue
+		}
+		v := envVar{Key: parts[0], Secret: parts[1] == "true", Value: parts[2], Description: parts[3]}
+		if value, ok := os.LookupEnv(v.Key); ok {
+			v.Value, v.Set = value, true
+		}
+		vars = append(vars, v)
+	}
+	return vars, s.Err()
+}
+
+// manifestData is what the manifest templates are rendered with.
+type manifestData struct {
+	Name     string
+	Image    string
+	Port     string
+	Schedule string
+	Vars     []envVar
+}
+
+func (d manifestData) Secrets() []envVar { return d.filter(true) }
+func (d manifestData) Env() []envVar     { return d.filter(false) }
+
+func (d manifestData) filter(secret bool) []envVar {
+	var res []envVar
+	for _, v := range d.Vars {
+		if v.Secret == secret {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+var manifestFuncs = template.FuncMap{
+	"quote": func(s string) string { return fmt.Sprintf("%q", s) },
+}
+
+var kubernetesTemplate = template.Must(template.New("kubernetes").Funcs(manifestFuncs).Parse(`apiVersion: v1
+kind: Secret
+metadata:
+  name: {{.Name}}
+type: Opaque
+stringData:
+{{- range .Secrets}}
+  # {{.Description}}
+  {{if not .Set}}# {{end}}{{.Key}}: {{quote .Value}}
+{{- end}}
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{.Name}}
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: {{.Name}}
+  template:
+    metadata:
+      labels:
+        app: {{.Name}}
+    spec:
+      containers:
+      - name: {{.Name}}
+        image: {{.Image}}
+        ports:
+        - containerPort: {{.Port}}
+        envFrom:
+        - secretRef:
+            name: {{.Name}}
+        env:
+{{- range .Env}}
+        # {{.Description}}
+        {{if not .Set}}# {{end}}- {name: {{.Key}}, value: {{quote .Value}}}
+{{- end}}
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: {{.Name}}
+spec:
+  selector:
+    app: {{.Name}}
+  ports:
+  - port: 80
+    targetPort: {{.Port}}
+{{- with .Schedule}}
+---
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: {{$.Name}}-cron
+spec:
+  schedule: {{quote .}}
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          restartPolicy: OnFailure
+          containers:
+          - name: cron
+            image: curlimages/curl
+            args: ["-fsS", "http://{{$.Name}}/cron"]
+{{- end}}
+`))
+
+var helmTemplate = template.Must(template.New("helm").Funcs(manifestFuncs).Parse(`image: {{.Image}}
+port: {{.Port}}
+{{- with .Schedule}}
+cronSchedule: {{quote .}}
+{{- end}}
+# stored in a Secret
+secretEnv:
+{{- range .Secrets}}
+  # {{.Description}}
+  {{if not .Set}}# {{end}}{{.Key}}: {{quote .Value}}
+{{- end}}
+env:
+{{- range .Env}}
+  # {{.Description}}
+  {{if not .Set}}# {{end}}{{.Key}}: {{quote .Value}}
+{{- end}}
+`))
+
+// runManifest renders the deployment manifests for the configuration in the environment.
+func runManifest(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("manifest", flag.ContinueOnError)
+	helm := flags.Bool("helm", false, "render a Helm values file instead of Kubernetes manifests")
+	name := flags.String("name", "github-reminder", "name of the Kubernetes resources")
+	image := flags.String("image", "srcd/github-reminder:latest", "container image to deploy")
+	schedule := flags.String("schedule", "@hourly", "schedule of the CronJob calling /cron, ignored if the cron interval is set")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	vars, err := configVars()
+	if err != nil {
+		return err
+	}
+
+	data := manifestData{Name: *name, Image: *image, Port: "8080", Schedule: *schedule, Vars: vars}
+	for _, v := range vars {
+		switch {
+		case v.Key == "GITHUB_REMINDER_ADDRESS":
+			if _, port, err := net.SplitHostPort(v.Value); err == nil && port != "" {
+				data.Port = port
+			}
+		case v.Key == "GITHUB_REMINDER_CRON_INTERVAL" && v.Set && v.Value != "0" && v.Value != "0s":
+			data.Schedule = ""
+		}
+	}
+
+	tmpl := kubernetesTemplate
+	if *helm {
+		tmpl = helmTemplate
+	}
+	return errors.Wrap(tmpl.Execute(out, data), "could not render manifest")
+}
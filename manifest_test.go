@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestManifest(t *testing.T) {
+	os.Setenv("GITHUB_REMINDER_SECRET", "s3cr3t")
+	os.Setenv("GITHUB_REMINDER_ADDRESS", ":9090")
+	defer os.Unsetenv("GITHUB_REMINDER_SECRET")
+	defer os.Unsetenv("GITHUB_REMINDER_ADDRESS")
+
+	var out bytes.Buffer
+	if err := runManifest(nil, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, s := range []string{
+		"  GITHUB_REMINDER_SECRET: \"s3cr3t\"\n",
+		"        - {name: GITHUB_REMINDER_ADDRESS, value: \":9090\"}\n",
+		"        # - {name: GITHUB_REMINDER_CADENCES, value: \"\"}\n",
+		"containerPort: 9090\n",
+		"kind: CronJob\n",
+	} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("expected manifest to contain %q; got:\n%s", s, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "name: GITHUB_REMINDER_SECRET") {
+		t.Errorf("expected secret to be kept out of the deployment")
+	}
+
+	os.Setenv("GITHUB_REMINDER_CRON_INTERVAL", "1h")
+	defer os.Unsetenv("GITHUB_REMINDER_CRON_INTERVAL")
+	out.Reset()
+	if err := runManifest([]string{"-helm"}, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(out.String(), "cronSchedule") {
+		t.Errorf("expected no cron schedule with a cron interval; got:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "  GITHUB_REMINDER_CRON_INTERVAL: \"1h\"\n") {
+		t.Errorf("expected cron interval in helm values; got:\n%s", out.String())
+	}
+}
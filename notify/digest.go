@@ -0,0 +1,104 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+// A Digester batches the events of the users who prefer a single daily digest
+// over being notified of every event, and passes the rest through. Digests
+// are sent as events of kind Digest once a day, at the configured hour.
+type Digester struct {
+	store storage.Store
+	next  Notifier
+	hour  int
+	users map[string]bool
+}
+
+// NewDigester returns a Digester keeping the pending events of the given users
+// in store until the hour of the day, in UTC, their digests are sent to next.
+func NewDigester(store storage.Store, next Notifier, hour int, users ...string) *Digester {
+	d := &Digester{store: store, next: next, hour: hour, users: make(map[string]bool)}
+	for _, u := range users {
+		d.users[strings.ToLower(strings.TrimPrefix(u, "@"))] = true
+	}
+	return d
+}
+
+// Notify stores the event if it's for a user receiving digests, or delivers it right away otherwise.
+func (d *Digester) Notify(ctx context.Context, e Event) error {
+	user := strings.ToLower(e.User)
+	if !d.users[user] {
+		return d.next.Notify(ctx, e)
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	key := storage.Key("digest", "pending", user, fmt.Sprintf("%019d", e.Time.UnixNano()))
+	return errors.Wrap(d.store.Put(ctx, key, e), "could not store event for digest")
+}
+
+// Flush sends the digests of the users with pending events, if it's the time of the day to do so
+// and their digest was not sent yet today.
+func (d *Digester) Flush(ctx context.Context, now time.Time) error {
+	now = now.UTC()
+	if now.Hour() < d.hour {
+		return nil
+	}
+	today := now.Format("2006-01-02")
+
+	for user := range d.users {
+		sentKey := storage.Key("digest", "sent", user)
+		var sent string
+		if err := d.store.Get(ctx, sentKey, &sent); err != nil && err != storage.ErrNotFound {
+			return errors.Wrap(err, "could not check last digest")
+		}
+		if sent == today {
+			continue
+		}
+
+		keys, err := d.store.List(ctx, storage.Key("digest", "pending", user)+"/")
+		if err != nil {
+			return errors.Wrap(err, "could not list pending events")
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		var events []Event
+		for _, key := range keys {
+			var e Event
+			if err := d.store.Get(ctx, key, &e); err != nil {
+				return errors.Wrapf(err, "could not fetch pending event %s", key)
+			}
+			events = append(events, e)
+		}
+
+		if err := d.next.Notify(ctx, digest(events[0].User, events, now)); err != nil {
+			return errors.Wrapf(err, "could not send digest to %s", user)
+		}
+		if err := d.store.Put(ctx, sentKey, today); err != nil {
+			return errors.Wrap(err, "could not record digest")
+		}
+		for _, key := range keys {
+			if err := d.store.Delete(ctx, key); err != nil {
+				return errors.Wrapf(err, "could not delete pending event %s", key)
+			}
+		}
+	}
+	return nil
+}
+
+// digest returns the event summarizing the events of a user.
+func digest(user string, events []Event, now time.Time) Event {
+	lines := []string{fmt.Sprintf("%d updates since the last digest:", len(events))}
+	for _, e := range events {
+		lines = append(lines, fmt.Sprintf("- %s/%s#%d %s: %s", e.Owner, e.Repo, e.Number, e.Title, e.Message))
+	}
+	return Event{Kind: Digest, User: user, Message: strings.Join(lines, "\n"), Time: now}
+}
@@ -0,0 +1,56 @@
+package notify
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+func TestDigester(t *testing.T) {
+	ctx := context.Background()
+	var sent []Event
+	next := NotifierFunc(func(ctx context.Context, e Event) error {
+		sent = append(sent, e)
+		return nil
+	})
+	d := NewDigester(storage.NewMemory(), next, 18, "@Alice")
+
+	morning := time.Date(2018, 8, 1, 9, 0, 0, 0, time.UTC)
+	evening := morning.Add(9 * time.Hour)
+	for i, e := range []Event{
+		{Kind: Reminder, Owner: "foo", Repo: "bar", Number: 1, User: "alice", Message: "it's reminder day!", Time: morning},
+		{Kind: Label, Owner: "foo", Repo: "bar", Number: 2, User: "alice", Message: "now labeled", Time: morning.Add(time.Hour)},
+		{Kind: Reminder, Owner: "foo", Repo: "bar", Number: 3, User: "bob", Message: "it's reminder day!", Time: morning},
+	} {
+		if err := d.Notify(ctx, e); err != nil {
+			t.Fatalf("unexpected error notifying event %d: %v", i, err)
+		}
+	}
+	if len(sent) != 1 || sent[0].User != "bob" {
+		t.Fatalf("expected only bob's event to be sent right away; got %+v", sent)
+	}
+
+	sent = nil
+	if err := d.Flush(ctx, morning.Add(2*time.Hour)); err != nil || len(sent) != 0 {
+		t.Fatalf("expected no digest before the evening; got %+v (%v)", sent, err)
+	}
+	if err := d.Flush(ctx, evening); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sent) != 1 || sent[0].Kind != Digest || sent[0].User != "alice" ||
+		!strings.Contains(sent[0].Message, "foo/bar#1") || !strings.Contains(sent[0].Message, "foo/bar#2") {
+		t.Fatalf("expected a digest for alice with both events; got %+v", sent)
+	}
+
+	sent = nil
+	d.Notify(ctx, Event{Kind: Reminder, Owner: "foo", Repo: "bar", Number: 4, User: "alice", Time: evening})
+	if err := d.Flush(ctx, evening.Add(time.Hour)); err != nil || len(sent) != 0 {
+		t.Fatalf("expected a single digest per day; got %+v (%v)", sent, err)
+	}
+	if err := d.Flush(ctx, evening.AddDate(0, 0, 1)); err != nil || len(sent) != 1 || !strings.Contains(sent[0].Message, "foo/bar#4") {
+		t.Errorf("expected the next digest the day after; got %+v (%v)", sent, err)
+	}
+}
@@ -0,0 +1,67 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Discord posts the reminder and overdue events to a Discord webhook, ignoring
+// the rest.
+type Discord struct {
+	// WebhookURL is the URL of the webhook.
+	WebhookURL string
+	// Client is used to post the events, http.DefaultClient if nil.
+	Client *http.Client
+}
+
+// NewDiscord returns a Discord notifier posting to the given webhook URL,
+// which must be one of Discord, like "https://discord.com/api/webhooks/...".
+func NewDiscord(webhook string) (*Discord, error) {
+	u, err := url.Parse(webhook)
+	if err != nil || u.Scheme != "https" || !strings.HasPrefix(u.Path, "/api/webhooks/") ||
+		(u.Host != "discord.com" && u.Host != "discordapp.com") {
+		return nil, errors.Errorf("invalid Discord webhook %q", webhook)
+	}
+	return &Discord{WebhookURL: webhook}, nil
+}
+
+// The colors of the embeds of the events in Discord.
+const (
+	discordReminderColor = 0x0366d6
+	discordOverdueColor  = 0xb60205
+)
+
+// Notify posts the event, if it's a reminder or an overdue issue, to the
+// webhook as an embed linking to the issue, with its deadline and the time left.
+func (d *Discord) Notify(ctx context.Context, e Event) error {
+	color := discordReminderColor
+	switch e.Kind {
+	case Reminder:
+	case Overdue:
+		color = discordOverdueColor
+	default:
+		return nil
+	}
+
+	embed := map[string]interface{}{
+		"title":       strings.TrimSpace(fmt.Sprintf("%s/%s#%d %s", e.Owner, e.Repo, e.Number, e.Title)),
+		"description": e.Message,
+		"color":       color,
+	}
+	if e.URL != "" {
+		embed["url"] = e.URL
+	}
+	if !e.Deadline.IsZero() {
+		embed["fields"] = []map[string]interface{}{
+			{"name": "Deadline", "value": e.Deadline.Format("2006-01-02 15:04 MST"), "inline": true},
+			{"name": "Time left", "value": e.timeLeft(), "inline": true},
+		}
+	}
+	msg := map[string]interface{}{"embeds": []interface{}{embed}}
+	return errors.Wrap(postJSON(ctx, d.Client, d.WebhookURL, msg), "could not post to Discord")
+}
@@ -0,0 +1,65 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDiscord(t *testing.T) {
+	var got []map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg struct {
+			Embeds []map[string]interface{} `json:"embeds"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			t.Errorf("could not decode message: %v", err)
+		}
+		got = append(got, msg.Embeds...)
+	}))
+	defer ts.Close()
+
+	d := &Discord{WebhookURL: ts.URL}
+	deadline := time.Now().Add(-time.Hour)
+	for _, e := range []Event{
+		{Kind: Label, Owner: "foo", Repo: "bar", Number: 1, Message: "now labeled deadline < 1"},
+		{Kind: Overdue, Owner: "foo", Repo: "bar", Number: 1, Title: "Fix it", URL: "https://github.com/foo/bar/issues/1",
+			Message: "Fix it is overdue", Deadline: deadline},
+		{Kind: Reminder, Owner: "foo", Repo: "bar", Number: 2, Message: "it's reminder day!"},
+	} {
+		if err := d.Notify(context.Background(), e); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("expected only the overdue and reminder events to be posted; got %v", got)
+	}
+	if got[0]["title"] != "foo/bar#1 Fix it" || got[0]["url"] != "https://github.com/foo/bar/issues/1" ||
+		got[0]["color"] != float64(discordOverdueColor) {
+		t.Errorf("expected the embed of the overdue issue; got %v", got[0])
+	}
+	if fields, _ := got[0]["fields"].([]interface{}); len(fields) != 2 {
+		t.Errorf("expected the deadline and the time left; got %v", got[0]["fields"])
+	}
+	if got[1]["title"] != "foo/bar#2" || got[1]["description"] != "it's reminder day!" || got[1]["fields"] != nil {
+		t.Errorf("expected the embed of the reminder; got %v", got[1])
+	}
+}
+
+func TestNewDiscord(t *testing.T) {
+	for webhook, valid := range map[string]bool{
+		"https://discord.com/api/webhooks/1/abc":    true,
+		"https://discordapp.com/api/webhooks/1/abc": true,
+		"http://discord.com/api/webhooks/1/abc":     false,
+		"https://example.com/api/webhooks/1/abc":    false,
+		"https://discord.com/channels/1":            false,
+	} {
+		if _, err := NewDiscord(webhook); (err == nil) != valid {
+			t.Errorf("expected %s to be valid: %v; got %v", webhook, valid, err)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Package notify provides the notification sinks used to let people know
+// about deadlines and reminders outside of GitHub.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// A Kind identifies the kind of an Event.
+type Kind string
+
+// The kinds of events notified by the bot.
+const (
+	// Reminder is sent when a reminder is due.
+	Reminder Kind = "reminder"
+	// Label is sent when an issue crosses a deadline label threshold.
+	Label Kind = "label"
+	// Overdue is sent when the deadline of an issue passes.
+	Overdue Kind = "overdue"
+	// Deadline is sent when the deadline of an open issue is set, changed,
+	// or cleared, in which case the event has none.
+	Deadline Kind = "deadline"
+	// Digest is sent once a day to users batching their events into a digest.
+	Digest Kind = "digest"
+	// Summary is sent once a week with the status of the deadlines of an
+	// installation, and belongs to no issue.
+	Summary Kind = "summary"
+)
+
+// An Event is something worth notifying about an issue.
+type Event struct {
+	Kind     Kind      `json:"kind"`
+	Owner    string    `json:"owner"`
+	Repo     string    `json:"repo"`
+	Number   int       `json:"number"`
+	Title    string    `json:"title"`
+	URL      string    `json:"url"`
+	User     string    `json:"user,omitempty"`
+	Label    string    `json:"label,omitempty"`
+	Deadline time.Time `json:"deadline,omitempty"`
+	Message  string    `json:"message"`
+	Time     time.Time `json:"time"`
+}
+
+// DaysLeft returns the number of whole days left until the deadline, negative if already passed.
+func (e Event) DaysLeft() int {
+	if e.Deadline.IsZero() {
+		return 0
+	}
+	return int(time.Until(e.Deadline).Hours() / 24)
+}
+
+// timeLeft describes the time left until the deadline, like "3 days left",
+// or nothing if there's no deadline.
+func (e Event) timeLeft() string {
+	if e.Deadline.IsZero() {
+		return ""
+	}
+	switch days := e.DaysLeft(); {
+	case days < -1:
+		return fmt.Sprintf("overdue by %d days", -days)
+	case time.Until(e.Deadline) < 0:
+		return "overdue"
+	case days == 0:
+		return "due in less than a day"
+	case days == 1:
+		return "1 day left"
+	default:
+		return fmt.Sprintf("%d days left", days)
+	}
+}
+
+// A Notifier delivers events to some destination.
+type Notifier interface {
+	Notify(ctx context.Context, e Event) error
+}
+
+// NotifierFunc allows using ordinary functions as a Notifier.
+type NotifierFunc func(ctx context.Context, e Event) error
+
+// Notify calls f.
+func (f NotifierFunc) Notify(ctx context.Context, e Event) error { return f(ctx, e) }
+
+// Multi delivers events to all of its notifiers, logging any errors.
+// It only fails if all of the notifiers fail.
+type Multi []Notifier
+
+// Notify delivers the event to all of the notifiers.
+func (m Multi) Notify(ctx context.Context, e Event) error {
+	var last error
+	failed := 0
+	for _, n := range m {
+		if err := n.Notify(ctx, e); err != nil {
+			logrus.Errorf("could not notify %s event for %s/%s#%d: %v", e.Kind, e.Owner, e.Repo, e.Number, err)
+			failed++
+			last = err
+		}
+	}
+	if failed > 0 && failed == len(m) {
+		return last
+	}
+	return nil
+}
+
+// Log is a Notifier writing events to the log.
+var Log Notifier = NotifierFunc(func(ctx context.Context, e Event) error {
+	logrus.Infof("%s event for %s/%s#%d: %s", e.Kind, e.Owner, e.Repo, e.Number, e.Message)
+	return nil
+})
@@ -0,0 +1,37 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestMulti(t *testing.T) {
+	var got []string
+	notifier := func(name string, err error) Notifier {
+		return NotifierFunc(func(ctx context.Context, e Event) error {
+			got = append(got, name+" "+e.Message)
+			return err
+		})
+	}
+	ctx := context.Background()
+	e := Event{Kind: Reminder, Owner: "foo", Repo: "bar", Number: 1, Message: "ship it"}
+
+	down := errors.New("down")
+	m := Multi{notifier("slack", nil), notifier("discord", down), notifier("webhook", nil)}
+	if err := m.Notify(ctx, e); err != nil {
+		t.Errorf("expected no error while some notifiers succeed; got %v", err)
+	}
+	if expected := []string{"slack ship it", "discord ship it", "webhook ship it"}; fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("expected the event to be delivered to every notifier %v; got %v", expected, got)
+	}
+
+	m = Multi{notifier("slack", errors.New("timeout")), notifier("discord", down)}
+	if err := m.Notify(ctx, e); err != down {
+		t.Errorf("expected the last error once every notifier fails; got %v", err)
+	}
+	if err := (Multi{}).Notify(ctx, e); err != nil {
+		t.Errorf("expected no error without notifiers; got %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// pagerDutyURL is the endpoint of the PagerDuty Events API v2.
+const pagerDutyURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDuty triggers and resolves incidents through the Events API v2 of
+// PagerDuty, for the service of its routing key.
+type PagerDuty struct {
+	// RoutingKey is the integration key of the service.
+	RoutingKey string
+	// Severity of the incidents, critical if empty.
+	Severity string
+	// URL of the API, the one of PagerDuty if empty.
+	URL string
+	// Client is used to call the API, http.DefaultClient if nil.
+	Client *http.Client
+}
+
+// Trigger opens an incident for the event, identified by key so it's only
+// opened once and can be resolved later.
+func (p *PagerDuty) Trigger(ctx context.Context, key string, e Event) error {
+	severity := p.Severity
+	if severity == "" {
+		severity = "critical"
+	}
+	details := map[string]interface{}{"issue": e.URL}
+	if !e.Deadline.IsZero() {
+		details["deadline"] = e.Deadline
+		details["time left"] = e.timeLeft()
+	}
+	msg := map[string]interface{}{
+		"routing_key":  p.RoutingKey,
+		"event_action": "trigger",
+		"dedup_key":    key,
+		"payload": map[string]interface{}{
+			"summary":        e.Message,
+			"source":         e.Owner + "/" + e.Repo,
+			"severity":       severity,
+			"custom_details": details,
+		},
+	}
+	if e.URL != "" {
+		msg["links"] = []map[string]string{{"href": e.URL, "text": "View on GitHub"}}
+	}
+	return errors.Wrap(postJSON(ctx, p.Client, p.url(), msg), "could not trigger PagerDuty incident")
+}
+
+// Resolve resolves the incident identified by key.
+func (p *PagerDuty) Resolve(ctx context.Context, key string) error {
+	msg := map[string]interface{}{
+		"routing_key":  p.RoutingKey,
+		"event_action": "resolve",
+		"dedup_key":    key,
+	}
+	return errors.Wrap(postJSON(ctx, p.Client, p.url(), msg), "could not resolve PagerDuty incident")
+}
+
+func (p *PagerDuty) url() string {
+	if p.URL == "" {
+		return pagerDutyURL
+	}
+	return p.URL
+}
@@ -0,0 +1,43 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPagerDuty(t *testing.T) {
+	var got []map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			t.Errorf("could not decode message: %v", err)
+		}
+		got = append(got, msg)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	p := &PagerDuty{RoutingKey: "routing", URL: ts.URL}
+	e := Event{Kind: Overdue, Owner: "foo", Repo: "bar", Number: 1, URL: "https://github.com/foo/bar/issues/1", Message: "foo/bar#1 Fix it is overdue"}
+	if err := p.Trigger(context.Background(), "key", e); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := p.Resolve(context.Background(), "key"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("expected two events; got %v", got)
+	}
+	payload, _ := got[0]["payload"].(map[string]interface{})
+	if got[0]["event_action"] != "trigger" || got[0]["routing_key"] != "routing" || got[0]["dedup_key"] != "key" ||
+		payload["summary"] != e.Message || payload["source"] != "foo/bar" || payload["severity"] != "critical" {
+		t.Errorf("unexpected trigger event %v", got[0])
+	}
+	if got[1]["event_action"] != "resolve" || got[1]["dedup_key"] != "key" || got[1]["payload"] != nil {
+		t.Errorf("unexpected resolve event %v", got[1])
+	}
+}
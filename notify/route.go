@@ -0,0 +1,53 @@
+package notify
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Routes delivers each event to the notifier of its repository, keyed by
+// "owner/repo", or else to the one of its owner, or else to the one of "*",
+// if any. Keys are matched regardless of their case. Digests and summaries,
+// which belong to no repository, only go to "*".
+type Routes map[string]Notifier
+
+// Notify delivers the event to the notifier of its repository or owner.
+func (r Routes) Notify(ctx context.Context, e Event) error {
+	targets := []string{"*"}
+	if e.Owner != "" {
+		targets = []string{e.Owner + "/" + e.Repo, e.Owner, "*"}
+	}
+	for _, t := range targets {
+		for key, n := range r {
+			if strings.EqualFold(key, t) {
+				return n.Notify(ctx, e)
+			}
+		}
+	}
+	return nil
+}
+
+// ParseRoutes parses routes given as "owner/repo=value", "owner=value", or
+// just "value" for the rest of the repositories, creating the notifier of each
+// from its value with sink, like a webhook URL.
+func ParseRoutes(entries []string, sink func(value string) (Notifier, error)) (Routes, error) {
+	r := make(Routes)
+	for _, s := range entries {
+		target, value := "*", strings.TrimSpace(s)
+		// the equals sign of a route comes before the scheme of a URL.
+		if i, j := strings.Index(value, "="), strings.Index(value, "://"); i >= 0 && (j < 0 || i < j) {
+			target, value = strings.TrimSpace(value[:i]), strings.TrimSpace(value[i+1:])
+		}
+		if target == "" || value == "" || strings.Count(target, "/") > 1 {
+			return nil, errors.Errorf("invalid route %q, expected owner/repo=value", s)
+		}
+		n, err := sink(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid route %q", s)
+		}
+		r[target] = n
+	}
+	return r, nil
+}
@@ -0,0 +1,41 @@
+package notify
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestRoutes(t *testing.T) {
+	var got []string
+	sink := func(value string) (Notifier, error) {
+		return NotifierFunc(func(ctx context.Context, e Event) error {
+			got = append(got, value)
+			return nil
+		}), nil
+	}
+	r, err := ParseRoutes([]string{"foo/bar=repo", "Foo=org", "https://example.com/?a=b"}, sink)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, e := range []Event{
+		{Owner: "foo", Repo: "bar"},
+		{Owner: "FOO", Repo: "other"},
+		{Owner: "baz", Repo: "bar"},
+		{Kind: Digest},
+	} {
+		if err := r.Notify(context.Background(), e); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	expected := []string{"repo", "org", "https://example.com/?a=b", "https://example.com/?a=b"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected the events routed to %v; got %v", expected, got)
+	}
+
+	for _, bad := range []string{"=url", "foo/bar/baz=url", "foo="} {
+		if _, err := ParseRoutes([]string{bad}, sink); err == nil {
+			t.Errorf("expected an error for %q", bad)
+		}
+	}
+}
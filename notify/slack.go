@@ -0,0 +1,101 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Slack posts events to a Slack incoming webhook.
+type Slack struct {
+	// WebhookURL is the URL of the incoming webhook.
+	WebhookURL string
+	// Channel, like "#deadlines", overrides the channel of the webhook if set.
+	Channel string
+	// Client is used to post the events, http.DefaultClient if nil.
+	Client *http.Client
+}
+
+// NewSlack returns a Slack notifier posting to the given webhook URL,
+// followed by the channel to post to, if any, as in
+// "https://hooks.slack.com/services/T000/B000/XXXX#deadlines".
+func NewSlack(webhook string) (*Slack, error) {
+	var channel string
+	if i := strings.LastIndex(webhook, "#"); i >= 0 {
+		webhook, channel = webhook[:i], "#"+webhook[i+1:]
+	}
+	if !validWebhook(webhook) {
+		return nil, errors.Errorf("invalid Slack webhook %q", webhook)
+	}
+	return &Slack{WebhookURL: webhook, Channel: channel}, nil
+}
+
+// slackEscaper escapes the control characters of the Slack messages, so
+// texts like "<!channel>" in the titles of the issues don't mention anyone.
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Notify posts the event to the webhook, with a link to its issue and the
+// time left until its deadline. Changes of deadlines aren't posted.
+func (s *Slack) Notify(ctx context.Context, e Event) error {
+	if e.Kind == Deadline {
+		return nil
+	}
+	text := slackEscaper.Replace(e.Message)
+	if e.URL != "" {
+		text = fmt.Sprintf("<%s|%s> %s", e.URL, slackEscaper.Replace(fmt.Sprintf("%s/%s#%d", e.Owner, e.Repo, e.Number)), text)
+	}
+	if left := e.timeLeft(); left != "" {
+		text += " (" + left + ")"
+	}
+	msg := map[string]string{"text": text}
+	if s.Channel != "" {
+		msg["channel"] = s.Channel
+	}
+	return errors.Wrap(postJSON(ctx, s.Client, s.WebhookURL, msg), "could not post to Slack")
+}
+
+// validWebhook reports whether the webhook is an absolute HTTP URL.
+func validWebhook(webhook string) bool {
+	u, err := url.Parse(webhook)
+	return err == nil && (u.Scheme == "https" || u.Scheme == "http") && u.Host != ""
+}
+
+// postJSON posts v encoded as JSON to the given URL.
+func postJSON(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return errors.Wrap(err, "could not encode message")
+	}
+	return post(ctx, client, url, body, nil)
+}
+
+// post posts the JSON body to the given URL with the extra headers given.
+func post(ctx context.Context, client *http.Client, url string, body []byte, header http.Header) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "could not create request")
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode >= 300 {
+		return errors.Errorf("webhook responded %s", res.Status)
+	}
+	return nil
+}
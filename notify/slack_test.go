@@ -0,0 +1,71 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSlack(t *testing.T) {
+	var got map[string]string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("could not decode message: %v", err)
+		}
+	}))
+	defer ts.Close()
+
+	s, err := NewSlack(ts.URL + "#deadlines")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	e := Event{Kind: Label, Owner: "foo", Repo: "bar", Number: 1, URL: "https://github.com/foo/bar/issues/1",
+		Message: "Fix it is now labeled deadline < 5", Deadline: time.Now().Add(3*24*time.Hour + time.Hour)}
+	if err := s.Notify(context.Background(), e); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]string{
+		"channel": "#deadlines",
+		"text":    "<https://github.com/foo/bar/issues/1|foo/bar#1> Fix it is now labeled deadline &lt; 5 (3 days left)",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v; got %v", expected, got)
+	}
+
+	e = Event{Kind: Reminder, Message: "<!channel> Q&A is due"}
+	if err := s.Notify(context.Background(), e); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := "&lt;!channel&gt; Q&amp;A is due"; got["text"] != expected {
+		t.Errorf("expected the message to be escaped as %q; got %q", expected, got["text"])
+	}
+
+	if _, err := NewSlack("hooks.slack.com"); err == nil {
+		t.Errorf("expected an error for a webhook without scheme")
+	}
+}
+
+func TestTimeLeft(t *testing.T) {
+	day := 24 * time.Hour
+	tests := map[time.Duration]string{
+		0:                  "",
+		12 * time.Hour:     "due in less than a day",
+		day + time.Hour:    "1 day left",
+		10*day + time.Hour: "10 days left",
+		-time.Hour:         "overdue",
+		-3*day - time.Hour: "overdue by 3 days",
+	}
+	for d, expected := range tests {
+		e := Event{}
+		if d != 0 {
+			e.Deadline = time.Now().Add(d)
+		}
+		if left := e.timeLeft(); left != expected {
+			t.Errorf("expected %q for %v; got %q", expected, d, left)
+		}
+	}
+}
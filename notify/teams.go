@@ -0,0 +1,77 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// Teams posts events to a Microsoft Teams incoming webhook, as adaptive cards.
+type Teams struct {
+	// WebhookURL is the URL of the incoming webhook.
+	WebhookURL string
+	// Client is used to post the events, http.DefaultClient if nil.
+	Client *http.Client
+}
+
+// NewTeams returns a Teams notifier posting to the given webhook URL.
+func NewTeams(webhook string) (*Teams, error) {
+	if !validWebhook(webhook) {
+		return nil, errors.Errorf("invalid Teams webhook %q", webhook)
+	}
+	return &Teams{WebhookURL: webhook}, nil
+}
+
+// Notify posts the event to the webhook as an adaptive card with its message,
+// its deadline and the time left, and a button to open its issue. Changes of
+// deadlines aren't posted.
+func (t *Teams) Notify(ctx context.Context, e Event) error {
+	if e.Kind == Deadline {
+		return nil
+	}
+	return errors.Wrap(postJSON(ctx, t.Client, t.WebhookURL, teamsMessage(e)), "could not post to Teams")
+}
+
+// teamsMessage returns the message of a Teams webhook with the adaptive card
+// of the event.
+func teamsMessage(e Event) map[string]interface{} {
+	var body []interface{}
+	if e.Owner != "" {
+		title := fmt.Sprintf("%s/%s#%d", e.Owner, e.Repo, e.Number)
+		if e.Title != "" {
+			title += " " + e.Title
+		}
+		body = append(body, map[string]interface{}{
+			"type": "TextBlock", "text": title, "weight": "Bolder", "size": "Medium", "wrap": true,
+		})
+	}
+	body = append(body, map[string]interface{}{"type": "TextBlock", "text": e.Message, "wrap": true})
+	if !e.Deadline.IsZero() {
+		body = append(body, map[string]interface{}{
+			"type": "FactSet",
+			"facts": []map[string]string{
+				{"title": "Deadline", "value": e.Deadline.Format("2006-01-02 15:04 MST")},
+				{"title": "Time left", "value": e.timeLeft()},
+			},
+		})
+	}
+
+	card := map[string]interface{}{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.2",
+		"body":    body,
+	}
+	if e.URL != "" {
+		card["actions"] = []map[string]string{{"type": "Action.OpenUrl", "title": "View on GitHub", "url": e.URL}}
+	}
+	return map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content":     card,
+		}},
+	}
+}
@@ -0,0 +1,71 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTeams(t *testing.T) {
+	var got struct {
+		Type        string `json:"type"`
+		Attachments []struct {
+			ContentType string `json:"contentType"`
+			Content     struct {
+				Type string `json:"type"`
+				Body []struct {
+					Type  string              `json:"type"`
+					Text  string              `json:"text"`
+					Facts []map[string]string `json:"facts"`
+				} `json:"body"`
+				Actions []map[string]string `json:"actions"`
+			} `json:"content"`
+		} `json:"attachments"`
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("could not decode message: %v", err)
+		}
+	}))
+	defer ts.Close()
+
+	n, err := NewTeams(ts.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	deadline := time.Date(2030, 8, 1, 0, 0, 0, 0, time.UTC)
+	e := Event{Kind: Reminder, Owner: "foo", Repo: "bar", Number: 1, Title: "Fix it", URL: "https://github.com/foo/bar/issues/1",
+		Message: "it's reminder day!", Deadline: deadline}
+	if err := n.Notify(context.Background(), e); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.Type != "message" || len(got.Attachments) != 1 || got.Attachments[0].ContentType != "application/vnd.microsoft.card.adaptive" {
+		t.Fatalf("expected a message with an adaptive card; got %+v", got)
+	}
+	card := got.Attachments[0].Content
+	if card.Type != "AdaptiveCard" || len(card.Body) != 3 {
+		t.Fatalf("expected a card with a title, a message, and facts; got %+v", card)
+	}
+	if card.Body[0].Text != "foo/bar#1 Fix it" || card.Body[1].Text != "it's reminder day!" {
+		t.Errorf("expected the issue and the message; got %+v", card.Body)
+	}
+	facts := []map[string]string{
+		{"title": "Deadline", "value": "2030-08-01 00:00 UTC"},
+		{"title": "Time left", "value": e.timeLeft()},
+	}
+	if !reflect.DeepEqual(card.Body[2].Facts, facts) {
+		t.Errorf("expected facts %v; got %v", facts, card.Body[2].Facts)
+	}
+	if len(card.Actions) != 1 || card.Actions[0]["url"] != e.URL {
+		t.Errorf("expected an action opening the issue; got %v", card.Actions)
+	}
+
+	if _, err := NewTeams("not a url"); err == nil {
+		t.Errorf("expected an error for an invalid webhook")
+	}
+}
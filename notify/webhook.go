@@ -0,0 +1,56 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// Webhook posts every event as JSON to an HTTP endpoint, so other systems can
+// react to the changes of deadlines. The requests carry the kind of the event
+// in the X-Reminder-Event header and, if there's a secret, the HMAC-SHA256 of
+// the body in the X-Reminder-Signature header as "sha256=" followed by its
+// hex digest, like the webhooks of GitHub.
+type Webhook struct {
+	// URL is the endpoint receiving the events.
+	URL string
+	// Secret signs the requests if not empty.
+	Secret []byte
+	// Client is used to post the events, http.DefaultClient if nil.
+	Client *http.Client
+}
+
+// NewWebhook returns a Webhook posting to the given URL, signing the requests
+// with secret if not empty.
+func NewWebhook(url string, secret []byte) (*Webhook, error) {
+	if !validWebhook(url) {
+		return nil, errors.Errorf("invalid webhook %q", url)
+	}
+	return &Webhook{URL: url, Secret: secret}, nil
+}
+
+// Notify posts the event to the endpoint.
+func (w *Webhook) Notify(ctx context.Context, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return errors.Wrap(err, "could not encode event")
+	}
+	header := http.Header{"X-Reminder-Event": {string(e.Kind)}}
+	if len(w.Secret) > 0 {
+		header.Set("X-Reminder-Signature", Signature(w.Secret, body))
+	}
+	return errors.Wrapf(post(ctx, w.Client, w.URL, body, header), "could not post to %s", w.URL)
+}
+
+// Signature returns the signature of the body with the secret, as sent in the
+// X-Reminder-Signature header, for receivers to compare with hmac.Equal.
+func Signature(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,44 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebhook(t *testing.T) {
+	var got Event
+	var signature, kind string
+	var body []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature, kind = r.Header.Get("X-Reminder-Signature"), r.Header.Get("X-Reminder-Event")
+		body, _ = ioutil.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Errorf("could not decode event: %v", err)
+		}
+	}))
+	defer ts.Close()
+
+	w, err := NewWebhook(ts.URL, []byte("secret"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	e := Event{Kind: Deadline, Owner: "foo", Repo: "bar", Number: 1, Message: "the deadline of Fix it was cleared"}
+	if err := w.Notify(context.Background(), e); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Kind != Deadline || got.Number != 1 || got.Message != e.Message || kind != "deadline" {
+		t.Errorf("expected the event as JSON; got %+v (%s)", got, kind)
+	}
+	if signature != Signature([]byte("secret"), body) || len(signature) != len("sha256=")+64 {
+		t.Errorf("expected the body to be signed; got %q", signature)
+	}
+
+	w.Secret = nil
+	if err := w.Notify(context.Background(), e); err != nil || signature != "" {
+		t.Errorf("expected no signature without a secret; got %q (%v)", signature, err)
+	}
+}
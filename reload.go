@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/bot"
+)
+
+// envFileKeys are the variables set by the last env file applied, so they can
+// be unset if they are removed from it.
+var envFileKeys []string
+
+// applyEnvFile sets the variables in the given file, one KEY=value per line,
+// ignoring empty lines and comments starting with #.
+func applyEnvFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "could not open env file")
+	}
+	defer f.Close()
+
+	vars := make(map[string]string)
+	s := bufio.NewScanner(f)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		i := strings.Index(line, "=")
+		if i <= 0 {
+			return errors.Errorf("bad line %d in env file, expected KEY=value", n)
+		}
+		vars[strings.TrimSpace(line[:i])] = strings.Trim(strings.TrimSpace(line[i+1:]), `"`)
+	}
+	if err := s.Err(); err != nil {
+		return errors.Wrap(err, "could not read env file")
+	}
+
+	for _, key := range envFileKeys {
+		if _, ok := vars[key]; !ok {
+			os.Unsetenv(key)
+		}
+	}
+	envFileKeys = envFileKeys[:0]
+	for key, value := range vars {
+		os.Setenv(key, value)
+		envFileKeys = append(envFileKeys, key)
+	}
+	return nil
+}
+
+// reloadOnHangup reloads the configuration and credentials of the bot every
+// time the process receives a SIGHUP, keeping the current ones on failure.
+func reloadOnHangup(b *bot.Bot) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	for range hup {
+		logrus.Infof("reloading configuration")
+		config, err := loadConfig()
+		if err != nil {
+			logrus.Errorf("could not reload configuration: %v", err)
+			continue
+		}
+		botConfig, opts, err := configure(config)
+		if err != nil {
+			logrus.Errorf("could not reload configuration: %v", err)
+			continue
+		}
+		if err := b.Reload(botConfig, opts...); err != nil {
+			logrus.Errorf("could not reload configuration: %v", err)
+			continue
+		}
+		logrus.Infof("configuration reloaded")
+	}
+}
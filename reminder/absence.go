@@ -0,0 +1,200 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/notify"
+	"github.com/src-d/github-reminder/storage"
+)
+
+// An Absence is a period of time during which a user is out of office.
+// Reminders for absent users are redirected to their backup, if any, or
+// deferred until they're back.
+type Absence struct {
+	User   string    `json:"user"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Backup string    `json:"backup,omitempty"`
+}
+
+// ParseAbsence parses an absence written as user:start/end[:backup],
+// like alice:2018-08-01/2018-08-15:bob.
+func ParseAbsence(s string) (Absence, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return Absence{}, errors.Errorf("bad absence %q, expected user:start/end[:backup]", s)
+	}
+	start, end, err := parseDateRange(parts[1])
+	if err != nil {
+		return Absence{}, errors.Wrap(err, "bad absence")
+	}
+	a := Absence{User: strings.TrimPrefix(parts[0], "@"), Start: start, End: end}
+	if len(parts) == 3 {
+		a.Backup = strings.TrimPrefix(parts[2], "@")
+	}
+	return a, nil
+}
+
+// WithAbsences configures the periods users are out of office,
+// in addition to the ones registered with the /ooo command.
+func WithAbsences(as ...Absence) Option {
+	return func(o *options) { o.absences = append(o.absences, as...) }
+}
+
+func (c *InstallationClient) absenceKey(user string) string {
+	return storage.Key("absence", c.appID, c.installationID, strings.ToLower(user))
+}
+
+// absence returns the absence of the user at the given time, if any.
+func (c *InstallationClient) absence(ctx context.Context, user string, t time.Time) (*Absence, error) {
+	within := func(a Absence) bool { return !t.Before(a.Start) && t.Before(a.End) }
+
+	for _, a := range c.opts.absences {
+		if strings.EqualFold(a.User, user) && within(a) {
+			return &a, nil
+		}
+	}
+
+	var a Absence
+	err := c.opts.store.Get(ctx, c.absenceKey(user), &a)
+	if err == storage.ErrNotFound || (err == nil && !within(a)) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not fetch absence for %s", user)
+	}
+	return &a, nil
+}
+
+// deferredReminder is a reminder for a user that was out of office when it was due.
+type deferredReminder struct {
+	Owner  string    `json:"owner"`
+	Repo   string    `json:"repo"`
+	Number int       `json:"number"`
+	User   string    `json:"user"`
+	Due    time.Time `json:"due"`
+}
+
+// remind posts the reminder comment for the given user,
+// redirecting it or deferring it if the user is out of office.
+// Nothing is posted while the issue is snoozed.
+func (c *InstallationClient) remind(ctx context.Context, issue *issue, user string, due time.Time) error {
+	owner, repo, number := issue.repo.owner, issue.repo.name, issue.number
+	if snoozed, err := c.snoozed(ctx, issue); err != nil || snoozed {
+		if snoozed {
+			logrus.Infof("%s/%s#%d is snoozed, skipping reminder for %s", owner, repo, number, user)
+		}
+		return err
+	}
+
+	def := fmt.Sprintf("hi @%s, %s", user, reminderText)
+	text := c.render(issue, "reminder", c.opts.templates.Reminder, issue.templateData(user), def)
+	if text != def {
+		text = fmt.Sprintf("%s\n%s", text, reminderMarker)
+	}
+	a, err := c.absence(ctx, user, time.Now())
+	if err != nil {
+		return err
+	}
+	if a != nil && a.Backup == "" {
+		logrus.Infof("%s is out of office, deferring reminder on %s/%s#%d", user, owner, repo, number)
+		d := deferredReminder{owner, repo, number, user, due}
+		key := storage.Key("deferred", c.appID, c.installationID, strings.ToLower(user), owner, repo, number, due.Format("2006-01-02"))
+		return errors.Wrap(c.opts.store.Put(ctx, key, d), "could not defer reminder")
+	}
+	if a != nil {
+		text = fmt.Sprintf("hi @%s, %s @%s is out of office until %s and you're their backup.",
+			a.Backup, reminderText, user, a.End.AddDate(0, 0, -1).Format("January 2"))
+	}
+
+	if err := c.postReminder(ctx, issue, user, text); err != nil {
+		return err
+	}
+
+	e := issue.event(notify.Reminder, text)
+	e.User = user
+	if a != nil {
+		e.User = a.Backup
+	}
+	c.notify(ctx, e, issue.policy)
+	return nil
+}
+
+// deliverDeferred posts the reminders deferred for users who are back in office.
+func (c *InstallationClient) deliverDeferred(ctx context.Context) error {
+	keys, err := c.opts.store.List(ctx, storage.Key("deferred", c.appID, c.installationID)+"/")
+	if err != nil {
+		return errors.Wrap(err, "could not list deferred reminders")
+	}
+
+	now := time.Now()
+	for _, key := range keys {
+		var d deferredReminder
+		if err := c.opts.store.Get(ctx, key, &d); err != nil {
+			return errors.Wrapf(err, "could not fetch deferred reminder %s", key)
+		}
+		a, err := c.absence(ctx, d.User, now)
+		if err != nil {
+			return err
+		}
+		if a != nil {
+			continue
+		}
+
+		text := fmt.Sprintf("hi @%s, welcome back! You had a reminder here on %s while you were out of office.",
+			d.User, d.Due.Format("January 2"))
+		if err := c.client.createIssueComment(ctx, d.Owner, d.Repo, d.Number, text); err != nil {
+			return errors.Wrapf(err, "could not comment on %s/%s#%d", d.Owner, d.Repo, d.Number)
+		}
+		if err := c.opts.store.Delete(ctx, key); err != nil {
+			return errors.Wrapf(err, "could not delete deferred reminder %s", key)
+		}
+	}
+	return nil
+}
+
+// oooCommand registers the author as out of office.
+//
+//	/ooo 2018-08-01 2018-08-15 [@backup]
+//	/ooo clear
+func oooCommand(ctx context.Context, c *InstallationClient, cmd Command) error {
+	reply := func(text string) error {
+		return c.client.createIssueComment(ctx, cmd.Owner, cmd.Repo, cmd.Number, fmt.Sprintf("@%s %s", cmd.Author, text))
+	}
+
+	if len(cmd.Args) == 1 && strings.EqualFold(cmd.Args[0], "clear") {
+		if err := c.opts.store.Delete(ctx, c.absenceKey(cmd.Author)); err != nil {
+			return errors.Wrap(err, "could not delete absence")
+		}
+		return reply("welcome back! your out of office period has been cleared.")
+	}
+
+	if len(cmd.Args) < 2 || len(cmd.Args) > 3 {
+		return reply("usage: `/ooo 2018-08-01 2018-08-15 [@backup]` or `/ooo clear`.")
+	}
+	start, end, err := parseDateRange(cmd.Args[0] + "/" + cmd.Args[1])
+	if err != nil {
+		return reply(fmt.Sprintf("could not understand those dates: %v.", err))
+	}
+	a := Absence{User: cmd.Author, Start: start, End: end}
+	if len(cmd.Args) == 3 {
+		a.Backup = strings.TrimPrefix(cmd.Args[2], "@")
+	}
+	if err := c.opts.store.Put(ctx, c.absenceKey(cmd.Author), a); err != nil {
+		return errors.Wrap(err, "could not store absence")
+	}
+
+	text := fmt.Sprintf("you're out of office from %s to %s, ", start.Format("January 2"), end.AddDate(0, 0, -1).Format("January 2"))
+	if a.Backup != "" {
+		text += fmt.Sprintf("your reminders will go to @%s.", a.Backup)
+	} else {
+		text += "your reminders will wait until you're back."
+	}
+	return reply(text)
+}
@@ -0,0 +1,78 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseAbsence(t *testing.T) {
+	a, err := ParseAbsence("@alice:2018-08-01/2018-08-15:@bob")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.User != "alice" || a.Backup != "bob" {
+		t.Errorf("unexpected absence %+v", a)
+	}
+	if _, err := ParseAbsence("alice"); err == nil {
+		t.Errorf("expected error for absence without dates")
+	}
+}
+
+func TestAbsentUserReminders(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	tests := []struct {
+		name     string
+		absence  Absence
+		expected string
+	}{
+		{"backup", Absence{User: "francesc", Start: today, End: today.AddDate(0, 0, 2), Backup: "bob"}, "@bob"},
+		{"deferred", Absence{User: "francesc", Start: today, End: today.AddDate(0, 0, 2)}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var comments []string
+			ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions([]Option{WithAbsences(tt.absence)}), client: &fakeClient{
+				_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) { return nil, nil },
+				_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+					return &issue{
+						repo:   repository{owner, repo},
+						number: number,
+						author: "francesc",
+						state:  "open",
+						body:   fmt.Sprintf("reminder: %s\n", time.Now().Format("2006-01-02")),
+					}, nil
+				},
+				_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error {
+					comments = append(comments, body)
+					return nil
+				},
+			}}
+
+			ctx := context.Background()
+			if err := ic.UpdateIssue(ctx, "foo", "bar", 1); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.expected == "" {
+				if len(comments) != 0 {
+					t.Fatalf("expected reminder to be deferred; got %v", comments)
+				}
+				// back in office.
+				ic.opts.absences = nil
+				if err := ic.deliverDeferred(ctx); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if len(comments) != 1 || !strings.Contains(comments[0], "welcome back") {
+					t.Fatalf("expected deferred reminder to be delivered; got %v", comments)
+				}
+				return
+			}
+			if len(comments) != 1 || !strings.Contains(comments[0], tt.expected) {
+				t.Fatalf("expected a comment mentioning %s; got %v", tt.expected, comments)
+			}
+		})
+	}
+}
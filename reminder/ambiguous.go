@@ -0,0 +1,149 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// numericDate matches the dates written as numbers separated by slashes, with
+// the year last, like 05/06/2018, either May 6 or June 5.
+var numericDate = regexp.MustCompile(`^(\d{1,2})/(\d{1,2})/(\d{4})$`)
+
+// parseNumericDate parses a date like 05/06/2018, with the day first if
+// dayFirst is set and the month first otherwise, unless only the other way is
+// a valid date. If both are valid and different it also returns the other one.
+func parseNumericDate(s string, dayFirst bool, loc *time.Location) (t, alt time.Time) {
+	m := numericDate.FindStringSubmatch(s)
+	if m == nil {
+		return time.Time{}, time.Time{}
+	}
+	a, _ := strconv.Atoi(m[1])
+	b, _ := strconv.Atoi(m[2])
+	year, _ := strconv.Atoi(m[3])
+	date := func(day, month int) time.Time {
+		if month < 1 || month > 12 || day < 1 {
+			return time.Time{}
+		}
+		t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, loc)
+		if t.Day() != day {
+			return time.Time{}
+		}
+		return t
+	}
+
+	t, alt = date(b, a), date(a, b)
+	if dayFirst {
+		t, alt = alt, t
+	}
+	if t.IsZero() || t.Equal(alt) {
+		return alt, time.Time{}
+	}
+	return t, alt
+}
+
+// dayFirst reports whether numeric dates have the day first, as in most
+// languages other than English.
+func (d dates) dayFirst() bool {
+	return d.language != "" && d.language != "en"
+}
+
+// ambiguous returns how a date written like 05/06/2018 is read and the
+// other way it could have been meant, if it's ambiguous.
+func (d dates) ambiguous(s string) (t, alt time.Time, ok bool) {
+	if !parseDate(s, d.loc, d.layouts).IsZero() {
+		return time.Time{}, time.Time{}, false
+	}
+	loc := d.loc
+	if loc == nil {
+		loc = time.UTC
+	}
+	t, alt = parseNumericDate(strings.TrimSpace(strings.Trim(strings.TrimSpace(s), ":")), d.dayFirst(), loc)
+	return t, alt, !alt.IsZero()
+}
+
+// ambiguousMarker marks the comments clarifying an ambiguous date.
+func ambiguousMarker(s string) string {
+	return fmt.Sprintf("<!-- github-reminder:ambiguous %s -->", s)
+}
+
+// A keywordValue is the text following a keyword, like ": 2018-08-01" for
+// "deadline: 2018-08-01", without the name of the checkpoint or the users a
+// reminder is for, and the time it was written.
+type keywordValue struct {
+	keyword string
+	value   string
+	created time.Time
+}
+
+// keywordValues returns the values of the deadline and reminder keywords in
+// the title, body, and comments of the issue not written by the bot.
+func (c *InstallationClient) keywordValues(issue *issue) []keywordValue {
+	var values []keywordValue
+	find := func(text string, created time.Time) {
+		text = issue.synonyms.replace(text)
+		for _, v := range issue.grammar.findValues("deadline", text) {
+			if strings.HasPrefix(v, "(") {
+				if i := strings.Index(v, ")"); i >= 0 {
+					v = v[i+1:]
+				}
+			}
+			values = append(values, keywordValue{"deadline", v, created})
+		}
+		for _, v := range issue.grammar.findValues("reminder", text) {
+			_, v = parseAddressees(v)
+			values = append(values, keywordValue{"reminder", v, created})
+		}
+	}
+	find(titleBrackets.Replace(issue.title), issue.created)
+	find(issue.body, issue.created)
+	for _, cm := range issue.comments {
+		if cm.author != c.login() {
+			find(cm.body, cm.created)
+		}
+	}
+	return values
+}
+
+// botCommented reports whether the bot already posted a comment with the given marker.
+func (c *InstallationClient) botCommented(issue *issue, marker string) bool {
+	for _, cm := range issue.comments {
+		if cm.author == c.login() && strings.Contains(cm.body, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// clarifyDates posts a single comment explaining how the ambiguous dates of
+// the issue not clarified yet are read, and how to write the other meaning.
+func (c *InstallationClient) clarifyDates(ctx context.Context, issue *issue) error {
+	d := c.dates(issue)
+	var lines, markers []string
+	seen := make(map[string]bool)
+	for _, kv := range c.keywordValues(issue) {
+		t, alt, ok := d.ambiguous(kv.value)
+		v := strings.TrimSpace(strings.Trim(strings.TrimSpace(kv.value), ":"))
+		if !ok || seen[v] {
+			continue
+		}
+		seen[v] = true
+		marker := ambiguousMarker(v)
+		if c.botCommented(issue, marker) {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("- `%s` is read as %s. If you meant %s, write `%s` instead.",
+			v, t.Format("January 2, 2006"), alt.Format("January 2, 2006"), alt.Format("2006-01-02")))
+		markers = append(markers, marker)
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	text := fmt.Sprintf("hi @%s, some dates in this issue could be read in more than one way:\n%s\n%s",
+		issue.author, strings.Join(lines, "\n"), strings.Join(markers, "\n"))
+	return c.Comment(ctx, issue.repo.owner, issue.repo.name, issue.number, text)
+}
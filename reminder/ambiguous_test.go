@@ -0,0 +1,65 @@
+package reminder
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseNumericDate(t *testing.T) {
+	day := func(month time.Month, d int) time.Time { return time.Date(2018, month, d, 0, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		s        string
+		dayFirst bool
+		t, alt   time.Time
+	}{
+		{"05/06/2018", false, day(5, 6), day(6, 5)},
+		{"05/06/2018", true, day(6, 5), day(5, 6)},
+		{"13/06/2018", false, day(6, 13), time.Time{}},
+		{"06/13/2018", true, day(6, 13), time.Time{}},
+		{"06/06/2018", false, day(6, 6), time.Time{}},
+		{"31/02/2018", false, time.Time{}, time.Time{}},
+		{"2018/06/05", false, time.Time{}, time.Time{}},
+	}
+	for _, tt := range tests {
+		got, alt := parseNumericDate(tt.s, tt.dayFirst, time.UTC)
+		if !got.Equal(tt.t) || !alt.Equal(tt.alt) {
+			t.Errorf("%q: expected %v and %v; got %v and %v", tt.s, tt.t, tt.alt, got, alt)
+		}
+	}
+}
+
+func TestClarifyDates(t *testing.T) {
+	var comments []comment
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil), client: &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) { return nil, nil },
+		_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+			return &issue{
+				repo: repository{owner, repo}, number: number, state: "open", author: "francesc",
+				body:     "deadline: 05/06/2030\nreminder: 13/06/2030",
+				comments: comments,
+			}, nil
+		},
+		_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error {
+			comments = append(comments, comment{author: botLogin, body: body, created: time.Now()})
+			return nil
+		},
+	}}
+
+	for i := 0; i < 2; i++ {
+		if err := ic.UpdateIssue(context.Background(), "foo", "bar", 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(comments) != 1 {
+		t.Fatalf("expected a single clarification; got %d comments", len(comments))
+	}
+	body := comments[0].body
+	if !strings.Contains(body, "`05/06/2030` is read as May 6, 2030") || !strings.Contains(body, "`2030-06-05`") {
+		t.Errorf("expected the clarification to explain the date; got %q", body)
+	}
+	if strings.Contains(body, "13/06/2030") {
+		t.Errorf("expected unambiguous dates not to be clarified; got %q", body)
+	}
+}
@@ -0,0 +1,88 @@
+package reminder
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+// learningScans is the number of scans used to learn a baseline before reporting anomalies.
+const learningScans = 3
+
+// AnomalyPolicy configures the detection of installation scans performing
+// an unusually large number of changes, e.g. because of a parsing regression.
+type AnomalyPolicy struct {
+	// Factor is how many times larger than the baseline the number of
+	// mutations in a scan needs to be to be considered anomalous.
+	// Defaults to 10.
+	Factor float64
+	// MinMutations is the minimum number of mutations for a scan to be
+	// considered anomalous, avoiding alerts on mostly idle installations.
+	// Defaults to 20.
+	MinMutations int
+	// Pause discards the changes of anomalous scans instead of applying them.
+	Pause bool
+	// Alert, if not nil, is called every time an anomaly is detected.
+	Alert func(Anomaly)
+}
+
+// An Anomaly describes a scan that computed an unusually large number of mutations.
+type Anomaly struct {
+	AppID          int
+	InstallationID int
+	Mutations      int
+	Baseline       float64
+	Paused         bool
+}
+
+// baseline is the exponential moving average of mutations per scan for an installation.
+type baseline struct {
+	Mean  float64 `json:"mean"`
+	Scans int     `json:"scans"`
+}
+
+// checkAnomaly compares the given number of mutations with the installation
+// baseline, returning whether the mutations should be applied.
+func (c *InstallationClient) checkAnomaly(ctx context.Context, mutations int) (bool, error) {
+	p := c.opts.anomaly
+	factor, min := p.Factor, p.MinMutations
+	if factor <= 0 {
+		factor = 10
+	}
+	if min <= 0 {
+		min = 20
+	}
+
+	key := storage.Key("baseline", c.appID, c.installationID)
+	var b baseline
+	if err := c.opts.store.Get(ctx, key, &b); err != nil && err != storage.ErrNotFound {
+		return false, errors.Wrap(err, "could not fetch mutation baseline")
+	}
+
+	reference := b.Mean
+	if reference < 1 {
+		reference = 1
+	}
+	if b.Scans >= learningScans && mutations >= min && float64(mutations) > factor*reference {
+		a := Anomaly{c.appID, c.installationID, mutations, b.Mean, p.Pause}
+		logrus.Errorf("installation %d/%d wants to perform %d mutations, usually %.1f",
+			c.appID, c.installationID, mutations, b.Mean)
+		if p.Alert != nil {
+			p.Alert(a)
+		}
+		// anomalous scans are not taken into account for the baseline.
+		return !p.Pause, nil
+	}
+
+	const alpha = 0.2
+	if b.Scans == 0 {
+		b.Mean = float64(mutations)
+	} else {
+		b.Mean = alpha*float64(mutations) + (1-alpha)*b.Mean
+	}
+	b.Scans++
+	return true, errors.Wrap(c.opts.store.Put(ctx, key, b), "could not store mutation baseline")
+}
@@ -0,0 +1,34 @@
+package reminder
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAnomalyDetection(t *testing.T) {
+	var alerts []Anomaly
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions([]Option{
+		WithAnomalyDetection(AnomalyPolicy{Pause: true, Alert: func(a Anomaly) { alerts = append(alerts, a) }}),
+	})}
+
+	ctx := context.Background()
+	for i := 0; i < learningScans; i++ {
+		ok, err := ic.checkAnomaly(ctx, 5)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !ok {
+			t.Fatalf("scan %d was considered anomalous while learning", i)
+		}
+	}
+
+	if ok, _ := ic.checkAnomaly(ctx, 15); !ok {
+		t.Errorf("expected small increase to be applied")
+	}
+	if ok, _ := ic.checkAnomaly(ctx, 500); ok {
+		t.Errorf("expected large increase to be paused")
+	}
+	if len(alerts) != 1 || alerts[0].Mutations != 500 {
+		t.Errorf("expected a single alert for 500 mutations; got %v", alerts)
+	}
+}
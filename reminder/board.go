@@ -0,0 +1,205 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+// A Project is a GitHub project, either one of the projects of an
+// organization or user, identified by their login and its number, or a
+// classic project, identified by its id.
+type Project struct {
+	Owner     string
+	Number    int
+	ClassicID int64
+}
+
+// ParseProject parses a project written as owner/number, like acme/5 for the
+// fifth project of the organization acme, or as classic:id for a classic
+// project.
+func ParseProject(s string) (Project, error) {
+	if strings.HasPrefix(s, "classic:") {
+		id, err := strconv.ParseInt(strings.TrimPrefix(s, "classic:"), 10, 64)
+		if err != nil || id <= 0 {
+			return Project{}, errors.Errorf("bad classic project %q, expected classic:id", s)
+		}
+		return Project{ClassicID: id}, nil
+	}
+	i := strings.LastIndex(s, "/")
+	if i <= 0 {
+		return Project{}, errors.Errorf("bad project %q, expected owner/number or classic:id", s)
+	}
+	n, err := strconv.Atoi(s[i+1:])
+	if err != nil || n <= 0 {
+		return Project{}, errors.Errorf("bad project %q, expected owner/number or classic:id", s)
+	}
+	return Project{Owner: s[:i], Number: n}, nil
+}
+
+func (p Project) String() string {
+	if p.ClassicID != 0 {
+		return fmt.Sprintf("classic:%d", p.ClassicID)
+	}
+	return fmt.Sprintf("%s/%d", p.Owner, p.Number)
+}
+
+// A ProjectBoard is a project the issues are added to once their deadline is
+// near, so a board of what's due soon populates itself.
+type ProjectBoard struct {
+	Project Project
+	// Within is how long before their deadline the issues are added.
+	Within time.Duration
+}
+
+// WithProjectBoard makes the bot add the issues to the project of the board
+// once their deadline is within its Within, or overdue. Issues are added to
+// classic projects in their first column. Each issue is added only once, so
+// the ones removed from the project by hand stay out of it.
+func WithProjectBoard(b ProjectBoard) Option {
+	return func(o *options) { o.board = &b }
+}
+
+// checkProjectBoard adds the issue to the project of the board if its
+// deadline is near and it wasn't added yet.
+func (c *InstallationClient) checkProjectBoard(ctx context.Context, issue *issue, deadline time.Time) error {
+	b := c.opts.board
+	if b == nil || deadline.IsZero() || time.Until(deadline) >= b.Within {
+		return nil
+	}
+	owner, repo, number := issue.repo.owner, issue.repo.name, issue.number
+	key := storage.Key("projectboard", c.appID, c.installationID, strings.ToLower(owner), strings.ToLower(repo), number)
+	var added string
+	if err := c.opts.store.Get(ctx, key, &added); err != nil && err != storage.ErrNotFound {
+		return errors.Wrap(err, "could not fetch project board")
+	}
+	if added == b.Project.String() {
+		return nil
+	}
+	logrus.Infof("adding %s/%s#%d to project %s", owner, repo, number, b.Project)
+	if err := c.client.addToProject(ctx, owner, repo, number, b.Project); err != nil {
+		return errors.Wrapf(err, "could not add %s/%s#%d to project %s", owner, repo, number, b.Project)
+	}
+	// it's moved to its column right away, even if it was missing before.
+	if err := c.opts.store.Delete(ctx, missingKey(c.appID, c.installationID, owner, repo, number)); err != nil && err != storage.ErrNotFound {
+		return errors.Wrap(err, "could not forget missing project item")
+	}
+	return errors.Wrap(c.opts.store.Put(ctx, key, b.Project.String()), "could not store project board")
+}
+
+// missingRecheck is how long the issues found not to be in the project of the
+// board are left alone before looking for them again, since finding them can
+// take a call by card of the project.
+const missingRecheck = 24 * time.Hour
+
+func missingKey(appID, installationID int, owner, repo string, number int) string {
+	return storage.Key("projectmissing", appID, installationID, strings.ToLower(owner), strings.ToLower(repo), number)
+}
+
+// BoardColumns are the columns the issues of the project board are moved
+// between as their deadline approaches, following their deadline labels, as in
+// Backlog, This Week, Today, and Overdue.
+type BoardColumns struct {
+	// Default is the column of the issues without a deadline label.
+	Default string
+	// Labels are the columns of the issues with each deadline label, like
+	// "deadline < 7", by its name.
+	Labels map[string]string
+	// Overdue is the column of the overdue issues, which stay in the column
+	// of their label if it's empty.
+	Overdue string
+	// Field is the single select field of the project whose options are the
+	// columns, Status if empty. Classic projects have actual columns.
+	Field string
+}
+
+// ParseBoardColumns parses the columns of a board written as semicolon
+// separated label=column pairs, using none and overdue for the issues
+// without a deadline label and the overdue ones, like
+// none=Backlog;deadline < 7=This Week;deadline < 1=Today;overdue=Overdue.
+func ParseBoardColumns(s string) (BoardColumns, error) {
+	cols := BoardColumns{Labels: make(map[string]string)}
+	for _, pair := range strings.Split(s, ";") {
+		i := strings.Index(pair, "=")
+		if i <= 0 || strings.TrimSpace(pair[i+1:]) == "" {
+			return BoardColumns{}, errors.Errorf("bad board column %q, expected label=column", pair)
+		}
+		label, column := strings.TrimSpace(pair[:i]), strings.TrimSpace(pair[i+1:])
+		switch label {
+		case "none":
+			cols.Default = column
+		case "overdue":
+			cols.Overdue = column
+		default:
+			cols.Labels[label] = column
+		}
+	}
+	return cols, nil
+}
+
+// WithBoardColumns makes the bot move the issues in the project of the board
+// between its columns as their deadline approaches. Issues that aren't in the
+// project are left alone.
+func WithBoardColumns(cols BoardColumns) Option {
+	if cols.Field == "" {
+		cols.Field = "Status"
+	}
+	return func(o *options) { o.columns = &cols }
+}
+
+// checkBoardColumn moves the issue to the column of the board for its
+// deadline, zero if it has none, and its deadline label, if it's not there
+// already.
+func (c *InstallationClient) checkBoardColumn(ctx context.Context, issue *issue, deadline time.Time, label string) error {
+	cols := c.opts.columns
+	if c.opts.board == nil || cols == nil {
+		return nil
+	}
+	column := cols.Default
+	switch {
+	case c.overdue(deadline) && cols.Overdue != "":
+		column = cols.Overdue
+	case cols.Labels[label] != "":
+		column = cols.Labels[label]
+	}
+	if column == "" {
+		return nil
+	}
+
+	owner, repo, number := issue.repo.owner, issue.repo.name, issue.number
+	key := storage.Key("projectcolumn", c.appID, c.installationID, strings.ToLower(owner), strings.ToLower(repo), number)
+	var last string
+	if err := c.opts.store.Get(ctx, key, &last); err != nil && err != storage.ErrNotFound {
+		return errors.Wrap(err, "could not fetch project column")
+	}
+	if last == column {
+		return nil
+	}
+	mkey := missingKey(c.appID, c.installationID, owner, repo, number)
+	var missing time.Time
+	if err := c.opts.store.Get(ctx, mkey, &missing); err != nil && err != storage.ErrNotFound {
+		return errors.Wrap(err, "could not fetch missing project item")
+	}
+	if time.Since(missing) < missingRecheck {
+		return nil
+	}
+
+	logrus.Debugf("moving %s/%s#%d to %s in project %s", owner, repo, number, column, c.opts.board.Project)
+	found, err := c.client.moveProjectItem(ctx, owner, repo, number, c.opts.board.Project, cols.Field, column)
+	if err != nil {
+		// a column missing from the project shouldn't stop the scan.
+		logrus.Warnf("could not move %s/%s#%d to %s: %v", owner, repo, number, column, err)
+		return nil
+	}
+	if !found {
+		return errors.Wrap(c.opts.store.Put(ctx, mkey, time.Now()), "could not store missing project item")
+	}
+	return errors.Wrap(c.opts.store.Put(ctx, key, column), "could not store project column")
+}
@@ -0,0 +1,176 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestParseProject(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected Project
+		err      bool
+	}{
+		{"acme/5", Project{Owner: "acme", Number: 5}, false},
+		{"classic:1234", Project{ClassicID: 1234}, false},
+		{"acme", Project{}, true},
+		{"acme/five", Project{}, true},
+		{"/5", Project{}, true},
+		{"classic:", Project{}, true},
+	}
+	for _, tt := range tests {
+		p, err := ParseProject(tt.in)
+		if (err != nil) != tt.err || p != tt.expected {
+			t.Errorf("expected %q to be %+v (error %v); got %+v (%v)", tt.in, tt.expected, tt.err, p, err)
+		}
+		if err == nil && p.String() != tt.in {
+			t.Errorf("expected %+v to be written as %q; got %q", p, tt.in, p)
+		}
+	}
+}
+
+func TestProjectBoard(t *testing.T) {
+	var added []string
+	ic := InstallationClient{appID: 42, installationID: 43,
+		opts: newOptions([]Option{WithProjectBoard(ProjectBoard{Project: Project{Owner: "acme", Number: 5}, Within: 7 * 24 * time.Hour})}),
+		client: &fakeClient{
+			_addToProject: func(ctx context.Context, owner, repo string, number int, p Project) error {
+				added = append(added, p.String())
+				return nil
+			},
+		}}
+
+	i := &issue{repo: repository{"foo", "bar"}, number: 1}
+	check := func(deadline time.Time, expected int) {
+		if err := ic.checkProjectBoard(context.Background(), i, deadline); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(added) != expected {
+			t.Fatalf("expected the issue to be added %d times; got %v", expected, added)
+		}
+	}
+
+	check(time.Time{}, 0)
+	check(time.Now().AddDate(0, 0, 10), 0)
+	check(time.Now().AddDate(0, 0, 5), 1)
+	// issues removed from the project by hand aren't added again.
+	check(time.Now().AddDate(0, 0, 4), 1)
+	check(time.Now().AddDate(0, 0, -1), 1)
+
+	ic.opts.board.Project = Project{ClassicID: 1234}
+	check(time.Now().AddDate(0, 0, -1), 2)
+	if added[1] != "classic:1234" {
+		t.Errorf("expected the issue to be added to the new project; got %v", added)
+	}
+}
+
+func TestParseBoardColumns(t *testing.T) {
+	cols, err := ParseBoardColumns("none=Backlog;deadline < 7=This Week; deadline < 1 = Today;overdue=Overdue")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cols.Default != "Backlog" || cols.Overdue != "Overdue" || len(cols.Labels) != 2 ||
+		cols.Labels["deadline < 7"] != "This Week" || cols.Labels["deadline < 1"] != "Today" {
+		t.Errorf("unexpected columns %+v", cols)
+	}
+	for _, s := range []string{"Backlog", "=Backlog", "none=", "none=Backlog;"} {
+		if _, err := ParseBoardColumns(s); err == nil {
+			t.Errorf("expected an error parsing %q", s)
+		}
+	}
+}
+
+func TestBoardColumn(t *testing.T) {
+	var moves []string
+	found := true
+	cols, _ := ParseBoardColumns("none=Backlog;deadline < 7=This Week;deadline < 1=Today;overdue=Overdue")
+	ic := InstallationClient{appID: 42, installationID: 43,
+		opts: newOptions([]Option{
+			WithProjectBoard(ProjectBoard{Project: Project{Owner: "acme", Number: 5}, Within: 7 * 24 * time.Hour}),
+			WithBoardColumns(cols),
+		}),
+		client: &fakeClient{
+			_moveProjectItem: func(ctx context.Context, owner, repo string, number int, p Project, field, column string) (bool, error) {
+				if field != "Status" {
+					t.Errorf("expected the Status field; got %s", field)
+				}
+				moves = append(moves, column)
+				return found, nil
+			},
+		}}
+
+	i := &issue{repo: repository{"foo", "bar"}, number: 1}
+	check := func(deadline time.Time, label string, expected ...string) {
+		if err := ic.checkBoardColumn(context.Background(), i, deadline, label); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if fmt.Sprint(moves) != fmt.Sprint(expected) {
+			t.Fatalf("expected moves %v; got %v", expected, moves)
+		}
+	}
+
+	// issues that aren't in the project are only looked for again a day
+	// later, or once added to it.
+	found = false
+	check(time.Now().AddDate(0, 0, 10), "", "Backlog")
+	found = true
+	check(time.Now().AddDate(0, 0, 10), "", "Backlog")
+	ic.opts.store.Put(context.Background(), missingKey(42, 43, "foo", "bar", 1), time.Now().Add(-missingRecheck))
+	check(time.Now().AddDate(0, 0, 10), "", "Backlog", "Backlog")
+	check(time.Now().AddDate(0, 0, 10), "", "Backlog", "Backlog")
+	check(time.Now().AddDate(0, 0, 5), "deadline < 7", "Backlog", "Backlog", "This Week")
+	check(time.Now().Add(time.Hour), "deadline < 1", "Backlog", "Backlog", "This Week", "Today")
+	check(time.Now().AddDate(0, 0, -1), "deadline < 1", "Backlog", "Backlog", "This Week", "Today", "Overdue")
+
+	// columns missing from the project don't fail the scan.
+	ic.client.(*fakeClient)._moveProjectItem = func(ctx context.Context, owner, repo string, number int, p Project, field, column string) (bool, error) {
+		return true, fmt.Errorf("the field %s of the project has no option %s", field, column)
+	}
+	if err := ic.checkBoardColumn(context.Background(), i, time.Now().AddDate(0, 0, 10), ""); err != nil {
+		t.Errorf("expected a missing column not to fail; got %v", err)
+	}
+}
+
+func TestMoveProjectCard(t *testing.T) {
+	var moved []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/projects/1234/columns":
+			fmt.Fprint(w, `[{"id": 1, "name": "Backlog"}, {"id": 2, "name": "This Week"}]`)
+		case "/projects/columns/1/cards":
+			fmt.Fprint(w, `[{"id": 10, "content_url": "https://api.github.com/repos/foo/bar/issues/2"},
+				{"id": 11, "content_url": "https://api.github.com/repos/Foo/bar/issues/1"}]`)
+		case "/projects/columns/2/cards":
+			fmt.Fprint(w, `[]`)
+		case "/projects/columns/cards/11/moves":
+			moved = append(moved, r.URL.Path)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	base, _ := url.Parse(srv.URL + "/")
+	c := &githubClient{client: newGitHubClient(srv.Client(), base)}
+	found, err := c.moveProjectItem(context.Background(), "foo", "bar", 1, Project{ClassicID: 1234}, "Status", "this week")
+	if err != nil || !found {
+		t.Fatalf("expected the card to be found; got %v (%v)", found, err)
+	}
+	if len(moved) != 1 {
+		t.Errorf("expected the card to be moved; got %v", moved)
+	}
+	found, err = c.moveProjectItem(context.Background(), "foo", "bar", 3, Project{ClassicID: 1234}, "Status", "This Week")
+	if err != nil || found {
+		t.Errorf("expected no card for other issues; got %v (%v)", found, err)
+	}
+	if _, err = c.moveProjectItem(context.Background(), "foo", "bar", 1, Project{ClassicID: 1234}, "Status", "Done"); err == nil {
+		t.Errorf("expected an error moving to an unknown column")
+	}
+}
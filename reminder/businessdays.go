@@ -0,0 +1,81 @@
+package reminder
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+// WithBusinessDays makes the deadline labels count business days, skipping
+// weekends, instead of calendar days in the repositories that didn't choose
+// either with /reminder days. A deadline on Monday is then labeled
+// "deadline < 2" on Friday.
+func WithBusinessDays() Option {
+	return func(o *options) { o.businessDays = true }
+}
+
+// The ways of counting the days left until a deadline.
+const (
+	calendarDays = "calendar"
+	businessDays = "business"
+)
+
+// repoDays records who chose how the days are counted in a repository.
+type repoDays struct {
+	Business bool      `json:"business"`
+	By       string    `json:"by"`
+	Time     time.Time `json:"time"`
+}
+
+func daysKey(appID, installationID int, owner, repo string) string {
+	return storage.Key("days", appID, installationID, strings.ToLower(owner), strings.ToLower(repo))
+}
+
+// BusinessDays checks whether the deadline labels of a repository count business days.
+func (c *InstallationClient) BusinessDays(ctx context.Context, owner, repo string) (bool, error) {
+	var rd repoDays
+	err := c.opts.store.Get(ctx, daysKey(c.appID, c.installationID, owner, repo), &rd)
+	if err == storage.ErrNotFound {
+		return c.opts.businessDays, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "could not fetch days of %s/%s", owner, repo)
+	}
+	return rd.Business, nil
+}
+
+// daysUntil returns the days left from now until the deadline, negative once
+// it's passed. Business days skip Saturdays and Sundays in the deadline's timezone.
+func daysUntil(now, deadline time.Time, business bool) float64 {
+	if !business {
+		return deadline.Sub(now).Hours() / 24
+	}
+
+	return (businessTime(deadline) - businessTime(now.In(deadline.Location()))).Hours() / 24
+}
+
+// businessEpoch is a Monday the business time is counted from.
+var businessEpoch = time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// businessTime returns the business time from businessEpoch until t, in the
+// timezone of t. The whole days are counted from their dates, five of every
+// seven, and those of the weekend last nothing.
+func businessTime(t time.Time) time.Duration {
+	date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(date.Sub(businessEpoch).Hours() / 24)
+	weeks, weekday := days/7, days%7
+	if weekday < 0 {
+		weeks, weekday = weeks-1, weekday+7
+	}
+
+	d := time.Duration(weeks*5) * 24 * time.Hour
+	if weekday < 5 {
+		midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+		return d + time.Duration(weekday)*24*time.Hour + t.Sub(midnight)
+	}
+	return d + 5*24*time.Hour
+}
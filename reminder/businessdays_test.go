@@ -0,0 +1,47 @@
+package reminder
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBusinessDays(t *testing.T) {
+	// a Friday at noon.
+	now := time.Date(2018, 8, 3, 12, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2018, 8, d, 0, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		deadline           time.Time
+		calendar, business float64
+	}{
+		{day(4), 0.5, 0.5},
+		{day(6), 2.5, 0.5},
+		{day(7), 3.5, 1.5},
+		{day(13), 9.5, 5.5},
+		{day(1), -2.5, -2.5},
+		{time.Date(2019, 8, 2, 12, 0, 0, 0, time.UTC), 364, 260},
+	}
+	for _, tt := range tests {
+		if got := daysUntil(now, tt.deadline, false); got != tt.calendar {
+			t.Errorf("%v: expected %v calendar days; got %v", tt.deadline, tt.calendar, got)
+		}
+		if got := daysUntil(now, tt.deadline, true); got != tt.business {
+			t.Errorf("%v: expected %v business days; got %v", tt.deadline, tt.business, got)
+		}
+	}
+
+	ctx := context.Background()
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions([]Option{WithBusinessDays()}), client: &fakeClient{
+		_permission:         func(ctx context.Context, owner, repo, user string) (string, error) { return "admin", nil },
+		_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error { return nil },
+	}}
+	if business, err := ic.BusinessDays(ctx, "foo", "bar"); err != nil || !business {
+		t.Errorf("expected business days by default; got %v (%v)", business, err)
+	}
+	if ok, err := ic.HandleComment(ctx, "foo", "bar", 1, "admin", "/reminder days calendar"); !ok || err != nil {
+		t.Fatalf("expected command to run; got %v, %v", ok, err)
+	}
+	if business, err := ic.BusinessDays(ctx, "Foo", "Bar"); err != nil || business {
+		t.Errorf("expected calendar days in the repository; got %v (%v)", business, err)
+	}
+}
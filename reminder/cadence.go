@@ -0,0 +1,125 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/src-d/github-reminder/notify"
+)
+
+// cadenceMarker is hidden in cadence reminder comments to find them later.
+const cadenceMarker = "<!-- github-reminder:cadence -->"
+
+// A Cadence configures periodic reminders as the deadline of issues with a
+// given label, usually a severity like sev1, approaches.
+type Cadence struct {
+	// Label is the label issues need to have for the cadence to apply.
+	Label string
+	// Before is how long before the deadline reminders start.
+	Before time.Duration
+	// Every is the time between reminders, zero for a single reminder.
+	Every time.Duration
+}
+
+// ParseCadence parses a cadence written as label:before[:every], using Go
+// durations, like sev1:168h:24h for daily reminders during the last week.
+func ParseCadence(s string) (Cadence, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" {
+		return Cadence{}, errors.Errorf("bad cadence %q, expected label:before[:every]", s)
+	}
+	c := Cadence{Label: parts[0]}
+	var err error
+	if c.Before, err = time.ParseDuration(parts[1]); err != nil {
+		return Cadence{}, errors.Wrapf(err, "bad cadence %q", s)
+	}
+	if len(parts) == 3 {
+		if c.Every, err = time.ParseDuration(parts[2]); err != nil {
+			return Cadence{}, errors.Wrapf(err, "bad cadence %q", s)
+		}
+	}
+	return c, nil
+}
+
+// String writes the cadence as parsed by ParseCadence.
+func (c Cadence) String() string {
+	s := c.Label + ":" + c.Before.String()
+	if c.Every > 0 {
+		s += ":" + c.Every.String()
+	}
+	return s
+}
+
+// WithCadences configures reminders by label. When an issue has the labels
+// of several cadences, the first one given applies.
+func WithCadences(cs ...Cadence) Option {
+	return func(o *options) { o.cadences = append(o.cadences, cs...) }
+}
+
+// cadence returns the cadence applying to the issue, if any.
+func (c *InstallationClient) cadence(issue *issue) *Cadence {
+	for i, cd := range c.opts.cadences {
+		for _, l := range issue.labels {
+			if strings.EqualFold(l, cd.Label) {
+				return &c.opts.cadences[i]
+			}
+		}
+	}
+	return nil
+}
+
+// checkCadence posts a reminder about the deadline if the cadence of the issue requires it.
+func (c *InstallationClient) checkCadence(ctx context.Context, issue *issue, deadline time.Time) error {
+	cd := c.cadence(issue)
+	if cd == nil || (c.opts.digestIssue != nil && c.opts.digestIssue.Only) {
+		return nil
+	}
+
+	now := time.Now()
+	start := deadline.Add(-cd.Before)
+	if now.Before(start) || !now.Before(deadline) {
+		return nil
+	}
+	if snoozed, err := c.snoozed(ctx, issue); err != nil || snoozed {
+		return err
+	}
+
+	var last time.Time
+	for _, comment := range issue.comments {
+		if comment.author == c.login() && strings.Contains(comment.body, cadenceMarker) && comment.created.After(last) {
+			last = comment.created
+		}
+	}
+	if !last.IsZero() && !last.Before(start) {
+		if cd.Every <= 0 {
+			return nil
+		}
+		// cron runs are not exactly periodic, allow some slack.
+		if now.Sub(last) < cd.Every-time.Hour {
+			return nil
+		}
+	}
+
+	days := int(time.Until(deadline).Hours() / 24)
+	due := fmt.Sprintf("this %s issue is due", cd.Label)
+	if issue.checkpoint != "" {
+		due = fmt.Sprintf("the %s checkpoint of this %s issue is due", issue.checkpoint, cd.Label)
+	}
+	data := issue.templateData(issue.author)
+	data.Label = cd.Label
+	text := c.render(issue, "nag", c.opts.templates.Nag, data,
+		fmt.Sprintf("hi @%s, %s in %d days, on %s.", issue.author, due, days, deadline.Format("January 2")))
+	text = fmt.Sprintf("%s\n%s", text, cadenceMarker)
+	if err := c.postReminder(ctx, issue, issue.author, text); err != nil {
+		return err
+	}
+
+	e := issue.event(notify.Reminder, text)
+	e.User, e.Deadline = issue.author, deadline
+	c.notify(ctx, e, issue.policy)
+	return nil
+}
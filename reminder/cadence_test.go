@@ -0,0 +1,54 @@
+package reminder
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCadence(t *testing.T) {
+	now := time.Now()
+	cadences := []Cadence{
+		{Label: "sev1", Before: 7 * 24 * time.Hour, Every: 24 * time.Hour},
+		{Label: "sev3", Before: 2 * 24 * time.Hour},
+	}
+	tests := []struct {
+		name     string
+		label    string
+		deadline time.Time
+		last     time.Time
+		expected bool
+	}{
+		{"too early", "sev1", now.AddDate(0, 0, 10), time.Time{}, false},
+		{"first reminder", "sev1", now.AddDate(0, 0, 5), time.Time{}, true},
+		{"reminded today", "sev1", now.AddDate(0, 0, 5), now.Add(-2 * time.Hour), false},
+		{"reminded yesterday", "sev1", now.AddDate(0, 0, 5), now.Add(-24 * time.Hour), true},
+		{"single reminder", "sev3", now.AddDate(0, 0, 1), time.Time{}, true},
+		{"single reminder done", "sev3", now.AddDate(0, 0, 1), now.Add(-24 * time.Hour), false},
+		{"no cadence", "sev2", now.AddDate(0, 0, 1), time.Time{}, false},
+		{"past deadline", "sev1", now.AddDate(0, 0, -1), time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commented := false
+			ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions([]Option{WithCadences(cadences...)}), client: &fakeClient{
+				_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error {
+					commented = true
+					return nil
+				},
+			}}
+
+			i := &issue{repo: repository{"foo", "bar"}, number: 1, author: "francesc", labels: []string{tt.label}}
+			if !tt.last.IsZero() {
+				i.comments = []comment{{author: botLogin, body: "reminder\n" + cadenceMarker, created: tt.last}}
+			}
+			if err := ic.checkCadence(context.Background(), i, tt.deadline); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if commented != tt.expected {
+				t.Errorf("expected comment to be %v; got %v", tt.expected, commented)
+			}
+		})
+	}
+}
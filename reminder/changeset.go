@@ -0,0 +1,156 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+// A Changeset holds the mutations computed for a repository that are waiting
+// for an operator's approval before being applied, along with the calls to
+// the pager and the store and the events that follow them.
+type Changeset struct {
+	AppID          int            `json:"app_id"`
+	InstallationID int            `json:"installation_id"`
+	Owner          string         `json:"owner"`
+	Repo           string         `json:"repo"`
+	Created        time.Time      `json:"created"`
+	Mutations      []Mutation     `json:"mutations"`
+	Deferred       []Deferred     `json:"deferred,omitempty"`
+	Events         []PendingEvent `json:"events,omitempty"`
+}
+
+// WithApprovalThreshold holds the changes of repository scans performing
+// more than n mutations until they're approved.
+func WithApprovalThreshold(n int) Option {
+	return func(o *options) { o.approvalThreshold = n }
+}
+
+func changesetKey(appID, installationID int, owner, repo string) string {
+	return storage.Key("changeset", appID, installationID, owner, repo)
+}
+
+// Changesets lists all of the pending changesets in the given store.
+func Changesets(ctx context.Context, store storage.Store) ([]Changeset, error) {
+	keys, err := store.List(ctx, "changeset/")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list changesets")
+	}
+
+	var res []Changeset
+	for _, key := range keys {
+		var cs Changeset
+		if err := store.Get(ctx, key, &cs); err != nil {
+			return nil, errors.Wrapf(err, "could not fetch changeset %s", key)
+		}
+		res = append(res, cs)
+	}
+	return res, nil
+}
+
+// hold stores what rec recorded as the pending changeset for a repository.
+func (c *InstallationClient) hold(ctx context.Context, owner, repo string, rec *recorder) error {
+	logrus.Warnf("holding %d mutations for %s/%s until approved", len(rec.mutations), owner, repo)
+	cs := Changeset{
+		AppID:          c.appID,
+		InstallationID: c.installationID,
+		Owner:          owner,
+		Repo:           repo,
+		Created:        time.Now(),
+		Mutations:      rec.mutations,
+		Deferred:       rec.deferred,
+		Events:         rec.events,
+	}
+	err := c.opts.store.Put(ctx, changesetKey(c.appID, c.installationID, owner, repo), cs)
+	return errors.Wrap(err, "could not store changeset")
+}
+
+// PendingChangeset returns the changeset waiting for approval for the given repository, or nil if none.
+func (c *InstallationClient) PendingChangeset(ctx context.Context, owner, repo string) (*Changeset, error) {
+	var cs Changeset
+	err := c.opts.store.Get(ctx, changesetKey(c.appID, c.installationID, owner, repo), &cs)
+	if err == storage.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch changeset")
+	}
+	return &cs, nil
+}
+
+// ApproveChangeset applies the pending changeset of a repository and discards it,
+// paging and delivering its events with the configuration of the repository.
+// It returns the number of mutations applied.
+func (c *InstallationClient) ApproveChangeset(ctx context.Context, owner, repo string) (int, error) {
+	c, err := c.forRepo(ctx, owner, repo)
+	if err != nil {
+		return 0, err
+	}
+	return c.approve(ctx, owner, repo)
+}
+
+// approve applies the pending changeset of a repository with the options of c,
+// which are already those of the repository.
+func (c *InstallationClient) approve(ctx context.Context, owner, repo string) (int, error) {
+	cs, err := c.PendingChangeset(ctx, owner, repo)
+	if err != nil {
+		return 0, err
+	}
+	if cs == nil {
+		return 0, nil
+	}
+
+	logrus.Infof("applying %d approved mutations for %s/%s", len(cs.Mutations), owner, repo)
+	if err := c.RejectChangeset(ctx, owner, repo); err != nil {
+		return 0, err
+	}
+	if err := c.apply(ctx, cs.Mutations); err != nil {
+		return len(cs.Mutations), err
+	}
+	return len(cs.Mutations), c.runDeferred(ctx, cs.Deferred, cs.Events)
+}
+
+// RejectChangeset discards the pending changeset of a repository.
+func (c *InstallationClient) RejectChangeset(ctx context.Context, owner, repo string) error {
+	err := c.opts.store.Delete(ctx, changesetKey(c.appID, c.installationID, owner, repo))
+	return errors.Wrap(err, "could not delete changeset")
+}
+
+// approveCommand applies the pending changeset for the repository where it's written.
+// Only users with write permissions on the repository can approve changesets.
+func approveCommand(ctx context.Context, c *InstallationClient, cmd Command) error {
+	allowed, err := c.canWrite(ctx, cmd.Owner, cmd.Repo, cmd.Author)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		logrus.Warnf("%s can not approve changesets in %s/%s", cmd.Author, cmd.Owner, cmd.Repo)
+		return nil
+	}
+
+	n, err := c.approve(ctx, cmd.Owner, cmd.Repo)
+	if err != nil {
+		return err
+	}
+	text := "there are no pending changes to approve."
+	if n > 0 {
+		text = fmt.Sprintf("applied %d pending changes.", n)
+	}
+	return c.client.createIssueComment(ctx, cmd.Owner, cmd.Repo, cmd.Number, fmt.Sprintf("@%s %s", cmd.Author, text))
+}
+
+// canWrite checks whether the user has write or admin permissions on the repository.
+func (c *InstallationClient) canWrite(ctx context.Context, owner, repo, user string) (bool, error) {
+	perm, err := c.client.permission(ctx, owner, repo, user)
+	if err != nil {
+		return false, errors.Wrapf(err, "could not check permissions for %s", user)
+	}
+	perm = strings.ToLower(perm)
+	return perm == "admin" || perm == "write", nil
+}
@@ -0,0 +1,188 @@
+package reminder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/src-d/github-reminder/notify"
+)
+
+func TestHoldAndApproveChangeset(t *testing.T) {
+	var comments []string
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions([]Option{WithApprovalThreshold(1)}), client: &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) { return []string{"deadline < 5"}, nil },
+		_issues:     func(ctx context.Context, owner, repo string) ([]int, error) { return []int{1, 2}, nil },
+		_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+			return &issue{
+				repo:   repository{owner, repo},
+				number: number,
+				author: "francesc",
+				state:  "open",
+				body:   fmt.Sprintf("reminder: %s\n", time.Now().Format("2006-01-02")),
+			}, nil
+		},
+		_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error {
+			comments = append(comments, body)
+			return nil
+		},
+		_removeIssueLabel: func(ctx context.Context, owner, repo string, number int, label string) error { return nil },
+		_permission:       func(ctx context.Context, owner, repo, user string) (string, error) { return "write", nil },
+	}}
+
+	ctx := context.Background()
+	if err := ic.UpdateRepo(ctx, "foo", "bar"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(comments) != 0 {
+		t.Fatalf("expected no comments before approval; got %v", comments)
+	}
+	cs, err := ic.PendingChangeset(ctx, "foo", "bar")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cs == nil || len(cs.Mutations) != 2 {
+		t.Fatalf("expected a changeset with two mutations; got %v", cs)
+	}
+
+	ok, err := ic.HandleComment(ctx, "foo", "bar", 1, "admin", "/approve")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("expected /approve to be handled")
+	}
+	if len(comments) != 3 {
+		t.Fatalf("expected two reminders and a confirmation; got %v", comments)
+	}
+	if cs, _ := ic.PendingChangeset(ctx, "foo", "bar"); cs != nil {
+		t.Errorf("expected changeset to be discarded after approval")
+	}
+}
+
+func TestApproveChangesetNotifies(t *testing.T) {
+	var events []notify.Kind
+	pager := &fakePager{}
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions([]Option{
+		WithApprovalThreshold(1),
+		WithNotifier(notify.NotifierFunc(func(ctx context.Context, e notify.Event) error {
+			events = append(events, e.Kind)
+			return nil
+		})),
+		WithEscalation(Escalation{Labels: []string{"Sev1"}, Pager: pager}),
+	}), client: &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) { return []string{"deadline < 5"}, nil },
+		_issues:     func(ctx context.Context, owner, repo string) ([]int, error) { return []int{1, 2}, nil },
+		_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+			return &issue{
+				repo:   repository{owner, repo},
+				number: number,
+				author: "francesc",
+				state:  "open",
+				labels: []string{"Sev1"},
+				body:   fmt.Sprintf("reminder: %s\ndeadline: 2018-01-01\n", time.Now().Format("2006-01-02")),
+			}, nil
+		},
+		_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error { return nil },
+		_addIssueLabel:      func(ctx context.Context, owner, repo string, number int, label string) error { return nil },
+		_removeIssueLabel:   func(ctx context.Context, owner, repo string, number int, label string) error { return nil },
+		_replaceIssueLabels: func(ctx context.Context, owner, repo string, number int, labels []string) error {
+			return nil
+		},
+	}}
+
+	ctx := context.Background()
+	if err := ic.UpdateRepo(ctx, "foo", "bar"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 0 || len(pager.calls) != 0 {
+		t.Fatalf("expected no events nor incidents before approval; got %v and %v", events, pager.calls)
+	}
+
+	if _, err := ic.ApproveChangeset(ctx, "foo", "bar"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []notify.Kind{notify.Reminder, notify.Reminder}; fmt.Sprint(events) != fmt.Sprint(expected) {
+		t.Errorf("expected the held events %v to be delivered; got %v", expected, events)
+	}
+	if expected := []string{"trigger github-reminder/foo/bar#1", "trigger github-reminder/foo/bar#2"}; fmt.Sprint(pager.calls) != fmt.Sprint(expected) {
+		t.Errorf("expected the held incidents %v to be opened; got %v", expected, pager.calls)
+	}
+	var open bool
+	if err := ic.opts.store.Get(ctx, escalationKey(42, 43, "foo", "bar", 1), &open); err != nil || !open {
+		t.Errorf("expected the incident to be stored once opened; got %v (%v)", open, err)
+	}
+}
+
+func TestRecordEveryWrite(t *testing.T) {
+	ctx := context.Background()
+	var calls []string
+	call := func(s string) error {
+		calls = append(calls, s)
+		return nil
+	}
+	fc := &fakeClient{
+		_createLabel: func(ctx context.Context, owner, repo, label, color string) error {
+			return call("create " + label + " " + color)
+		},
+		_editLabelColor: func(ctx context.Context, owner, repo, label, color string) error {
+			return call("color " + label + " " + color)
+		},
+		_createIssue: func(ctx context.Context, owner, repo, title, body string) (int, error) {
+			return 7, call("open " + title)
+		},
+		_editIssueBody: func(ctx context.Context, owner, repo string, number int, body string) error {
+			return call("edit " + body)
+		},
+		_pinIssue: func(ctx context.Context, owner, repo string, number int) error {
+			return call(fmt.Sprint("pin ", number))
+		},
+		_createCheckRun: func(ctx context.Context, owner, repo string, run checkRun) error {
+			return call("check " + run.conclusion)
+		},
+		_createStatus: func(ctx context.Context, owner, repo string, s commitStatus) error { return call("status " + s.state) },
+		_setProjectDate: func(ctx context.Context, owner, repo string, number int, field string, date time.Time) error {
+			return call("date " + date.Format("2006-01-02"))
+		},
+		_addToProject: func(ctx context.Context, owner, repo string, number int, p Project) error {
+			return call("add " + p.String())
+		},
+		_moveProjectItem: func(ctx context.Context, owner, repo string, number int, p Project, field, column string) (bool, error) {
+			return true, call("move " + column)
+		},
+	}
+	ic := &InstallationClient{appID: 42, installationID: 43, client: fc, opts: newOptions(nil)}
+	scan, rec := ic.recording()
+	p := Project{Owner: "acme", Number: 5}
+	scan.client.createLabel(ctx, "foo", "bar", "deadline < 1", "ff0000")
+	scan.client.editLabelColor(ctx, "foo", "bar", "deadline < 1", "00ff00")
+	scan.openIssue(ctx, "foo", "bar", "Digest", "body", "digestissue/42/43/foo/bar", true)
+	scan.client.editIssueBody(ctx, "foo", "bar", 1, "new")
+	scan.client.createCheckRun(ctx, "foo", "bar", checkRun{sha: "abc", conclusion: "failure"})
+	scan.client.createStatus(ctx, "foo", "bar", commitStatus{sha: "abc", state: "pending"})
+	scan.client.setProjectDate(ctx, "foo", "bar", 1, "Due", time.Date(2018, 8, 1, 0, 0, 0, 0, time.UTC))
+	scan.client.addToProject(ctx, "foo", "bar", 1, p)
+	scan.client.moveProjectItem(ctx, "foo", "bar", 1, p, "Status", "Today")
+	if len(calls) != 0 {
+		t.Fatalf("expected the writes to be recorded; got %v", calls)
+	}
+
+	// held mutations are stored as JSON before being applied.
+	b, _ := json.Marshal(rec.mutations)
+	var ms []Mutation
+	json.Unmarshal(b, &ms)
+	if err := ic.apply(ctx, ms); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"create deadline < 1 ff0000", "color deadline < 1 00ff00", "open Digest", "pin 7", "edit new",
+		"check failure", "status pending", "date 2018-08-01", "add acme/5", "move Today"}
+	if fmt.Sprint(calls) != fmt.Sprint(expected) {
+		t.Errorf("expected the recorded writes %v to be applied; got %v", expected, calls)
+	}
+	var number int
+	if err := ic.opts.store.Get(ctx, "digestissue/42/43/foo/bar", &number); err != nil || number != 7 {
+		t.Errorf("expected the number of the issue opened to be stored; got %d (%v)", number, err)
+	}
+}
@@ -0,0 +1,77 @@
+package reminder
+
+import (
+	"strings"
+	"time"
+)
+
+// A checkpoint is one of the deadlines of an issue, named like "design" in
+// "deadline(design): 2018-08-01", or unnamed. Its time is zero if it was
+// cleared, as in "deadline: none".
+type checkpoint struct {
+	name string
+	time time.Time
+}
+
+// clearedDeadline reports whether the text after a deadline keyword clears it.
+func clearedDeadline(s string) bool {
+	switch strings.TrimSpace(strings.Trim(strings.TrimSpace(s), ":")) {
+	case "none", "cancelled", "canceled":
+		return true
+	}
+	return false
+}
+
+// findCheckpoints returns the checkpoints following the given keyword in a
+// body written at the given time.
+func (g Grammar) findCheckpoints(word, body string, created time.Time, d dates) []checkpoint {
+	var cps []checkpoint
+	for _, v := range g.findValues(word, body) {
+		var name string
+		if strings.HasPrefix(v, "(") {
+			i := strings.Index(v, ")")
+			if i < 0 {
+				continue
+			}
+			name, v = strings.TrimSpace(v[1:i]), v[i+1:]
+		}
+		if clearedDeadline(v) {
+			cps = append(cps, checkpoint{name: name})
+		} else if t := d.parse(v, created); !t.IsZero() {
+			cps = append(cps, checkpoint{name, t})
+		}
+	}
+	return cps
+}
+
+// nextCheckpoint returns the nearest checkpoint that hasn't passed yet, taking
+// the last one written for each name, or the last to pass if all of them did.
+// It's zero if all of them were cleared.
+func (c *InstallationClient) nextCheckpoint(cps []checkpoint, now time.Time) checkpoint {
+	latest := make(map[string]checkpoint)
+	for _, cp := range cps {
+		latest[cp.name] = cp
+	}
+
+	var next, last checkpoint
+	for _, cp := range latest {
+		if cp.time.IsZero() {
+			continue
+		}
+		end := cp.time
+		if c.allDay(end) {
+			end = end.AddDate(0, 0, 1)
+		}
+		if end.After(now) {
+			if next.time.IsZero() || cp.time.Before(next.time) || (cp.time.Equal(next.time) && cp.name < next.name) {
+				next = cp
+			}
+		} else if cp.time.After(last.time) || (cp.time.Equal(last.time) && cp.name < last.name) {
+			last = cp
+		}
+	}
+	if !next.time.IsZero() {
+		return next
+	}
+	return last
+}
@@ -0,0 +1,78 @@
+package reminder
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCheckpoints(t *testing.T) {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	date := func(days int) string { return today.AddDate(0, 0, days).Format("2006-01-02") }
+
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil)}
+	tests := []struct {
+		body       string
+		comment    string
+		checkpoint string
+		days       int
+	}{
+		{"deadline(design): " + date(5) + "\ndeadline(ship): " + date(20), "", "design", 5},
+		{"deadline(design): " + date(-5) + "\ndeadline(ship): " + date(20), "", "ship", 20},
+		{"deadline(design): " + date(0) + "\ndeadline(ship): " + date(20), "", "design", 0},
+		{"deadline(design): " + date(-5) + "\ndeadline(ship): " + date(-2), "", "ship", -2},
+		{"deadline(design): " + date(5) + "\ndeadline(ship): " + date(20), "deadline(design): " + date(30), "ship", 20},
+		{"deadline: " + date(3), "", "", 3},
+	}
+	for _, tt := range tests {
+		i := &issue{body: tt.body}
+		if tt.comment != "" {
+			i.comments = []comment{{body: tt.comment}}
+		}
+		got, err := ic.deadline(context.Background(), i)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if expected := today.AddDate(0, 0, tt.days); !got.Equal(expected) || i.checkpoint != tt.checkpoint {
+			t.Errorf("%q: expected %s on %v; got %s on %v", tt.body, tt.checkpoint, expected, i.checkpoint, got)
+		}
+	}
+}
+
+func TestClearedDeadline(t *testing.T) {
+	var removed []string
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil), client: &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) {
+			return []string{"deadline < 5", "deadline < 30", "bug"}, nil
+		},
+		_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+			return &issue{
+				repo: repository{owner, repo}, number: number, state: "open",
+				body:     "deadline: " + time.Now().AddDate(0, 0, 3).Format("2006-01-02"),
+				labels:   []string{"bug", "deadline < 5"},
+				comments: []comment{{author: "francesc", body: "deadline: cancelled"}},
+			}, nil
+		},
+		_removeIssueLabel: func(ctx context.Context, owner, repo string, number int, label string) error {
+			removed = append(removed, label)
+			return nil
+		},
+		_addIssueLabel: func(ctx context.Context, owner, repo string, number int, label string) error {
+			t.Errorf("expected no label to be added; got %s", label)
+			return nil
+		},
+	}}
+
+	if err := ic.UpdateIssue(context.Background(), "foo", "bar", 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(removed) != 1 || removed[0] != "deadline < 5" {
+		t.Errorf("expected deadline label to be removed; got %v", removed)
+	}
+
+	i := &issue{body: "deadline(design): 2018-08-01\ndeadline(ship): 2018-08-20\ndeadline(ship): none"}
+	if got, err := ic.deadline(context.Background(), i); err != nil || i.checkpoint != "design" || i.cleared {
+		t.Errorf("expected only the ship checkpoint to be cleared; got %s on %v (%v)", i.checkpoint, got, err)
+	}
+}
@@ -0,0 +1,106 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+// checkRunName is the name of the check runs of the deadlines of the pull
+// requests, as shown in their merge box.
+const checkRunName = "deadline"
+
+// A checkRun is a completed check run on the head commit of a pull request.
+type checkRun struct {
+	sha        string
+	conclusion string
+	title      string
+	summary    string
+}
+
+// WithDeadlineChecks makes the bot report the deadline of every pull request
+// as a check run on its head commit, failing once it's overdue, so it's seen
+// in the merge box and can be required by branch protection.
+// Only GitHub Apps with write access to checks can create check runs.
+func WithDeadlineChecks() Option {
+	return func(o *options) { o.checks = true }
+}
+
+// checkRunState is the last check run created for a pull request.
+type checkRunState struct {
+	SHA        string `json:"sha"`
+	Conclusion string `json:"conclusion"`
+	Title      string `json:"title"`
+}
+
+// checkDeadlineRun creates a check run on the head commit of the pull request
+// reporting its deadline, zero if it has none, unless the same one was
+// already created. Pull requests that never had a deadline get none.
+// The head commit is only fetched when the webhook of the update didn't tell
+// it and the check run changed, since the webhooks of the pushes keep the
+// check runs of the new commits up to date.
+func (c *InstallationClient) checkDeadlineRun(ctx context.Context, issue *issue, deadline time.Time) error {
+	if !c.opts.checks || !issue.pullRequest {
+		return nil
+	}
+	owner, repo, number := issue.repo.owner, issue.repo.name, issue.number
+	key := storage.Key("checkrun", c.appID, c.installationID, strings.ToLower(owner), strings.ToLower(repo), number)
+	var state checkRunState
+	err := c.opts.store.Get(ctx, key, &state)
+	if err != nil && err != storage.ErrNotFound {
+		return errors.Wrap(err, "could not fetch check run")
+	}
+	if deadline.IsZero() && err == storage.ErrNotFound {
+		return nil
+	}
+
+	run := c.deadlineCheckRun(deadline, issue.checkpoint, time.Now())
+	run.sha = issue.head
+	if run.sha == "" {
+		if state.Conclusion == run.conclusion && state.Title == run.title {
+			return nil
+		}
+		if run.sha, err = c.client.headSHA(ctx, owner, repo, number); err != nil {
+			return err
+		}
+	}
+	if state == (checkRunState{SHA: run.sha, Conclusion: run.conclusion, Title: run.title}) {
+		return nil
+	}
+	logrus.Debugf("reporting deadline of %s/%s#%d as %s", owner, repo, number, run.conclusion)
+	if err := c.client.createCheckRun(ctx, owner, repo, run); err != nil {
+		return errors.Wrapf(err, "could not create check run on %s/%s#%d", owner, repo, number)
+	}
+	state = checkRunState{SHA: run.sha, Conclusion: run.conclusion, Title: run.title}
+	return errors.Wrap(c.opts.store.Put(ctx, key, state), "could not store check run")
+}
+
+// deadlineCheckRun returns the check run reporting the deadline at now,
+// neutral if it's zero. The deadlines without a time of day last all of it.
+func (c *InstallationClient) deadlineCheckRun(deadline time.Time, checkpoint string, now time.Time) checkRun {
+	if deadline.IsZero() {
+		return checkRun{conclusion: "neutral", title: "No deadline", summary: "This pull request has no deadline."}
+	}
+	due, date := deadline, deadline.Format("Monday, January 2 2006 15:04 MST")
+	if c.allDay(deadline) {
+		due, date = deadline.AddDate(0, 0, 1), deadline.Format("Monday, January 2 2006")
+	}
+	run := checkRun{conclusion: "success", title: daysLeft(due, now)}
+	if now.After(due) {
+		run.conclusion = "failure"
+	}
+	run.title = strings.ToUpper(run.title[:1]) + run.title[1:]
+
+	what := "This pull request"
+	if checkpoint != "" {
+		what = fmt.Sprintf("The %s checkpoint of this pull request", checkpoint)
+	}
+	run.summary = fmt.Sprintf("%s is due on %s.", what, date)
+	return run
+}
@@ -0,0 +1,76 @@
+package reminder
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDeadlineCheckRun(t *testing.T) {
+	sha, fetched := "abc", 0
+	var runs []checkRun
+	ic := InstallationClient{appID: 42, installationID: 43,
+		opts: newOptions([]Option{WithDeadlineChecks()}),
+		client: &fakeClient{
+			_headSHA: func(ctx context.Context, owner, repo string, number int) (string, error) {
+				fetched++
+				return sha, nil
+			},
+			_createCheckRun: func(ctx context.Context, owner, repo string, run checkRun) error {
+				runs = append(runs, run)
+				return nil
+			},
+		}}
+
+	i := &issue{repo: repository{"foo", "bar"}, number: 1, pullRequest: true}
+	check := func(deadline time.Time, expected int) {
+		if err := ic.checkDeadlineRun(context.Background(), i, deadline); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(runs) != expected {
+			t.Fatalf("expected %d check runs; got %+v", expected, runs)
+		}
+	}
+
+	check(time.Time{}, 0)
+	deadline := time.Now().Add(3*24*time.Hour + time.Hour)
+	check(deadline, 1)
+	if r := runs[0]; r.sha != "abc" || r.conclusion != "success" || r.title != "3 days left" {
+		t.Errorf("unexpected check run %+v", r)
+	}
+	check(deadline, 1)
+	if fetched != 1 {
+		t.Errorf("expected the head commit to be fetched only for the new check run; got %d", fetched)
+	}
+	// new commits get their own check run, on the commit of the webhook.
+	i.head = "def"
+	check(deadline, 2)
+	if r := runs[1]; r.sha != "def" || fetched != 1 {
+		t.Errorf("expected a check run on def without fetching it; got %+v", r)
+	}
+	check(deadline, 2)
+	i.head, sha = "", "def"
+
+	check(time.Now().Add(-3*24*time.Hour-time.Hour), 3)
+	if r := runs[2]; r.conclusion != "failure" || r.title != "Overdue by 3 days" {
+		t.Errorf("unexpected check run %+v", r)
+	}
+	check(time.Time{}, 4)
+	if r := runs[3]; r.conclusion != "neutral" {
+		t.Errorf("expected a neutral check run without a deadline; got %+v", r)
+	}
+
+	// issues don't have commits.
+	i = &issue{repo: repository{"foo", "bar"}, number: 2}
+	check(deadline, 4)
+}
+
+func TestDeadlineCheckRunAllDay(t *testing.T) {
+	ic := InstallationClient{opts: newOptions(nil)}
+	now := time.Date(2018, 8, 3, 15, 0, 0, 0, time.UTC)
+	run := ic.deadlineCheckRun(time.Date(2018, 8, 3, 0, 0, 0, 0, time.UTC), "docs", now)
+	if run.conclusion != "success" || run.title != "Due in less than a day" ||
+		run.summary != "The docs checkpoint of this pull request is due on Friday, August 3 2018." {
+		t.Errorf("expected an all-day deadline to last all of it; got %+v", run)
+	}
+}
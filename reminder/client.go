@@ -2,6 +2,12 @@ package reminder
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/go-github/github"
@@ -13,34 +19,103 @@ type repository struct {
 	name  string
 }
 
+// A labelEvent is a label applied to or removed from an issue.
+type labelEvent struct {
+	actor   string
+	label   string
+	added   bool
+	created time.Time
+}
+
 type comment struct {
+	id      int64
 	author  string
 	body    string
 	created time.Time
 }
 
 type issue struct {
-	repo     repository
-	number   int
-	title    string
-	body     string
-	author   string
-	state    string
-	comments []comment
+	repo      repository
+	number    int
+	title     string
+	body      string
+	author    string
+	state     string
+	locked    bool
+	created   time.Time
+	closed    time.Time
+	url       string
+	labels    []string
+	assignees []string
+	reactions int
+	comments  []comment
+	// grammar is the grammar of the repository, used to read the issue.
+	grammar Grammar
+	// language is the code of the language dates can be written in besides English.
+	language string
+	// synonyms are the synonyms of the keywords in the repository.
+	synonyms synonyms
+	// checkpoint is the name of the checkpoint its deadline belongs to, if any.
+	checkpoint string
+	// cleared is set when its deadline was cleared with "deadline: none".
+	cleared bool
+	// written is set when its deadline, or its clearing, was written in it
+	// rather than taken from its projects or imported.
+	written bool
+	// deadline is the deadline found in it, zero if none, once read.
+	deadline time.Time
+	// milestone is the due date of its milestone, zero if it has none or
+	// the milestone is closed.
+	milestone time.Time
+
+	// pullRequest is set when the issue is a pull request.
+	pullRequest bool
+	// head is the SHA of the head commit of the pull request, if known from
+	// the webhook that changed it.
+	head string
+	// policy is the path policy applying to the pull request, if any.
+	policy *PathPolicy
 }
 
 type client interface {
 	installations(ctx context.Context) ([]int, error)
+	repoInstallation(ctx context.Context, owner, repo string) (int, error)
 	repos(ctx context.Context) ([]repository, error)
 	repoLabels(ctx context.Context, owner, repo string) ([]string, error)
+	labelColors(ctx context.Context, owner, repo string) (map[string]string, error)
+	issueLabels(ctx context.Context, owner, repo string, number int) ([]string, error)
 	issues(ctx context.Context, owner, repo string) ([]int, error)
+	closedIssues(ctx context.Context, owner, repo string, page int) (numbers []int, next int, err error)
 	issue(ctx context.Context, owner, repo string, number int) (*issue, error)
 	createIssueComment(ctx context.Context, owner, repo string, number int, body string) error
+	createIssue(ctx context.Context, owner, repo, title, body string) (int, error)
+	editIssueBody(ctx context.Context, owner, repo string, number int, body string) error
+	pinIssue(ctx context.Context, owner, repo string, number int) error
+	headSHA(ctx context.Context, owner, repo string, number int) (string, error)
+	createCheckRun(ctx context.Context, owner, repo string, run checkRun) error
+	createStatus(ctx context.Context, owner, repo string, status commitStatus) error
 	removeIssueLabel(ctx context.Context, owner, repo string, number int, label string) error
 	addIssueLabel(ctx context.Context, owner, repo string, number int, label string) error
+	replaceIssueLabels(ctx context.Context, owner, repo string, number int, labels []string) error
+	editLabelColor(ctx context.Context, owner, repo, label, color string) error
+	createLabel(ctx context.Context, owner, repo, label, color string) error
+	minimizeComment(ctx context.Context, owner, repo string, id int64) error
+	projectDate(ctx context.Context, owner, repo string, number int, field string) (time.Time, error)
+	setProjectDate(ctx context.Context, owner, repo string, number int, field string, date time.Time) error
+	projectItems(ctx context.Context, owner, repo string, number int) ([]string, error)
+	addToProject(ctx context.Context, owner, repo string, number int, p Project) error
+	moveProjectItem(ctx context.Context, owner, repo string, number int, p Project, field, column string) (bool, error)
+	permission(ctx context.Context, owner, repo, user string) (string, error)
+	files(ctx context.Context, owner, repo string, number int) ([]string, error)
+	file(ctx context.Context, owner, repo, path string) ([]byte, error)
+	labelEvents(ctx context.Context, owner, repo string, number int) ([]labelEvent, error)
 }
 
-type githubClient struct{ client *github.Client }
+type githubClient struct {
+	client *github.Client
+	// user is set when authenticated as a user rather than as an installation.
+	user bool
+}
 
 func (c *githubClient) installations(ctx context.Context) ([]int, error) {
 	insts, _, err := c.client.Apps.ListInstallations(ctx, nil)
@@ -55,8 +130,28 @@ func (c *githubClient) installations(ctx context.Context) ([]int, error) {
 	return ids, nil
 }
 
+// repoInstallation returns the id of the installation of the application in
+// a repository. The client library doesn't support finding it yet.
+func (c *githubClient) repoInstallation(ctx context.Context, owner, repo string) (int, error) {
+	req, err := c.client.NewRequest("GET", fmt.Sprintf("repos/%s/%s/installation", owner, repo), nil)
+	if err != nil {
+		return 0, err
+	}
+	var inst github.Installation
+	if _, err := c.client.Do(ctx, req, &inst); err != nil {
+		return 0, errors.Wrapf(err, "could not fetch installation of %s/%s", owner, repo)
+	}
+	return int(inst.GetID()), nil
+}
+
 func (c *githubClient) repos(ctx context.Context) ([]repository, error) {
-	rs, _, err := c.client.Apps.ListRepos(ctx, nil)
+	var rs []*github.Repository
+	var err error
+	if c.user {
+		rs, _, err = c.client.Repositories.List(ctx, "", nil)
+	} else {
+		rs, _, err = c.client.Apps.ListRepos(ctx, nil)
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "could not list repositories")
 	}
@@ -80,17 +175,78 @@ func (c *githubClient) repoLabels(ctx context.Context, owner, repo string) ([]st
 	return ss, nil
 }
 
+// labelColors returns the colors of the labels of a repository by their name.
+func (c *githubClient) labelColors(ctx context.Context, owner, repo string) (map[string]string, error) {
+	opt := &github.ListOptions{PerPage: 100}
+	colors := make(map[string]string)
+	for {
+		ls, res, err := c.client.Issues.ListLabels(ctx, owner, repo, opt)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not list labels")
+		}
+		for _, l := range ls {
+			colors[l.GetName()] = l.GetColor()
+		}
+		if res.NextPage == 0 {
+			return colors, nil
+		}
+		opt.Page = res.NextPage
+	}
+}
+
+// issueLabels returns the current labels of an issue.
+func (c *githubClient) issueLabels(ctx context.Context, owner, repo string, number int) ([]string, error) {
+	opt := &github.ListOptions{PerPage: 100}
+	var names []string
+	for {
+		ls, res, err := c.client.Issues.ListLabelsByIssue(ctx, owner, repo, number, opt)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not list labels of issue %s/%s#%d", owner, repo, number)
+		}
+		for _, l := range ls {
+			names = append(names, l.GetName())
+		}
+		if res.NextPage == 0 {
+			return names, nil
+		}
+		opt.Page = res.NextPage
+	}
+}
+
+// issues lists every open issue of a repository, going through all the pages.
 func (c *githubClient) issues(ctx context.Context, owner, repo string) ([]int, error) {
-	issues, _, err := c.client.Issues.ListByRepo(ctx, owner, repo, &github.IssueListByRepoOptions{State: "open"})
+	opt := &github.IssueListByRepoOptions{State: "open", ListOptions: github.ListOptions{PerPage: 100}}
+	var ids []int
+	for {
+		issues, res, err := c.client.Issues.ListByRepo(ctx, owner, repo, opt)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not list issues")
+		}
+		for _, issue := range issues {
+			ids = append(ids, issue.GetNumber())
+		}
+		if res.NextPage == 0 {
+			return ids, nil
+		}
+		opt.Page = res.NextPage
+	}
+}
+
+// closedIssues lists a page of closed issues, returning the next page or 0 if it was the last.
+func (c *githubClient) closedIssues(ctx context.Context, owner, repo string, page int) ([]int, int, error) {
+	issues, res, err := c.client.Issues.ListByRepo(ctx, owner, repo, &github.IssueListByRepoOptions{
+		State:       "closed",
+		ListOptions: github.ListOptions{Page: page, PerPage: 100},
+	})
 	if err != nil {
-		return nil, errors.Wrap(err, "could not list issues")
+		return nil, 0, errors.Wrap(err, "could not list closed issues")
 	}
 
 	ids := make([]int, 0, len(issues))
 	for _, issue := range issues {
 		ids = append(ids, issue.GetNumber())
 	}
-	return ids, nil
+	return ids, res.NextPage, nil
 }
 
 func (c *githubClient) issue(ctx context.Context, owner, repo string, number int) (*issue, error) {
@@ -105,28 +261,168 @@ func (c *githubClient) issue(ctx context.Context, owner, repo string, number int
 	}
 
 	i := &issue{
-		repo:   repository{owner, repo},
-		number: number,
-		title:  res.GetTitle(),
-		body:   res.GetBody(),
-		author: res.GetUser().GetLogin(),
-		state:  res.GetState(),
+		repo:    repository{owner, repo},
+		number:  number,
+		title:   res.GetTitle(),
+		body:    res.GetBody(),
+		author:  res.GetUser().GetLogin(),
+		state:   res.GetState(),
+		locked:  res.GetLocked(),
+		created: res.GetCreatedAt(),
+		closed:  res.GetClosedAt(),
+		url:     res.GetHTMLURL(),
+
+		pullRequest: res.PullRequestLinks != nil,
+	}
+	if m := res.Milestone; m.GetState() == "open" {
+		i.milestone = m.GetDueOn()
+	}
+	for _, l := range res.Labels {
+		i.labels = append(i.labels, l.GetName())
+	}
+	for _, a := range res.Assignees {
+		i.assignees = append(i.assignees, a.GetLogin())
+	}
+	if res.Reactions != nil {
+		i.reactions = res.Reactions.GetTotalCount()
 	}
 	for _, c := range cs {
 		i.comments = append(i.comments, comment{
+			id:      c.GetID(),
 			author:  c.GetUser().GetLogin(),
 			body:    c.GetBody(),
 			created: c.GetCreatedAt(),
 		})
 	}
+	if !i.pullRequest {
+		return i, nil
+	}
+
+	// the comments of the review threads are read like the rest, in the order
+	// they were written. They're never minimized, so they have no id.
+	rcs, err := c.reviewComments(ctx, owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	i.comments = append(i.comments, rcs...)
+	sort.SliceStable(i.comments, func(a, b int) bool { return i.comments[a].created.Before(i.comments[b].created) })
 	return i, nil
 }
 
+// reviewComments lists the comments of the review threads of a pull request,
+// going through all the pages.
+func (c *githubClient) reviewComments(ctx context.Context, owner, repo string, number int) ([]comment, error) {
+	opt := &github.PullRequestListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	var cs []comment
+	for {
+		rcs, res, err := c.client.PullRequests.ListComments(ctx, owner, repo, number, opt)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not fetch review comments")
+		}
+		for _, rc := range rcs {
+			cs = append(cs, comment{
+				author:  rc.GetUser().GetLogin(),
+				body:    rc.GetBody(),
+				created: rc.GetCreatedAt(),
+			})
+		}
+		if res.NextPage == 0 {
+			return cs, nil
+		}
+		opt.Page = res.NextPage
+	}
+}
+
 func (c *githubClient) createIssueComment(ctx context.Context, owner, repo string, number int, body string) error {
 	_, _, err := c.client.Issues.CreateComment(ctx, owner, repo, number, &github.IssueComment{Body: &body})
 	return err
 }
 
+// createIssue opens an issue, returning its number.
+func (c *githubClient) createIssue(ctx context.Context, owner, repo, title, body string) (int, error) {
+	i, _, err := c.client.Issues.Create(ctx, owner, repo, &github.IssueRequest{Title: &title, Body: &body})
+	if err != nil {
+		return 0, err
+	}
+	return i.GetNumber(), nil
+}
+
+func (c *githubClient) editIssueBody(ctx context.Context, owner, repo string, number int, body string) error {
+	_, _, err := c.client.Issues.Edit(ctx, owner, repo, number, &github.IssueRequest{Body: &body})
+	return err
+}
+
+// pinIssue pins an issue to the top of the issues of its repository. Issues
+// can only be pinned through the GraphQL API, which identifies them by their
+// node id.
+func (c *githubClient) pinIssue(ctx context.Context, owner, repo string, number int) error {
+	i, _, err := c.client.Issues.Get(ctx, owner, repo, number)
+	if err != nil {
+		return errors.Wrapf(err, "could not fetch issue %s/%s#%d", owner, repo, number)
+	}
+
+	req, err := c.client.NewRequest("POST", graphQLURL(c.client.BaseURL), map[string]interface{}{
+		"query":     pinMutation,
+		"variables": map[string]string{"id": i.GetNodeID()},
+	})
+	if err != nil {
+		return err
+	}
+	var res struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if _, err := c.client.Do(ctx, req, &res); err != nil {
+		return errors.Wrapf(err, "could not pin issue %s/%s#%d", owner, repo, number)
+	}
+	if len(res.Errors) > 0 {
+		return errors.Errorf("could not pin issue %s/%s#%d: %s", owner, repo, number, res.Errors[0].Message)
+	}
+	return nil
+}
+
+const pinMutation = `mutation($id: ID!) {
+  pinIssue(input: {issueId: $id}) { issue { id } }
+}`
+
+// headSHA returns the SHA of the head commit of a pull request.
+func (c *githubClient) headSHA(ctx context.Context, owner, repo string, number int) (string, error) {
+	pr, _, err := c.client.PullRequests.Get(ctx, owner, repo, number)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not fetch pull request %s/%s#%d", owner, repo, number)
+	}
+	return pr.GetHead().GetSHA(), nil
+}
+
+// createCheckRun creates a completed check run. The client library doesn't
+// support the Checks API yet, which is still in preview.
+func (c *githubClient) createCheckRun(ctx context.Context, owner, repo string, run checkRun) error {
+	req, err := c.client.NewRequest("POST", fmt.Sprintf("repos/%s/%s/check-runs", owner, repo), map[string]interface{}{
+		"name":         checkRunName,
+		"head_sha":     run.sha,
+		"status":       "completed",
+		"conclusion":   run.conclusion,
+		"completed_at": time.Now().UTC().Format(time.RFC3339),
+		"output":       map[string]string{"title": run.title, "summary": run.summary},
+	})
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github.antiope-preview+json")
+	_, err = c.client.Do(ctx, req, nil)
+	return err
+}
+
+func (c *githubClient) createStatus(ctx context.Context, owner, repo string, status commitStatus) error {
+	_, _, err := c.client.Repositories.CreateStatus(ctx, owner, repo, status.sha, &github.RepoStatus{
+		State:       &status.state,
+		Description: &status.description,
+		Context:     github.String(statusContext),
+	})
+	return err
+}
+
 func (c *githubClient) removeIssueLabel(ctx context.Context, owner, repo string, number int, label string) error {
 	_, err := c.client.Issues.RemoveLabelForIssue(ctx, owner, repo, number, label)
 	return err
@@ -136,3 +432,578 @@ func (c *githubClient) addIssueLabel(ctx context.Context, owner, repo string, nu
 	_, _, err := c.client.Issues.AddLabelsToIssue(ctx, owner, repo, number, []string{label})
 	return err
 }
+
+func (c *githubClient) replaceIssueLabels(ctx context.Context, owner, repo string, number int, labels []string) error {
+	if labels == nil {
+		// a nil list would be sent as null rather than removing all of the labels.
+		labels = []string{}
+	}
+	_, _, err := c.client.Issues.ReplaceLabelsForIssue(ctx, owner, repo, number, labels)
+	return err
+}
+
+func (c *githubClient) editLabelColor(ctx context.Context, owner, repo, label, color string) error {
+	_, _, err := c.client.Issues.EditLabel(ctx, owner, repo, label, &github.Label{Name: &label, Color: &color})
+	return err
+}
+
+func (c *githubClient) createLabel(ctx context.Context, owner, repo, label, color string) error {
+	_, _, err := c.client.Issues.CreateLabel(ctx, owner, repo, &github.Label{Name: &label, Color: &color})
+	return err
+}
+
+// minimizeComment hides an issue comment as outdated. Comments can only be
+// minimized through the GraphQL API, which identifies them by their node id.
+func (c *githubClient) minimizeComment(ctx context.Context, owner, repo string, id int64) error {
+	req, err := c.client.NewRequest("GET", fmt.Sprintf("repos/%s/%s/issues/comments/%d", owner, repo, id), nil)
+	if err != nil {
+		return err
+	}
+	var cm struct {
+		NodeID string `json:"node_id"`
+	}
+	if _, err := c.client.Do(ctx, req, &cm); err != nil {
+		return errors.Wrapf(err, "could not fetch comment %d", id)
+	}
+
+	req, err = c.client.NewRequest("POST", graphQLURL(c.client.BaseURL), map[string]interface{}{
+		"query":     minimizeMutation,
+		"variables": map[string]string{"id": cm.NodeID},
+	})
+	if err != nil {
+		return err
+	}
+	var res struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if _, err := c.client.Do(ctx, req, &res); err != nil {
+		return errors.Wrapf(err, "could not minimize comment %d", id)
+	}
+	if len(res.Errors) > 0 {
+		return errors.Errorf("could not minimize comment %d: %s", id, res.Errors[0].Message)
+	}
+	return nil
+}
+
+const minimizeMutation = `mutation($id: ID!) {
+  minimizeComment(input: {subjectId: $id, classifier: OUTDATED}) { minimizedComment { isMinimized } }
+}`
+
+// projectDate returns the earliest value of the given date field in the
+// projects the issue belongs to, zero if it has none.
+func (c *githubClient) projectDate(ctx context.Context, owner, repo string, number int, field string) (time.Time, error) {
+	req, err := c.client.NewRequest("POST", graphQLURL(c.client.BaseURL), map[string]interface{}{
+		"query":     projectDateQuery,
+		"variables": map[string]interface{}{"owner": owner, "repo": repo, "number": number, "field": field},
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	type items struct {
+		Nodes []struct {
+			Field *struct {
+				Date string `json:"date"`
+			} `json:"fieldValueByName"`
+		} `json:"nodes"`
+	}
+	var res struct {
+		Data struct {
+			Repository struct {
+				Issue *struct {
+					ProjectItems items `json:"projectItems"`
+				} `json:"issueOrPullRequest"`
+			} `json:"repository"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if _, err := c.client.Do(ctx, req, &res); err != nil {
+		return time.Time{}, errors.Wrapf(err, "could not fetch projects of %s/%s#%d", owner, repo, number)
+	}
+	if len(res.Errors) > 0 {
+		return time.Time{}, errors.Errorf("could not fetch projects of %s/%s#%d: %s", owner, repo, number, res.Errors[0].Message)
+	}
+	if res.Data.Repository.Issue == nil {
+		return time.Time{}, nil
+	}
+
+	var date time.Time
+	for _, n := range res.Data.Repository.Issue.ProjectItems.Nodes {
+		if n.Field == nil || n.Field.Date == "" {
+			continue
+		}
+		t, err := time.Parse("2006-01-02", n.Field.Date)
+		if err != nil {
+			return time.Time{}, errors.Wrapf(err, "bad date in projects of %s/%s#%d", owner, repo, number)
+		}
+		if date.IsZero() || t.Before(date) {
+			date = t
+		}
+	}
+	return date, nil
+}
+
+// projectDateQuery fetches a field of the project items of an issue or pull
+// request. Fields of other types than dates are ignored.
+const projectDateQuery = `query($owner: String!, $repo: String!, $number: Int!, $field: String!) {
+  repository(owner: $owner, name: $repo) {
+    issueOrPullRequest(number: $number) {
+      ... on Issue { projectItems(first: 20) { nodes { ...date } } }
+      ... on PullRequest { projectItems(first: 20) { nodes { ...date } } }
+    }
+  }
+}
+
+fragment date on ProjectV2Item {
+  fieldValueByName(name: $field) { ... on ProjectV2ItemFieldDateValue { date } }
+}`
+
+// setProjectDate sets the given date field of the project items of an issue
+// to the date, or clears it if the date is zero. Items whose field already has
+// the date are left alone, as are projects without such a date field.
+func (c *githubClient) setProjectDate(ctx context.Context, owner, repo string, number int, field string, date time.Time) error {
+	type items struct {
+		Nodes []struct {
+			ID      string `json:"id"`
+			Project struct {
+				ID    string `json:"id"`
+				Field *struct {
+					ID       string `json:"id"`
+					DataType string `json:"dataType"`
+				} `json:"field"`
+			} `json:"project"`
+			Value *struct {
+				Date string `json:"date"`
+			} `json:"fieldValueByName"`
+		} `json:"nodes"`
+	}
+	var res struct {
+		Repository struct {
+			Issue *struct {
+				ProjectItems items `json:"projectItems"`
+			} `json:"issueOrPullRequest"`
+		} `json:"repository"`
+	}
+	vars := map[string]interface{}{"owner": owner, "repo": repo, "number": number, "field": field}
+	if err := c.graphQL(ctx, projectItemsQuery, vars, &res); err != nil {
+		return errors.Wrapf(err, "could not fetch projects of %s/%s#%d", owner, repo, number)
+	}
+	if res.Repository.Issue == nil {
+		return nil
+	}
+
+	value := ""
+	if !date.IsZero() {
+		value = date.Format("2006-01-02")
+	}
+	for _, n := range res.Repository.Issue.ProjectItems.Nodes {
+		f := n.Project.Field
+		if f == nil || f.DataType != "DATE" {
+			continue
+		}
+		if (n.Value == nil && value == "") || (n.Value != nil && n.Value.Date == value) {
+			continue
+		}
+		vars := map[string]interface{}{"project": n.Project.ID, "item": n.ID, "field": f.ID}
+		mutation := clearProjectDateMutation
+		if value != "" {
+			vars["date"], mutation = value, setProjectDateMutation
+		}
+		if err := c.graphQL(ctx, mutation, vars, nil); err != nil {
+			return errors.Wrapf(err, "could not set %s in projects of %s/%s#%d", field, owner, repo, number)
+		}
+	}
+	return nil
+}
+
+// projectItems returns the ids of the project items of an issue.
+func (c *githubClient) projectItems(ctx context.Context, owner, repo string, number int) ([]string, error) {
+	type items struct {
+		Nodes []struct {
+			ID string `json:"id"`
+		} `json:"nodes"`
+	}
+	var res struct {
+		Repository struct {
+			Issue *struct {
+				ProjectItems items `json:"projectItems"`
+			} `json:"issueOrPullRequest"`
+		} `json:"repository"`
+	}
+	vars := map[string]interface{}{"owner": owner, "repo": repo, "number": number}
+	if err := c.graphQL(ctx, projectItemIDsQuery, vars, &res); err != nil {
+		return nil, errors.Wrapf(err, "could not fetch projects of %s/%s#%d", owner, repo, number)
+	}
+	if res.Repository.Issue == nil {
+		return nil, nil
+	}
+	var ids []string
+	for _, n := range res.Repository.Issue.ProjectItems.Nodes {
+		ids = append(ids, n.ID)
+	}
+	return ids, nil
+}
+
+// projectItemIDsQuery fetches the ids of the project items of an issue or
+// pull request.
+const projectItemIDsQuery = `query($owner: String!, $repo: String!, $number: Int!) {
+  repository(owner: $owner, name: $repo) {
+    issueOrPullRequest(number: $number) {
+      ... on Issue { projectItems(first: 20) { nodes { id } } }
+      ... on PullRequest { projectItems(first: 20) { nodes { id } } }
+    }
+  }
+}`
+
+// projectItemsQuery fetches the project items of an issue or pull request
+// with the id and current value of a field of their projects.
+const projectItemsQuery = `query($owner: String!, $repo: String!, $number: Int!, $field: String!) {
+  repository(owner: $owner, name: $repo) {
+    issueOrPullRequest(number: $number) {
+      ... on Issue { projectItems(first: 20) { nodes { ...item } } }
+      ... on PullRequest { projectItems(first: 20) { nodes { ...item } } }
+    }
+  }
+}
+
+fragment item on ProjectV2Item {
+  id
+  project { id field(name: $field) { ... on ProjectV2Field { id dataType } } }
+  fieldValueByName(name: $field) { ... on ProjectV2ItemFieldDateValue { date } }
+}`
+
+const setProjectDateMutation = `mutation($project: ID!, $item: ID!, $field: ID!, $date: Date!) {
+  updateProjectV2ItemFieldValue(input: {projectId: $project, itemId: $item, fieldId: $field, value: {date: $date}}) {
+    projectV2Item { id }
+  }
+}`
+
+const clearProjectDateMutation = `mutation($project: ID!, $item: ID!, $field: ID!) {
+  clearProjectV2ItemFieldValue(input: {projectId: $project, itemId: $item, fieldId: $field}) {
+    projectV2Item { id }
+  }
+}`
+
+// addToProject adds an issue to a project, or to the first column of a
+// classic project.
+func (c *githubClient) addToProject(ctx context.Context, owner, repo string, number int, p Project) error {
+	i, _, err := c.client.Issues.Get(ctx, owner, repo, number)
+	if err != nil {
+		return errors.Wrapf(err, "could not fetch issue %s/%s#%d", owner, repo, number)
+	}
+
+	if p.ClassicID != 0 {
+		cols, _, err := c.client.Projects.ListProjectColumns(ctx, p.ClassicID, nil)
+		if err != nil {
+			return errors.Wrap(err, "could not list project columns")
+		}
+		if len(cols) == 0 {
+			return errors.New("the project has no columns")
+		}
+		card := &github.ProjectCardOptions{ContentID: i.GetID(), ContentType: "Issue"}
+		if i.IsPullRequest() {
+			// cards of pull requests refer to them by their id as pull requests.
+			pr, _, err := c.client.PullRequests.Get(ctx, owner, repo, number)
+			if err != nil {
+				return errors.Wrapf(err, "could not fetch pull request %s/%s#%d", owner, repo, number)
+			}
+			card.ContentID, card.ContentType = pr.GetID(), "PullRequest"
+		}
+		_, _, err = c.client.Projects.CreateProjectCard(ctx, cols[0].GetID(), card)
+		return err
+	}
+
+	var res struct {
+		Owner *struct {
+			Project *struct {
+				ID string `json:"id"`
+			} `json:"projectV2"`
+		} `json:"repositoryOwner"`
+	}
+	if err := c.graphQL(ctx, projectQuery, map[string]interface{}{"owner": p.Owner, "number": p.Number}, &res); err != nil {
+		return errors.Wrap(err, "could not fetch project")
+	}
+	if res.Owner == nil || res.Owner.Project == nil {
+		return errors.New("project not found")
+	}
+	return c.graphQL(ctx, addProjectItemMutation, map[string]interface{}{"project": res.Owner.Project.ID, "content": i.GetNodeID()}, nil)
+}
+
+// projectQuery fetches the id of a project of an organization or user.
+const projectQuery = `query($owner: String!, $number: Int!) {
+  repositoryOwner(login: $owner) {
+    ... on Organization { projectV2(number: $number) { id } }
+    ... on User { projectV2(number: $number) { id } }
+  }
+}`
+
+// addProjectItemMutation adds an issue to a project, unless it's already in it.
+const addProjectItemMutation = `mutation($project: ID!, $content: ID!) {
+  addProjectV2ItemById(input: {projectId: $project, contentId: $content}) { item { id } }
+}`
+
+// moveProjectItem moves the card of an issue in a classic project to the
+// given column, or sets the single select field of its item in a project to
+// the option named after the column. It reports whether the issue is in the
+// project.
+func (c *githubClient) moveProjectItem(ctx context.Context, owner, repo string, number int, p Project, field, column string) (bool, error) {
+	if p.ClassicID != 0 {
+		return c.moveProjectCard(ctx, owner, repo, number, p.ClassicID, column)
+	}
+
+	var project struct {
+		Owner *struct {
+			Project *struct {
+				ID string `json:"id"`
+			} `json:"projectV2"`
+		} `json:"repositoryOwner"`
+	}
+	if err := c.graphQL(ctx, projectQuery, map[string]interface{}{"owner": p.Owner, "number": p.Number}, &project); err != nil {
+		return false, errors.Wrap(err, "could not fetch project")
+	}
+	if project.Owner == nil || project.Owner.Project == nil {
+		return false, errors.New("project not found")
+	}
+	projectID := project.Owner.Project.ID
+
+	type items struct {
+		Nodes []struct {
+			ID      string `json:"id"`
+			Project struct {
+				ID    string `json:"id"`
+				Field *struct {
+					ID      string `json:"id"`
+					Options []struct {
+						ID   string `json:"id"`
+						Name string `json:"name"`
+					} `json:"options"`
+				} `json:"field"`
+			} `json:"project"`
+			Value *struct {
+				Name string `json:"name"`
+			} `json:"fieldValueByName"`
+		} `json:"nodes"`
+	}
+	var res struct {
+		Repository struct {
+			Issue *struct {
+				ProjectItems items `json:"projectItems"`
+			} `json:"issueOrPullRequest"`
+		} `json:"repository"`
+	}
+	vars := map[string]interface{}{"owner": owner, "repo": repo, "number": number, "field": field}
+	if err := c.graphQL(ctx, projectStatusQuery, vars, &res); err != nil {
+		return false, errors.Wrapf(err, "could not fetch projects of %s/%s#%d", owner, repo, number)
+	}
+	if res.Repository.Issue == nil {
+		return false, nil
+	}
+	for _, n := range res.Repository.Issue.ProjectItems.Nodes {
+		if n.Project.ID != projectID {
+			continue
+		}
+		if n.Value != nil && strings.EqualFold(n.Value.Name, column) {
+			return true, nil
+		}
+		f := n.Project.Field
+		if f == nil {
+			return true, errors.Errorf("the project has no single select field %s", field)
+		}
+		for _, o := range f.Options {
+			if strings.EqualFold(o.Name, column) {
+				vars := map[string]interface{}{"project": projectID, "item": n.ID, "field": f.ID, "option": o.ID}
+				return true, c.graphQL(ctx, setProjectOptionMutation, vars, nil)
+			}
+		}
+		return true, errors.Errorf("the field %s of the project has no option %s", field, column)
+	}
+	return false, nil
+}
+
+// moveProjectCard moves the card of an issue in a classic project to the
+// column with the given name, reporting whether it has one.
+func (c *githubClient) moveProjectCard(ctx context.Context, owner, repo string, number int, projectID int64, column string) (bool, error) {
+	cols, _, err := c.client.Projects.ListProjectColumns(ctx, projectID, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return false, errors.Wrap(err, "could not list project columns")
+	}
+	var target int64
+	for _, col := range cols {
+		if strings.EqualFold(col.GetName(), column) {
+			target = col.GetID()
+		}
+	}
+	if target == 0 {
+		return false, errors.Errorf("the project has no column %s", column)
+	}
+
+	// the cards of both issues and pull requests link to them as issues.
+	suffix := strings.ToLower(fmt.Sprintf("/repos/%s/%s/issues/%d", owner, repo, number))
+	for _, col := range cols {
+		opt := &github.ListOptions{PerPage: 100}
+		for {
+			cards, res, err := c.client.Projects.ListProjectCards(ctx, col.GetID(), opt)
+			if err != nil {
+				return false, errors.Wrap(err, "could not list project cards")
+			}
+			for _, card := range cards {
+				if !strings.HasSuffix(strings.ToLower(card.GetContentURL()), suffix) {
+					continue
+				}
+				if col.GetID() == target {
+					return true, nil
+				}
+				_, err := c.client.Projects.MoveProjectCard(ctx, card.GetID(), &github.ProjectCardMoveOptions{
+					Position: "top",
+					ColumnID: target,
+				})
+				return true, err
+			}
+			if res.NextPage == 0 {
+				break
+			}
+			opt.Page = res.NextPage
+		}
+	}
+	return false, nil
+}
+
+// projectStatusQuery fetches the project items of an issue or pull request
+// with the value and options of a single select field of their projects.
+const projectStatusQuery = `query($owner: String!, $repo: String!, $number: Int!, $field: String!) {
+  repository(owner: $owner, name: $repo) {
+    issueOrPullRequest(number: $number) {
+      ... on Issue { projectItems(first: 20) { nodes { ...item } } }
+      ... on PullRequest { projectItems(first: 20) { nodes { ...item } } }
+    }
+  }
+}
+
+fragment item on ProjectV2Item {
+  id
+  project { id field(name: $field) { ... on ProjectV2SingleSelectField { id options { id name } } } }
+  fieldValueByName(name: $field) { ... on ProjectV2ItemFieldSingleSelectValue { name } }
+}`
+
+const setProjectOptionMutation = `mutation($project: ID!, $item: ID!, $field: ID!, $option: String!) {
+  updateProjectV2ItemFieldValue(input: {projectId: $project, itemId: $item, fieldId: $field, value: {singleSelectOptionId: $option}}) {
+    projectV2Item { id }
+  }
+}`
+
+// graphQL runs a GraphQL query or mutation, decoding its data into data if
+// not nil.
+func (c *githubClient) graphQL(ctx context.Context, query string, vars map[string]interface{}, data interface{}) error {
+	req, err := c.client.NewRequest("POST", graphQLURL(c.client.BaseURL), map[string]interface{}{
+		"query":     query,
+		"variables": vars,
+	})
+	if err != nil {
+		return err
+	}
+	var res struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if _, err := c.client.Do(ctx, req, &res); err != nil {
+		return err
+	}
+	if len(res.Errors) > 0 {
+		return errors.New(res.Errors[0].Message)
+	}
+	if data == nil || len(res.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(res.Data, data)
+}
+
+// graphQLURL returns the GraphQL endpoint corresponding to a REST API base URL:
+// https://api.github.com/graphql, or /api/graphql for GitHub Enterprise Server.
+func graphQLURL(base *url.URL) string {
+	u := *base
+	if strings.HasSuffix(u.Path, "/api/v3/") {
+		u.Path = strings.TrimSuffix(u.Path, "v3/") + "graphql"
+	} else {
+		u.Path += "graphql"
+	}
+	return u.String()
+}
+
+func (c *githubClient) permission(ctx context.Context, owner, repo, user string) (string, error) {
+	level, _, err := c.client.Repositories.GetPermissionLevel(ctx, owner, repo, user)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not fetch permission level for %s", user)
+	}
+	return level.GetPermission(), nil
+}
+
+func (c *githubClient) files(ctx context.Context, owner, repo string, number int) ([]string, error) {
+	opt := &github.ListOptions{PerPage: 100}
+	var names []string
+	for {
+		fs, res, err := c.client.PullRequests.ListFiles(ctx, owner, repo, number, opt)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not list pull request files")
+		}
+		for _, f := range fs {
+			names = append(names, f.GetFilename())
+		}
+		if res.NextPage == 0 {
+			return names, nil
+		}
+		opt.Page = res.NextPage
+	}
+}
+
+// file returns the contents of a file in the default branch of a repository,
+// or nil if there's no such file.
+func (c *githubClient) file(ctx context.Context, owner, repo, path string) ([]byte, error) {
+	fc, _, res, err := c.client.Repositories.GetContents(ctx, owner, repo, path, nil)
+	if res != nil && res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not fetch %s", path)
+	}
+	if fc == nil {
+		return nil, nil
+	}
+	content, err := fc.GetContent()
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not decode %s", path)
+	}
+	return []byte(content), nil
+}
+
+// labelEvents returns the labels applied to and removed from an issue, in the
+// order they happened, as shown in its timeline.
+func (c *githubClient) labelEvents(ctx context.Context, owner, repo string, number int) ([]labelEvent, error) {
+	opt := &github.ListOptions{PerPage: 100}
+	var events []labelEvent
+	for {
+		ts, res, err := c.client.Issues.ListIssueTimeline(ctx, owner, repo, number, opt)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not list issue timeline")
+		}
+		for _, t := range ts {
+			if e := t.GetEvent(); e == "labeled" || e == "unlabeled" {
+				events = append(events, labelEvent{
+					actor:   t.GetActor().GetLogin(),
+					label:   t.GetLabel().GetName(),
+					added:   e == "labeled",
+					created: t.GetCreatedAt(),
+				})
+			}
+		}
+		if res.NextPage == 0 {
+			return events, nil
+		}
+		opt.Page = res.NextPage
+	}
+}
@@ -0,0 +1,86 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestReviewComments(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/repos/foo/bar/issues/1":
+			fmt.Fprint(w, `{"number": 1, "state": "open", "pull_request": {"url": "https://api.github.com/repos/foo/bar/pulls/1"}}`)
+		case "/api/v3/repos/foo/bar/issues/1/comments":
+			fmt.Fprint(w, `[{"id": 1, "body": "deadline: 2018-08-01", "created_at": "2018-07-01T00:00:00Z"},
+				{"id": 2, "body": "LGTM", "created_at": "2018-07-03T00:00:00Z"}]`)
+		case "/api/v3/repos/foo/bar/pulls/1/comments":
+			if r.URL.Query().Get("page") == "2" {
+				fmt.Fprint(w, `[{"id": 10, "body": "done", "created_at": "2018-07-04T00:00:00Z"}]`)
+				return
+			}
+			w.Header().Set("Link", fmt.Sprintf(`<%s/api/v3/repos/foo/bar/pulls/1/comments?page=2>; rel="next"`, srv.URL))
+			fmt.Fprint(w, `[{"id": 9, "body": "deadline: 2018-08-10", "created_at": "2018-07-02T00:00:00Z"}]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	base, _ := url.Parse(srv.URL + "/api/v3/")
+	c := &githubClient{client: newGitHubClient(srv.Client(), base)}
+	i, err := c.issue(context.Background(), "foo", "bar", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var bodies []string
+	for _, cm := range i.comments {
+		bodies = append(bodies, cm.body)
+	}
+	if expected := fmt.Sprint([]string{"deadline: 2018-08-01", "deadline: 2018-08-10", "LGTM", "done"}); fmt.Sprint(bodies) != expected {
+		t.Errorf("expected the comments %s of every page in the order they were written; got %q", expected, bodies)
+	}
+}
+
+func TestIssuesPages(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/repos/foo/bar/issues" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("page") == "2" {
+			fmt.Fprint(w, `[{"number": 31}]`)
+			return
+		}
+		w.Header().Set("Link", fmt.Sprintf(`<%s/api/v3/repos/foo/bar/issues?page=2>; rel="next"`, srv.URL))
+		fmt.Fprint(w, `[{"number": 1}, {"number": 2}]`)
+	}))
+	defer srv.Close()
+
+	base, _ := url.Parse(srv.URL + "/api/v3/")
+	c := &githubClient{client: newGitHubClient(srv.Client(), base)}
+	numbers, err := c.issues(context.Background(), "foo", "bar")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := fmt.Sprint([]int{1, 2, 31}); fmt.Sprint(numbers) != expected {
+		t.Errorf("expected the open issues of every page %s; got %v", expected, numbers)
+	}
+}
+
+func TestUploadURL(t *testing.T) {
+	for base, expected := range map[string]string{
+		"https://ghes.example.com/api/v3/": "https://ghes.example.com/api/uploads/",
+		"http://127.0.0.1:1234/":           "http://127.0.0.1:1234/",
+	} {
+		u, _ := url.Parse(base)
+		if got := newGitHubClient(nil, u).UploadURL.String(); got != expected {
+			t.Errorf("expected the uploads of %s at %s; got %s", base, expected, got)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package reminder
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// WithClosedIssueCleanup makes the bot remove the deadline labels of the
+// issues once they're closed, so they don't show up when searching by label.
+func WithClosedIssueCleanup() Option {
+	return func(o *options) { o.cleanupClosed = true }
+}
+
+// stripDeadlineLabels removes the deadline labels of a closed issue, if enabled.
+func (c *InstallationClient) stripDeadlineLabels(ctx context.Context, issue *issue, labels []Label) error {
+	if !c.opts.cleanupClosed {
+		return nil
+	}
+	if len(withoutDeadlineLabels(issue.labels, labels)) == len(issue.labels) {
+		return nil
+	}
+	current, err := c.client.issueLabels(ctx, issue.repo.owner, issue.repo.name, issue.number)
+	if err != nil {
+		return err
+	}
+	want := withoutDeadlineLabels(current, labels)
+	if len(want) == len(current) {
+		return nil
+	}
+	logrus.Debugf("removing the deadline labels of closed issue %s/%s#%d", issue.repo.owner, issue.repo.name, issue.number)
+	err = c.client.replaceIssueLabels(ctx, issue.repo.owner, issue.repo.name, issue.number, want)
+	return errors.Wrapf(err, "could not remove the deadline labels of issue %s/%s#%d", issue.repo.owner, issue.repo.name, issue.number)
+}
+
+// stripPrunedLabel removes the label recorded for an issue that is no longer
+// open, if enabled, in case it was closed while the bot missed its webhook.
+// Failures are only logged, since the issue could have been deleted.
+func (c *InstallationClient) stripPrunedLabel(ctx context.Context, d Deadline) {
+	if !c.opts.cleanupClosed || d.Label == "" {
+		return
+	}
+	if err := c.client.removeIssueLabel(ctx, d.Owner, d.Repo, d.Number, d.Label); err != nil {
+		logrus.Warnf("could not remove label %s from %s/%s#%d: %v", d.Label, d.Owner, d.Repo, d.Number, err)
+	}
+}
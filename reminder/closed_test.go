@@ -0,0 +1,59 @@
+package reminder
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClosedIssueCleanup(t *testing.T) {
+	ctx := context.Background()
+	var replaced [][]string
+	var removed []string
+	fc := &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) {
+			return []string{"deadline < 5", "deadline < 30"}, nil
+		},
+		_issues: func(ctx context.Context, owner, repo string) ([]int, error) { return nil, nil },
+		_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+			return &issue{repo: repository{owner, repo}, number: number, state: "closed", labels: []string{"bug", "deadline < 5"}}, nil
+		},
+		_replaceIssueLabels: func(ctx context.Context, owner, repo string, number int, labels []string) error {
+			replaced = append(replaced, labels)
+			return nil
+		},
+		_removeIssueLabel: func(ctx context.Context, owner, repo string, number int, label string) error {
+			removed = append(removed, label)
+			return nil
+		},
+	}
+
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil), client: fc}
+	if err := ic.UpdateIssue(ctx, "foo", "bar", 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(replaced) != 0 {
+		t.Errorf("expected the labels of closed issues to be kept unless enabled; got %v", replaced)
+	}
+
+	ic.opts = newOptions([]Option{WithClosedIssueCleanup()})
+	if err := ic.UpdateIssue(ctx, "foo", "bar", 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := [][]string{{"bug"}}; !reflect.DeepEqual(replaced, expected) {
+		t.Errorf("expected the labels to be replaced with %v; got %v", expected, replaced)
+	}
+
+	// the issue closed while the bot missed its webhook is cleaned up by the next scan.
+	d := Deadline{Owner: "foo", Repo: "bar", Number: 2, Deadline: time.Now(), Label: "deadline < 30"}
+	if err := ic.opts.store.Put(ctx, deadlineKey(42, 43, "foo", "bar", 2), d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ic.UpdateRepo(ctx, "foo", "bar"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"deadline < 30"}; !reflect.DeepEqual(removed, expected) {
+		t.Errorf("expected %v to be removed from the issue no longer open; got %v", expected, removed)
+	}
+}
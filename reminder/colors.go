@@ -0,0 +1,106 @@
+package reminder
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+// UrgencyColors are the colors, as hex RGB values without the leading #, given to deadline
+// labels depending on how close the most urgent open issue carrying them is to its deadline.
+type UrgencyColors struct {
+	// Calm is used while more than half of the label's days are left.
+	Calm string
+	// Soon is used once half of the label's days have passed.
+	Soon string
+	// Urgent is used with a day or less left, or once the deadline has passed.
+	Urgent string
+}
+
+// DefaultUrgencyColors go from green to amber to red.
+var DefaultUrgencyColors = UrgencyColors{Calm: "0e8a16", Soon: "fbca04", Urgent: "b60205"}
+
+// WithUrgencyColors enables updating the colors of the deadline labels of a repository
+// after each scan. Empty colors are taken from DefaultUrgencyColors.
+func WithUrgencyColors(colors UrgencyColors) Option {
+	colors = colors.withDefaults()
+	return func(o *options) { o.colors = &colors }
+}
+
+// withDefaults returns the colors with the empty ones taken from DefaultUrgencyColors.
+func (u UrgencyColors) withDefaults() UrgencyColors {
+	if u.Calm == "" {
+		u.Calm = DefaultUrgencyColors.Calm
+	}
+	if u.Soon == "" {
+		u.Soon = DefaultUrgencyColors.Soon
+	}
+	if u.Urgent == "" {
+		u.Urgent = DefaultUrgencyColors.Urgent
+	}
+	return u
+}
+
+// color returns the color for a label of the given days with left time until the deadline.
+func (u UrgencyColors) color(l Label, left time.Duration) string {
+	switch days := left.Hours() / 24; {
+	case days <= 1:
+		return u.Urgent
+	case days <= l.Duration().Hours()/24/2:
+		return u.Soon
+	default:
+		return u.Calm
+	}
+}
+
+// updateLabelColors sets the color of every deadline label in the repository according
+// to the most urgent open issue carrying it, as found in the inventory.
+// Labels not carried by any issue are left alone.
+func (c *InstallationClient) updateLabelColors(ctx context.Context, owner, repo string, labels []Label) error {
+	if c.opts.colors == nil {
+		return nil
+	}
+
+	deadlines, err := listDeadlines(ctx, c.opts.store, storage.Key("deadline", c.appID, c.installationID, owner, repo)+"/")
+	if err != nil {
+		return err
+	}
+
+	// deadlines are sorted by due date, so the first one seen for each label is the most urgent.
+	urgent := make(map[string]time.Time)
+	for _, d := range deadlines {
+		if _, ok := urgent[d.Label]; !ok && d.Label != "" {
+			urgent[d.Label] = d.Deadline
+		}
+	}
+
+	for _, l := range labels {
+		deadline, ok := urgent[l.Name]
+		if !ok {
+			continue
+		}
+		color := c.opts.colors.color(l, time.Until(deadline))
+
+		key := storage.Key("color", c.appID, c.installationID, owner, repo, l.Name)
+		var current string
+		if err := c.opts.store.Get(ctx, key, &current); err != nil && err != storage.ErrNotFound {
+			return errors.Wrapf(err, "could not fetch color of label %s", l.Name)
+		}
+		if current == color {
+			continue
+		}
+
+		logrus.Debugf("setting color of label %s in %s/%s to %s", l.Name, owner, repo, color)
+		if err := c.client.editLabelColor(ctx, owner, repo, l.Name, color); err != nil {
+			return errors.Wrapf(err, "could not update color of label %s", l.Name)
+		}
+		if err := c.opts.store.Put(ctx, key, color); err != nil {
+			return errors.Wrapf(err, "could not store color of label %s", l.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package reminder
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestUpdateLabelColors(t *testing.T) {
+	now := time.Now()
+	ctx := context.Background()
+	colors := map[string]string{}
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions([]Option{WithUrgencyColors(UrgencyColors{})}), client: &fakeClient{
+		_editLabelColor: func(ctx context.Context, owner, repo, label, color string) error {
+			colors[label] = color
+			return nil
+		},
+	}}
+
+	record := func(number int, deadline time.Time, label string) {
+		ic.recordDeadline(ctx, &issue{repo: repository{"foo", "bar"}, number: number}, deadline, label)
+	}
+	record(1, now.AddDate(0, 0, 6), "week")
+	record(2, now.AddDate(0, 0, 2), "week")
+	record(3, now.Add(12*time.Hour), "day")
+	record(4, now.AddDate(0, 0, 20), "month")
+
+	labels := []Label{{Name: "day", Days: 1}, {Name: "week", Days: 7}, {Name: "month", Days: 30}, {Name: "year", Days: 365}}
+	if err := ic.updateLabelColors(ctx, "foo", "bar", labels); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]string{"day": "b60205", "week": "fbca04", "month": "0e8a16"}
+	for l, c := range expected {
+		if colors[l] != c {
+			t.Errorf("expected label %s to be %s; got %q", l, c, colors[l])
+		}
+	}
+	if c, ok := colors["year"]; ok {
+		t.Errorf("expected unused label to be left alone; got %s", c)
+	}
+
+	colors = map[string]string{}
+	if err := ic.updateLabelColors(ctx, "foo", "bar", labels); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(colors) != 0 {
+		t.Errorf("expected no redundant color updates; got %v", colors)
+	}
+}
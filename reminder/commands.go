@@ -0,0 +1,128 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+// A Command is an instruction to the bot written at the beginning of a comment, like "/approve".
+type Command struct {
+	Name   string
+	Args   []string
+	Owner  string
+	Repo   string
+	Number int
+	Author string
+}
+
+// A CommandFunc runs a command using the client of the installation where it was written.
+type CommandFunc func(ctx context.Context, c *InstallationClient, cmd Command) error
+
+var (
+	commandsMu sync.RWMutex
+	commands   = map[string]CommandFunc{
+		"/approve":  approveCommand,
+		"/deadline": deadlineCommand,
+		"/ooo":      oooCommand,
+		"/reminder": reminderCommand,
+		"/snooze":   snoozeCommand,
+	}
+)
+
+// RegisterCommand adds a custom command, like "/triage", run whenever a comment
+// starts with it. Command names are case insensitive. It panics if the name
+// doesn't start with a slash or is already registered.
+func RegisterCommand(name string, fn CommandFunc) {
+	name = strings.ToLower(name)
+	if !strings.HasPrefix(name, "/") || len(name) < 2 || strings.ContainsAny(name, " \t\n") {
+		panic("reminder: bad command name " + name)
+	}
+	if fn == nil {
+		panic("reminder: nil function for command " + name)
+	}
+
+	commandsMu.Lock()
+	defer commandsMu.Unlock()
+	if _, ok := commands[name]; ok {
+		panic("reminder: command " + name + " registered twice")
+	}
+	commands[name] = fn
+}
+
+// parseCommand returns the command in the first line of the given comment body, if any.
+func parseCommand(body string) (name string, args []string, ok bool) {
+	line := strings.TrimSpace(body)
+	if i := strings.Index(line, "\n"); i >= 0 {
+		line = line[:i]
+	}
+	fields := strings.Fields(line)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return "", nil, false
+	}
+	return strings.ToLower(fields[0]), fields[1:], true
+}
+
+// HandleComment runs the command contained in a new comment, if any.
+// It returns whether a known command was found.
+func (c *InstallationClient) HandleComment(ctx context.Context, owner, repo string, number int, author, body string) (bool, error) {
+	g, err := c.Grammar(ctx, owner, repo)
+	if err != nil {
+		return false, err
+	}
+	name, args, ok := g.parseCommand(body)
+	if !ok {
+		return false, nil
+	}
+	commandsMu.RLock()
+	run, ok := commands[name]
+	commandsMu.RUnlock()
+	if !ok {
+		return false, nil
+	}
+
+	// commands read dates in the timezone and language of the repository too.
+	if c, err = c.forRepo(ctx, owner, repo); err != nil {
+		return true, err
+	}
+	logrus.Infof("running %s by %s in %s/%s#%d", name, author, owner, repo, number)
+	cmd := Command{Name: name, Args: args, Owner: owner, Repo: repo, Number: number, Author: author}
+	return true, run(ctx, c, cmd)
+}
+
+// Reply comments on the issue where the command was written, mentioning its author.
+func (c *InstallationClient) Reply(ctx context.Context, cmd Command, text string) error {
+	return c.Comment(ctx, cmd.Owner, cmd.Repo, cmd.Number, fmt.Sprintf("@%s %s", cmd.Author, text))
+}
+
+// Comment posts a comment on an issue or pull request.
+func (c *InstallationClient) Comment(ctx context.Context, owner, repo string, number int, body string) error {
+	err := c.client.createIssueComment(ctx, owner, repo, number, body)
+	return errors.Wrapf(err, "could not comment on %s/%s#%d", owner, repo, number)
+}
+
+// AddLabel applies a label to an issue or pull request.
+func (c *InstallationClient) AddLabel(ctx context.Context, owner, repo string, number int, label string) error {
+	err := c.client.addIssueLabel(ctx, owner, repo, number, label)
+	return errors.Wrapf(err, "could not apply label %s", label)
+}
+
+// RemoveLabel removes a label from an issue or pull request.
+func (c *InstallationClient) RemoveLabel(ctx context.Context, owner, repo string, number int, label string) error {
+	err := c.client.removeIssueLabel(ctx, owner, repo, number, label)
+	return errors.Wrapf(err, "could not remove label %s", label)
+}
+
+// Permission returns the permission of a user in a repository: admin, write, read, or none.
+func (c *InstallationClient) Permission(ctx context.Context, owner, repo, user string) (string, error) {
+	return c.client.permission(ctx, owner, repo, user)
+}
+
+// Store returns the store where the client keeps its state, which commands can use to keep theirs.
+func (c *InstallationClient) Store() storage.Store { return c.opts.store }
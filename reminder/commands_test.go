@@ -0,0 +1,60 @@
+package reminder
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+var registerTriage sync.Once
+
+func TestRegisterCommand(t *testing.T) {
+	registerTriage.Do(func() {
+		RegisterCommand("/Triage", func(ctx context.Context, c *InstallationClient, cmd Command) error {
+			if err := c.Store().Put(ctx, "triage/"+cmd.Args[0], cmd.Author); err != nil {
+				return err
+			}
+			if err := c.AddLabel(ctx, cmd.Owner, cmd.Repo, cmd.Number, cmd.Args[0]); err != nil {
+				return err
+			}
+			return c.Reply(ctx, cmd, "triaged")
+		})
+	})
+
+	ctx := context.Background()
+	var labels, comments []string
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil), client: &fakeClient{
+		_addIssueLabel: func(ctx context.Context, owner, repo string, number int, label string) error {
+			labels = append(labels, label)
+			return nil
+		},
+		_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error {
+			comments = append(comments, body)
+			return nil
+		},
+	}}
+
+	ok, err := ic.HandleComment(ctx, "foo", "bar", 1, "alice", "/triage p1\nthis is urgent")
+	if !ok || err != nil {
+		t.Fatalf("expected custom command to run; got %v, %v", ok, err)
+	}
+	if !reflect.DeepEqual(labels, []string{"p1"}) || !reflect.DeepEqual(comments, []string{"@alice triaged"}) {
+		t.Errorf("unexpected labels %v and comments %v", labels, comments)
+	}
+	var author string
+	if err := ic.Store().Get(ctx, "triage/p1", &author); err != nil || author != "alice" {
+		t.Errorf("expected command to use the store; got %q (%v)", author, err)
+	}
+
+	for _, name := range []string{"/ooo", "triage2", "/"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected registering %q to panic", name)
+				}
+			}()
+			RegisterCommand(name, func(ctx context.Context, c *InstallationClient, cmd Command) error { return nil })
+		}()
+	}
+}
@@ -0,0 +1,231 @@
+package reminder
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A DateParser reads the dates written in a way other than an absolute date,
+// like "next friday". It's given the text in lower case, and the start of the
+// day it was written, in the timezone of the bot, and returns the start of the
+// day it refers to, or zero if it doesn't understand it.
+type DateParser interface {
+	ParseDate(s string, day time.Time) time.Time
+}
+
+// DateParserFunc allows using ordinary functions as a DateParser.
+type DateParserFunc func(s string, day time.Time) time.Time
+
+// ParseDate calls f.
+func (f DateParserFunc) ParseDate(s string, day time.Time) time.Time {
+	return f(s, day)
+}
+
+// RelativeDates reads dates like "today", "tomorrow", "in 5 days", "2 weeks", or "in a month".
+var RelativeDates DateParser = DateParserFunc(parseRelative)
+
+// NaturalDates reads dates like "friday", "next friday", "next week", "next
+// month", "end of week", "end of month", or "end of year". Weekdays refer to the
+// first one after the day they were written, and weeks go from Monday to Sunday.
+var NaturalDates DateParser = DateParserFunc(parseNatural)
+
+// DefaultDateParsers are the parsers used unless WithDateParsers is given.
+var DefaultDateParsers = []DateParser{RelativeDates, NaturalDates}
+
+// WithDateParsers replaces DefaultDateParsers as the ways to read the dates
+// that aren't absolute. Without any parser only absolute dates are read.
+func WithDateParsers(ps ...DateParser) Option {
+	return func(o *options) { o.parsers = append([]DateParser{}, ps...) }
+}
+
+// WithDateLayouts adds layouts absolute dates can be written in, like
+// "02.01.2006" or "2 Jan 06", following the reference time of the time package.
+// They're tried after the default ones, and month and day names can be written
+// in any case.
+func WithDateLayouts(layouts ...string) Option {
+	return func(o *options) { o.layouts = append(o.layouts, layouts...) }
+}
+
+// dates reads the dates written in issues.
+type dates struct {
+	// loc is the timezone of the dates without one, UTC if nil.
+	loc     *time.Location
+	layouts []string
+	// language is the code of the language dates can be written in besides English.
+	language string
+	parsers  []DateParser
+}
+
+// dates returns the way to read the dates of the issue.
+func (c *InstallationClient) dates(issue *issue) dates {
+	return dates{loc: c.opts.location, layouts: c.opts.layouts, language: issue.language, parsers: c.opts.parsers}
+}
+
+// parse parses either an absolute date, a duration since the time the text was
+// created, or a date understood by the parsers, relative to that time too.
+func (d dates) parse(s string, created time.Time) time.Time {
+	if t := parseDate(s, d.loc, d.layouts); !t.IsZero() {
+		return t
+	}
+
+	s = strings.Join(strings.Fields(strings.Trim(strings.TrimSpace(s), ":")), " ")
+	loc := d.loc
+	if loc == nil {
+		loc = time.UTC
+	}
+	if t, _ := parseNumericDate(s, d.dayFirst(), loc); !t.IsZero() {
+		return t.UTC()
+	}
+	if t := parseLocalDate(s, d.language, loc); !t.IsZero() || created.IsZero() {
+		return t
+	}
+	created = created.In(loc)
+	day := time.Date(created.Year(), created.Month(), created.Day(), 0, 0, 0, 0, loc)
+	if t := parseDuration(s, created, day); !t.IsZero() {
+		return t.UTC()
+	}
+	for _, p := range d.parsers {
+		if t := p.ParseDate(s, day); !t.IsZero() {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
+
+func parseRelative(s string, day time.Time) time.Time {
+	switch s {
+	case "today":
+		return day
+	case "tomorrow":
+		return day.AddDate(0, 0, 1)
+	}
+
+	fields := strings.Fields(strings.TrimPrefix(s, "in "))
+	if len(fields) != 2 {
+		return time.Time{}
+	}
+	n, err := strconv.Atoi(fields[0])
+	if fields[0] == "a" || fields[0] == "an" {
+		n, err = 1, nil
+	}
+	if err != nil || n < 0 {
+		return time.Time{}
+	}
+	switch strings.TrimSuffix(fields[1], "s") {
+	case "day":
+		return day.AddDate(0, 0, n)
+	case "week":
+		return day.AddDate(0, 0, 7*n)
+	case "month":
+		return day.AddDate(0, n, 0)
+	}
+	return time.Time{}
+}
+
+// parseDuration parses a duration written like "10d", "2w", "72h", or "1h30m",
+// returning the time it ends from the given one. Days and weeks are counted
+// from the start of the day, so the deadline lasts the whole day, while other
+// durations are exact.
+func parseDuration(s string, created, day time.Time) time.Time {
+	if strings.HasSuffix(s, "d") || strings.HasSuffix(s, "w") {
+		t, _ := addShortDuration(s, day)
+		return t
+	}
+	dur, err := time.ParseDuration(s)
+	if err != nil || dur <= 0 {
+		return time.Time{}
+	}
+	return created.Add(dur)
+}
+
+// addShortDuration adds a positive duration in hours, days, or weeks written
+// like "12h", "3d", or "2w" to the given time.
+func addShortDuration(s string, t time.Time) (time.Time, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if len(s) < 2 {
+		return time.Time{}, false
+	}
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n <= 0 {
+		return time.Time{}, false
+	}
+	switch s[len(s)-1] {
+	case 'h':
+		return t.Add(time.Duration(n) * time.Hour), true
+	case 'd':
+		return t.AddDate(0, 0, n), true
+	case 'w':
+		return t.AddDate(0, 0, 7*n), true
+	}
+	return time.Time{}, false
+}
+
+// parseBefore parses a time before the deadline, like "3 days before deadline",
+// "1 week before", or "2 hours before the deadline", returning the function
+// computing it from the deadline.
+func parseBefore(s string) (func(deadline time.Time) time.Time, bool) {
+	fields := strings.Fields(strings.Trim(strings.TrimSpace(s), ":"))
+	switch {
+	case len(fields) == 3:
+	case len(fields) == 4 && fields[3] == "deadline":
+	case len(fields) == 5 && fields[3] == "the" && fields[4] == "deadline":
+	default:
+		return nil, false
+	}
+	if fields[2] != "before" {
+		return nil, false
+	}
+
+	n, err := strconv.Atoi(fields[0])
+	if fields[0] == "a" || fields[0] == "an" {
+		n, err = 1, nil
+	}
+	if err != nil || n < 0 {
+		return nil, false
+	}
+	switch strings.TrimSuffix(fields[1], "s") {
+	case "hour":
+		return func(t time.Time) time.Time { return t.Add(-time.Duration(n) * time.Hour) }, true
+	case "day":
+		return func(t time.Time) time.Time { return t.AddDate(0, 0, -n) }, true
+	case "week":
+		return func(t time.Time) time.Time { return t.AddDate(0, 0, -7*n) }, true
+	}
+	return nil, false
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+}
+
+func parseNatural(s string, day time.Time) time.Time {
+	s = strings.Replace(s, " the ", " ", 1)
+	// days since the start of the week, on Monday.
+	weekday := (int(day.Weekday()) + 6) % 7
+	switch s {
+	case "next week":
+		return day.AddDate(0, 0, 7-weekday)
+	case "end of week":
+		return day.AddDate(0, 0, 6-weekday)
+	case "next month":
+		return time.Date(day.Year(), day.Month()+1, 1, 0, 0, 0, 0, day.Location())
+	case "end of month":
+		return time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location())
+	case "end of year":
+		return time.Date(day.Year(), time.December, 31, 0, 0, 0, 0, day.Location())
+	}
+
+	for _, prefix := range []string{"next ", "on ", "this "} {
+		s = strings.TrimPrefix(s, prefix)
+	}
+	if wd, ok := weekdays[s]; ok {
+		days := (int(wd) - int(day.Weekday()) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+		return day.AddDate(0, 0, days)
+	}
+	return time.Time{}
+}
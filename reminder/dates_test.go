@@ -0,0 +1,92 @@
+package reminder
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNaturalDates(t *testing.T) {
+	// a Wednesday.
+	created := time.Date(2018, 8, 1, 15, 30, 0, 0, time.UTC)
+	day := func(month time.Month, d int) time.Time { return time.Date(2018, month, d, 0, 0, 0, 0, time.UTC) }
+	tests := map[string]time.Time{
+		"friday":          day(8, 3),
+		"next friday":     day(8, 3),
+		"on wednesday":    day(8, 8),
+		"next week":       day(8, 6),
+		"end of week":     day(8, 5),
+		"end of the week": day(8, 5),
+		"next month":      day(9, 1),
+		"end of month":    day(8, 31),
+		"end of  year":    day(12, 31),
+		"in 2 days":       day(8, 3),
+		"someday":         {},
+	}
+
+	d := dates{parsers: DefaultDateParsers}
+	for s, expected := range tests {
+		if got := d.parse(s, created); !got.Equal(expected) {
+			t.Errorf("%q: expected %v; got %v", s, expected, got)
+		}
+	}
+
+	strict := dates{parsers: []DateParser{RelativeDates}}
+	if got := strict.parse("next friday", created); !got.IsZero() {
+		t.Errorf("expected natural dates to be disabled; got %v", got)
+	}
+	if got := strict.parse("in 2 days", created); !got.Equal(day(8, 3)) {
+		t.Errorf("expected relative dates to be read; got %v", got)
+	}
+}
+
+func TestDateLayouts(t *testing.T) {
+	expected := time.Date(2018, 8, 1, 0, 0, 0, 0, time.UTC)
+	d := dates{layouts: []string{"02.01.2006", "2 Jan 06"}}
+	for _, s := range []string{"01.08.2018", "1 aug 18", "2018-08-01"} {
+		if got := d.parse(s, time.Time{}); !got.Equal(expected) {
+			t.Errorf("%q: expected %v; got %v", s, expected, got)
+		}
+	}
+	if got := (dates{}).parse("01.08.2018", time.Time{}); !got.IsZero() {
+		t.Errorf("expected layouts not to be used unless given; got %v", got)
+	}
+}
+
+func TestReminderBeforeDeadline(t *testing.T) {
+	deadline := time.Date(2018, 8, 20, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		s        string
+		expected time.Time
+	}{
+		{"3 days before deadline", time.Date(2018, 8, 17, 0, 0, 0, 0, time.UTC)},
+		{"1 week before", time.Date(2018, 8, 13, 0, 0, 0, 0, time.UTC)},
+		{"a week before the deadline", time.Date(2018, 8, 13, 0, 0, 0, 0, time.UTC)},
+		{": 2 hours before", time.Date(2018, 8, 19, 22, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		before, ok := parseBefore(tt.s)
+		if !ok {
+			t.Errorf("%q: expected a time before the deadline", tt.s)
+			continue
+		}
+		if got := before(deadline); !got.Equal(tt.expected) {
+			t.Errorf("%q: expected %v; got %v", tt.s, tt.expected, got)
+		}
+	}
+	for _, s := range []string{"3 days", "before deadline", "3 days after deadline", "3 months before", "3 days before lunch"} {
+		if _, ok := parseBefore(s); ok {
+			t.Errorf("%q: expected no time before the deadline", s)
+		}
+	}
+
+	// the reminder moves with the deadline, and is ignored without one.
+	ic := InstallationClient{opts: newOptions(nil)}
+	is := &issue{}
+	moved := deadline.AddDate(0, 0, 7)
+	if got := ic.findReminders(is, "reminder: 3 days before deadline", deadline, deadline, moved); len(got) != 1 || !got[0].at.Equal(moved.AddDate(0, 0, -3)) {
+		t.Errorf("expected a reminder 3 days before %v; got %v", moved, got)
+	}
+	if got := ic.findReminders(is, "reminder: 3 days before deadline", deadline, deadline, time.Time{}); len(got) != 0 {
+		t.Errorf("expected no reminders without a deadline; got %v", got)
+	}
+}
@@ -0,0 +1,102 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+// commandDeadline is a deadline set with the /deadline command. It's zero if
+// the deadline was cleared.
+type commandDeadline struct {
+	Deadline time.Time `json:"deadline"`
+	By       string    `json:"by"`
+	Time     time.Time `json:"time"`
+}
+
+func commandDeadlineKey(appID, installationID int, owner, repo string, number int) string {
+	return storage.Key("deadlinecmd", appID, installationID, strings.ToLower(owner), strings.ToLower(repo), number)
+}
+
+// commandDeadline returns the deadline set for the issue with /deadline, if any.
+func (c *InstallationClient) commandDeadline(ctx context.Context, issue *issue) (*commandDeadline, error) {
+	var cd commandDeadline
+	err := c.opts.store.Get(ctx, commandDeadlineKey(c.appID, c.installationID, issue.repo.owner, issue.repo.name, issue.number), &cd)
+	if err == storage.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch deadline set by command")
+	}
+	return &cd, nil
+}
+
+// deadlineCommand sets the deadline of an issue, replacing the ones written
+// before it, moves it a number of hours, days, or weeks, or clears it.
+//
+//	/deadline 2018-08-01
+//	/deadline +7d
+//	/deadline clear
+func deadlineCommand(ctx context.Context, c *InstallationClient, cmd Command) error {
+	reply := func(text string) error {
+		return c.client.createIssueComment(ctx, cmd.Owner, cmd.Repo, cmd.Number, fmt.Sprintf("@%s %s", cmd.Author, text))
+	}
+	const usage = "usage: `/deadline 2018-08-01`, `/deadline +7d`, or `/deadline clear`."
+	if len(cmd.Args) == 0 {
+		return reply(usage)
+	}
+
+	issue, err := c.client.issue(ctx, cmd.Owner, cmd.Repo, cmd.Number)
+	if err != nil {
+		return err
+	}
+	if err := c.readSettings(ctx, issue); err != nil {
+		return err
+	}
+
+	loc := c.opts.location
+	if loc == nil {
+		loc = time.UTC
+	}
+	now := time.Now().In(loc)
+	arg := strings.Join(cmd.Args, " ")
+	var deadline time.Time
+	switch {
+	case len(cmd.Args) == 1 && (strings.EqualFold(arg, "clear") || clearedDeadline(strings.ToLower(arg))):
+	case strings.HasPrefix(arg, "+"):
+		// moves the current deadline, or today if there's none.
+		from, err := c.deadline(ctx, issue)
+		if err != nil {
+			return err
+		}
+		if from.IsZero() {
+			from = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+		}
+		var ok bool
+		if deadline, ok = addShortDuration(arg[1:], from); !ok {
+			return reply(usage)
+		}
+	default:
+		if deadline = c.dates(issue).parse(arg, now); deadline.IsZero() {
+			return reply(usage)
+		}
+	}
+
+	cd := commandDeadline{Deadline: deadline, By: cmd.Author, Time: now}
+	if err := c.opts.store.Put(ctx, commandDeadlineKey(c.appID, c.installationID, cmd.Owner, cmd.Repo, cmd.Number), cd); err != nil {
+		return errors.Wrap(err, "could not store deadline")
+	}
+	if deadline.IsZero() {
+		return reply("the deadline of this issue was cleared.")
+	}
+	layout := "January 2, 2006"
+	if !c.allDay(deadline) {
+		layout = "January 2, 2006 15:04 MST"
+	}
+	return reply(fmt.Sprintf("the deadline of this issue is now %s.", deadline.In(loc).Format(layout)))
+}
@@ -0,0 +1,61 @@
+package reminder
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeadlineCommand(t *testing.T) {
+	var replies []string
+	is := &issue{
+		repo: repository{"foo", "bar"}, number: 1, author: "francesc", state: "open",
+		body: "deadline: 2018-07-01", created: time.Now().Add(-time.Hour),
+	}
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil), client: &fakeClient{
+		_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) { return is, nil },
+		_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error {
+			replies = append(replies, body)
+			return nil
+		},
+	}}
+
+	ctx := context.Background()
+	deadline := func() time.Time {
+		d, err := ic.deadline(ctx, is)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return d
+	}
+	run := func(body, reply string) {
+		if _, err := ic.HandleComment(ctx, "foo", "bar", 1, "alice", body); err != nil {
+			t.Fatalf("%s: unexpected error: %v", body, err)
+		}
+		if last := replies[len(replies)-1]; !strings.Contains(last, reply) {
+			t.Fatalf("%s: expected reply %q; got %q", body, reply, last)
+		}
+	}
+
+	run("/deadline 2018-08-01", "is now August 1, 2018")
+	if d := deadline(); !d.Equal(time.Date(2018, 8, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the command to replace the deadline in the body; got %v", d)
+	}
+	run("/deadline +1w", "is now August 8, 2018")
+	if d := deadline(); !d.Equal(time.Date(2018, 8, 8, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the deadline to be moved a week; got %v", d)
+	}
+	run("/deadline soon", "usage")
+
+	is.comments = append(is.comments, comment{author: "bob", body: "deadline: 2018-09-01", created: time.Now().Add(time.Minute)})
+	if d := deadline(); !d.Equal(time.Date(2018, 9, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected later comments to replace the command; got %v", d)
+	}
+
+	is.comments = nil
+	run("/deadline clear", "was cleared")
+	if d := deadline(); !d.IsZero() || !is.cleared {
+		t.Errorf("expected the deadline to be cleared; got %v", d)
+	}
+}
@@ -0,0 +1,278 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// NewDemoClient returns a client working on a built-in dataset instead of GitHub,
+// printing every change it performs to w. It requires no credentials and lets
+// users try the bot before registering a GitHub App.
+func NewDemoClient(w io.Writer, opts ...Option) *InstallationClient {
+	return newInstallationClient(0, 0, newDemoData(w, time.Now()), newOptions(opts))
+}
+
+// demoClient is an in memory client for a fictional organization.
+type demoClient struct {
+	w      io.Writer
+	labels []string
+	data   map[int]*issue
+}
+
+const demoOwner, demoRepo = "acme", "rocket"
+
+func newDemoData(w io.Writer, now time.Time) *demoClient {
+	repo := repository{demoOwner, demoRepo}
+	date := func(days int) string { return now.AddDate(0, 0, days).Format("2006-01-02") }
+	url := func(n int) string { return fmt.Sprintf("https://github.com/%s/%s/issues/%d", demoOwner, demoRepo, n) }
+
+	issues := []*issue{
+		{number: 1, title: "Ship the 1.0 release", author: "alice",
+			body: "We promised the launch to our customers.\n\ndeadline: " + date(3)},
+		{number: 2, title: "Migrate CI to the new runners", author: "bob",
+			body:   "The old runners are being decommissioned.\n\ndeadline: " + date(20),
+			labels: []string{"deadline < 5"}},
+		{number: 3, title: "Write the upgrade guide", author: "carol", assignees: []string{"dave"},
+			body:     "Needed for the release notes.",
+			comments: []comment{{author: "carol", body: "reminder: " + date(0), created: now.AddDate(0, 0, -2)}}},
+		{number: 4, title: "Refactor the storage layer", author: "dave", pullRequest: true,
+			body: "No rush on this one.\n\ndeadline: " + date(60)},
+		{number: 5, title: "Fix the flaky login test", author: "erin", state: "closed", closed: now.AddDate(0, 0, -1),
+			body: "deadline: " + date(1), labels: []string{"deadline < 5"}},
+	}
+
+	c := &demoClient{
+		w:      w,
+		labels: []string{"bug", "deadline < 5", "deadline < 30"},
+		data:   make(map[int]*issue),
+	}
+	for _, i := range issues {
+		i.repo = repo
+		i.url = url(i.number)
+		if i.state == "" {
+			i.state = "open"
+		}
+		c.data[i.number] = i
+	}
+	return c
+}
+
+func (c *demoClient) installations(ctx context.Context) ([]int, error) { return []int{0}, nil }
+
+func (c *demoClient) repoInstallation(ctx context.Context, owner, repo string) (int, error) {
+	return 0, nil
+}
+
+func (c *demoClient) repos(ctx context.Context) ([]repository, error) {
+	return []repository{{demoOwner, demoRepo}}, nil
+}
+
+func (c *demoClient) repoLabels(ctx context.Context, owner, repo string) ([]string, error) {
+	return c.labels, nil
+}
+
+// labelColors returns no colors, as if every label had been created by hand.
+func (c *demoClient) labelColors(ctx context.Context, owner, repo string) (map[string]string, error) {
+	return nil, nil
+}
+
+func (c *demoClient) issues(ctx context.Context, owner, repo string) ([]int, error) {
+	var numbers []int
+	for n, i := range c.data {
+		if i.state == "open" {
+			numbers = append(numbers, n)
+		}
+	}
+	sort.Ints(numbers)
+	return numbers, nil
+}
+
+func (c *demoClient) closedIssues(ctx context.Context, owner, repo string, page int) ([]int, int, error) {
+	var numbers []int
+	for n, i := range c.data {
+		if i.state != "open" {
+			numbers = append(numbers, n)
+		}
+	}
+	sort.Ints(numbers)
+	return numbers, 0, nil
+}
+
+func (c *demoClient) issue(ctx context.Context, owner, repo string, number int) (*issue, error) {
+	i, ok := c.data[number]
+	if !ok {
+		return nil, errors.Errorf("could not fetch issue %s/%s#%d: not found", owner, repo, number)
+	}
+	cp := *i
+	cp.labels = append([]string(nil), i.labels...)
+	cp.comments = append([]comment(nil), i.comments...)
+	return &cp, nil
+}
+
+func (c *demoClient) issueLabels(ctx context.Context, owner, repo string, number int) ([]string, error) {
+	i, err := c.issue(ctx, owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	return i.labels, nil
+}
+
+func (c *demoClient) createIssueComment(ctx context.Context, owner, repo string, number int, body string) error {
+	fmt.Fprintf(c.w, "%s/%s#%d: comment %q\n", owner, repo, number, body)
+	if i, ok := c.data[number]; ok {
+		i.comments = append(i.comments, comment{author: botLogin, body: body, created: time.Now()})
+	}
+	return nil
+}
+
+func (c *demoClient) createIssue(ctx context.Context, owner, repo, title, body string) (int, error) {
+	number := 1
+	for n := range c.data {
+		if n >= number {
+			number = n + 1
+		}
+	}
+	fmt.Fprintf(c.w, "%s/%s#%d: open issue %q\n", owner, repo, number, title)
+	c.data[number] = &issue{repo: repository{owner, repo}, number: number, title: title, body: body,
+		author: botLogin, state: "open", created: time.Now(),
+		url: fmt.Sprintf("https://github.com/%s/%s/issues/%d", owner, repo, number)}
+	return number, nil
+}
+
+func (c *demoClient) editIssueBody(ctx context.Context, owner, repo string, number int, body string) error {
+	i, ok := c.data[number]
+	if !ok {
+		return errors.Errorf("issue %d not found", number)
+	}
+	fmt.Fprintf(c.w, "%s/%s#%d: edit body\n", owner, repo, number)
+	i.body = body
+	return nil
+}
+
+func (c *demoClient) pinIssue(ctx context.Context, owner, repo string, number int) error {
+	fmt.Fprintf(c.w, "%s/%s#%d: pin issue\n", owner, repo, number)
+	return nil
+}
+
+func (c *demoClient) headSHA(ctx context.Context, owner, repo string, number int) (string, error) {
+	return fmt.Sprintf("%040x", number), nil
+}
+
+func (c *demoClient) createCheckRun(ctx context.Context, owner, repo string, run checkRun) error {
+	fmt.Fprintf(c.w, "%s/%s@%.7s: check run %s, %s\n", owner, repo, run.sha, run.conclusion, run.title)
+	return nil
+}
+
+func (c *demoClient) createStatus(ctx context.Context, owner, repo string, status commitStatus) error {
+	fmt.Fprintf(c.w, "%s/%s@%.7s: status %s, %s\n", owner, repo, status.sha, status.state, status.description)
+	return nil
+}
+
+func (c *demoClient) setProjectDate(ctx context.Context, owner, repo string, number int, field string, date time.Time) error {
+	fmt.Fprintf(c.w, "%s/%s#%d: set project field %q to %s\n", owner, repo, number, field, date.Format("2006-01-02"))
+	return nil
+}
+
+func (c *demoClient) projectItems(ctx context.Context, owner, repo string, number int) ([]string, error) {
+	return []string{"demo"}, nil
+}
+
+func (c *demoClient) addToProject(ctx context.Context, owner, repo string, number int, p Project) error {
+	fmt.Fprintf(c.w, "%s/%s#%d: add to project %s\n", owner, repo, number, p)
+	return nil
+}
+
+func (c *demoClient) moveProjectItem(ctx context.Context, owner, repo string, number int, p Project, field, column string) (bool, error) {
+	fmt.Fprintf(c.w, "%s/%s#%d: move to %s in project %s\n", owner, repo, number, column, p)
+	return true, nil
+}
+
+func (c *demoClient) removeIssueLabel(ctx context.Context, owner, repo string, number int, label string) error {
+	i, ok := c.data[number]
+	if !ok {
+		return errors.Errorf("issue %d not found", number)
+	}
+	for j, l := range i.labels {
+		if l == label {
+			fmt.Fprintf(c.w, "%s/%s#%d: remove label %q\n", owner, repo, number, label)
+			i.labels = append(i.labels[:j], i.labels[j+1:]...)
+			return nil
+		}
+	}
+	return errors.Errorf("label %s not found", label)
+}
+
+// replaceIssueLabels shows the labels removed and added as separate changes.
+func (c *demoClient) replaceIssueLabels(ctx context.Context, owner, repo string, number int, labels []string) error {
+	i, ok := c.data[number]
+	if !ok {
+		return errors.Errorf("issue %d not found", number)
+	}
+	for _, l := range i.labels {
+		if !hasLabel(labels, l) {
+			fmt.Fprintf(c.w, "%s/%s#%d: remove label %q\n", owner, repo, number, l)
+		}
+	}
+	for _, l := range labels {
+		if !hasLabel(i.labels, l) {
+			fmt.Fprintf(c.w, "%s/%s#%d: add label %q\n", owner, repo, number, l)
+		}
+	}
+	i.labels = append([]string(nil), labels...)
+	return nil
+}
+
+func (c *demoClient) addIssueLabel(ctx context.Context, owner, repo string, number int, label string) error {
+	i, ok := c.data[number]
+	if !ok {
+		return errors.Errorf("issue %d not found", number)
+	}
+	for _, l := range i.labels {
+		if l == label {
+			return nil
+		}
+	}
+	fmt.Fprintf(c.w, "%s/%s#%d: add label %q\n", owner, repo, number, label)
+	i.labels = append(i.labels, label)
+	return nil
+}
+
+func (c *demoClient) editLabelColor(ctx context.Context, owner, repo, label, color string) error {
+	fmt.Fprintf(c.w, "%s/%s: color label %q #%s\n", owner, repo, label, color)
+	return nil
+}
+
+func (c *demoClient) createLabel(ctx context.Context, owner, repo, label, color string) error {
+	fmt.Fprintf(c.w, "%s/%s: create label %q #%s\n", owner, repo, label, color)
+	return nil
+}
+
+func (c *demoClient) minimizeComment(ctx context.Context, owner, repo string, id int64) error {
+	fmt.Fprintf(c.w, "%s/%s: minimize comment %d\n", owner, repo, id)
+	return nil
+}
+
+func (c *demoClient) projectDate(ctx context.Context, owner, repo string, number int, field string) (time.Time, error) {
+	return time.Time{}, nil
+}
+
+func (c *demoClient) permission(ctx context.Context, owner, repo, user string) (string, error) {
+	return "write", nil
+}
+
+func (c *demoClient) files(ctx context.Context, owner, repo string, number int) ([]string, error) {
+	return nil, nil
+}
+
+func (c *demoClient) file(ctx context.Context, owner, repo, path string) ([]byte, error) {
+	return nil, nil
+}
+
+func (c *demoClient) labelEvents(ctx context.Context, owner, repo string, number int) ([]labelEvent, error) {
+	return nil, nil
+}
@@ -0,0 +1,33 @@
+package reminder
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestDemoClient(t *testing.T) {
+	var out bytes.Buffer
+	c := NewDemoClient(&out)
+	if err := c.UpdateInstallation(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, s := range []string{
+		`acme/rocket#1: add label "deadline < 5"`,
+		`acme/rocket#2: remove label "deadline < 5"`,
+		`acme/rocket#3: comment`,
+	} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("expected output to contain %s; got:\n%s", s, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := c.UpdateInstallation(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("expected second scan to change nothing; got:\n%s", out.String())
+	}
+}
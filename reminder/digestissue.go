@@ -0,0 +1,153 @@
+package reminder
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/github"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+// digestMarker is hidden in the body of the digest issues to tell them apart
+// from the rest, so the bot doesn't read the dates in them as deadlines.
+const digestMarker = "<!-- github-reminder:digest -->"
+
+// A DigestIssue is an issue the bot opens and pins in each repository,
+// keeping its body up to date with a table of the open issues with a
+// deadline, sorted by due date.
+type DigestIssue struct {
+	// Title of the issue, "Upcoming deadlines" if empty.
+	Title string
+	// Only replaces the cadence reminders with the digest issue, instead of
+	// posting both.
+	Only bool
+}
+
+// WithDigestIssue makes the bot keep a digest issue in every repository,
+// updated on every scan. Closing it stops the updates until it's reopened.
+func WithDigestIssue(d DigestIssue) Option {
+	if d.Title == "" {
+		d.Title = "Upcoming deadlines"
+	}
+	return func(o *options) { o.digestIssue = &d }
+}
+
+func digestIssueKey(appID, installationID int, owner, repo string) string {
+	return storage.Key("digestissue", appID, installationID, strings.ToLower(owner), strings.ToLower(repo))
+}
+
+// isDigestIssue reports whether the issue is a digest issue of the bot.
+func isDigestIssue(issue *issue) bool {
+	return strings.Contains(issue.body, digestMarker)
+}
+
+// updateDigestIssue opens and pins the digest issue of the repository if it
+// doesn't have one yet, or it was deleted, or updates its body with the
+// current deadlines.
+func (c *InstallationClient) updateDigestIssue(ctx context.Context, owner, repo string) error {
+	if c.opts.digestIssue == nil {
+		return nil
+	}
+	ds, err := listDeadlines(ctx, c.opts.store, storage.Key("deadline", c.appID, c.installationID, owner, repo)+"/")
+	if err != nil {
+		return err
+	}
+	body := digestBody(ds, time.Now())
+
+	key := digestIssueKey(c.appID, c.installationID, owner, repo)
+	var number int
+	err = c.opts.store.Get(ctx, key, &number)
+	if err != nil && err != storage.ErrNotFound {
+		return errors.Wrap(err, "could not fetch digest issue")
+	}
+	if err == storage.ErrNotFound {
+		return c.openDigestIssue(ctx, owner, repo, key, body)
+	}
+
+	issue, err := c.client.issue(ctx, owner, repo, number)
+	if isGone(err) {
+		logrus.Infof("digest issue %s/%s#%d is gone", owner, repo, number)
+		return c.openDigestIssue(ctx, owner, repo, key, body)
+	}
+	if err != nil {
+		return err
+	}
+	if issue.state != "open" || issue.body == body {
+		return nil
+	}
+	logrus.Debugf("updating digest issue %s/%s#%d", owner, repo, number)
+	err = c.client.editIssueBody(ctx, owner, repo, number, body)
+	return errors.Wrapf(err, "could not update digest issue %s/%s#%d", owner, repo, number)
+}
+
+// openDigestIssue opens and pins a digest issue in the repository, storing its
+// number under key.
+func (c *InstallationClient) openDigestIssue(ctx context.Context, owner, repo, key, body string) error {
+	logrus.Infof("opening digest issue in %s/%s", owner, repo)
+	// only a few issues can be pinned in each repository, so the digest
+	// issue is kept even if it can't be.
+	err := c.openIssue(ctx, owner, repo, c.opts.digestIssue.Title, body, key, true)
+	return errors.Wrapf(err, "could not open digest issue in %s/%s", owner, repo)
+}
+
+// isGone checks whether err is GitHub answering that an issue doesn't exist,
+// or was deleted.
+func isGone(err error) bool {
+	res, ok := errors.Cause(err).(*github.ErrorResponse)
+	return ok && res.Response != nil &&
+		(res.Response.StatusCode == http.StatusNotFound || res.Response.StatusCode == http.StatusGone)
+}
+
+// digestBody returns the body of a digest issue listing the deadlines, sorted
+// by due date, at now.
+func digestBody(ds []Deadline, now time.Time) string {
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, digestMarker)
+	if len(ds) == 0 {
+		fmt.Fprintln(&buf, "No open issue has a deadline.")
+		return buf.String()
+	}
+	fmt.Fprintln(&buf, "The open issues with a deadline, by due date, updated by the bot on every scan.")
+	fmt.Fprintln(&buf)
+	fmt.Fprintln(&buf, "| Issue | Due | Time left |")
+	fmt.Fprintln(&buf, "| --- | --- | --- |")
+	for _, d := range ds {
+		title := d.Title
+		if d.Checkpoint != "" {
+			title += " (" + d.Checkpoint + ")"
+		}
+		fmt.Fprintf(&buf, "| #%d %s | %s | %s |\n",
+			d.Number, escapeCell(title), d.Deadline.Format("Mon, Jan 2 2006"), daysLeft(d.Deadline, now))
+	}
+	return buf.String()
+}
+
+// escapeCell escapes the text of a cell of a Markdown table.
+func escapeCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
+
+// daysLeft describes the whole days left at now until the deadline.
+func daysLeft(deadline, now time.Time) string {
+	left := deadline.Sub(now)
+	days := int(left.Hours() / 24)
+	switch {
+	case days < -1:
+		return fmt.Sprintf("overdue by %d days", -days)
+	case left < 0:
+		return "overdue"
+	case days == 0:
+		return "due in less than a day"
+	case days == 1:
+		return "1 day left"
+	default:
+		return fmt.Sprintf("%d days left", days)
+	}
+}
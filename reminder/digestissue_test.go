@@ -0,0 +1,108 @@
+package reminder
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/github"
+	"github.com/pkg/errors"
+)
+
+func TestDigestIssue(t *testing.T) {
+	in := func(days int) string { return time.Now().AddDate(0, 0, days).Format("2006-01-02") }
+	issues := map[int]*issue{
+		1: {number: 1, state: "open", title: "Ship | it", body: "deadline: " + in(3)},
+		2: {number: 2, state: "open", title: "Docs", body: "deadline: " + in(10)},
+		3: {number: 3, state: "open", title: "No rush"},
+	}
+	var created, edited, pinned []string
+	fc := &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) { return nil, nil },
+		_issues: func(ctx context.Context, owner, repo string) ([]int, error) {
+			var numbers []int
+			for n := range issues {
+				numbers = append(numbers, n)
+			}
+			return numbers, nil
+		},
+		_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+			if issues[number] == nil {
+				return nil, &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusGone}}
+			}
+			cp := *issues[number]
+			cp.repo = repository{owner, repo}
+			return &cp, nil
+		},
+		_createIssue: func(ctx context.Context, owner, repo, title, body string) (int, error) {
+			if title == "" {
+				return 0, errors.New("validation failed")
+			}
+			created = append(created, title)
+			issues[10] = &issue{number: 10, state: "open", title: title, body: body, author: botLogin}
+			return 10, nil
+		},
+		_editIssueBody: func(ctx context.Context, owner, repo string, number int, body string) error {
+			edited = append(edited, body)
+			issues[number].body = body
+			return nil
+		},
+		_pinIssue: func(ctx context.Context, owner, repo string, number int) error {
+			pinned = append(pinned, "#10")
+			return nil
+		},
+	}
+	ic := InstallationClient{appID: 42, installationID: 43, client: fc,
+		opts: newOptions([]Option{WithDigestIssue(DigestIssue{})})}
+	update := func() {
+		if err := ic.UpdateRepo(context.Background(), "foo", "bar"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	update()
+	if len(created) != 1 || created[0] != "Upcoming deadlines" || len(pinned) != 1 {
+		t.Fatalf("expected a pinned digest issue; got %v, pinned %v", created, pinned)
+	}
+	body := issues[10].body
+	first, second := strings.Index(body, `#1 Ship \| it`), strings.Index(body, "#2 Docs")
+	if first < 0 || second < first || strings.Contains(body, "#3") {
+		t.Errorf("expected #1 and #2 by due date in the digest; got %q", body)
+	}
+
+	// the digest isn't read as an issue with deadlines, nor updated if unchanged.
+	update()
+	if len(created) != 1 || len(edited) != 0 {
+		t.Fatalf("expected the digest issue to be kept as is; got %v and %q", created, edited)
+	}
+
+	issues[2].body = "deadline: " + in(1)
+	update()
+	if len(edited) != 1 || strings.Index(edited[0], "#2 Docs") > strings.Index(edited[0], "#1 Ship") {
+		t.Errorf("expected #2 before #1 in the updated digest; got %q", edited)
+	}
+
+	issues[10].state = "closed"
+	issues[2].body = ""
+	update()
+	if len(edited) != 1 {
+		t.Errorf("expected a closed digest issue not to be updated; got %q", edited)
+	}
+
+	// a deleted digest issue is opened again.
+	delete(issues, 10)
+	update()
+	if len(created) != 2 || issues[10] == nil {
+		t.Errorf("expected the deleted digest issue to be opened again; got %v", created)
+	}
+
+	// the repository is still scanned if the digest issue can't be opened.
+	delete(issues, 10)
+	ic.opts.digestIssue.Title = ""
+	update()
+	if len(created) != 2 {
+		t.Errorf("expected the digest issue not to be opened; got %v", created)
+	}
+}
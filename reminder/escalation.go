@@ -0,0 +1,91 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/notify"
+	"github.com/src-d/github-reminder/storage"
+)
+
+// A Pager opens and resolves incidents, like notify.PagerDuty.
+type Pager interface {
+	// Trigger opens the incident identified by key for the event.
+	Trigger(ctx context.Context, key string, e notify.Event) error
+	// Resolve resolves the incident identified by key.
+	Resolve(ctx context.Context, key string) error
+}
+
+// An Escalation opens an incident for the open issues carrying any of its
+// Labels, like "severity: critical", once their deadline passes, and resolves
+// it when the issue is closed, or its deadline is moved or cleared.
+type Escalation struct {
+	Labels []string
+	Pager  Pager
+}
+
+// WithEscalation makes the bot open incidents for the critical overdue issues.
+func WithEscalation(e Escalation) Option {
+	return func(o *options) { o.escalation = &e }
+}
+
+func escalationKey(appID, installationID int, owner, repo string, number int) string {
+	return storage.Key("escalation", appID, installationID, strings.ToLower(owner), strings.ToLower(repo), number)
+}
+
+// checkEscalation opens an incident for the issue if it's critical and its
+// deadline, zero if it has none or it's closed, has passed, and resolves the
+// one opened before otherwise.
+func (c *InstallationClient) checkEscalation(ctx context.Context, issue *issue, deadline time.Time) error {
+	esc := c.opts.escalation
+	if esc == nil {
+		return nil
+	}
+	owner, repo, number := issue.repo.owner, issue.repo.name, issue.number
+	key := escalationKey(c.appID, c.installationID, owner, repo, number)
+	var open bool
+	if err := c.opts.store.Get(ctx, key, &open); err != nil && err != storage.ErrNotFound {
+		return errors.Wrap(err, "could not fetch escalation")
+	}
+
+	critical := false
+	for _, l := range issue.labels {
+		for _, e := range esc.Labels {
+			critical = critical || strings.EqualFold(l, e)
+		}
+	}
+	overdue := critical && issue.state == "open" && c.overdue(deadline)
+	// incidents are identified by issue, so they're the same across scans.
+	id := fmt.Sprintf("github-reminder/%s/%s#%d", strings.ToLower(owner), strings.ToLower(repo), number)
+	switch {
+	case overdue && !open:
+		logrus.Debugf("opening incident for %s/%s#%d", owner, repo, number)
+		e := issue.event(notify.Overdue, fmt.Sprintf("%s/%s#%d %s is overdue", owner, repo, number, issue.title))
+		e.User, e.Deadline = issue.author, deadline
+		d := deferredPut(key, true)
+		d.Incident, d.Event = id, &e
+		return c.whenApplied(ctx, d)
+	case !overdue && open:
+		logrus.Debugf("resolving incident for %s/%s#%d", owner, repo, number)
+		return c.whenApplied(ctx, Deferred{Incident: id, Key: key})
+	}
+	return nil
+}
+
+// resolvePrunedEscalation resolves the incident of an issue that is no longer
+// open, in case it was closed while the bot missed its webhook. Failures are
+// only logged, like when pruning the rest of its state.
+func (c *InstallationClient) resolvePrunedEscalation(ctx context.Context, d Deadline) {
+	if c.opts.escalation == nil {
+		return
+	}
+	is := &issue{repo: repository{d.Owner, d.Repo}, number: d.Number, state: "closed"}
+	if err := c.checkEscalation(ctx, is, time.Time{}); err != nil {
+		logrus.Warnf("could not resolve incident of %s/%s#%d: %v", d.Owner, d.Repo, d.Number, err)
+	}
+}
@@ -0,0 +1,108 @@
+package reminder
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/src-d/github-reminder/notify"
+)
+
+// fakePager records the incidents triggered and resolved.
+type fakePager struct {
+	calls []string
+}
+
+func (p *fakePager) Trigger(ctx context.Context, key string, e notify.Event) error {
+	p.calls = append(p.calls, "trigger "+key)
+	return nil
+}
+
+func (p *fakePager) Resolve(ctx context.Context, key string) error {
+	p.calls = append(p.calls, "resolve "+key)
+	return nil
+}
+
+func TestEscalation(t *testing.T) {
+	is := &issue{repo: repository{"foo", "bar"}, number: 1, state: "open", body: "deadline: 2018-01-01", labels: []string{"Sev1"}}
+	fc := &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) { return []string{"deadline < 5"}, nil },
+		_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+			cp := *is
+			return &cp, nil
+		},
+		_addIssueLabel:    func(ctx context.Context, owner, repo string, number int, label string) error { return nil },
+		_removeIssueLabel: func(ctx context.Context, owner, repo string, number int, label string) error { return nil },
+		_replaceIssueLabels: func(ctx context.Context, owner, repo string, number int, labels []string) error {
+			return nil
+		},
+	}
+	pager := &fakePager{}
+	ic := InstallationClient{appID: 42, installationID: 43, client: fc,
+		opts: newOptions([]Option{WithEscalation(Escalation{Labels: []string{"sev1"}, Pager: pager})})}
+	update := func(expected ...string) {
+		pager.calls = nil
+		if err := ic.UpdateIssue(context.Background(), "foo", "bar", 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(pager.calls, expected) {
+			t.Errorf("expected %v; got %v", expected, pager.calls)
+		}
+	}
+
+	const key = "github-reminder/foo/bar#1"
+	update("trigger " + key)
+	update()
+
+	is.body = "deadline: " + time.Now().AddDate(0, 0, 3).Format("2006-01-02")
+	update("resolve " + key)
+
+	is.body, is.labels = "deadline: 2018-01-01", nil
+	update()
+
+	is.labels = []string{"sev1"}
+	update("trigger " + key)
+	is.state = "closed"
+	update("resolve " + key)
+	update()
+
+	// issues closed while the webhook was missed are resolved when pruned.
+	is.state = "open"
+	update("trigger " + key)
+	if err := ic.pruneDeadlines(context.Background(), "foo", "bar", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"trigger " + key, "resolve " + key}; !reflect.DeepEqual(pager.calls, expected) {
+		t.Errorf("expected %v; got %v", expected, pager.calls)
+	}
+}
+
+func TestEscalationHeld(t *testing.T) {
+	is := &issue{repo: repository{"foo", "bar"}, number: 1, state: "open", body: "deadline: 2018-01-01", labels: []string{"sev1"}}
+	fc := &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) { return []string{"deadline < 5"}, nil },
+		_issues:     func(ctx context.Context, owner, repo string) ([]int, error) { return []int{1, 2}, nil },
+		_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+			cp := *is
+			cp.number = number
+			return &cp, nil
+		},
+	}
+	pager := &fakePager{}
+	ic := InstallationClient{appID: 42, installationID: 43, client: fc, opts: newOptions([]Option{
+		WithEscalation(Escalation{Labels: []string{"sev1"}, Pager: pager}),
+		WithApprovalThreshold(1),
+		WithOverdueLabel("overdue"),
+	})}
+	ctx := context.Background()
+	if err := ic.UpdateRepo(ctx, "foo", "bar"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cs, err := ic.PendingChangeset(ctx, "foo", "bar"); err != nil || cs == nil {
+		t.Fatalf("expected the changes to be held; got %v, %v", cs, err)
+	}
+	if len(pager.calls) != 0 {
+		t.Errorf("expected no incident while the changes are held; got %v", pager.calls)
+	}
+}
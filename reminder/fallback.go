@@ -0,0 +1,95 @@
+package reminder
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/notify"
+	"github.com/src-d/github-reminder/storage"
+)
+
+// WithFallback sets the notifier used to deliver the reminders of locked issues,
+// where the bot can't rely on commenting, such as one sending them by Slack or email.
+// Without it reminders are commented on locked issues too.
+func WithFallback(n notify.Notifier) Option {
+	return func(o *options) { o.fallback = n }
+}
+
+// A Fallback is a reminder delivered through the fallback notifier.
+type Fallback struct {
+	Owner   string    `json:"owner"`
+	Repo    string    `json:"repo"`
+	Number  int       `json:"number"`
+	User    string    `json:"user"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+func fallbackPrefix(appID, installationID int, owner, repo string, number int) string {
+	return storage.Key("fallback", appID, installationID, owner, repo, number) + "/"
+}
+
+// Fallbacks lists the reminders of an installation delivered through the fallback notifier, oldest first.
+func Fallbacks(ctx context.Context, store storage.Store, appID, installationID int) ([]Fallback, error) {
+	return listFallbacks(ctx, store, storage.Key("fallback", appID, installationID)+"/")
+}
+
+func listFallbacks(ctx context.Context, store storage.Store, prefix string) ([]Fallback, error) {
+	keys, err := store.List(ctx, prefix)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list fallbacks")
+	}
+
+	var res []Fallback
+	for _, key := range keys {
+		var f Fallback
+		if err := store.Get(ctx, key, &f); err != nil {
+			return nil, errors.Wrapf(err, "could not fetch fallback %s", key)
+		}
+		res = append(res, f)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Time.Before(res[j].Time) })
+	return res, nil
+}
+
+// postReminder comments the reminder for user on the issue or, if the issue is
+// locked and there's a fallback notifier, sends it through the fallback instead.
+func (c *InstallationClient) postReminder(ctx context.Context, issue *issue, user, text string) error {
+	owner, repo, number := issue.repo.owner, issue.repo.name, issue.number
+	if !issue.locked || c.opts.fallback == nil {
+		if err := c.client.createIssueComment(ctx, owner, repo, number, text); err != nil {
+			return errors.Wrapf(err, "could not comment on %s/%s#%d", owner, repo, number)
+		}
+		return c.minimizeReminders(ctx, issue)
+	}
+
+	logrus.Infof("%s/%s#%d is locked, sending reminder for %s through the fallback", owner, repo, number, user)
+	e := issue.event(notify.Reminder, text)
+	e.User = user
+	c.deliver(ctx, PendingEvent{Event: e, Notifier: fallbackNotifier})
+
+	f := Fallback{Owner: owner, Repo: repo, Number: number, User: user, Message: text, Time: time.Now()}
+	key := fallbackPrefix(c.appID, c.installationID, owner, repo, number) + f.Time.UTC().Format(time.RFC3339Nano)
+	return errors.Wrap(c.opts.store.Put(ctx, key, f), "could not record fallback")
+}
+
+// addFallbacks adds the reminders sent through the fallback for a locked issue
+// to its comments, as if they had been commented, so they're not sent again.
+func (c *InstallationClient) addFallbacks(ctx context.Context, issue *issue) error {
+	if !issue.locked || c.opts.fallback == nil {
+		return nil
+	}
+	fs, err := listFallbacks(ctx, c.opts.store,
+		fallbackPrefix(c.appID, c.installationID, issue.repo.owner, issue.repo.name, issue.number))
+	if err != nil {
+		return err
+	}
+	for _, f := range fs {
+		issue.comments = append(issue.comments, comment{author: c.login(), body: f.Message, created: f.Time})
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package reminder
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/src-d/github-reminder/notify"
+)
+
+func TestLockedIssueFallback(t *testing.T) {
+	ctx := context.Background()
+	today := time.Now().UTC().Format("2006-01-02")
+
+	var comments []string
+	var events []notify.Event
+	fc := &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) { return nil, nil },
+		_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+			return &issue{repo: repository{"foo", "bar"}, number: 1, author: "francesc", state: "open",
+				locked: true, body: "reminder: " + today}, nil
+		},
+		_files: func(ctx context.Context, owner, repo string, number int) ([]string, error) { return nil, nil },
+		_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error {
+			comments = append(comments, body)
+			return nil
+		},
+	}
+	fallback := notify.NotifierFunc(func(ctx context.Context, e notify.Event) error {
+		events = append(events, e)
+		return nil
+	})
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions([]Option{WithFallback(fallback)}), client: fc}
+
+	for run := 0; run < 2; run++ {
+		if err := ic.UpdateIssue(ctx, "foo", "bar", 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(comments) != 0 {
+		t.Errorf("expected no comments on a locked issue; got %v", comments)
+	}
+	if len(events) != 1 || events[0].User != "francesc" || events[0].Kind != notify.Reminder {
+		t.Fatalf("expected a single reminder for francesc through the fallback; got %+v", events)
+	}
+
+	fs, err := Fallbacks(ctx, ic.opts.store, 42, 43)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fs) != 1 || fs[0].Number != 1 || fs[0].User != "francesc" {
+		t.Errorf("expected the fallback to be recorded; got %+v", fs)
+	}
+}
@@ -0,0 +1,59 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// WithDateFeedback makes the bot reply once to each deadline it can't read,
+// explaining the formats it understands, instead of silently ignoring it.
+func WithDateFeedback() Option {
+	return func(o *options) { o.dateFeedback = true }
+}
+
+// unreadMarker marks the comments explaining a deadline couldn't be read.
+func unreadMarker(s string) string {
+	return fmt.Sprintf("<!-- github-reminder:unread %s -->", s)
+}
+
+// dateFormats explains the formats of the deadlines understood by the bot.
+// The examples don't follow the keyword, so they're not read as deadlines.
+const dateFormats = "After the `deadline:` keyword, dates can be written like `2018-08-01`, `2018-08-01 15:30 CET`, " +
+	"`August 1, 2018`, `in 5 days`, `2w`, or `next friday`, and `none` clears the deadline."
+
+// explainDates replies once to the deadlines of the issue that couldn't be
+// read, if enabled, listing them in a single comment.
+func (c *InstallationClient) explainDates(ctx context.Context, issue *issue) error {
+	if !c.opts.dateFeedback {
+		return nil
+	}
+
+	d := c.dates(issue)
+	var lines, markers []string
+	seen := make(map[string]bool)
+	for _, kv := range c.keywordValues(issue) {
+		v := strings.TrimSpace(kv.value)
+		if kv.keyword != "deadline" || !strings.HasPrefix(v, ":") {
+			continue
+		}
+		v = strings.TrimSpace(strings.TrimPrefix(v, ":"))
+		if v == "" || seen[v] || clearedDeadline(v) || !d.parse(v, kv.created).IsZero() {
+			continue
+		}
+		seen[v] = true
+		marker := unreadMarker(v)
+		if c.botCommented(issue, marker) {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("- `deadline: %s`", v))
+		markers = append(markers, marker)
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	text := fmt.Sprintf("hi @%s, I couldn't understand these deadlines, so they were not registered:\n%s\n\n%s\n%s",
+		issue.author, strings.Join(lines, "\n"), dateFormats, strings.Join(markers, "\n"))
+	return c.Comment(ctx, issue.repo.owner, issue.repo.name, issue.number, text)
+}
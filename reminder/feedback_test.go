@@ -0,0 +1,50 @@
+package reminder
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDateFeedback(t *testing.T) {
+	var comments []comment
+	body := "deadline: the day after the release\nthe deadline is tight\ndeadline: none"
+	fc := &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) { return nil, nil },
+		_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+			return &issue{repo: repository{owner, repo}, number: number, state: "open", author: "francesc", body: body, comments: comments}, nil
+		},
+		_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error {
+			comments = append(comments, comment{author: botLogin, body: body, created: time.Now()})
+			return nil
+		},
+	}
+
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil), client: fc}
+	if err := ic.UpdateIssue(context.Background(), "foo", "bar", 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(comments) != 0 {
+		t.Fatalf("expected no feedback unless enabled; got %v", comments)
+	}
+
+	ic.opts = newOptions([]Option{WithDateFeedback()})
+	for i := 0; i < 2; i++ {
+		if err := ic.UpdateIssue(context.Background(), "foo", "bar", 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(comments) != 1 {
+		t.Fatalf("expected a single reply; got %d comments", len(comments))
+	}
+	if text := comments[0].body; !strings.Contains(text, "`deadline: the day after the release`") ||
+		strings.Contains(text, "tight") || strings.Contains(text, "`deadline: none`") {
+		t.Errorf("expected only the unread deadline to be listed; got %q", text)
+	}
+
+	is, _ := fc.issue(context.Background(), "foo", "bar", 1)
+	if d, err := ic.deadline(context.Background(), is); err != nil || !d.IsZero() {
+		t.Errorf("expected the reply not to be read as a deadline; got %v (%v)", d, err)
+	}
+}
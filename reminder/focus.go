@@ -0,0 +1,54 @@
+package reminder
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// WithFocusLabel makes the bot apply the given label, like "this week", to the
+// issues whose deadline falls within the current week, from Monday to Sunday.
+// The label is removed once the week is over, so it gives a weekly focus view
+// maintained automatically.
+func WithFocusLabel(label string) Option {
+	return func(o *options) { o.focus = label }
+}
+
+// focusWeek returns the start and end of the week containing t.
+func focusWeek(t time.Time) (start, end time.Time) {
+	t = t.UTC()
+	days := (int(t.Weekday()) - int(time.Monday) + 7) % 7
+	start = time.Date(t.Year(), t.Month(), t.Day()-days, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 0, 7)
+}
+
+// checkFocus adds or removes the focus label depending on whether the deadline,
+// zero if the issue has none, falls within the current week.
+func (c *InstallationClient) checkFocus(ctx context.Context, issue *issue, deadline time.Time) error {
+	label := c.opts.focus
+	if label == "" {
+		return nil
+	}
+
+	start, end := focusWeek(time.Now())
+	want := !deadline.IsZero() && !deadline.Before(start) && deadline.Before(end)
+	has := false
+	for _, l := range issue.labels {
+		has = has || strings.EqualFold(l, label)
+	}
+
+	owner, repo, number := issue.repo.owner, issue.repo.name, issue.number
+	switch {
+	case want && !has:
+		if err := c.client.addIssueLabel(ctx, owner, repo, number, label); err != nil {
+			return errors.Wrapf(err, "could not apply label %s", label)
+		}
+	case !want && has:
+		if err := c.client.removeIssueLabel(ctx, owner, repo, number, label); err != nil {
+			return errors.Wrapf(err, "could not remove label %s", label)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package reminder
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestFocusWeek(t *testing.T) {
+	monday := time.Date(2018, 8, 6, 0, 0, 0, 0, time.UTC)
+	for _, day := range []time.Time{monday, monday.Add(15 * time.Hour), monday.AddDate(0, 0, 6).Add(23 * time.Hour)} {
+		start, end := focusWeek(day)
+		if !start.Equal(monday) || !end.Equal(monday.AddDate(0, 0, 7)) {
+			t.Errorf("expected week of %v to be %v-%v; got %v-%v", day, monday, monday.AddDate(0, 0, 7), start, end)
+		}
+	}
+}
+
+func TestCheckFocus(t *testing.T) {
+	start, _ := focusWeek(time.Now())
+	tests := []struct {
+		name     string
+		labels   []string
+		deadline time.Time
+		added    bool
+		removed  bool
+	}{
+		{"due this week", nil, start.AddDate(0, 0, 6), true, false},
+		{"already labeled", []string{"this week"}, start, false, false},
+		{"due next week", []string{"this week"}, start.AddDate(0, 0, 7), false, true},
+		{"overdue", []string{"This Week"}, start.AddDate(0, 0, -1), false, true},
+		{"no deadline", []string{"this week"}, time.Time{}, false, true},
+		{"no deadline nor label", nil, time.Time{}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var added, removed bool
+			ic := InstallationClient{opts: newOptions([]Option{WithFocusLabel("this week")}), client: &fakeClient{
+				_addIssueLabel: func(ctx context.Context, owner, repo string, number int, label string) error {
+					added = true
+					return nil
+				},
+				_removeIssueLabel: func(ctx context.Context, owner, repo string, number int, label string) error {
+					removed = true
+					return nil
+				},
+			}}
+			i := &issue{repo: repository{"foo", "bar"}, number: 1, labels: tt.labels}
+			if err := ic.checkFocus(context.Background(), i, tt.deadline); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if added != tt.added || removed != tt.removed {
+				t.Errorf("expected added %v and removed %v; got %v and %v", tt.added, tt.removed, added, removed)
+			}
+		})
+	}
+}
+
+func TestFocusClosedIssue(t *testing.T) {
+	start, _ := focusWeek(time.Now())
+	var removed []string
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions([]Option{WithFocusLabel("this week")}), client: &fakeClient{
+		_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+			return &issue{repo: repository{owner, repo}, number: number, state: "closed", closed: time.Now(),
+				labels: []string{"this week"}, body: "deadline: " + start.AddDate(0, 0, 6).Format("2006-01-02")}, nil
+		},
+		_removeIssueLabel: func(ctx context.Context, owner, repo string, number int, label string) error {
+			removed = append(removed, label)
+			return nil
+		},
+	}}
+	if err := ic.updateIssue(context.Background(), "foo", "bar", 1, "", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(removed) != 1 || removed[0] != "this week" {
+		t.Errorf("expected the focus label to be removed from the closed issue; got %v", removed)
+	}
+}
@@ -0,0 +1,137 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/notify"
+	"github.com/src-d/github-reminder/storage"
+)
+
+// A FollowUp is a step of the escalation of the overdue issues, mentioning
+// its Users, like a secondary contact or a team lead, some time after the
+// deadline of an issue passes.
+type FollowUp struct {
+	// After is how long after the deadline the users are mentioned.
+	After time.Duration
+	// Users are the logins, or teams like org/team, to mention.
+	Users []string
+}
+
+// ParseFollowUp parses a follow-up written as after:user[:user...], using a
+// Go duration, like 72h:alice to mention alice three days after the deadline.
+func ParseFollowUp(s string) (FollowUp, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 {
+		return FollowUp{}, errors.Errorf("bad follow-up %q, expected after:user[:user...]", s)
+	}
+	after, err := time.ParseDuration(parts[0])
+	if err != nil {
+		return FollowUp{}, errors.Wrapf(err, "bad follow-up %q", s)
+	}
+	if after < 0 {
+		return FollowUp{}, errors.Errorf("bad follow-up %q, it can't be before the deadline", s)
+	}
+	f := FollowUp{After: after}
+	for _, u := range parts[1:] {
+		u = strings.TrimPrefix(strings.TrimSpace(u), "@")
+		if u == "" {
+			return FollowUp{}, errors.Errorf("bad follow-up %q, missing user", s)
+		}
+		f.Users = append(f.Users, u)
+	}
+	return f, nil
+}
+
+// String writes the follow-up as parsed by ParseFollowUp.
+func (f FollowUp) String() string {
+	return f.After.String() + ":" + strings.Join(f.Users, ":")
+}
+
+// WithFollowUps configures the escalation chain of the overdue issues. Each
+// follow-up is posted once per deadline, in the order of their After, and
+// only the last one due is posted if several are due at once.
+func WithFollowUps(fs ...FollowUp) Option {
+	fs = append([]FollowUp(nil), fs...)
+	sort.SliceStable(fs, func(i, j int) bool { return fs[i].After < fs[j].After })
+	return func(o *options) { o.followUps = fs }
+}
+
+// followUpState records how many follow-ups of the escalation chain were
+// posted for the deadline of an issue.
+type followUpState struct {
+	Deadline time.Time `json:"deadline"`
+	Steps    int       `json:"steps"`
+}
+
+// checkFollowUps posts the follow-up of the escalation chain due for the
+// issue, if it's overdue and it wasn't posted for its deadline yet.
+func (c *InstallationClient) checkFollowUps(ctx context.Context, issue *issue, deadline time.Time) error {
+	if len(c.opts.followUps) == 0 || !c.overdue(deadline) {
+		return nil
+	}
+	due := deadline
+	if c.allDay(due) {
+		due = due.AddDate(0, 0, 1)
+	}
+	late := time.Since(due)
+	steps := 0
+	for _, f := range c.opts.followUps {
+		if f.After <= late {
+			steps++
+		}
+	}
+	if steps == 0 {
+		return nil
+	}
+
+	owner, repo, number := issue.repo.owner, issue.repo.name, issue.number
+	key := storage.Key("followup", c.appID, c.installationID, strings.ToLower(owner), strings.ToLower(repo), number)
+	var state followUpState
+	if err := c.opts.store.Get(ctx, key, &state); err != nil && err != storage.ErrNotFound {
+		return errors.Wrap(err, "could not fetch follow-ups")
+	}
+	if !state.Deadline.Equal(deadline) {
+		state = followUpState{Deadline: deadline}
+	}
+	if state.Steps >= steps {
+		return nil
+	}
+	if snoozed, err := c.snoozed(ctx, issue); err != nil || snoozed {
+		return err
+	}
+
+	f := c.opts.followUps[steps-1]
+	logrus.Infof("following up on overdue issue %s/%s#%d with %v", owner, repo, number, f.Users)
+	mentions := make([]string, len(f.Users))
+	for i, u := range f.Users {
+		mentions[i] = "@" + u
+	}
+	days := int(late.Hours() / 24)
+	since := "less than a day"
+	if days == 1 {
+		since = "1 day"
+	} else if days > 1 {
+		since = fmt.Sprintf("%d days", days)
+	}
+	text := fmt.Sprintf("hi %s, this issue has been overdue for %s, since %s. Could you follow up on it?",
+		strings.Join(mentions, " "), since, deadline.Format("January 2"))
+	if err := c.postReminder(ctx, issue, f.Users[0], text); err != nil {
+		return err
+	}
+	state.Steps = steps
+	if err := c.whenApplied(ctx, deferredPut(key, state)); err != nil {
+		return err
+	}
+
+	e := issue.event(notify.Reminder, text)
+	e.User, e.Deadline = f.Users[0], deadline
+	c.notify(ctx, e, issue.policy)
+	return nil
+}
@@ -0,0 +1,115 @@
+package reminder
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFollowUp(t *testing.T) {
+	f, err := ParseFollowUp("72h:@alice:org/leads")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := (FollowUp{After: 72 * time.Hour, Users: []string{"alice", "org/leads"}}); !reflect.DeepEqual(f, expected) {
+		t.Errorf("expected %v; got %v", expected, f)
+	}
+	for _, bad := range []string{"72h", "3d:alice", "-1h:alice", "72h:alice:"} {
+		if _, err := ParseFollowUp(bad); err == nil {
+			t.Errorf("expected error parsing %q", bad)
+		}
+	}
+}
+
+func TestFollowUps(t *testing.T) {
+	var bodies []string
+	ic := InstallationClient{appID: 42, installationID: 43,
+		opts: newOptions([]Option{WithFollowUps(
+			FollowUp{After: 168 * time.Hour, Users: []string{"carol"}},
+			FollowUp{After: 48 * time.Hour, Users: []string{"bob"}},
+		)}),
+		client: &fakeClient{
+			_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error {
+				bodies = append(bodies, body)
+				return nil
+			},
+		}}
+
+	i := &issue{repo: repository{"foo", "bar"}, number: 1, author: "francesc", state: "open"}
+	check := func(deadline time.Time, expected ...string) {
+		bodies = nil
+		if err := ic.checkFollowUps(context.Background(), i, deadline); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(bodies) != len(expected) {
+			t.Fatalf("expected %d comments; got %q", len(expected), bodies)
+		}
+		for j, b := range bodies {
+			if !strings.HasPrefix(b, "hi @"+expected[j]+",") {
+				t.Errorf("expected a follow-up for %s; got %q", expected[j], b)
+			}
+		}
+	}
+
+	deadline := time.Now().Add(-time.Hour).Truncate(time.Minute)
+	check(deadline)
+	deadline = deadline.Add(-48 * time.Hour)
+	check(deadline, "bob")
+	check(deadline)
+
+	// the chain goes on with the next step for the same deadline.
+	key := "followup/42/43/foo/bar/1"
+	deadline = time.Now().Add(-8 * 24 * time.Hour).Truncate(time.Minute)
+	if err := ic.opts.store.Put(context.Background(), key, followUpState{Deadline: deadline, Steps: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	check(deadline, "carol")
+	check(deadline)
+
+	// a new deadline missed by long enough only gets its last follow-up.
+	check(deadline.Add(-time.Minute), "carol")
+}
+
+func TestFollowUpsRecorded(t *testing.T) {
+	ctx := context.Background()
+	var bodies []string
+	ic := &InstallationClient{appID: 42, installationID: 43,
+		opts: newOptions([]Option{WithFollowUps(FollowUp{After: 48 * time.Hour, Users: []string{"bob"}})}),
+		client: &fakeClient{
+			_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error {
+				bodies = append(bodies, body)
+				return nil
+			},
+		}}
+	i := &issue{repo: repository{"foo", "bar"}, number: 1, author: "francesc", state: "open"}
+	deadline := time.Now().Add(-72 * time.Hour).Truncate(time.Minute)
+
+	// a follow-up that is only recorded is not remembered, so that discarding
+	// it follows up again on the next scan.
+	for n := 1; n <= 2; n++ {
+		scan, rec := ic.recording()
+		if err := scan.checkFollowUps(ctx, i, deadline); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(rec.mutations) != 1 || len(bodies) != 0 {
+			t.Fatalf("expected the follow-up to be recorded on scan %d; got %v and %q", n, rec.mutations, bodies)
+		}
+		if n == 2 {
+			if err := ic.flush(ctx, rec); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+	}
+	if len(bodies) != 1 {
+		t.Fatalf("expected the follow-up to be posted once applied; got %q", bodies)
+	}
+	scan, rec := ic.recording()
+	if err := scan.checkFollowUps(ctx, i, deadline); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rec.mutations) != 0 {
+		t.Errorf("expected the applied follow-up to be remembered; got %v", rec.mutations)
+	}
+}
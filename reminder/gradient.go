@@ -0,0 +1,82 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// WithLabelGradient makes the bot keep the colors of the deadline labels of a
+// repository in a gradient by urgency, from the Urgent color of the shortest
+// label through Soon to the Calm color of the longest one, recoloring those
+// that drift from it after each scan. Empty colors are taken from
+// DefaultUrgencyColors. It's ignored along with WithUrgencyColors, which
+// colors the labels by their issues instead.
+func WithLabelGradient(colors UrgencyColors) Option {
+	colors = colors.withDefaults()
+	return func(o *options) { o.gradient = &colors }
+}
+
+// gradient returns the color of each of the given labels, sorted by
+// increasing duration.
+func (u UrgencyColors) gradient(labels []Label) map[string]string {
+	colors := make(map[string]string, len(labels))
+	for i, l := range labels {
+		p := 0.0
+		if len(labels) > 1 {
+			p = float64(i) / float64(len(labels)-1)
+		}
+		if p <= 0.5 {
+			colors[l.Name] = mixColors(u.Urgent, u.Soon, 2*p)
+		} else {
+			colors[l.Name] = mixColors(u.Soon, u.Calm, 2*p-1)
+		}
+	}
+	return colors
+}
+
+// mixColors returns the hex RGB color at p, from 0 to 1, of the way from a to
+// b, or a if any of them can't be parsed.
+func mixColors(a, b string, p float64) string {
+	x, err := strconv.ParseUint(a, 16, 32)
+	if err != nil {
+		return a
+	}
+	y, err := strconv.ParseUint(b, 16, 32)
+	if err != nil {
+		return a
+	}
+	var mix uint64
+	for _, shift := range []uint{16, 8, 0} {
+		from, to := float64(x>>shift&0xff), float64(y>>shift&0xff)
+		mix |= uint64(from+(to-from)*p+0.5) << shift
+	}
+	return fmt.Sprintf("%06x", mix)
+}
+
+// updateLabelGradient recolors the deadline labels of the repository whose
+// color isn't the one given by the gradient, if enabled.
+func (c *InstallationClient) updateLabelGradient(ctx context.Context, owner, repo string, labels []Label) error {
+	if c.opts.gradient == nil || c.opts.colors != nil || len(labels) == 0 {
+		return nil
+	}
+	current, err := c.client.labelColors(ctx, owner, repo)
+	if err != nil {
+		return errors.Wrap(err, "could not fetch label colors")
+	}
+	want := c.opts.gradient.gradient(labels)
+	for _, l := range labels {
+		if strings.EqualFold(current[l.Name], want[l.Name]) {
+			continue
+		}
+		logrus.Debugf("setting color of label %s in %s/%s to %s", l.Name, owner, repo, want[l.Name])
+		if err := c.client.editLabelColor(ctx, owner, repo, l.Name, want[l.Name]); err != nil {
+			return errors.Wrapf(err, "could not update color of label %s", l.Name)
+		}
+	}
+	return nil
+}
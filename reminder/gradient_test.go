@@ -0,0 +1,60 @@
+package reminder
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestMixColors(t *testing.T) {
+	tests := []struct {
+		a, b string
+		p    float64
+		mix  string
+	}{
+		{"000000", "ffffff", 0, "000000"},
+		{"000000", "ffffff", 1, "ffffff"},
+		{"000000", "ffffff", 0.5, "808080"},
+		{"b60205", "fbca04", 0.5, "d96605"},
+		{"red", "ffffff", 0.5, "red"},
+	}
+	for _, tt := range tests {
+		if mix := mixColors(tt.a, tt.b, tt.p); mix != tt.mix {
+			t.Errorf("expected %s at %v from %s to %s; got %s", tt.mix, tt.p, tt.a, tt.b, mix)
+		}
+	}
+}
+
+func TestUpdateLabelGradient(t *testing.T) {
+	ctx := context.Background()
+	labels := []Label{{Name: "deadline < 1", Days: 1}, {Name: "deadline < 5", Days: 5}, {Name: "deadline < 30", Days: 30}}
+	current := map[string]string{"deadline < 1": "B60205", "deadline < 5": "ededed"}
+	edited := map[string]string{}
+	fc := &fakeClient{
+		_labelColors: func(ctx context.Context, owner, repo string) (map[string]string, error) { return current, nil },
+		_editLabelColor: func(ctx context.Context, owner, repo, label, color string) error {
+			edited[label] = color
+			return nil
+		},
+	}
+
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil), client: fc}
+	if err := ic.updateLabelGradient(ctx, "foo", "bar", labels); err != nil || len(edited) != 0 {
+		t.Errorf("expected no colors to be updated by default; got %v (%v)", edited, err)
+	}
+
+	ic.opts = newOptions([]Option{WithLabelGradient(UrgencyColors{})})
+	if err := ic.updateLabelGradient(ctx, "foo", "bar", labels); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]string{"deadline < 5": "fbca04", "deadline < 30": "0e8a16"}
+	if !reflect.DeepEqual(edited, expected) {
+		t.Errorf("expected the labels drifting from the gradient to be recolored with %v; got %v", expected, edited)
+	}
+
+	edited = map[string]string{}
+	ic.opts = newOptions([]Option{WithLabelGradient(UrgencyColors{}), WithUrgencyColors(UrgencyColors{})})
+	if err := ic.updateLabelGradient(ctx, "foo", "bar", labels); err != nil || len(edited) != 0 {
+		t.Errorf("expected the gradient to be ignored along with urgency colors; got %v (%v)", edited, err)
+	}
+}
@@ -0,0 +1,131 @@
+package reminder
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+// A Grammar is a version of the syntax of the keywords and commands understood
+// by the bot. Each repository keeps the grammar it was set to, so new syntax
+// can be adopted one repository at a time without changing how the text of
+// existing issues is read.
+type Grammar int
+
+// The grammar versions, the zero value being the same as GrammarV1.
+const (
+	// GrammarV1 finds keywords like "deadline" anywhere in the text.
+	GrammarV1 Grammar = 1
+	// GrammarV2 only finds keywords at the beginning of a line, or of a list
+	// item, ignoring quoted text and code blocks.
+	GrammarV2 Grammar = 2
+	// GrammarV3 reads the text as Markdown, finding keywords anywhere but in
+	// code blocks, code spans, quotes, and HTML comments.
+	GrammarV3 Grammar = 3
+
+	// LatestGrammar is the newest grammar version.
+	LatestGrammar = GrammarV3
+)
+
+// ParseGrammar parses a grammar version written as 2 or v2.
+func ParseGrammar(s string) (Grammar, error) {
+	v, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(s), "v"))
+	if err != nil || v < int(GrammarV1) || v > int(LatestGrammar) {
+		return 0, errors.Errorf("unknown grammar version %q, expected 1 to %d", s, LatestGrammar)
+	}
+	return Grammar(v), nil
+}
+
+func (g Grammar) String() string { return "v" + strconv.Itoa(int(g.version())) }
+
+func (g Grammar) version() Grammar {
+	if g == 0 {
+		return GrammarV1
+	}
+	return g
+}
+
+// WithGrammar sets the grammar of the repositories that don't have one set with
+// /reminder grammar. GrammarV1 is used by default.
+func WithGrammar(g Grammar) Option {
+	return func(o *options) { o.grammar = g }
+}
+
+// repoGrammar records who set the grammar of a repository.
+type repoGrammar struct {
+	Version Grammar   `json:"version"`
+	By      string    `json:"by"`
+	Time    time.Time `json:"time"`
+}
+
+func grammarKey(appID, installationID int, owner, repo string) string {
+	return storage.Key("grammar", appID, installationID, strings.ToLower(owner), strings.ToLower(repo))
+}
+
+// Grammar returns the grammar used to read the issues and comments of a repository.
+func (c *InstallationClient) Grammar(ctx context.Context, owner, repo string) (Grammar, error) {
+	var rg repoGrammar
+	err := c.opts.store.Get(ctx, grammarKey(c.appID, c.installationID, owner, repo), &rg)
+	if err == storage.ErrNotFound {
+		return c.opts.grammar.version(), nil
+	}
+	if err != nil {
+		return 0, errors.Wrapf(err, "could not fetch grammar of %s/%s", owner, repo)
+	}
+	return rg.Version.version(), nil
+}
+
+// findTimes returns the times following the given keyword in a body written at
+// the given time, which relative times like "in 5 days" are resolved against.
+func (g Grammar) findTimes(word, body string, created time.Time, d dates) []time.Time {
+	var times []time.Time
+	for _, v := range g.findValues(word, body) {
+		if t := d.parse(v, created); !t.IsZero() {
+			times = append(times, t)
+		}
+	}
+	return times
+}
+
+// findValues returns the text following the given keyword in a body, up to the end of the line.
+func (g Grammar) findValues(word, body string) []string {
+	switch g.version() {
+	case GrammarV1:
+		return findValues(word, body)
+	case GrammarV3:
+		return findValues(word, stripMarkdown(body))
+	}
+
+	var values []string
+	code := false
+	for _, line := range strings.Split(strings.ToLower(body), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "```") {
+			code = !code
+			continue
+		}
+		if code || strings.HasPrefix(line, ">") {
+			continue
+		}
+		line = strings.TrimLeft(line, "-*+ ")
+		// the keyword is followed by its separator, or the name of a
+		// checkpoint, so words starting like it, as "deadlines", aren't
+		// read as it.
+		if !strings.HasPrefix(line, word) || len(line) > len(word) && !strings.ContainsAny(line[len(word):len(word)+1], ":( \t") {
+			continue
+		}
+		values = append(values, line[len(word):])
+	}
+	return values
+}
+
+// parseCommand returns the command in the given comment body, if any.
+// Commands are read the same way by all of the grammar versions so far.
+func (g Grammar) parseCommand(body string) (name string, args []string, ok bool) {
+	return parseCommand(body)
+}
@@ -0,0 +1,90 @@
+package reminder
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseGrammar(t *testing.T) {
+	for s, expected := range map[string]Grammar{"1": GrammarV1, "v2": GrammarV2, "V2": GrammarV2} {
+		if g, err := ParseGrammar(s); err != nil || g != expected {
+			t.Errorf("expected %q to be grammar %v; got %v (%v)", s, expected, g, err)
+		}
+	}
+	for _, s := range []string{"0", "4", "latest"} {
+		if _, err := ParseGrammar(s); err == nil {
+			t.Errorf("expected %q not to be a grammar", s)
+		}
+	}
+}
+
+func TestGrammarFindTimes(t *testing.T) {
+	date := time.Date(2018, 8, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		body string
+		v1   bool
+		v2   bool
+		v3   bool
+	}{
+		{"deadline: 2018-08-01", true, true, true},
+		{"some text\n- Deadline 2018-08-01", true, true, true},
+		{"as discussed, the deadline: 2018-08-01", true, false, true},
+		{"> deadline: 2018-08-01", true, false, false},
+		{"```\ndeadline: 2018-08-01\n```", true, false, false},
+		{"  ~~~go\ndeadline: 2018-08-01\n  ~~~", true, true, false},
+		{"run it with `deadline: 2018-08-01`", false, false, false},
+		{"run it with `deadline: 2018-08-01`\nas said, deadline: 2018-08-01", true, false, true},
+		{"<!--\ndeadline: 2018-08-01\n-->", true, true, false},
+		{"example:\n\n    deadline: 2018-08-01", true, true, false},
+		{"- item\n\n    deadline: 2018-08-01", true, true, true},
+		{"deadline\t2018-08-01", true, true, true},
+	}
+
+	for _, tt := range tests {
+		for g, expected := range map[Grammar]bool{0: tt.v1, GrammarV1: tt.v1, GrammarV2: tt.v2, GrammarV3: tt.v3} {
+			times := g.findTimes("deadline", tt.body, time.Time{}, dates{})
+			found := len(times) == 1 && times[0].Equal(date)
+			if found != expected {
+				t.Errorf("expected grammar %v to find a deadline in %q to be %v; got %v", g, tt.body, expected, times)
+			}
+		}
+	}
+}
+
+func TestGrammarKeywordSeparator(t *testing.T) {
+	body := "deadlines: 2018-08-01\ndeadline-extension: 2018-08-02\ndeadline\nDeadline 2018-08-03"
+	if values := GrammarV2.findValues("deadline", body); len(values) != 2 || values[0] != "" || values[1] != " 2018-08-03" {
+		t.Errorf("expected only the lines with the keyword itself; got %q", values)
+	}
+
+	for _, g := range []Grammar{GrammarV1, GrammarV2, GrammarV3} {
+		cps := g.findCheckpoints("deadline", "deadline(design): 2024-05-01", time.Time{}, dates{})
+		if len(cps) != 1 || cps[0].name != "design" || !cps[0].time.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("expected grammar %v to read the design checkpoint; got %+v", g, cps)
+		}
+	}
+}
+
+func TestGrammarCommand(t *testing.T) {
+	ctx := context.Background()
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil), client: &fakeClient{
+		_permission: func(ctx context.Context, owner, repo, user string) (string, error) { return "admin", nil },
+		_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error {
+			return nil
+		},
+	}}
+
+	if g, err := ic.Grammar(ctx, "foo", "bar"); err != nil || g != GrammarV1 {
+		t.Fatalf("expected grammar v1 by default; got %v (%v)", g, err)
+	}
+	if ok, err := ic.HandleComment(ctx, "foo", "bar", 1, "admin", "/reminder grammar 2"); !ok || err != nil {
+		t.Fatalf("expected command to run; got %v, %v", ok, err)
+	}
+	if g, err := ic.Grammar(ctx, "Foo", "Bar"); err != nil || g != GrammarV2 {
+		t.Errorf("expected grammar v2; got %v (%v)", g, err)
+	}
+	if g, err := ic.Grammar(ctx, "foo", "baz"); err != nil || g != GrammarV1 {
+		t.Errorf("expected other repositories to keep grammar v1; got %v (%v)", g, err)
+	}
+}
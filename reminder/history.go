@@ -0,0 +1,182 @@
+package reminder
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+// An Outcome tells whether a closed issue met its deadline.
+type Outcome struct {
+	Owner    string    `json:"owner"`
+	Repo     string    `json:"repo"`
+	Number   int       `json:"number"`
+	Title    string    `json:"title"`
+	Deadline time.Time `json:"deadline"`
+	Closed   time.Time `json:"closed"`
+	// Met is set if the issue was closed before the end of the deadline day.
+	Met bool `json:"met"`
+}
+
+// Slip is how late the issue was closed, zero if it met its deadline.
+func (o Outcome) Slip() time.Duration {
+	if o.Met {
+		return 0
+	}
+	return o.Closed.Sub(o.Deadline.AddDate(0, 0, 1))
+}
+
+func historyKey(appID, installationID int, owner, repo string, number int) string {
+	return storage.Key("history", appID, installationID, owner, repo, number)
+}
+
+// History lists the outcomes of the closed issues with deadlines of an installation sorted by deadline.
+func History(ctx context.Context, store storage.Store, appID, installationID int) ([]Outcome, error) {
+	keys, err := store.List(ctx, storage.Key("history", appID, installationID)+"/")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list history")
+	}
+
+	var res []Outcome
+	for _, key := range keys {
+		var o Outcome
+		if err := store.Get(ctx, key, &o); err != nil {
+			return nil, errors.Wrapf(err, "could not fetch outcome %s", key)
+		}
+		res = append(res, o)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Deadline.Before(res[j].Deadline) })
+	return res, nil
+}
+
+// History lists the outcomes of the closed issues with deadlines of the installation sorted by deadline.
+func (c *InstallationClient) History(ctx context.Context) ([]Outcome, error) {
+	return History(ctx, c.opts.store, c.appID, c.installationID)
+}
+
+// recordOutcome stores whether a closed issue met its deadline, if it had one.
+func (c *InstallationClient) recordOutcome(ctx context.Context, issue *issue) error {
+	if issue.closed.IsZero() {
+		return nil
+	}
+	deadline, err := c.deadline(ctx, issue)
+	if err != nil || deadline.IsZero() {
+		return err
+	}
+
+	o := Outcome{
+		Owner:    issue.repo.owner,
+		Repo:     issue.repo.name,
+		Number:   issue.number,
+		Title:    issue.title,
+		Deadline: deadline,
+		Closed:   issue.closed,
+		Met:      issue.closed.Before(deadline.AddDate(0, 0, 1)),
+	}
+	err = c.opts.store.Put(ctx, historyKey(c.appID, c.installationID, o.Owner, o.Repo, o.Number), o)
+	return errors.Wrap(err, "could not record outcome")
+}
+
+// WithBackfill enables walking the closed issues of every installation to
+// record the outcome of their deadlines, processing at most n issues on each
+// installation update so the rate limit is not exhausted.
+func WithBackfill(n int) Option {
+	return func(o *options) { o.backfill = n }
+}
+
+// backfillCursor is where the backfill of an installation stopped.
+type backfillCursor struct {
+	// Done are the repositories completely walked.
+	Done map[string]bool `json:"done"`
+	// Repo is the repository being walked, Page the next page of closed
+	// issues, and Skip the issues of the page already recorded.
+	Repo string `json:"repo"`
+	Page int    `json:"page"`
+	Skip int    `json:"skip"`
+}
+
+// Backfill records the outcomes of at most n closed issues, resuming from
+// where the previous call stopped. It returns whether all of the repositories
+// of the installation have been walked.
+func (c *InstallationClient) Backfill(ctx context.Context, n int) (bool, error) {
+	key := storage.Key("backfill", c.appID, c.installationID)
+	var cur backfillCursor
+	if err := c.opts.store.Get(ctx, key, &cur); err != nil && err != storage.ErrNotFound {
+		return false, errors.Wrap(err, "could not fetch backfill cursor")
+	}
+	if cur.Done == nil {
+		cur.Done = make(map[string]bool)
+	}
+
+	repos, err := c.client.repos(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "could not list repositories")
+	}
+
+	for _, repo := range repos {
+		name := repo.owner + "/" + repo.name
+		if cur.Done[name] {
+			continue
+		}
+		if cur.Repo != name {
+			cur.Repo, cur.Page, cur.Skip = name, 1, 0
+		}
+
+		g, err := c.Grammar(ctx, repo.owner, repo.name)
+		if err != nil {
+			return false, err
+		}
+		lang, err := c.Language(ctx, repo.owner, repo.name)
+		if err != nil {
+			return false, err
+		}
+		syn, err := c.repoSynonyms(ctx, repo.owner, repo.name)
+		if err != nil {
+			return false, err
+		}
+		for cur.Page > 0 {
+			if n <= 0 {
+				return false, errors.Wrap(c.opts.store.Put(ctx, key, cur), "could not store backfill cursor")
+			}
+			numbers, next, err := c.client.closedIssues(ctx, repo.owner, repo.name, cur.Page)
+			if err != nil {
+				return false, err
+			}
+			if cur.Skip > len(numbers) {
+				cur.Skip = len(numbers)
+			}
+			for _, number := range numbers[cur.Skip:] {
+				if n <= 0 {
+					return false, errors.Wrap(c.opts.store.Put(ctx, key, cur), "could not store backfill cursor")
+				}
+				issue, err := c.client.issue(ctx, repo.owner, repo.name, number)
+				if err != nil {
+					return false, err
+				}
+				issue.grammar, issue.language, issue.synonyms = g, lang, newSynonyms(syn, lang)
+				if err := c.recordOutcome(ctx, issue); err != nil {
+					return false, err
+				}
+				n--
+				cur.Skip++
+			}
+			cur.Page, cur.Skip = next, 0
+			if err := c.opts.store.Put(ctx, key, cur); err != nil {
+				return false, errors.Wrap(err, "could not store backfill cursor")
+			}
+		}
+
+		logrus.Infof("backfilled history of %s", name)
+		cur.Done[name] = true
+		cur.Repo = ""
+		if err := c.opts.store.Put(ctx, key, cur); err != nil {
+			return false, errors.Wrap(err, "could not store backfill cursor")
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,63 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestBackfill(t *testing.T) {
+	ctx := context.Background()
+	deadline := time.Date(2018, 6, 20, 0, 0, 0, 0, time.UTC)
+	pages := map[int][]int{1: {1, 2, 3}, 2: {4}}
+	fetched := 0
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil), client: &fakeClient{
+		_repos: func(ctx context.Context) ([]repository, error) { return []repository{{"foo", "bar"}}, nil },
+		_closedIssues: func(ctx context.Context, owner, repo string, page int) ([]int, int, error) {
+			next := page + 1
+			if _, ok := pages[next]; !ok {
+				next = 0
+			}
+			return pages[page], next, nil
+		},
+		_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+			fetched++
+			i := &issue{repo: repository{owner, repo}, number: number, state: "closed",
+				closed: deadline.AddDate(0, 0, number-2).Add(time.Hour)}
+			if number != 3 {
+				i.body = fmt.Sprintf("deadline: %s", deadline.Format("2006-01-02"))
+			}
+			return i, nil
+		},
+	}}
+
+	done, err := ic.Backfill(ctx, 2)
+	if err != nil || done {
+		t.Fatalf("expected backfill to stop in the first page; got %v, %v", done, err)
+	}
+	if fetched != 2 {
+		t.Fatalf("expected 2 issues fetched; got %d", fetched)
+	}
+
+	done, err = ic.Backfill(ctx, 2)
+	if err != nil || !done {
+		t.Fatalf("expected backfill to finish; got %v, %v", done, err)
+	}
+	if fetched != 4 {
+		t.Fatalf("expected backfill to resume in the first page; got %d issues fetched", fetched)
+	}
+
+	history, err := ic.History(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	met := map[int]bool{}
+	for _, o := range history {
+		met[o.Number] = o.Met
+	}
+	expected := map[int]bool{1: true, 2: true, 4: false}
+	if fmt.Sprint(met) != fmt.Sprint(expected) {
+		t.Errorf("expected outcomes %v; got %v", expected, met)
+	}
+}
@@ -0,0 +1,125 @@
+package reminder
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+// An ImportMode is how imported deadlines are written.
+type ImportMode string
+
+// The supported import modes.
+const (
+	// ImportComment comments the deadline on the issue, as if written by a person.
+	ImportComment ImportMode = "comment"
+	// ImportMetadata stores the deadline in the bot, leaving the issue text
+	// untouched. Deadlines written in the issue take precedence over it.
+	ImportMetadata ImportMode = "metadata"
+)
+
+// An ImportedDeadline is the deadline of an issue coming from another tracker.
+type ImportedDeadline struct {
+	Owner    string    `json:"owner"`
+	Repo     string    `json:"repo"`
+	Number   int       `json:"number"`
+	Deadline time.Time `json:"deadline"`
+}
+
+// ReadDeadlinesCSV reads deadlines written as owner/repo#number,deadline lines,
+// with dates in any of the formats understood in issues. A header line is skipped.
+func ReadDeadlinesCSV(r io.Reader) ([]ImportedDeadline, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 2
+	cr.TrimLeadingSpace = true
+
+	var ds []ImportedDeadline
+	for line := 1; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return ds, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read deadlines")
+		}
+
+		d, err := parseImportedDeadline(record[0], record[1])
+		if err != nil && line == 1 {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "bad deadline in line %d", line)
+		}
+		ds = append(ds, d)
+	}
+}
+
+func parseImportedDeadline(ref, date string) (ImportedDeadline, error) {
+	var d ImportedDeadline
+	slash, hash := strings.Index(ref, "/"), strings.LastIndex(ref, "#")
+	if slash <= 0 || hash < slash+2 {
+		return d, errors.Errorf("bad issue %q, expected owner/repo#number", ref)
+	}
+	number, err := strconv.Atoi(ref[hash+1:])
+	if err != nil || number <= 0 {
+		return d, errors.Errorf("bad issue %q, expected owner/repo#number", ref)
+	}
+	d.Owner, d.Repo, d.Number = ref[:slash], ref[slash+1:hash], number
+
+	if d.Deadline = parseDate(strings.ToLower(date), nil, nil); d.Deadline.IsZero() {
+		return d, errors.Errorf("bad date %q", date)
+	}
+	return d, nil
+}
+
+func importedKey(appID, installationID int, owner, repo string, number int) string {
+	return storage.Key("imported", appID, installationID, owner, repo, number)
+}
+
+// importedDeadline returns the deadline imported for the issue, zero if none.
+func (c *InstallationClient) importedDeadline(ctx context.Context, issue *issue) (time.Time, error) {
+	var d ImportedDeadline
+	err := c.opts.store.Get(ctx, importedKey(c.appID, c.installationID, issue.repo.owner, issue.repo.name, issue.number), &d)
+	if err == storage.ErrNotFound {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "could not fetch imported deadline")
+	}
+	return d.Deadline, nil
+}
+
+// ImportDeadlines writes the given deadlines with the given mode and updates
+// their issues, applying the labels right away. It stops at the first failure,
+// returning the number of deadlines imported until then.
+func (c *InstallationClient) ImportDeadlines(ctx context.Context, ds []ImportedDeadline, mode ImportMode) (int, error) {
+	if mode != ImportComment && mode != ImportMetadata {
+		return 0, errors.Errorf("unknown import mode %q", mode)
+	}
+
+	for i, d := range ds {
+		switch mode {
+		case ImportComment:
+			text := fmt.Sprintf("deadline: %s", d.Deadline.Format("2006-01-02"))
+			if err := c.client.createIssueComment(ctx, d.Owner, d.Repo, d.Number, text); err != nil {
+				return i, errors.Wrapf(err, "could not comment on %s/%s#%d", d.Owner, d.Repo, d.Number)
+			}
+		case ImportMetadata:
+			if err := c.opts.store.Put(ctx, importedKey(c.appID, c.installationID, d.Owner, d.Repo, d.Number), d); err != nil {
+				return i, errors.Wrap(err, "could not store imported deadline")
+			}
+		}
+		if err := c.UpdateIssue(ctx, d.Owner, d.Repo, d.Number); err != nil {
+			return i, err
+		}
+	}
+	return len(ds), nil
+}
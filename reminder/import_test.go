@@ -0,0 +1,69 @@
+package reminder
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadDeadlinesCSV(t *testing.T) {
+	ds, err := ReadDeadlinesCSV(strings.NewReader("issue,deadline\nsrc-d/go-git#12,2018-08-01\nsrc-d/go-git#13, August 2 2018\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ds) != 2 {
+		t.Fatalf("expected 2 deadlines; got %+v", ds)
+	}
+	if d := ds[1]; d.Owner != "src-d" || d.Repo != "go-git" || d.Number != 13 || !d.Deadline.Equal(time.Date(2018, 8, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected deadline %+v", d)
+	}
+
+	for _, bad := range []string{
+		"src-d/go-git#12,2018-08-01\nsrc-d/go-git,2018-08-01\n",
+		"src-d/go-git#12,2018-08-01\nsrc-d/go-git#13,someday\n",
+		"src-d/go-git#12,2018-08-01,extra\n",
+	} {
+		if _, err := ReadDeadlinesCSV(strings.NewReader(bad)); err == nil {
+			t.Errorf("expected error reading %q", bad)
+		}
+	}
+}
+
+func TestImportDeadlines(t *testing.T) {
+	ctx := context.Background()
+	deadline := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 3)
+
+	for _, mode := range []ImportMode{ImportComment, ImportMetadata} {
+		t.Run(string(mode), func(t *testing.T) {
+			i := &issue{repo: repository{"foo", "bar"}, number: 1, author: "francesc", state: "open"}
+			var labels []string
+			ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil), client: &fakeClient{
+				_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) {
+					return []string{"deadline < 5"}, nil
+				},
+				_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) { return i, nil },
+				_files: func(ctx context.Context, owner, repo string, number int) ([]string, error) { return nil, nil },
+				_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error {
+					i.comments = append(i.comments, comment{author: botLogin, body: body})
+					return nil
+				},
+				_addIssueLabel: func(ctx context.Context, owner, repo string, number int, label string) error {
+					labels = append(labels, label)
+					return nil
+				},
+			}}
+
+			n, err := ic.ImportDeadlines(ctx, []ImportedDeadline{{"foo", "bar", 1, deadline}}, mode)
+			if err != nil || n != 1 {
+				t.Fatalf("expected 1 deadline imported; got %d (%v)", n, err)
+			}
+			if len(labels) != 1 || labels[0] != "deadline < 5" {
+				t.Errorf("expected deadline label to be applied; got %v", labels)
+			}
+			if commented := len(i.comments) > 0; commented != (mode == ImportComment) {
+				t.Errorf("expected commented to be %v; got %v", mode == ImportComment, commented)
+			}
+		})
+	}
+}
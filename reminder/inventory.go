@@ -0,0 +1,172 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/notify"
+	"github.com/src-d/github-reminder/storage"
+)
+
+// A Deadline is an open issue with a deadline, as found in the last scan.
+type Deadline struct {
+	Owner    string    `json:"owner"`
+	Repo     string    `json:"repo"`
+	Number   int       `json:"number"`
+	Title    string    `json:"title"`
+	URL      string    `json:"url"`
+	Deadline time.Time `json:"deadline"`
+	// Checkpoint is the name of the checkpoint due, for issues with several.
+	Checkpoint string    `json:"checkpoint,omitempty"`
+	Label      string    `json:"label,omitempty"`
+	Updated    time.Time `json:"updated"`
+}
+
+// TimeLeft describes the time left at now until the deadline, like "3 days
+// left" or "overdue by 2 days".
+func (d Deadline) TimeLeft(now time.Time) string {
+	return daysLeft(d.Deadline, now)
+}
+
+func deadlineKey(appID, installationID int, owner, repo string, number int) string {
+	return storage.Key("deadline", appID, installationID, owner, repo, number)
+}
+
+// Deadlines lists the deadlines found in the issues of an installation sorted by due date.
+func Deadlines(ctx context.Context, store storage.Store, appID, installationID int) ([]Deadline, error) {
+	return listDeadlines(ctx, store, storage.Key("deadline", appID, installationID)+"/")
+}
+
+func listDeadlines(ctx context.Context, store storage.Store, prefix string) ([]Deadline, error) {
+	keys, err := store.List(ctx, prefix)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list deadlines")
+	}
+
+	var res []Deadline
+	for _, key := range keys {
+		var d Deadline
+		if err := store.Get(ctx, key, &d); err != nil {
+			return nil, errors.Wrapf(err, "could not fetch deadline %s", key)
+		}
+		res = append(res, d)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Deadline.Before(res[j].Deadline) })
+	return res, nil
+}
+
+// RepoDeadlines lists the deadlines found in the issues of a repository of an
+// installation sorted by due date, reading only those of the repository.
+func RepoDeadlines(ctx context.Context, store storage.Store, appID, installationID int, owner, repo string) ([]Deadline, error) {
+	keys, err := store.List(ctx, storage.Key("deadline", appID, installationID)+"/")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list deadlines")
+	}
+
+	var res []Deadline
+	for _, key := range keys {
+		parts := strings.Split(key, "/")
+		if len(parts) < 5 || !strings.EqualFold(parts[3], owner) || !strings.EqualFold(parts[4], repo) {
+			continue
+		}
+		var d Deadline
+		if err := store.Get(ctx, key, &d); err != nil {
+			return nil, errors.Wrapf(err, "could not fetch deadline %s", key)
+		}
+		res = append(res, d)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Deadline.Before(res[j].Deadline) })
+	return res, nil
+}
+
+// Deadlines lists the deadlines found in the issues of the installation sorted by due date.
+func (c *InstallationClient) Deadlines(ctx context.Context) ([]Deadline, error) {
+	return Deadlines(ctx, c.opts.store, c.appID, c.installationID)
+}
+
+// recordDeadline keeps track of the deadline of an issue and the label applied to it,
+// or forgets it if the deadline is zero, notifying when the deadline of an open
+// issue changes. Failures are only logged since the inventory is not critical.
+func (c *InstallationClient) recordDeadline(ctx context.Context, issue *issue, deadline time.Time, label string) {
+	key := deadlineKey(c.appID, c.installationID, issue.repo.owner, issue.repo.name, issue.number)
+
+	var old Deadline
+	err := c.opts.store.Get(ctx, key, &old)
+	switch {
+	case err != nil && err != storage.ErrNotFound:
+		logrus.Warnf("could not fetch deadline of %s/%s#%d: %v", issue.repo.owner, issue.repo.name, issue.number, err)
+	case issue.state == "open" && !old.Deadline.Equal(deadline):
+		c.notifyDeadline(ctx, issue, deadline)
+	}
+
+	if deadline.IsZero() {
+		err = c.opts.store.Delete(ctx, key)
+	} else {
+		err = c.opts.store.Put(ctx, key, Deadline{
+			Owner:      issue.repo.owner,
+			Repo:       issue.repo.name,
+			Number:     issue.number,
+			Title:      issue.title,
+			URL:        issue.url,
+			Deadline:   deadline,
+			Checkpoint: issue.checkpoint,
+			Label:      label,
+			Updated:    time.Now(),
+		})
+	}
+	if err != nil {
+		logrus.Warnf("could not record deadline for %s/%s#%d: %v", issue.repo.owner, issue.repo.name, issue.number, err)
+	}
+}
+
+// notifyDeadline notifies the deadline notifier that the deadline of the issue
+// is now the given one, or was cleared if it's zero.
+func (c *InstallationClient) notifyDeadline(ctx context.Context, issue *issue, deadline time.Time) {
+	message := fmt.Sprintf("the deadline of %s is now %s", issue.title, deadline.Format("2006-01-02 15:04 MST"))
+	if deadline.IsZero() {
+		message = fmt.Sprintf("the deadline of %s was cleared", issue.title)
+	}
+	if c.opts.deadlineNotifier == nil {
+		return
+	}
+	e := issue.event(notify.Deadline, message)
+	e.User, e.Deadline = issue.author, deadline
+	c.deliver(ctx, PendingEvent{Event: e, Notifier: deadlineNotifier})
+}
+
+// pruneDeadlines forgets the deadlines of issues in the repository that are no
+// longer open, removing their labels if closed issues are cleaned up, and
+// resolving their incidents.
+func (c *InstallationClient) pruneDeadlines(ctx context.Context, owner, repo string, open []int) error {
+	isOpen := make(map[string]bool, len(open))
+	for _, number := range open {
+		isOpen[deadlineKey(c.appID, c.installationID, owner, repo, number)] = true
+	}
+
+	keys, err := c.opts.store.List(ctx, storage.Key("deadline", c.appID, c.installationID, owner, repo)+"/")
+	if err != nil {
+		return errors.Wrap(err, "could not list deadlines")
+	}
+	for _, key := range keys {
+		if isOpen[key] {
+			continue
+		}
+		var d Deadline
+		if err := c.opts.store.Get(ctx, key, &d); err != nil {
+			logrus.Warnf("could not fetch deadline %s: %v", key, err)
+		} else {
+			c.stripPrunedLabel(ctx, d)
+			c.resolvePrunedEscalation(ctx, d)
+		}
+		if err := c.opts.store.Delete(ctx, key); err != nil {
+			return errors.Wrapf(err, "could not delete deadline %s", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package reminder
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/src-d/github-reminder/notify"
+)
+
+func TestDeadlineEvents(t *testing.T) {
+	ctx := context.Background()
+	var events, others []notify.Event
+	ic := InstallationClient{appID: 42, installationID: 43, client: &fakeClient{}, opts: newOptions([]Option{
+		WithDeadlineNotifier(notify.NotifierFunc(func(ctx context.Context, e notify.Event) error {
+			events = append(events, e)
+			return nil
+		})),
+		WithNotifier(notify.NotifierFunc(func(ctx context.Context, e notify.Event) error {
+			others = append(others, e)
+			return nil
+		})),
+	})}
+
+	is := &issue{repo: repository{"foo", "bar"}, number: 1, title: "Fix it", author: "francesc", state: "open"}
+	first := time.Date(2030, 8, 1, 0, 0, 0, 0, time.UTC)
+	second := first.AddDate(0, 0, 7)
+	for i, tt := range []struct {
+		deadline time.Time
+		state    string
+		expected *time.Time
+	}{
+		{first, "open", &first},
+		{first, "open", nil},
+		{second, "open", &second},
+		{time.Time{}, "open", &time.Time{}},
+		{time.Time{}, "open", nil},
+		{first, "open", &first},
+		{time.Time{}, "closed", nil},
+	} {
+		events = nil
+		is.state = tt.state
+		ic.recordDeadline(ctx, is, tt.deadline, "")
+		switch {
+		case tt.expected == nil && len(events) != 0:
+			t.Errorf("%d: expected no events; got %+v", i, events)
+		case tt.expected != nil && (len(events) != 1 || events[0].Kind != notify.Deadline ||
+			!events[0].Deadline.Equal(*tt.expected) || events[0].User != "francesc"):
+			t.Errorf("%d: expected a deadline event for %v; got %+v", i, *tt.expected, events)
+		}
+	}
+	if len(others) != 0 {
+		t.Errorf("expected the deadline events to go only to the deadline notifier; got %+v", others)
+	}
+}
@@ -0,0 +1,47 @@
+package reminder
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+// UpdateLabel handles a label created, edited, or deleted in a repository,
+// named name and, if it was renamed, formerly from. If either is a deadline
+// label, the colors known for them are forgotten and the repository is
+// scanned again, so the labels of its issues follow the new set of deadline
+// labels. Other labels are ignored.
+func (c *InstallationClient) UpdateLabel(ctx context.Context, owner, repo, name, from string) error {
+	c, err := c.forRepo(ctx, owner, repo)
+	if err != nil {
+		return err
+	}
+	prefix, err := c.LabelPrefix(ctx, owner, repo)
+	if err != nil {
+		return err
+	}
+	isDeadline := func(label string) bool {
+		n, ok := matchLabel(prefix, label)
+		if ok {
+			_, ok = parseLabel(label, n)
+		}
+		return ok
+	}
+	if !isDeadline(name) && (from == "" || !isDeadline(from)) {
+		return nil
+	}
+
+	for _, l := range []string{name, from} {
+		if l == "" {
+			continue
+		}
+		if err := c.opts.store.Delete(ctx, storage.Key("color", c.appID, c.installationID, owner, repo, l)); err != nil {
+			return errors.Wrapf(err, "could not forget color of label %s", l)
+		}
+	}
+	logrus.Infof("deadline label %q changed in %s/%s, updating repository", name, owner, repo)
+	return c.UpdateRepo(ctx, owner, repo)
+}
@@ -0,0 +1,50 @@
+package reminder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+func TestUpdateLabel(t *testing.T) {
+	scans := 0
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil), client: &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) {
+			return []string{"deadline < 3", "bug"}, nil
+		},
+		_issues: func(ctx context.Context, owner, repo string) ([]int, error) {
+			scans++
+			return nil, nil
+		},
+	}}
+	ctx := context.Background()
+	colorKey := storage.Key("color", 42, 43, "foo", "bar", "deadline < 5")
+	if err := ic.opts.store.Put(ctx, colorKey, "ff0000"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name, label, from string
+		scans             int
+	}{
+		{"other label", "bug", "", 0},
+		{"other label renamed", "defect", "bug", 0},
+		{"deadline label created", "deadline < 7", "", 1},
+		{"deadline label renamed", "deadline < 3", "deadline < 5", 2},
+		{"deadline label replaced", "urgent", "deadline < 1", 3},
+	}
+	for _, tt := range tests {
+		if err := ic.UpdateLabel(ctx, "foo", "bar", tt.label, tt.from); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if scans != tt.scans {
+			t.Errorf("%s: expected %d scans; got %d", tt.name, tt.scans, scans)
+		}
+	}
+
+	var color string
+	if err := ic.opts.store.Get(ctx, colorKey, &color); err != storage.ErrNotFound {
+		t.Errorf("expected the color of the renamed label to be forgotten; got %q (%v)", color, err)
+	}
+}
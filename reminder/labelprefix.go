@@ -0,0 +1,122 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pkg/errors"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+// DefaultLabelPrefix is the prefix of the deadline labels, followed by their
+// number of days, unless another one is chosen.
+const DefaultLabelPrefix = "deadline <"
+
+// WithLabelPrefix sets the prefix of the deadline labels, like "due-in:" for
+// labels like "due-in: 5", in the repositories that didn't choose another one
+// with /reminder labels. It can also be a template with the days anywhere in
+// the label, like "⏰ {days}d left" for labels like "⏰ 5d left".
+func WithLabelPrefix(prefix string) Option {
+	return func(o *options) { o.labelPrefix = strings.TrimSpace(prefix) }
+}
+
+// daysPlaceholder is replaced by the days in the label templates.
+const daysPlaceholder = "{days}"
+
+// LabelName returns the name of the deadline label with the given prefix, or
+// template, and days.
+func LabelName(prefix string, days int) string {
+	return formatLabel(prefix, strconv.Itoa(days))
+}
+
+// HourLabelName returns the name of the deadline label with the given prefix,
+// or template, and hours, like "deadline < 12h".
+func HourLabelName(prefix string, hours int) string {
+	return formatLabel(hourTemplate(prefix), fmt.Sprintf("%dh", hours))
+}
+
+// hourTemplate returns the template of the hour labels of a template writing
+// a unit after the days, like "⏰ {days} left" for "⏰ {days}d left", so its
+// hour labels are "⏰ 12h left" rather than "⏰ 12hd left".
+func hourTemplate(prefix string) string {
+	i := strings.Index(prefix, daysPlaceholder)
+	if i < 0 {
+		return prefix
+	}
+	i += len(daysPlaceholder)
+	j := strings.IndexFunc(prefix[i:], func(r rune) bool { return !unicode.IsLetter(r) })
+	if j < 0 {
+		j = len(prefix) - i
+	}
+	if _, ok := labelUnits[strings.ToLower(prefix[i:i+j])]; j == 0 || !ok {
+		return prefix
+	}
+	return prefix[:i] + prefix[i+j:]
+}
+
+func formatLabel(prefix, n string) string {
+	prefix = strings.TrimSpace(prefix)
+	if strings.Contains(prefix, daysPlaceholder) {
+		return strings.Replace(prefix, daysPlaceholder, n, 1)
+	}
+	return prefix + " " + n
+}
+
+// matchLabel returns the days of a label, with their unit if any, if it has
+// the given prefix or matches the given template, or its hour labels.
+func matchLabel(prefix, label string) (string, bool) {
+	if n, ok := matchTemplate(prefix, label); ok {
+		return n, true
+	}
+	if hours := hourTemplate(prefix); hours != prefix {
+		return matchTemplate(hours, label)
+	}
+	return "", false
+}
+
+func matchTemplate(prefix, label string) (string, bool) {
+	i := strings.Index(prefix, daysPlaceholder)
+	if i < 0 {
+		if !strings.HasPrefix(label, prefix) {
+			return "", false
+		}
+		return strings.TrimSpace(strings.TrimPrefix(label, prefix)), true
+	}
+	before, after := prefix[:i], prefix[i+len(daysPlaceholder):]
+	if len(label) < len(before)+len(after) || !strings.HasPrefix(label, before) || !strings.HasSuffix(label, after) {
+		return "", false
+	}
+	return strings.TrimSpace(label[len(before) : len(label)-len(after)]), true
+}
+
+// repoLabelPrefix records who chose the prefix of the deadline labels of a repository.
+type repoLabelPrefix struct {
+	Prefix string    `json:"prefix"`
+	By     string    `json:"by"`
+	Time   time.Time `json:"time"`
+}
+
+func labelPrefixKey(appID, installationID int, owner, repo string) string {
+	return storage.Key("labelprefix", appID, installationID, strings.ToLower(owner), strings.ToLower(repo))
+}
+
+// LabelPrefix returns the prefix of the deadline labels of a repository.
+func (c *InstallationClient) LabelPrefix(ctx context.Context, owner, repo string) (string, error) {
+	var rp repoLabelPrefix
+	err := c.opts.store.Get(ctx, labelPrefixKey(c.appID, c.installationID, owner, repo), &rp)
+	if err == storage.ErrNotFound {
+		if c.opts.labelPrefix != "" {
+			return c.opts.labelPrefix, nil
+		}
+		return DefaultLabelPrefix, nil
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "could not fetch label prefix of %s/%s", owner, repo)
+	}
+	return rp.Prefix, nil
+}
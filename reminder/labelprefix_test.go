@@ -0,0 +1,92 @@
+package reminder
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLabelPrefix(t *testing.T) {
+	ctx := context.Background()
+	var comments []string
+	fc := &fakeClient{
+		_permission: func(ctx context.Context, owner, repo, user string) (string, error) { return "admin", nil },
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) {
+			return []string{"deadline < 30", "due-in: 5", "due-in:10", "due-in: soon", "bug"}, nil
+		},
+		_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error {
+			comments = append(comments, body)
+			return nil
+		},
+	}
+	labels := func(ic InstallationClient) []Label {
+		ls, err := ic.LabelsInRepo(ctx, "foo", "bar")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return ls
+	}
+
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil), client: fc}
+	if ls, expected := labels(ic), []Label{{Name: "deadline < 30", Days: 30}}; !reflect.DeepEqual(ls, expected) {
+		t.Errorf("expected labels %v by default; got %v", expected, ls)
+	}
+
+	ic.opts = newOptions([]Option{WithLabelPrefix("due-in:")})
+	expected := []Label{{Name: "due-in: 5", Days: 5}, {Name: "due-in:10", Days: 10}}
+	if ls := labels(ic); !reflect.DeepEqual(ls, expected) {
+		t.Errorf("expected labels %v with the prefix; got %v", expected, ls)
+	}
+
+	ic.opts = newOptions(nil)
+	if _, err := ic.HandleComment(ctx, "foo", "bar", 1, "admin", "/reminder labels due-in:"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(comments) != 1 || !strings.Contains(comments[0], "`due-in: 5`") {
+		t.Errorf("unexpected replies %q", comments)
+	}
+	if ls := labels(ic); !reflect.DeepEqual(ls, expected) {
+		t.Errorf("expected labels %v with the prefix of the repository; got %v", expected, ls)
+	}
+}
+
+func TestLabelTemplate(t *testing.T) {
+	fc := &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) {
+			return []string{"⏰ 5d left", "⏰ 12h left", "⏰ 2w left", "⏰ soon left", "deadline < 30", "5d left"}, nil
+		},
+	}
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions([]Option{WithLabelPrefix("⏰ {days} left")}), client: fc}
+	ls, err := ic.LabelsInRepo(context.Background(), "foo", "bar")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []Label{{Name: "⏰ 12h left", Hours: 12}, {Name: "⏰ 5d left", Days: 5}, {Name: "⏰ 2w left", Days: 14}}
+	if !reflect.DeepEqual(ls, expected) {
+		t.Errorf("expected labels %v with the template; got %v", expected, ls)
+	}
+
+	tests := []struct{ prefix, days, hours string }{
+		{"deadline <", "deadline < 5", "deadline < 12h"},
+		{"⏰ {days}d left", "⏰ 5d left", "⏰ 12h left"},
+		{"{days}D", "5D", "12h"},
+		{" {days} days to go ", "5 days to go", "12h days to go"},
+	}
+	for _, tt := range tests {
+		if name := LabelName(tt.prefix, 5); name != tt.days {
+			t.Errorf("expected label %q for %q; got %q", tt.days, tt.prefix, name)
+		}
+		if name := HourLabelName(tt.prefix, 12); name != tt.hours {
+			t.Errorf("expected label %q for %q; got %q", tt.hours, tt.prefix, name)
+		}
+
+		// the labels written are read back.
+		for _, l := range []Label{{Name: tt.days, Days: 5}, {Name: tt.hours, Hours: 12}} {
+			n, ok := matchLabel(strings.TrimSpace(tt.prefix), l.Name)
+			if got, ok2 := parseLabel(l.Name, n); !ok || !ok2 || got != l {
+				t.Errorf("expected %q to be read as %+v with %q; got %+v", l.Name, l, tt.prefix, got)
+			}
+		}
+	}
+}
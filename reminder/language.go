@@ -0,0 +1,152 @@
+package reminder
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+// A language lets dates be written with the month names of other languages
+// than English, like "3 de junio 2018", always with the day before the month.
+type language struct {
+	// months are the names of the months in lower case, several for some of them.
+	months [12][]string
+	// fillers are the words ignored, like "de" in Spanish.
+	fillers []string
+	// deadline and reminder are the words for the keywords, which are always
+	// their synonyms in the repositories using the language.
+	deadline, reminder []string
+}
+
+// languages are the languages dates can be written in besides English, by code.
+var languages = map[string]language{
+	"es": {
+		months: [12][]string{{"enero"}, {"febrero"}, {"marzo"}, {"abril"}, {"mayo"}, {"junio"}, {"julio"},
+			{"agosto"}, {"septiembre", "setiembre"}, {"octubre"}, {"noviembre"}, {"diciembre"}},
+		fillers:  []string{"de", "del"},
+		deadline: []string{"fecha límite", "fecha limite", "plazo"},
+		reminder: []string{"recordatorio", "recordar"},
+	},
+	"fr": {
+		months: [12][]string{{"janvier"}, {"février", "fevrier"}, {"mars"}, {"avril"}, {"mai"}, {"juin"},
+			{"juillet"}, {"août", "aout"}, {"septembre"}, {"octobre"}, {"novembre"}, {"décembre", "decembre"}},
+		fillers:  []string{"le"},
+		deadline: []string{"échéance", "echeance", "date limite"},
+		reminder: []string{"rappel"},
+	},
+	"de": {
+		months: [12][]string{{"januar", "jänner"}, {"februar"}, {"märz", "maerz"}, {"april"}, {"mai"}, {"juni"},
+			{"juli"}, {"august"}, {"september"}, {"oktober"}, {"november"}, {"dezember"}},
+		fillers:  []string{"am"},
+		deadline: []string{"frist", "fällig", "faellig"},
+		reminder: []string{"erinnerung"},
+	},
+	"pt": {
+		months: [12][]string{{"janeiro"}, {"fevereiro"}, {"março", "marco"}, {"abril"}, {"maio"}, {"junho"},
+			{"julho"}, {"agosto"}, {"setembro"}, {"outubro"}, {"novembro"}, {"dezembro"}},
+		fillers:  []string{"de"},
+		deadline: []string{"prazo", "data limite"},
+		reminder: []string{"lembrete"},
+	},
+}
+
+// ParseLanguage checks the code of a language dates can be written in, en
+// being English, the one always understood.
+func ParseLanguage(s string) (string, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if _, ok := languages[s]; ok || s == "en" {
+		return s, nil
+	}
+	var codes []string
+	for code := range languages {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return "", errors.Errorf("unknown language %q, expected en, %s", s, strings.Join(codes, ", "))
+}
+
+// WithLanguage makes the bot understand the dates written in the given
+// language, besides English, in the repositories that didn't choose another
+// one with /reminder language.
+func WithLanguage(code string) Option {
+	return func(o *options) { o.language = code }
+}
+
+// repoLanguage records who chose the language of a repository.
+type repoLanguage struct {
+	Language string    `json:"language"`
+	By       string    `json:"by"`
+	Time     time.Time `json:"time"`
+}
+
+func languageKey(appID, installationID int, owner, repo string) string {
+	return storage.Key("language", appID, installationID, strings.ToLower(owner), strings.ToLower(repo))
+}
+
+// Language returns the language the dates of a repository can be written in, besides English.
+func (c *InstallationClient) Language(ctx context.Context, owner, repo string) (string, error) {
+	var rl repoLanguage
+	err := c.opts.store.Get(ctx, languageKey(c.appID, c.installationID, owner, repo), &rl)
+	if err == storage.ErrNotFound {
+		return c.opts.language, nil
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "could not fetch language of %s/%s", owner, repo)
+	}
+	return rl.Language, nil
+}
+
+// localDateLayouts are the layouts of the dates once translated.
+var localDateLayouts = []string{"2 January 2006", "2 January 2006 15:04"}
+
+// parseLocalDate parses a date written in the given language, like "3 de
+// junio 2018", by translating it to English, or returns zero if it's not one.
+func parseLocalDate(s, code string, loc *time.Location) time.Time {
+	lang, ok := languages[code]
+	if !ok {
+		return time.Time{}
+	}
+
+	var words []string
+	for _, w := range strings.Fields(strings.Replace(strings.ToLower(s), ",", " ", -1)) {
+		w = strings.TrimRight(w, ".")
+		if w == "" {
+			continue
+		}
+		if w[0] >= '0' && w[0] <= '9' {
+			// ordinals like "1er" in French.
+			w = strings.TrimSuffix(w, "er")
+		}
+		filler := false
+		for _, f := range lang.fillers {
+			filler = filler || w == f
+		}
+		if filler {
+			continue
+		}
+		for i, names := range lang.months {
+			for _, name := range names {
+				if w == name {
+					w = time.Month(i + 1).String()
+				}
+			}
+		}
+		words = append(words, w)
+	}
+
+	s = strings.Join(words, " ")
+	if rest, zone := splitZone(s); zone != nil {
+		s, loc = rest, zone
+	}
+	for _, l := range localDateLayouts {
+		if t, err := time.ParseInLocation(l, s, loc); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
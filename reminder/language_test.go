@@ -0,0 +1,57 @@
+package reminder
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLocalDates(t *testing.T) {
+	expected := time.Date(2018, 6, 3, 0, 0, 0, 0, time.UTC)
+	tests := map[string]string{
+		"es": "3 de junio de 2018",
+		"fr": "3 juin 2018",
+		"de": "3. Juni 2018",
+		"pt": "3 de junho de 2018",
+	}
+	for code, s := range tests {
+		d := dates{language: code}
+		if got := d.parse(s, time.Time{}); !got.Equal(expected) {
+			t.Errorf("%s: expected %q to be %v; got %v", code, s, expected, got)
+		}
+		if got := (dates{}).parse(s, time.Time{}); !got.IsZero() {
+			t.Errorf("expected %q not to be read in English; got %v", s, got)
+		}
+	}
+
+	for _, s := range []string{"3 juin 2018 .", "3 juin 2018 ..", "."} {
+		if got := (dates{language: "fr"}).parse(s, time.Time{}); s != "." && !got.Equal(expected) {
+			t.Errorf("expected %q to be %v; got %v", s, expected, got)
+		}
+	}
+
+	if got := (dates{language: "fr"}).parse("1er août 2018 18:00 cet", time.Time{}); !got.Equal(time.Date(2018, 8, 1, 17, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected date %v", got)
+	}
+	if got := (dates{language: "fr"}).parse("septembre 2018", time.Time{}); !got.IsZero() {
+		t.Errorf("expected dates without a day not to be read; got %v", got)
+	}
+
+	ctx := context.Background()
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions([]Option{WithLanguage("de")}), client: &fakeClient{
+		_permission:         func(ctx context.Context, owner, repo, user string) (string, error) { return "admin", nil },
+		_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error { return nil },
+	}}
+	if ok, err := ic.HandleComment(ctx, "foo", "bar", 1, "admin", "/reminder language ES"); !ok || err != nil {
+		t.Fatalf("expected command to run; got %v, %v", ok, err)
+	}
+	if lang, err := ic.Language(ctx, "foo", "bar"); err != nil || lang != "es" {
+		t.Errorf("expected spanish in the repository; got %q (%v)", lang, err)
+	}
+	if lang, err := ic.Language(ctx, "foo", "baz"); err != nil || lang != "de" {
+		t.Errorf("expected german by default; got %q (%v)", lang, err)
+	}
+	if _, err := ParseLanguage("xx"); err == nil {
+		t.Errorf("expected unknown language to fail")
+	}
+}
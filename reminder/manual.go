@@ -0,0 +1,76 @@
+package reminder
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+// WithManualLabels makes the bot respect the deadline labels applied to or
+// removed from an issue by people, instead of reverting them in the next
+// scan, until the deadline of the issue changes.
+func WithManualLabels() Option {
+	return func(o *options) { o.manualLabels = true }
+}
+
+// manualLabel records the last change of the deadline labels of an issue made
+// by a person, and the deadline of the issue at the time.
+type manualLabel struct {
+	Time     time.Time `json:"time"`
+	Deadline time.Time `json:"deadline"`
+}
+
+func manualLabelKey(appID, installationID int, owner, repo string, number int) string {
+	return storage.Key("manuallabel", appID, installationID, strings.ToLower(owner), strings.ToLower(repo), number)
+}
+
+// isBot reports whether the login is the one of an app, like the bot itself.
+func isBot(login string) bool {
+	return strings.HasSuffix(login, "[bot]")
+}
+
+// manualOverride reports whether the deadline labels of the issue were last
+// changed by a person, if enabled, and the issue still has the deadline it
+// had then, so the bot must leave them as they are.
+func (c *InstallationClient) manualOverride(ctx context.Context, issue *issue, deadline time.Time, labels []Label) (bool, error) {
+	if !c.opts.manualLabels {
+		return false, nil
+	}
+	owner, repo, number := issue.repo.owner, issue.repo.name, issue.number
+	events, err := c.client.labelEvents(ctx, owner, repo, number)
+	if err != nil {
+		return false, err
+	}
+	var last *labelEvent
+	for i, e := range events {
+		for _, l := range labels {
+			if strings.EqualFold(e.label, l.Name) {
+				last = &events[i]
+			}
+		}
+	}
+
+	key := manualLabelKey(c.appID, c.installationID, owner, repo, number)
+	if last == nil || isBot(last.actor) || last.actor == c.login() {
+		return false, errors.Wrap(c.opts.store.Delete(ctx, key), "could not forget manual label")
+	}
+
+	var ml manualLabel
+	err = c.opts.store.Get(ctx, key, &ml)
+	if err != nil && err != storage.ErrNotFound {
+		return false, errors.Wrap(err, "could not fetch manual label")
+	}
+	if err == storage.ErrNotFound || !ml.Time.Equal(last.created) {
+		// a new change, which holds for the current deadline.
+		ml = manualLabel{Time: last.created, Deadline: deadline}
+		if err := c.opts.store.Put(ctx, key, ml); err != nil {
+			return false, errors.Wrap(err, "could not store manual label")
+		}
+		return true, nil
+	}
+	return ml.Deadline.Equal(deadline), nil
+}
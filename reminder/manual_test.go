@@ -0,0 +1,63 @@
+package reminder
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestManualLabels(t *testing.T) {
+	var replaced [][]string
+	in := func(days int) string { return "deadline: " + time.Now().AddDate(0, 0, days).Format("2006-01-02") }
+	is := &issue{repo: repository{"foo", "bar"}, number: 1, state: "open", labels: []string{"deadline < 30"}, body: in(3)}
+	events := []labelEvent{
+		{actor: botLogin, label: "deadline < 5", added: true, created: time.Now().Add(-2 * time.Hour)},
+		{actor: "francesc", label: "deadline < 5", added: false, created: time.Now().Add(-time.Hour)},
+		{actor: "francesc", label: "deadline < 30", added: true, created: time.Now().Add(-time.Hour)},
+		{actor: "francesc", label: "bug", added: true, created: time.Now()},
+	}
+	fc := &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) {
+			return []string{"deadline < 5", "deadline < 30"}, nil
+		},
+		_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+			cp := *is
+			return &cp, nil
+		},
+		_labelEvents: func(ctx context.Context, owner, repo string, number int) ([]labelEvent, error) { return events, nil },
+		_replaceIssueLabels: func(ctx context.Context, owner, repo string, number int, labels []string) error {
+			replaced = append(replaced, labels)
+			return nil
+		},
+	}
+	update := func(ic InstallationClient) {
+		replaced = nil
+		if err := ic.UpdateIssue(context.Background(), "foo", "bar", 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil), client: fc}
+	if update(ic); !reflect.DeepEqual(replaced, [][]string{{"deadline < 5"}}) {
+		t.Errorf("expected the manual label to be reverted by default; got %v", replaced)
+	}
+
+	ic.opts = newOptions([]Option{WithManualLabels()})
+	for i := 0; i < 2; i++ {
+		if update(ic); len(replaced) != 0 {
+			t.Errorf("expected the manual label to be kept; got %v", replaced)
+		}
+	}
+
+	is.body = in(2)
+	if update(ic); !reflect.DeepEqual(replaced, [][]string{{"deadline < 5"}}) {
+		t.Errorf("expected the labels to be updated once the deadline changes; got %v", replaced)
+	}
+
+	is.body = in(3)
+	events = append(events, labelEvent{actor: botLogin, label: "deadline < 30", added: true, created: time.Now()})
+	if update(ic); !reflect.DeepEqual(replaced, [][]string{{"deadline < 5"}}) {
+		t.Errorf("expected the labels changed by the bot to be updated; got %v", replaced)
+	}
+}
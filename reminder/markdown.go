@@ -0,0 +1,54 @@
+package reminder
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	htmlComments = regexp.MustCompile(`(?s)<!--.*?-->`)
+	codeSpans    = regexp.MustCompile("``[^`]*``|`[^`\n]*`")
+	listItem     = regexp.MustCompile(`^\s*([-*+]|\d+[.)])\s`)
+)
+
+// stripMarkdown removes the parts of a Markdown text that aren't written by
+// its author as such: fenced and indented code blocks, code spans, quotes, and
+// HTML comments, which are left by many issue templates.
+func stripMarkdown(body string) string {
+	body = htmlComments.ReplaceAllString(body, " ")
+
+	var lines []string
+	var fence string
+	blank, list := true, false
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if indent < 4 && (strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")) {
+			fence = trimmed[:3]
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			blank = true
+			continue
+		case strings.HasPrefix(trimmed, ">"):
+		case (strings.HasPrefix(line, "\t") || indent >= 4) && blank && !list:
+			// an indented code block, unless it continues a list item.
+		default:
+			if indent == 0 {
+				list = listItem.MatchString(line)
+			}
+			lines = append(lines, codeSpans.ReplaceAllString(line, " "))
+		}
+		blank = false
+	}
+	return strings.Join(lines, "\n")
+}
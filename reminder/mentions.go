@@ -0,0 +1,27 @@
+package reminder
+
+// who the reminders mention, as written in the configuration of a repository.
+const (
+	mentionAssignees = "assignees"
+	mentionAuthor    = "author"
+)
+
+// WithAssigneeMentions makes the reminders written without users mention the
+// current assignees of the issue instead of the author of the reminder, or
+// the author if the issue isn't assigned to anyone.
+func WithAssigneeMentions() Option {
+	return func(o *options) { o.mentionAssignees = true }
+}
+
+// reminded returns the users to mention in a reminder written by the author
+// for the given users, if any.
+func (c *InstallationClient) reminded(issue *issue, author string, users []string) []string {
+	switch {
+	case len(users) > 0:
+		return users
+	case c.opts.mentionAssignees && len(issue.assignees) > 0:
+		return issue.assignees
+	default:
+		return []string{author}
+	}
+}
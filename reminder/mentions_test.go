@@ -0,0 +1,54 @@
+package reminder
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAssigneeMentions(t *testing.T) {
+	today := time.Now().Format("2006-01-02")
+	tests := []struct {
+		name      string
+		opts      []Option
+		body      string
+		assignees []string
+		expected  []string
+	}{
+		{"author", nil, "reminder: " + today, []string{"alice"}, []string{"francesc"}},
+		{"assignees", []Option{WithAssigneeMentions()}, "reminder: " + today, []string{"alice", "bob"}, []string{"alice", "bob"}},
+		{"unassigned", []Option{WithAssigneeMentions()}, "reminder: " + today, nil, []string{"francesc"}},
+		{"explicit users", []Option{WithAssigneeMentions()}, "reminder for @carol: " + today, []string{"alice"}, []string{"carol"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mentioned []string
+			ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(tt.opts), client: &fakeClient{
+				_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) { return nil, nil },
+				_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+					return &issue{
+						repo: repository{owner, repo}, number: number, state: "open", author: "francesc",
+						assignees: tt.assignees, body: tt.body,
+					}, nil
+				},
+				_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error {
+					mentioned = append(mentioned, strings.TrimPrefix(strings.Fields(body)[1], "@"))
+					return nil
+				},
+			}}
+
+			if err := ic.UpdateIssue(context.Background(), "foo", "bar", 1); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for i := range mentioned {
+				mentioned[i] = strings.TrimSuffix(mentioned[i], ",")
+			}
+			if !reflect.DeepEqual(mentioned, tt.expected) {
+				t.Errorf("expected reminders for %v; got %v", tt.expected, mentioned)
+			}
+		})
+	}
+}
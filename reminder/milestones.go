@@ -0,0 +1,42 @@
+package reminder
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// WithMilestoneDeadlines makes the bot read the due date of the open milestone
+// of the issues without a deadline written in them, or in their projects, as
+// their deadline.
+func WithMilestoneDeadlines() Option {
+	return func(o *options) { o.milestones = true }
+}
+
+// milestoneDeadline returns the due date of the milestone of the issue, zero
+// if it has none or milestones aren't read.
+func (c *InstallationClient) milestoneDeadline(issue *issue) time.Time {
+	if !c.opts.milestones || issue.milestone.IsZero() {
+		return time.Time{}
+	}
+	// due dates have no timezone, they're read as those written in issues.
+	loc := c.opts.location
+	if loc == nil {
+		loc = time.UTC
+	}
+	d := issue.milestone.UTC()
+	return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc).UTC()
+}
+
+// UpdateMilestone handles a milestone created, edited, closed, or deleted in a
+// repository, scanning it again so the deadlines of its issues follow the due
+// date of the milestone right away. It does nothing unless the deadlines are
+// read from milestones.
+func (c *InstallationClient) UpdateMilestone(ctx context.Context, owner, repo string) error {
+	if !c.opts.milestones {
+		return nil
+	}
+	logrus.Infof("milestone changed in %s/%s, updating repository", owner, repo)
+	return c.UpdateRepo(ctx, owner, repo)
+}
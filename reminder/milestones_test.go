@@ -0,0 +1,47 @@
+package reminder
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMilestoneDeadline(t *testing.T) {
+	due := time.Date(2018, 8, 1, 7, 0, 0, 0, time.UTC)
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions([]Option{WithMilestoneDeadlines()}), client: &fakeClient{}}
+
+	got, err := ic.deadline(context.Background(), &issue{milestone: due})
+	if expected := time.Date(2018, 8, 1, 0, 0, 0, 0, time.UTC); err != nil || !got.Equal(expected) {
+		t.Errorf("expected the due date of the milestone %v; got %v (%v)", expected, got, err)
+	}
+	got, err = ic.deadline(context.Background(), &issue{milestone: due, body: "deadline: 2018-08-20"})
+	if expected := time.Date(2018, 8, 20, 0, 0, 0, 0, time.UTC); err != nil || !got.Equal(expected) {
+		t.Errorf("expected the deadline written in the issue %v; got %v (%v)", expected, got, err)
+	}
+
+	ic.opts = newOptions(nil)
+	if got, err := ic.deadline(context.Background(), &issue{milestone: due}); err != nil || !got.IsZero() {
+		t.Errorf("expected milestones to be ignored by default; got %v (%v)", got, err)
+	}
+}
+
+func TestUpdateMilestone(t *testing.T) {
+	scans := 0
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil), client: &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) {
+			return []string{"deadline < 3"}, nil
+		},
+		_issues: func(ctx context.Context, owner, repo string) ([]int, error) {
+			scans++
+			return nil, nil
+		},
+	}}
+
+	if err := ic.UpdateMilestone(context.Background(), "foo", "bar"); err != nil || scans != 0 {
+		t.Errorf("expected no scan without milestone deadlines; got %d (%v)", scans, err)
+	}
+	ic.opts = newOptions([]Option{WithMilestoneDeadlines()})
+	if err := ic.UpdateMilestone(context.Background(), "foo", "bar"); err != nil || scans != 1 {
+		t.Errorf("expected the repository to be scanned; got %d scans (%v)", scans, err)
+	}
+}
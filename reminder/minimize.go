@@ -0,0 +1,73 @@
+package reminder
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+// WithMinimizedReminders makes the bot hide its previous reminders on an issue
+// as outdated whenever it posts a new one. Minimized comments are not deleted,
+// they can still be expanded by anyone reading the issue.
+func WithMinimizedReminders() Option {
+	return func(o *options) { o.minimize = true }
+}
+
+// reminderText is contained in every reminder comment with the default text.
+const reminderText = "it's reminder day!"
+
+// reminderMarker is hidden in the reminder comments written with a template.
+const reminderMarker = "<!-- github-reminder:reminder -->"
+
+// isReminder reports whether the comment is a reminder posted by the bot.
+func (c *InstallationClient) isReminder(cm comment) bool {
+	return c.isDueReminder(cm) || (cm.author == c.login() && strings.Contains(cm.body, cadenceMarker))
+}
+
+// isDueReminder reports whether the comment is a reminder of the ones written
+// in the issues posted by the bot, leaving out those of the cadences.
+func (c *InstallationClient) isDueReminder(cm comment) bool {
+	return cm.author == c.login() && (strings.Contains(cm.body, reminderText) || strings.Contains(cm.body, reminderMarker))
+}
+
+// minimizeReminders minimizes the reminders posted on the issue before the
+// current one. The newest minimized comment is stored so each of them is
+// minimized only once, and the rest are minimized on a later update.
+func (c *InstallationClient) minimizeReminders(ctx context.Context, issue *issue) error {
+	if !c.opts.minimize {
+		return nil
+	}
+
+	key := storage.Key("minimized", c.appID, c.installationID, issue.repo.owner, issue.repo.name, issue.number)
+	var last int64
+	if err := c.opts.store.Get(ctx, key, &last); err != nil && err != storage.ErrNotFound {
+		return errors.Wrap(err, "could not fetch minimized reminders")
+	}
+
+	newest := last
+	for _, cm := range issue.comments {
+		if !c.isReminder(cm) || cm.id <= last {
+			continue
+		}
+		err := c.client.minimizeComment(ctx, issue.repo.owner, issue.repo.name, cm.id)
+		if errors.Cause(err) == errQuiet {
+			break
+		}
+		if err != nil {
+			logrus.Warnf("could not minimize comment %d on %s/%s#%d: %v",
+				cm.id, issue.repo.owner, issue.repo.name, issue.number, err)
+			break
+		}
+		if cm.id > newest {
+			newest = cm.id
+		}
+	}
+	if newest == last {
+		return nil
+	}
+	return errors.Wrap(c.opts.store.Put(ctx, key, newest), "could not store minimized reminders")
+}
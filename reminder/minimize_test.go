@@ -0,0 +1,92 @@
+package reminder
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMinimizeReminders(t *testing.T) {
+	var minimized []int64
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions([]Option{WithMinimizedReminders()}), client: &fakeClient{
+		_minimizeComment: func(ctx context.Context, owner, repo string, id int64) error {
+			minimized = append(minimized, id)
+			return nil
+		},
+	}}
+
+	i := &issue{repo: repository{"foo", "bar"}, number: 1, comments: []comment{
+		{id: 1, author: botLogin, body: "hi @francesc, it's reminder day!"},
+		{id: 2, author: "francesc", body: "thanks, it's reminder day!"},
+		{id: 3, author: botLogin, body: "@francesc you're out of office"},
+		{id: 4, author: botLogin, body: "hi @francesc, this sev1 issue is due in 3 days\n" + cadenceMarker},
+	}}
+	ctx := context.Background()
+	if err := ic.minimizeReminders(ctx, i); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []int64{1, 4}; !reflect.DeepEqual(minimized, expected) {
+		t.Errorf("expected comments %v to be minimized; got %v", expected, minimized)
+	}
+
+	minimized = nil
+	i.comments = append(i.comments, comment{id: 5, author: botLogin, body: "hi @francesc, it's reminder day!"})
+	if err := ic.minimizeReminders(ctx, i); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []int64{5}; !reflect.DeepEqual(minimized, expected) {
+		t.Errorf("expected comments %v to be minimized; got %v", expected, minimized)
+	}
+}
+
+func TestMinimizeAfterQuietPeriod(t *testing.T) {
+	var minimized []int64
+	qc := &quietClient{
+		client: &fakeClient{
+			_minimizeComment: func(ctx context.Context, owner, repo string, id int64) error {
+				minimized = append(minimized, id)
+				return nil
+			},
+		},
+		periods: []QuietPeriod{{Start: time.Now().Add(-time.Hour), End: time.Now().Add(time.Hour)}},
+	}
+	ic := InstallationClient{appID: 42, installationID: 43, client: qc,
+		opts: newOptions([]Option{WithMinimizedReminders()})}
+
+	i := &issue{repo: repository{"foo", "bar"}, number: 1, comments: []comment{
+		{id: 1, author: botLogin, body: "hi @francesc, it's reminder day!"},
+	}}
+	ctx := context.Background()
+	if err := ic.minimizeReminders(ctx, i); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(minimized) != 0 {
+		t.Fatalf("expected no comment minimized during the quiet period; got %v", minimized)
+	}
+
+	qc.periods = nil
+	if err := ic.minimizeReminders(ctx, i); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []int64{1}; !reflect.DeepEqual(minimized, expected) {
+		t.Errorf("expected comments %v to be minimized after the quiet period; got %v", expected, minimized)
+	}
+}
+
+func TestGraphQLURL(t *testing.T) {
+	tests := map[string]string{
+		"https://api.github.com/":            "https://api.github.com/graphql",
+		"https://github.example.com/api/v3/": "https://github.example.com/api/graphql",
+	}
+	for base, expected := range tests {
+		u, err := url.Parse(base)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := graphQLURL(u); got != expected {
+			t.Errorf("expected GraphQL URL for %s to be %s; got %s", base, expected, got)
+		}
+	}
+}
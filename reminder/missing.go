@@ -0,0 +1,85 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// missingMarker is hidden in the comments asking for a deadline to find them later.
+const missingMarker = "<!-- github-reminder:missing -->"
+
+// A RequiredDeadline asks for a deadline in the open issues carrying any of
+// the given labels, like "priority: high", when none can be found.
+type RequiredDeadline struct {
+	// Labels are the labels of the issues that need a deadline.
+	Labels []string
+	// Label, like "needs-deadline", is applied to the issues without one
+	// instead of commenting, and removed once they have one.
+	Label string
+}
+
+// WithRequiredDeadline makes the bot ask for a deadline in the issues with
+// the labels of r, with a single comment or a label.
+func WithRequiredDeadline(r RequiredDeadline) Option {
+	return func(o *options) { o.required = &r }
+}
+
+// requiredBy returns the label of the issue requiring a deadline, if any.
+func (c *InstallationClient) requiredBy(issue *issue) string {
+	if c.opts.required == nil {
+		return ""
+	}
+	for _, l := range issue.labels {
+		for _, r := range c.opts.required.Labels {
+			if strings.EqualFold(l, r) {
+				return l
+			}
+		}
+	}
+	return ""
+}
+
+// checkMissingDeadline asks for the deadline of the issue if it requires one
+// and it has none, zero, unless it was cleared on purpose. If a label is used
+// to ask for it, it's removed once the issue has a deadline.
+func (c *InstallationClient) checkMissingDeadline(ctx context.Context, issue *issue, deadline time.Time) error {
+	if c.opts.required == nil {
+		return nil
+	}
+	owner, repo, number := issue.repo.owner, issue.repo.name, issue.number
+	by := c.requiredBy(issue)
+	missing := by != "" && deadline.IsZero() && !issue.cleared
+
+	name := c.opts.required.Label
+	if name == "" {
+		if !missing || c.botCommented(issue, missingMarker) {
+			return nil
+		}
+		// the keyword isn't followed by a date, so the comment isn't read as a deadline.
+		text := fmt.Sprintf("hi @%s, this issue is labeled `%s` but has no deadline yet. "+
+			"Please add one with the `/deadline` command, or a line starting with `deadline:` followed by the date.\n%s",
+			issue.author, by, missingMarker)
+		return c.Comment(ctx, owner, repo, number, text)
+	}
+
+	current := ""
+	for _, l := range issue.labels {
+		if strings.EqualFold(l, name) {
+			current = l
+		}
+	}
+	switch {
+	case missing && current == "":
+		logrus.Debugf("applying %s to issue %s/%s#%d", name, owner, repo, number)
+		return errors.Wrapf(c.client.addIssueLabel(ctx, owner, repo, number, name), "could not apply label %s", name)
+	case !missing && current != "":
+		logrus.Debugf("removing %s from issue %s/%s#%d", current, owner, repo, number)
+		return errors.Wrapf(c.client.removeIssueLabel(ctx, owner, repo, number, current), "could not remove label %s", current)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package reminder
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequiredDeadline(t *testing.T) {
+	var comments []comment
+	var added, removed []string
+	is := &issue{repo: repository{"foo", "bar"}, number: 1, state: "open", author: "erizocosmico", labels: []string{"Priority: High"}}
+	fc := &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) { return nil, nil },
+		_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+			cp := *is
+			cp.comments = comments
+			return &cp, nil
+		},
+		_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error {
+			comments = append(comments, comment{author: botLogin, body: body, created: time.Now()})
+			return nil
+		},
+		_addIssueLabel: func(ctx context.Context, owner, repo string, number int, label string) error {
+			added = append(added, label)
+			return nil
+		},
+		_removeIssueLabel: func(ctx context.Context, owner, repo string, number int, label string) error {
+			removed = append(removed, label)
+			return nil
+		},
+	}
+	update := func(ic InstallationClient) {
+		if err := ic.UpdateIssue(context.Background(), "foo", "bar", 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil), client: fc}
+	if update(ic); len(comments) != 0 {
+		t.Fatalf("expected no comment unless enabled; got %v", comments)
+	}
+
+	ic.opts = newOptions([]Option{WithRequiredDeadline(RequiredDeadline{Labels: []string{"priority: high"}})})
+	update(ic)
+	update(ic)
+	if len(comments) != 1 || !strings.Contains(comments[0].body, "@erizocosmico") || !strings.Contains(comments[0].body, "`Priority: High`") {
+		t.Fatalf("expected a single comment asking for a deadline; got %v", comments)
+	}
+	cp, _ := fc.issue(context.Background(), "foo", "bar", 1)
+	for _, g := range []Grammar{GrammarV1, LatestGrammar} {
+		cp.grammar = g
+		if d, err := ic.deadline(context.Background(), cp); err != nil || !d.IsZero() || cp.cleared {
+			t.Errorf("expected the comment not to be read as a deadline with grammar %v; got %v (%v)", g, d, err)
+		}
+	}
+
+	comments = nil
+	ic.opts = newOptions([]Option{WithRequiredDeadline(RequiredDeadline{Labels: []string{"priority: high"}, Label: "needs-deadline"})})
+	if update(ic); len(comments) != 0 || !reflect.DeepEqual(added, []string{"needs-deadline"}) {
+		t.Errorf("expected the label to be applied instead of commenting; got comments %v and labels %v", comments, added)
+	}
+
+	is.labels = append(is.labels, "needs-deadline")
+	is.body = "deadline: " + time.Now().AddDate(0, 0, 3).Format("2006-01-02")
+	if update(ic); !reflect.DeepEqual(removed, []string{"needs-deadline"}) {
+		t.Errorf("expected the label to be removed once there's a deadline; got %v", removed)
+	}
+
+	removed, added = nil, nil
+	is.labels, is.body = []string{"bug"}, ""
+	if update(ic); len(added) != 0 || len(removed) != 0 {
+		t.Errorf("expected issues without the labels to be left alone; got added %v and removed %v", added, removed)
+	}
+}
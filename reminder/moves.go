@@ -0,0 +1,148 @@
+package reminder
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+// repoState are the kinds of the state kept by repository, under keys like
+// kind/app/installation/owner/repo, followed by the number of the issue for
+// the state of the issues. lower is set for those whose owner and repository
+// are lowercased. The deliveries deferred or held for later keep the names
+// they were queued with, since their messages are already written.
+var repoState = []struct {
+	kind  string
+	lower bool
+}{
+	{"deadline", false},
+	{"history", false},
+	{"schedule", false},
+	{"imported", false},
+	{"fallback", false},
+	{"minimized", false},
+	{"changeset", false},
+	{"color", false},
+	{"overduecomment", true},
+	{"followup", true},
+	{"escalation", true},
+	{"snooze", true},
+	{"deadlinecmd", true},
+	{"manuallabel", true},
+	{"checkrun", true},
+	{"commitstatus", true},
+	{"projectsync", true},
+	{"projectboard", true},
+	{"projectcolumn", true},
+	{"projectmissing", true},
+	{"disabled", true},
+	{"days", true},
+	{"language", true},
+	{"synonyms", true},
+	{"grammar", true},
+	{"labelprefix", true},
+	{"digestissue", true},
+	{"welcome", true},
+}
+
+// MoveRepo handles a repository renamed or transferred from fromOwner/fromRepo
+// to owner/repo, moving the state the bot keeps for it and its issues to its
+// new name, and scanning it again.
+func (c *InstallationClient) MoveRepo(ctx context.Context, fromOwner, fromRepo, owner, repo string) error {
+	logrus.Infof("repository %s/%s moved to %s/%s", fromOwner, fromRepo, owner, repo)
+	if err := c.moveState(ctx, repository{fromOwner, fromRepo}, 0, c, repository{owner, repo}, 0); err != nil {
+		return err
+	}
+	return c.UpdateRepo(ctx, owner, repo)
+}
+
+// MoveIssue handles an issue transferred from fromOwner/fromRepo#fromNumber to
+// owner/repo#number, moving the state the bot keeps for it to the new issue
+// in the installation dst, c itself unless the issue was transferred to
+// another owner, and updating it there so it gets the deadline labels of its
+// new repository.
+func (c *InstallationClient) MoveIssue(ctx context.Context, fromOwner, fromRepo string, fromNumber int, dst *InstallationClient, owner, repo string, number int) error {
+	logrus.Infof("issue %s/%s#%d transferred to %s/%s#%d", fromOwner, fromRepo, fromNumber, owner, repo, number)
+	if err := c.moveState(ctx, repository{fromOwner, fromRepo}, fromNumber, dst, repository{owner, repo}, number); err != nil {
+		return err
+	}
+	return dst.UpdateIssue(ctx, owner, repo, number)
+}
+
+// moveState moves the state kept for the issue number of the repository from
+// to the issue toNumber of the repository to in the installation of dst, or
+// the state of the whole repository and its issues if the numbers are zero.
+// The owner, repository, and number in the values are moved too.
+func (c *InstallationClient) moveState(ctx context.Context, from repository, number int, dst *InstallationClient, to repository, toNumber int) error {
+	for _, s := range repoState {
+		// only the keys starting like those of the repository, or of the
+		// issue, are listed.
+		owner, name := from.owner, from.name
+		if s.lower {
+			owner, name = strings.ToLower(owner), strings.ToLower(name)
+		}
+		prefix := storage.Key(s.kind, c.appID, c.installationID, owner, name)
+		if number != 0 {
+			prefix = storage.Key(prefix, number)
+		}
+		keys, err := c.opts.store.List(ctx, prefix)
+		if err != nil {
+			return errors.Wrapf(err, "could not list %s", s.kind)
+		}
+		for _, key := range keys {
+			parts := strings.Split(key, "/")
+			if parts[4] != name || number != 0 && (len(parts) < 6 || parts[5] != strconv.Itoa(number)) {
+				continue
+			}
+			parts[2], parts[3], parts[4] = strconv.Itoa(dst.installationID), to.owner, to.name
+			if s.lower {
+				parts[3], parts[4] = strings.ToLower(to.owner), strings.ToLower(to.name)
+			}
+			if number != 0 {
+				parts[5] = strconv.Itoa(toNumber)
+			}
+			if err := c.moveKey(ctx, key, dst, strings.Join(parts, "/"), from, number, to, toNumber); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// moveKey moves the value under key to the key moved in the store of dst,
+// replacing the owner, repository, and number in it if it has them.
+func (c *InstallationClient) moveKey(ctx context.Context, key string, dst *InstallationClient, moved string, from repository, number int, to repository, toNumber int) error {
+	var v json.RawMessage
+	if err := c.opts.store.Get(ctx, key, &v); err != nil {
+		return errors.Wrapf(err, "could not fetch %s", key)
+	}
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(v, &fields) == nil {
+		var owner, repo string
+		var n int
+		json.Unmarshal(fields["owner"], &owner)
+		json.Unmarshal(fields["repo"], &repo)
+		json.Unmarshal(fields["number"], &n)
+		if strings.EqualFold(owner, from.owner) && strings.EqualFold(repo, from.name) {
+			fields["owner"], _ = json.Marshal(to.owner)
+			fields["repo"], _ = json.Marshal(to.name)
+			if number != 0 && n == number {
+				fields["number"], _ = json.Marshal(toNumber)
+			}
+			v, _ = json.Marshal(fields)
+		}
+	}
+	if err := dst.opts.store.Put(ctx, moved, v); err != nil {
+		return errors.Wrapf(err, "could not store %s", moved)
+	}
+	if moved == key {
+		return nil
+	}
+	return errors.Wrapf(c.opts.store.Delete(ctx, key), "could not delete %s", key)
+}
@@ -0,0 +1,112 @@
+package reminder
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+func TestMoveIssue(t *testing.T) {
+	var added []string
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil), client: &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) {
+			if owner != "acme" || repo != "Web" {
+				t.Errorf("expected the labels of the new repository; got those of %s/%s", owner, repo)
+			}
+			return []string{"deadline < 7"}, nil
+		},
+		_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+			return &issue{
+				repo: repository{owner, repo}, number: number, state: "open", author: "francesc",
+				body: "deadline: " + time.Now().AddDate(0, 0, 3).Format("2006-01-02"),
+			}, nil
+		},
+		_addIssueLabel: func(ctx context.Context, owner, repo string, number int, label string) error {
+			added = append(added, storage.Key(owner, repo, number, label))
+			return nil
+		},
+	}}
+	ctx, store := context.Background(), ic.opts.store
+
+	d := Deadline{Owner: "Foo", Repo: "bar", Number: 1, Title: "Ship it", Deadline: time.Now().AddDate(0, 0, 3)}
+	other := Deadline{Owner: "Foo", Repo: "bar", Number: 12, Title: "Ship it too", Deadline: time.Now().AddDate(0, 0, 3)}
+	store.Put(ctx, deadlineKey(42, 43, "Foo", "bar", 1), d)
+	store.Put(ctx, deadlineKey(42, 43, "Foo", "bar", 12), other)
+	store.Put(ctx, snoozeKey(42, 43, "Foo", "bar", 1), time.Now().AddDate(0, 0, 1))
+
+	if err := ic.MoveIssue(ctx, "Foo", "bar", 1, &ic, "acme", "Web", 7); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(added) != 1 || added[0] != "acme/Web/7/deadline < 7" {
+		t.Errorf("expected the labels of the new repository to be applied; got %v", added)
+	}
+
+	var got Deadline
+	if err := store.Get(ctx, deadlineKey(42, 43, "acme", "Web", 7), &got); err != nil {
+		t.Fatalf("expected the deadline to be moved: %v", err)
+	}
+	if got.Owner != "acme" || got.Repo != "Web" || got.Number != 7 {
+		t.Errorf("expected the deadline of acme/Web#7; got %s/%s#%d", got.Owner, got.Repo, got.Number)
+	}
+	if err := store.Get(ctx, deadlineKey(42, 43, "Foo", "bar", 1), &got); err != storage.ErrNotFound {
+		t.Errorf("expected the former deadline to be deleted; got %v", err)
+	}
+	if err := store.Get(ctx, deadlineKey(42, 43, "Foo", "bar", 12), &got); err != nil || got.Number != 12 {
+		t.Errorf("expected the other issues to be left alone; got %v (%v)", got, err)
+	}
+	var until time.Time
+	if err := store.Get(ctx, snoozeKey(42, 43, "acme", "Web", 7), &until); err != nil {
+		t.Errorf("expected the snooze to be moved: %v", err)
+	}
+
+	// issues transferred to another owner move to its installation.
+	dst := InstallationClient{appID: 42, installationID: 44, opts: ic.opts, client: &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) { return nil, nil },
+		_issue:      ic.client.(*fakeClient)._issue,
+	}}
+	if err := ic.MoveIssue(ctx, "acme", "Web", 7, &dst, "other", "site", 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := store.Get(ctx, deadlineKey(42, 44, "other", "site", 3), &got); err != nil || got.Owner != "other" {
+		t.Errorf("expected the deadline to be moved to the other installation; got %+v (%v)", got, err)
+	}
+	if err := store.Get(ctx, snoozeKey(42, 44, "other", "site", 3), &until); err != nil {
+		t.Errorf("expected the snooze to be moved to the other installation: %v", err)
+	}
+	if keys, _ := store.List(ctx, storage.Key("deadline", 42, 43, "acme")); len(keys) != 0 {
+		t.Errorf("expected the former keys to be deleted; got %v", keys)
+	}
+}
+
+func TestMoveRepo(t *testing.T) {
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil), client: &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) { return nil, nil },
+	}}
+	ctx, store := context.Background(), ic.opts.store
+
+	store.Put(ctx, deadlineKey(42, 43, "foo", "bar", 1), Deadline{Owner: "foo", Repo: "bar", Number: 1})
+	store.Put(ctx, deadlineKey(42, 43, "foo", "barbie", 1), Deadline{Owner: "foo", Repo: "barbie", Number: 1})
+	store.Put(ctx, storage.Key("color", 42, 43, "foo", "bar", "deadline < 7"), "ff0000")
+	store.Put(ctx, disabledKey(42, 43, "foo", "bar"), disabledRepo{By: "francesc", Time: time.Now()})
+
+	if err := ic.MoveRepo(ctx, "foo", "bar", "Acme", "Bar"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, key := range []string{
+		deadlineKey(42, 43, "Acme", "Bar", 1),
+		deadlineKey(42, 43, "foo", "barbie", 1),
+		storage.Key("color", 42, 43, "Acme", "Bar", "deadline < 7"),
+		disabledKey(42, 43, "Acme", "Bar"),
+	} {
+		var v interface{}
+		if err := store.Get(ctx, key, &v); err != nil {
+			t.Errorf("expected %s to be stored: %v", key, err)
+		}
+	}
+	keys, _ := store.List(ctx, storage.Key("deadline", 42, 43, "foo", "bar")+"/")
+	if len(keys) != 0 {
+		t.Errorf("expected the former keys to be deleted; got %v", keys)
+	}
+}
@@ -0,0 +1,384 @@
+package reminder
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/notify"
+)
+
+// A MutationKind identifies the kind of change applied to an issue.
+type MutationKind string
+
+// The kinds of mutations performed by the bot.
+const (
+	CommentMutation       MutationKind = "comment"
+	AddLabelMutation      MutationKind = "add-label"
+	RemoveLabelMutation   MutationKind = "remove-label"
+	ReplaceLabelsMutation MutationKind = "replace-labels"
+	MinimizeMutation      MutationKind = "minimize"
+	CreateLabelMutation   MutationKind = "create-label"
+	LabelColorMutation    MutationKind = "label-color"
+	CreateIssueMutation   MutationKind = "create-issue"
+	EditIssueMutation     MutationKind = "edit-issue"
+	PinIssueMutation      MutationKind = "pin-issue"
+	CheckRunMutation      MutationKind = "check-run"
+	StatusMutation        MutationKind = "status"
+	ProjectDateMutation   MutationKind = "project-date"
+	AddToProjectMutation  MutationKind = "add-to-project"
+	MoveProjectMutation   MutationKind = "move-project-item"
+)
+
+// A Mutation is a change the bot wants to perform on an issue.
+// Value holds the comment body, the label name, the labels of the issue one
+// per line, or the id of the comment to minimize depending on the kind. The
+// rest of the kinds, taking more than one value, hold their arguments in it
+// as JSON.
+type Mutation struct {
+	Kind   MutationKind `json:"kind"`
+	Owner  string       `json:"owner"`
+	Repo   string       `json:"repo"`
+	Number int          `json:"number"`
+	Value  string       `json:"value"`
+}
+
+// mutationArgs are the arguments of the mutations taking more than one value.
+type mutationArgs struct {
+	Label string `json:"label,omitempty"`
+	Color string `json:"color,omitempty"`
+
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+	// Key stores the number of the issue created, pinned if Pin is set.
+	Key string `json:"key,omitempty"`
+	Pin bool   `json:"pin,omitempty"`
+
+	SHA         string `json:"sha,omitempty"`
+	Conclusion  string `json:"conclusion,omitempty"`
+	Summary     string `json:"summary,omitempty"`
+	State       string `json:"state,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	Project *Project   `json:"project,omitempty"`
+	Field   string     `json:"field,omitempty"`
+	Column  string     `json:"column,omitempty"`
+	Date    *time.Time `json:"date,omitempty"`
+}
+
+// argsMutation returns the mutation of the given kind with its arguments.
+func argsMutation(kind MutationKind, owner, repo string, number int, args mutationArgs) Mutation {
+	b, _ := json.Marshal(args)
+	return Mutation{kind, owner, repo, number, string(b)}
+}
+
+func labelMutation(kind MutationKind, owner, repo, label, color string) Mutation {
+	return argsMutation(kind, owner, repo, 0, mutationArgs{Label: label, Color: color})
+}
+
+func checkRunMutation(owner, repo string, run checkRun) Mutation {
+	return argsMutation(CheckRunMutation, owner, repo, 0, mutationArgs{
+		SHA: run.sha, Conclusion: run.conclusion, Title: run.title, Summary: run.summary,
+	})
+}
+
+func statusMutation(owner, repo string, status commitStatus) Mutation {
+	return argsMutation(StatusMutation, owner, repo, 0, mutationArgs{
+		SHA: status.sha, State: status.state, Description: status.description,
+	})
+}
+
+func projectDateMutation(owner, repo string, number int, field string, date time.Time) Mutation {
+	return argsMutation(ProjectDateMutation, owner, repo, number, mutationArgs{Field: field, Date: &date})
+}
+
+func moveProjectMutation(owner, repo string, number int, p Project, field, column string) Mutation {
+	return argsMutation(MoveProjectMutation, owner, repo, number, mutationArgs{Project: &p, Field: field, Column: column})
+}
+
+// recorder is a client that records all of the write operations instead of performing them.
+type recorder struct {
+	client
+	mutations []Mutation
+	events    []PendingEvent
+	deferred  []Deferred
+}
+
+// A Deferred is a call kept until the mutations are applied: the call to the
+// pager of the escalations opening the Incident for Event, or resolving it if
+// there's no Event, if any, and then storing Value under Key, or deleting it
+// if there's no Value. They're plain values so they can be held along with
+// the mutations of a changeset.
+type Deferred struct {
+	Incident string          `json:"incident,omitempty"`
+	Event    *notify.Event   `json:"event,omitempty"`
+	Key      string          `json:"key"`
+	Value    json.RawMessage `json:"value,omitempty"`
+}
+
+// deferredPut returns the call storing v under key.
+func deferredPut(key string, v interface{}) Deferred {
+	b, _ := json.Marshal(v)
+	return Deferred{Key: key, Value: b}
+}
+
+// whenApplied makes the call d, or keeps it for when the mutations are applied
+// while recording them, so the incidents paged and the state stored are held
+// or discarded along with them.
+func (c *InstallationClient) whenApplied(ctx context.Context, d Deferred) error {
+	if rec, ok := c.client.(*recorder); ok {
+		rec.deferred = append(rec.deferred, d)
+		return nil
+	}
+
+	if esc := c.opts.escalation; d.Incident != "" && esc != nil {
+		if d.Event != nil {
+			if err := esc.Pager.Trigger(ctx, d.Incident, *d.Event); err != nil {
+				return err
+			}
+		} else if err := esc.Pager.Resolve(ctx, d.Incident); err != nil {
+			return err
+		}
+	}
+	if d.Value == nil {
+		return errors.Wrapf(c.opts.store.Delete(ctx, d.Key), "could not delete %s", d.Key)
+	}
+	return errors.Wrapf(c.opts.store.Put(ctx, d.Key, d.Value), "could not store %s", d.Key)
+}
+
+func (r *recorder) createIssueComment(ctx context.Context, owner, repo string, number int, body string) error {
+	r.mutations = append(r.mutations, Mutation{CommentMutation, owner, repo, number, body})
+	return nil
+}
+
+func (r *recorder) removeIssueLabel(ctx context.Context, owner, repo string, number int, label string) error {
+	r.mutations = append(r.mutations, Mutation{RemoveLabelMutation, owner, repo, number, label})
+	return nil
+}
+
+func (r *recorder) addIssueLabel(ctx context.Context, owner, repo string, number int, label string) error {
+	r.mutations = append(r.mutations, Mutation{AddLabelMutation, owner, repo, number, label})
+	return nil
+}
+
+func (r *recorder) replaceIssueLabels(ctx context.Context, owner, repo string, number int, labels []string) error {
+	r.mutations = append(r.mutations, Mutation{ReplaceLabelsMutation, owner, repo, number, strings.Join(labels, "\n")})
+	return nil
+}
+
+func (r *recorder) minimizeComment(ctx context.Context, owner, repo string, id int64) error {
+	r.mutations = append(r.mutations, Mutation{MinimizeMutation, owner, repo, 0, strconv.FormatInt(id, 10)})
+	return nil
+}
+
+func (r *recorder) createLabel(ctx context.Context, owner, repo, label, color string) error {
+	r.mutations = append(r.mutations, labelMutation(CreateLabelMutation, owner, repo, label, color))
+	return nil
+}
+
+func (r *recorder) editLabelColor(ctx context.Context, owner, repo, label, color string) error {
+	r.mutations = append(r.mutations, labelMutation(LabelColorMutation, owner, repo, label, color))
+	return nil
+}
+
+// createIssue returns 0, since the issue is only opened once applied.
+func (r *recorder) createIssue(ctx context.Context, owner, repo, title, body string) (int, error) {
+	r.mutations = append(r.mutations, argsMutation(CreateIssueMutation, owner, repo, 0, mutationArgs{Title: title, Body: body}))
+	return 0, nil
+}
+
+func (r *recorder) editIssueBody(ctx context.Context, owner, repo string, number int, body string) error {
+	r.mutations = append(r.mutations, Mutation{EditIssueMutation, owner, repo, number, body})
+	return nil
+}
+
+func (r *recorder) pinIssue(ctx context.Context, owner, repo string, number int) error {
+	r.mutations = append(r.mutations, Mutation{PinIssueMutation, owner, repo, number, ""})
+	return nil
+}
+
+func (r *recorder) createCheckRun(ctx context.Context, owner, repo string, run checkRun) error {
+	r.mutations = append(r.mutations, checkRunMutation(owner, repo, run))
+	return nil
+}
+
+func (r *recorder) createStatus(ctx context.Context, owner, repo string, status commitStatus) error {
+	r.mutations = append(r.mutations, statusMutation(owner, repo, status))
+	return nil
+}
+
+func (r *recorder) setProjectDate(ctx context.Context, owner, repo string, number int, field string, date time.Time) error {
+	r.mutations = append(r.mutations, projectDateMutation(owner, repo, number, field, date))
+	return nil
+}
+
+func (r *recorder) addToProject(ctx context.Context, owner, repo string, number int, p Project) error {
+	r.mutations = append(r.mutations, argsMutation(AddToProjectMutation, owner, repo, number, mutationArgs{Project: &p}))
+	return nil
+}
+
+// moveProjectItem reports the issue as found, since it's only looked for
+// once applied.
+func (r *recorder) moveProjectItem(ctx context.Context, owner, repo string, number int, p Project, field, column string) (bool, error) {
+	r.mutations = append(r.mutations, moveProjectMutation(owner, repo, number, p, field, column))
+	return true, nil
+}
+
+// openIssue opens an issue, pinning it if pin is set and storing its number
+// under key, or records doing so while recording.
+func (c *InstallationClient) openIssue(ctx context.Context, owner, repo, title, body, key string, pin bool) error {
+	m := argsMutation(CreateIssueMutation, owner, repo, 0, mutationArgs{Title: title, Body: body, Key: key, Pin: pin})
+	if rec, ok := c.client.(*recorder); ok {
+		rec.mutations = append(rec.mutations, m)
+		return nil
+	}
+	return c.apply(ctx, []Mutation{m})
+}
+
+// recording returns a copy of c recording all of its write operations.
+func (c *InstallationClient) recording() (*InstallationClient, *recorder) {
+	rec := &recorder{client: c.client}
+	rc := *c
+	rc.client = rec
+	return &rc, rec
+}
+
+// flush applies the mutations recorded by rec, runs the calls it deferred, and
+// delivers its events.
+func (c *InstallationClient) flush(ctx context.Context, rec *recorder) error {
+	if err := c.apply(ctx, rec.mutations); err != nil {
+		return err
+	}
+	return c.runDeferred(ctx, rec.deferred, rec.events)
+}
+
+// runDeferred makes the deferred calls and delivers the events kept until the
+// mutations were applied.
+func (c *InstallationClient) runDeferred(ctx context.Context, ds []Deferred, events []PendingEvent) error {
+	for _, d := range ds {
+		if err := c.whenApplied(ctx, d); err != nil {
+			return err
+		}
+	}
+	for _, pe := range events {
+		c.deliver(ctx, pe)
+	}
+	return nil
+}
+
+// apply performs the given mutations in order.
+func (c *InstallationClient) apply(ctx context.Context, ms []Mutation) error {
+	for _, m := range ms {
+		switch m.Kind {
+		case CommentMutation:
+			if err := c.client.createIssueComment(ctx, m.Owner, m.Repo, m.Number, m.Value); err != nil {
+				return errors.Wrapf(err, "could not comment on %s/%s#%d", m.Owner, m.Repo, m.Number)
+			}
+		case AddLabelMutation:
+			if err := c.client.addIssueLabel(ctx, m.Owner, m.Repo, m.Number, m.Value); err != nil {
+				return errors.Wrapf(err, "could not apply label %s", m.Value)
+			}
+		case RemoveLabelMutation:
+			// labels not present in the issue fail to be removed, that's fine.
+			c.client.removeIssueLabel(ctx, m.Owner, m.Repo, m.Number, m.Value)
+		case ReplaceLabelsMutation:
+			var labels []string
+			if m.Value != "" {
+				labels = strings.Split(m.Value, "\n")
+			}
+			if err := c.client.replaceIssueLabels(ctx, m.Owner, m.Repo, m.Number, labels); err != nil {
+				return errors.Wrapf(err, "could not replace labels of %s/%s#%d", m.Owner, m.Repo, m.Number)
+			}
+		case MinimizeMutation:
+			id, err := strconv.ParseInt(m.Value, 10, 64)
+			if err != nil {
+				logrus.Errorf("bad comment id %q", m.Value)
+				continue
+			}
+			// an outdated reminder left visible is not worth failing for.
+			if err := c.client.minimizeComment(ctx, m.Owner, m.Repo, id); err != nil {
+				logrus.Warnf("could not minimize comment %d on %s/%s: %v", id, m.Owner, m.Repo, err)
+			}
+		case EditIssueMutation:
+			if err := c.client.editIssueBody(ctx, m.Owner, m.Repo, m.Number, m.Value); err != nil {
+				return errors.Wrapf(err, "could not edit %s/%s#%d", m.Owner, m.Repo, m.Number)
+			}
+		case PinIssueMutation:
+			// only a few issues can be pinned in each repository.
+			if err := c.client.pinIssue(ctx, m.Owner, m.Repo, m.Number); err != nil {
+				logrus.Warnf("could not pin %s/%s#%d: %v", m.Owner, m.Repo, m.Number, err)
+			}
+		default:
+			var args mutationArgs
+			if err := json.Unmarshal([]byte(m.Value), &args); err != nil {
+				logrus.Errorf("bad arguments of %s mutation %q", m.Kind, m.Value)
+				continue
+			}
+			if err := c.applyArgs(ctx, m, args); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// applyArgs performs a mutation taking more than one value.
+func (c *InstallationClient) applyArgs(ctx context.Context, m Mutation, args mutationArgs) error {
+	switch m.Kind {
+	case CreateLabelMutation:
+		err := c.client.createLabel(ctx, m.Owner, m.Repo, args.Label, args.Color)
+		return errors.Wrapf(err, "could not create label %s in %s/%s", args.Label, m.Owner, m.Repo)
+	case LabelColorMutation:
+		err := c.client.editLabelColor(ctx, m.Owner, m.Repo, args.Label, args.Color)
+		return errors.Wrapf(err, "could not color label %s in %s/%s", args.Label, m.Owner, m.Repo)
+	case CreateIssueMutation:
+		number, err := c.client.createIssue(ctx, m.Owner, m.Repo, args.Title, args.Body)
+		if err != nil || number == 0 {
+			return errors.Wrapf(err, "could not open issue in %s/%s", m.Owner, m.Repo)
+		}
+		if args.Pin {
+			if err := c.client.pinIssue(ctx, m.Owner, m.Repo, number); err != nil {
+				logrus.Warnf("could not pin %s/%s#%d: %v", m.Owner, m.Repo, number, err)
+			}
+		}
+		if args.Key == "" {
+			return nil
+		}
+		return errors.Wrapf(c.opts.store.Put(ctx, args.Key, number), "could not store %s", args.Key)
+	case CheckRunMutation:
+		err := c.client.createCheckRun(ctx, m.Owner, m.Repo, checkRun{args.SHA, args.Conclusion, args.Title, args.Summary})
+		return errors.Wrapf(err, "could not create check run in %s/%s", m.Owner, m.Repo)
+	case StatusMutation:
+		err := c.client.createStatus(ctx, m.Owner, m.Repo, commitStatus{args.SHA, args.State, args.Description})
+		return errors.Wrapf(err, "could not set status in %s/%s", m.Owner, m.Repo)
+	case ProjectDateMutation:
+		if args.Date == nil {
+			return nil
+		}
+		err := c.client.setProjectDate(ctx, m.Owner, m.Repo, m.Number, args.Field, *args.Date)
+		return errors.Wrapf(err, "could not set project date of %s/%s#%d", m.Owner, m.Repo, m.Number)
+	case AddToProjectMutation:
+		if args.Project == nil {
+			return nil
+		}
+		err := c.client.addToProject(ctx, m.Owner, m.Repo, m.Number, *args.Project)
+		return errors.Wrapf(err, "could not add %s/%s#%d to project", m.Owner, m.Repo, m.Number)
+	case MoveProjectMutation:
+		if args.Project == nil {
+			return nil
+		}
+		// like when scanning, a missing column shouldn't stop the rest.
+		if _, err := c.client.moveProjectItem(ctx, m.Owner, m.Repo, m.Number, *args.Project, args.Field, args.Column); err != nil {
+			logrus.Warnf("could not move %s/%s#%d to %s: %v", m.Owner, m.Repo, m.Number, args.Column, err)
+		}
+		return nil
+	default:
+		logrus.Errorf("unknown mutation kind %q", m.Kind)
+		return nil
+	}
+}
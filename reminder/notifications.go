@@ -0,0 +1,109 @@
+package reminder
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/notify"
+)
+
+// WithNotifier sets the notifier receiving events about reminders and deadlines.
+func WithNotifier(n notify.Notifier) Option {
+	return func(o *options) { o.notifier = n }
+}
+
+// WithDeadlineNotifier sets the notifier receiving the events of the deadlines
+// set, changed or cleared, which are sent to no other notifier since there's
+// one for every change of every issue.
+func WithDeadlineNotifier(n notify.Notifier) Option {
+	return func(o *options) { o.deadlineNotifier = n }
+}
+
+// event returns a new event of the given kind for the issue.
+func (i *issue) event(kind notify.Kind, message string) notify.Event {
+	return notify.Event{
+		Kind:    kind,
+		Owner:   i.repo.owner,
+		Repo:    i.repo.name,
+		Number:  i.number,
+		Title:   i.title,
+		URL:     i.url,
+		Message: message,
+		Time:    time.Now(),
+	}
+}
+
+// The notifiers receiving a PendingEvent other than the configured one.
+const (
+	deadlineNotifier = "deadline"
+	fallbackNotifier = "fallback"
+)
+
+// A PendingEvent is an event waiting to be delivered. Its notifiers are only
+// named, so it can be held along with the mutations of a changeset: Notifier
+// is either empty, for the configured notifier, "deadline" or "fallback", and
+// Policy is one plus the index of the path policy whose notifier receives the
+// event too, if any.
+type PendingEvent struct {
+	Event    notify.Event `json:"event"`
+	Notifier string       `json:"notifier,omitempty"`
+	Policy   int          `json:"policy,omitempty"`
+}
+
+// notify delivers the event to the configured notifier, if any, and the one
+// of the given policy.
+// Events produced while recording are delivered once the mutations are applied.
+func (c *InstallationClient) notify(ctx context.Context, e notify.Event, p *PathPolicy) {
+	pe := PendingEvent{Event: e}
+	for i := range c.opts.policies {
+		if p == &c.opts.policies[i] {
+			pe.Policy = i + 1
+		}
+	}
+	c.deliver(ctx, pe)
+}
+
+// notifier returns the notifiers receiving the event, or nil if there are none.
+func (c *InstallationClient) notifier(pe PendingEvent) notify.Notifier {
+	ns := []notify.Notifier{c.opts.notifier}
+	switch pe.Notifier {
+	case deadlineNotifier:
+		ns = []notify.Notifier{c.opts.deadlineNotifier}
+	case fallbackNotifier:
+		ns = []notify.Notifier{c.opts.fallback}
+	}
+	if pe.Policy > 0 && pe.Policy <= len(c.opts.policies) {
+		ns = append(ns, c.opts.policies[pe.Policy-1].Notifier)
+	}
+
+	var res notify.Multi
+	for _, n := range ns {
+		if n != nil {
+			res = append(res, n)
+		}
+	}
+	switch len(res) {
+	case 0:
+		return nil
+	case 1:
+		return res[0]
+	}
+	return res
+}
+
+func (c *InstallationClient) deliver(ctx context.Context, pe PendingEvent) {
+	n := c.notifier(pe)
+	if n == nil {
+		return
+	}
+	if rec, ok := c.client.(*recorder); ok {
+		rec.events = append(rec.events, pe)
+		return
+	}
+	e := pe.Event
+	if err := n.Notify(ctx, e); err != nil {
+		logrus.Errorf("could not notify %s event for %s/%s#%d: %v", e.Kind, e.Owner, e.Repo, e.Number, err)
+	}
+}
@@ -0,0 +1,110 @@
+package reminder
+
+import (
+	"time"
+
+	"github.com/src-d/github-reminder/notify"
+	"github.com/src-d/github-reminder/storage"
+)
+
+// An Option modifies the default behavior of the clients.
+type Option func(*options)
+
+type options struct {
+	baseURL string
+	store   storage.Store
+	anomaly *AnomalyPolicy
+	// login is the author of the comments of the bot when it isn't botLogin,
+	// like the user of the token of the clients created with NewTokenClient.
+	login string
+
+	approvalThreshold int
+	quiet             []QuietPeriod
+	absences          []Absence
+	scorer            Scorer
+	notifier          notify.Notifier
+	deadlineNotifier  notify.Notifier
+	policies          []PathPolicy
+	cadences          []Cadence
+	colors            *UrgencyColors
+	gradient          *UrgencyColors
+	backfill          int
+	minimize          bool
+	fallback          notify.Notifier
+	focus             string
+	grammar           Grammar
+	location          *time.Location
+	endOfDay          bool
+	dateFeedback      bool
+	labelPrefix       string
+	overdueLabel      string
+	required          *RequiredDeadline
+	escalation        *Escalation
+	followUps         []FollowUp
+	digestIssue       *DigestIssue
+	summary           *WeeklySummary
+	checks            bool
+	statuses          *time.Duration
+	templates         Templates
+	cleanupClosed     bool
+	manualLabels      bool
+	mentionAssignees  bool
+	layouts           []string
+	parsers           []DateParser
+	businessDays      bool
+	projectField      string
+	projectSync       string
+	milestones        bool
+	retry             Retry
+	rateLimits        *RateLimitPolicy
+	board             *ProjectBoard
+	columns           *BoardColumns
+	welcome           bool
+	language          string
+
+	// set only by the configuration file of a repository.
+	disabled         bool
+	deadlineSynonyms []string
+	reminderSynonyms []string
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.store == nil {
+		o.store = storage.NewMemory()
+	}
+	if o.scorer == nil {
+		o.scorer = DaysScorer
+	}
+	if o.parsers == nil {
+		o.parsers = DefaultDateParsers
+	}
+	if o.retry.Attempts == 0 {
+		o.retry = DefaultRetry
+	}
+	if o.rateLimits == nil {
+		o.rateLimits = &DefaultRateLimitPolicy
+	}
+	return o
+}
+
+// WithStore sets the store used to persist state across runs.
+// By default all state is kept in memory and lost once the client is discarded.
+func WithStore(s storage.Store) Option {
+	return func(o *options) { o.store = s }
+}
+
+// WithAnomalyDetection enables the detection of installation scans performing
+// an unusually large number of changes.
+func WithAnomalyDetection(p AnomalyPolicy) Option {
+	return func(o *options) { o.anomaly = &p }
+}
+
+// WithBaseURL sets the GitHub API URL, e.g. https://github.example.com/api/v3/ for
+// GitHub Enterprise Server. By default https://api.github.com/ is used.
+func WithBaseURL(url string) Option {
+	return func(o *options) { o.baseURL = url }
+}
@@ -0,0 +1,67 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/notify"
+)
+
+// WithOverdueLabel makes the bot apply the given label, like "overdue", to the
+// open issues whose deadline has passed, and remove it once the deadline is
+// moved or cleared, or the issue is closed.
+func WithOverdueLabel(name string) Option {
+	return func(o *options) { o.overdueLabel = strings.TrimSpace(name) }
+}
+
+// overdue reports whether the deadline has passed, at the exact time given or
+// at the end of the day for deadlines without one.
+func (c *InstallationClient) overdue(deadline time.Time) bool {
+	if deadline.IsZero() {
+		return false
+	}
+	if c.allDay(deadline) {
+		deadline = deadline.AddDate(0, 0, 1)
+	}
+	return time.Now().After(deadline)
+}
+
+// checkOverdue applies the overdue label to the issue if its deadline, zero
+// if it has none or it's closed, has passed, and removes it otherwise.
+func (c *InstallationClient) checkOverdue(ctx context.Context, issue *issue, deadline time.Time) error {
+	name := c.opts.overdueLabel
+	if name == "" {
+		return nil
+	}
+	owner, repo, number := issue.repo.owner, issue.repo.name, issue.number
+	// the label carried by the issue, which GitHub matches regardless of its case.
+	current := ""
+	for _, l := range issue.labels {
+		if strings.EqualFold(l, name) {
+			current = l
+		}
+	}
+
+	overdue := issue.state == "open" && c.overdue(deadline)
+	switch {
+	case overdue && current == "":
+		logrus.Debugf("applying %s to issue %s/%s#%d", name, owner, repo, number)
+		if err := c.client.addIssueLabel(ctx, owner, repo, number, name); err != nil {
+			return errors.Wrapf(err, "could not apply label %s", name)
+		}
+		e := issue.event(notify.Overdue, fmt.Sprintf("%s is overdue and now labeled %s", issue.title, name))
+		e.User, e.Label, e.Deadline = issue.author, name, deadline
+		c.notify(ctx, e, issue.policy)
+	case !overdue && current != "":
+		logrus.Debugf("removing %s from issue %s/%s#%d", current, owner, repo, number)
+		if err := c.client.removeIssueLabel(ctx, owner, repo, number, current); err != nil {
+			return errors.Wrapf(err, "could not remove label %s", current)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package reminder
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOverdueLabel(t *testing.T) {
+	var added, removed []string
+	is := &issue{repo: repository{"foo", "bar"}, number: 1, state: "open", body: "deadline: 2018-01-01"}
+	fc := &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) { return []string{"deadline < 5"}, nil },
+		_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+			cp := *is
+			return &cp, nil
+		},
+		_addIssueLabel: func(ctx context.Context, owner, repo string, number int, label string) error {
+			added = append(added, label)
+			return nil
+		},
+		_removeIssueLabel: func(ctx context.Context, owner, repo string, number int, label string) error {
+			removed = append(removed, label)
+			return nil
+		},
+	}
+	update := func(ic InstallationClient) {
+		added, removed = nil, nil
+		if err := ic.UpdateIssue(context.Background(), "foo", "bar", 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil), client: fc}
+	if update(ic); len(added) != 0 {
+		t.Errorf("expected no label without an overdue label; got %v", added)
+	}
+
+	ic.opts = newOptions([]Option{WithOverdueLabel("overdue")})
+	if update(ic); !reflect.DeepEqual(added, []string{"overdue"}) {
+		t.Errorf("expected the overdue label to be applied; got %v", added)
+	}
+
+	is.labels = []string{"Overdue"}
+	if update(ic); len(added) != 0 {
+		t.Errorf("expected the overdue label not to be applied twice; got %v", added)
+	}
+
+	is.body = "deadline: " + time.Now().AddDate(0, 0, 3).Format("2006-01-02")
+	if update(ic); !reflect.DeepEqual(removed, []string{"Overdue"}) || !reflect.DeepEqual(added, []string{"deadline < 5"}) {
+		t.Errorf("expected the overdue label to be replaced once the deadline is extended; got added %v and removed %v", added, removed)
+	}
+
+	is.body, is.state = "deadline: 2018-01-01", "closed"
+	if update(ic); !reflect.DeepEqual(removed, []string{"Overdue"}) {
+		t.Errorf("expected the overdue label to be removed from closed issues; got %v", removed)
+	}
+}
@@ -0,0 +1,77 @@
+package reminder
+
+import (
+	"context"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/notify"
+)
+
+// A PathPolicy customizes how pull requests changing some paths of a repository
+// are handled, e.g. to be stricter with changes in critical parts of a monorepo.
+type PathPolicy struct {
+	// Paths are either directory prefixes ending with a slash, like "payments/",
+	// or patterns matched with path.Match against the full path and the file
+	// name, like "*.sql".
+	Paths []string
+	// Margin brings deadlines closer by the given duration when choosing labels,
+	// so the urgency labels are applied earlier.
+	Margin time.Duration
+	// Scorer, if not nil, replaces the default scorer.
+	Scorer Scorer
+	// Notifier, if not nil, receives the events of the pull request in addition
+	// to the default notifier.
+	Notifier notify.Notifier
+}
+
+// WithPathPolicies sets the policies for pull requests changing specific paths.
+// The first policy matching any of the files changed by a pull request applies.
+func WithPathPolicies(ps ...PathPolicy) Option {
+	return func(o *options) { o.policies = append(o.policies, ps...) }
+}
+
+// matches checks whether the given file matches any of the policy paths.
+func (p PathPolicy) matches(file string) bool {
+	for _, pattern := range p.Paths {
+		if strings.HasSuffix(pattern, "/") {
+			if strings.HasPrefix(file, pattern) {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(pattern, file); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, path.Base(file)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// pathPolicy returns the policy applying to the issue, if any.
+// Only pull requests can match path policies.
+func (c *InstallationClient) pathPolicy(ctx context.Context, issue *issue) (*PathPolicy, error) {
+	if len(c.opts.policies) == 0 || !issue.pullRequest {
+		return nil, nil
+	}
+
+	files, err := c.client.files(ctx, issue.repo.owner, issue.repo.name, issue.number)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not list files changed by %s/%s#%d", issue.repo.owner, issue.repo.name, issue.number)
+	}
+	for i, p := range c.opts.policies {
+		for _, f := range files {
+			if p.matches(f) {
+				logrus.Debugf("%s/%s#%d matches policy for %v", issue.repo.owner, issue.repo.name, issue.number, p.Paths)
+				return &c.opts.policies[i], nil
+			}
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,59 @@
+package reminder
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPathPolicyMatches(t *testing.T) {
+	p := PathPolicy{Paths: []string{"payments/", "*.sql"}}
+	tests := map[string]bool{
+		"payments/api/handler.go": true,
+		"migrations/001.sql":      true,
+		"schema.sql":              true,
+		"docs/payments.md":        false,
+		"paymentsx/main.go":       false,
+	}
+	for file, expected := range tests {
+		if got := p.matches(file); got != expected {
+			t.Errorf("%s: expected match to be %v; got %v", file, expected, got)
+		}
+	}
+}
+
+func TestPathPolicyMargin(t *testing.T) {
+	var added []string
+	ic := InstallationClient{appID: 42, installationID: 43, client: &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) {
+			return []string{"deadline < 5", "deadline < 30"}, nil
+		},
+		_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+			return &issue{
+				repo:        repository{owner, repo},
+				number:      number,
+				state:       "open",
+				pullRequest: true,
+				body:        "deadline: " + time.Now().AddDate(0, 0, 10).Format("2006-01-02"),
+			}, nil
+		},
+		_files: func(ctx context.Context, owner, repo string, number int) ([]string, error) {
+			return []string{"README.md", "payments/charge.go"}, nil
+		},
+		_removeIssueLabel: func(ctx context.Context, owner, repo string, number int, label string) error { return nil },
+		_addIssueLabel: func(ctx context.Context, owner, repo string, number int, label string) error {
+			added = append(added, label)
+			return nil
+		},
+	}, opts: newOptions([]Option{WithPathPolicies(PathPolicy{
+		Paths:  []string{"payments/"},
+		Margin: 7 * 24 * time.Hour,
+	})})}
+
+	if err := ic.UpdateIssue(context.Background(), "foo", "bar", 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(added) != 1 || added[0] != "deadline < 5" {
+		t.Errorf("expected stricter label for payments change; got %v", added)
+	}
+}
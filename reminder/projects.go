@@ -0,0 +1,97 @@
+package reminder
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+// WithProjectDateField makes the bot read the deadline of the issues without
+// one written in them from the given date field of the GitHub projects they
+// belong to, like "Due date". If an issue is in several projects the earliest
+// date is used.
+func WithProjectDateField(name string) Option {
+	return func(o *options) { o.projectField = name }
+}
+
+// projectDeadline returns the deadline of the issue in its projects, zero if
+// it has none or no project field is configured.
+func (c *InstallationClient) projectDeadline(ctx context.Context, issue *issue) (time.Time, error) {
+	if c.opts.projectField == "" {
+		return time.Time{}, nil
+	}
+	date, err := c.client.projectDate(ctx, issue.repo.owner, issue.repo.name, issue.number, c.opts.projectField)
+	if err != nil || date.IsZero() {
+		return time.Time{}, err
+	}
+	// project dates have no timezone, they're read as those written in issues.
+	loc := c.opts.location
+	if loc == nil {
+		loc = time.UTC
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc).UTC(), nil
+}
+
+// WithProjectSync makes the bot copy the deadlines written in the issues to
+// the given date field of the GitHub projects they belong to, like "Due date",
+// updating it whenever they change. Deadlines cleared with "deadline: none"
+// clear it too, while issues without a deadline written in them leave it
+// alone, so it can still be managed from the projects.
+func WithProjectSync(field string) Option {
+	return func(o *options) { o.projectSync = field }
+}
+
+// syncProjectDate copies the deadline written in the issue, zero if it was
+// cleared, to its projects unless it was already copied to all of its items,
+// so the projects it's added to later get it too.
+func (c *InstallationClient) syncProjectDate(ctx context.Context, issue *issue, deadline time.Time) error {
+	if c.opts.projectSync == "" || !issue.written {
+		return nil
+	}
+	var date time.Time
+	if !deadline.IsZero() {
+		loc := c.opts.location
+		if loc == nil {
+			loc = time.UTC
+		}
+		d := deadline.In(loc)
+		date = time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+	}
+
+	owner, repo, number := issue.repo.owner, issue.repo.name, issue.number
+	items, err := c.client.projectItems(ctx, owner, repo, number)
+	if err != nil {
+		return err
+	}
+	var keys []string
+	for _, item := range items {
+		key := storage.Key("projectsync", c.appID, c.installationID, strings.ToLower(owner), strings.ToLower(repo), number, item)
+		var last time.Time
+		err := c.opts.store.Get(ctx, key, &last)
+		if err != nil && err != storage.ErrNotFound {
+			return errors.Wrap(err, "could not fetch project date")
+		}
+		if err == storage.ErrNotFound || !last.Equal(date) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	logrus.Debugf("setting %s of %s/%s#%d in its projects to %s", c.opts.projectSync, owner, repo, number, date.Format("2006-01-02"))
+	if err := c.client.setProjectDate(ctx, owner, repo, number, c.opts.projectSync, date); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := c.opts.store.Put(ctx, key, date); err != nil {
+			return errors.Wrap(err, "could not store project date")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package reminder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProjectDate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || r.URL.Path != "/api/graphql" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if req.Variables["field"] != "Due date" || req.Variables["number"] != 1.0 {
+			t.Errorf("unexpected variables %v", req.Variables)
+		}
+		fmt.Fprint(w, `{"data": {"repository": {"issueOrPullRequest": {"projectItems": {"nodes": [
+			{"fieldValueByName": {"date": "2018-08-10"}},
+			{"fieldValueByName": null},
+			{"fieldValueByName": {"date": "2018-08-01"}}
+		]}}}}}`)
+	}))
+	defer srv.Close()
+
+	base, _ := url.Parse(srv.URL + "/api/v3/")
+	c := &githubClient{client: newGitHubClient(srv.Client(), base)}
+	date, err := c.projectDate(context.Background(), "foo", "bar", 1, "Due date")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := time.Date(2018, 8, 1, 0, 0, 0, 0, time.UTC); !date.Equal(expected) {
+		t.Errorf("expected the earliest date %v; got %v", expected, date)
+	}
+}
+
+func TestProjectDeadline(t *testing.T) {
+	date := time.Date(2018, 8, 1, 0, 0, 0, 0, time.UTC)
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions([]Option{WithProjectDateField("Due date")}), client: &fakeClient{
+		_projectDate: func(ctx context.Context, owner, repo string, number int, field string) (time.Time, error) {
+			return date, nil
+		},
+	}}
+
+	got, err := ic.deadline(context.Background(), &issue{})
+	if err != nil || !got.Equal(date) {
+		t.Errorf("expected the project date %v; got %v (%v)", date, got, err)
+	}
+	got, err = ic.deadline(context.Background(), &issue{body: "deadline: 2018-08-20"})
+	if expected := date.AddDate(0, 0, 19); err != nil || !got.Equal(expected) {
+		t.Errorf("expected the deadline written in the issue %v; got %v (%v)", expected, got, err)
+	}
+}
+
+func TestSetProjectDate(t *testing.T) {
+	var mutations []map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if strings.HasPrefix(req.Query, "mutation") {
+			mutations = append(mutations, req.Variables)
+			fmt.Fprint(w, `{"data": {}}`)
+			return
+		}
+		fmt.Fprint(w, `{"data": {"repository": {"issueOrPullRequest": {"projectItems": {"nodes": [
+			{"id": "I1", "project": {"id": "P1", "field": {"id": "F1", "dataType": "DATE"}}, "fieldValueByName": null},
+			{"id": "I2", "project": {"id": "P2", "field": {"id": "F2", "dataType": "DATE"}}, "fieldValueByName": {"date": "2018-08-01"}},
+			{"id": "I3", "project": {"id": "P3", "field": {"id": "F3", "dataType": "TEXT"}}, "fieldValueByName": null},
+			{"id": "I4", "project": {"id": "P4", "field": null}, "fieldValueByName": null}
+		]}}}}}`)
+	}))
+	defer srv.Close()
+
+	base, _ := url.Parse(srv.URL + "/")
+	c := &githubClient{client: newGitHubClient(srv.Client(), base)}
+	if err := c.setProjectDate(context.Background(), "foo", "bar", 1, "Due date", time.Date(2018, 8, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mutations) != 1 || mutations[0]["item"] != "I1" || mutations[0]["field"] != "F1" || mutations[0]["date"] != "2018-08-01" {
+		t.Errorf("expected only the field of the first item to be set; got %v", mutations)
+	}
+
+	mutations = nil
+	if err := c.setProjectDate(context.Background(), "foo", "bar", 1, "Due date", time.Time{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mutations) != 1 || mutations[0]["item"] != "I2" || mutations[0]["date"] != nil {
+		t.Errorf("expected only the field of the second item to be cleared; got %v", mutations)
+	}
+}
+
+func TestProjectSync(t *testing.T) {
+	var dates []time.Time
+	items := []string{"I1"}
+	ic := InstallationClient{appID: 42, installationID: 43,
+		opts: newOptions([]Option{WithProjectSync("Due date"), WithTimezone(time.FixedZone("CEST", 2*3600))}),
+		client: &fakeClient{
+			_projectItems: func(ctx context.Context, owner, repo string, number int) ([]string, error) {
+				return items, nil
+			},
+			_setProjectDate: func(ctx context.Context, owner, repo string, number int, field string, date time.Time) error {
+				dates = append(dates, date)
+				return nil
+			},
+		}}
+
+	i := &issue{repo: repository{"foo", "bar"}, number: 1}
+	check := func(written bool, deadline time.Time, expected ...time.Time) {
+		i.written = written
+		if err := ic.syncProjectDate(context.Background(), i, deadline); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(dates) != len(expected) {
+			t.Fatalf("expected project dates %v; got %v", expected, dates)
+		}
+		for j := range expected {
+			if !dates[j].Equal(expected[j]) {
+				t.Errorf("expected project dates %v; got %v", expected, dates)
+			}
+		}
+	}
+
+	aug1 := time.Date(2018, 8, 1, 0, 0, 0, 0, time.UTC)
+	// deadlines taken from the projects aren't written back.
+	check(false, aug1)
+	// midnight in the timezone of the dates is still the same day.
+	check(true, time.Date(2018, 7, 31, 22, 0, 0, 0, time.UTC), aug1)
+	check(true, aug1.Add(2*time.Hour), aug1)
+	check(true, aug1.AddDate(0, 0, 3), aug1, aug1.AddDate(0, 0, 3))
+	check(true, time.Time{}, aug1, aug1.AddDate(0, 0, 3), time.Time{})
+	// the issues added to another project get their date there too.
+	items = append(items, "I2")
+	check(true, time.Time{}, aug1, aug1.AddDate(0, 0, 3), time.Time{}, time.Time{})
+	check(true, time.Time{}, aug1, aug1.AddDate(0, 0, 3), time.Time{}, time.Time{})
+}
@@ -0,0 +1,203 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+// A QuietPeriod is a range of time, e.g. a code freeze, during which the bot
+// doesn't post any comments. The comments suppressed during the period are
+// posted as a single catch-up comment per issue once it's over.
+type QuietPeriod struct {
+	Start time.Time
+	End   time.Time
+	// Labels suppresses label changes too, rather than updating them silently.
+	Labels bool
+}
+
+// ParseQuietPeriod parses a period written as two dates separated by a slash,
+// like 2018-12-20/2019-01-07. Both days are included in the period.
+func ParseQuietPeriod(s string) (QuietPeriod, error) {
+	start, end, err := parseDateRange(s)
+	if err != nil {
+		return QuietPeriod{}, errors.Wrap(err, "bad quiet period")
+	}
+	return QuietPeriod{Start: start, End: end}, nil
+}
+
+// parseDateRange parses two dates separated by a slash, like 2018-12-20/2019-01-07,
+// returning the beginning of the first day and the end of the last one.
+func parseDateRange(s string) (start, end time.Time, err error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 {
+		return start, end, errors.Errorf("expected start/end in %q", s)
+	}
+	start, err = time.Parse("2006-01-02", strings.TrimSpace(parts[0]))
+	if err != nil {
+		return start, end, errors.Wrapf(err, "bad start date in %q", s)
+	}
+	end, err = time.Parse("2006-01-02", strings.TrimSpace(parts[1]))
+	if err != nil {
+		return start, end, errors.Wrapf(err, "bad end date in %q", s)
+	}
+	if end.Before(start) {
+		return start, end, errors.Errorf("%q ends before it starts", s)
+	}
+	return start, end.AddDate(0, 0, 1), nil
+}
+
+// WithQuietPeriods configures periods of time during which no comments are posted.
+func WithQuietPeriods(ps ...QuietPeriod) Option {
+	return func(o *options) { o.quiet = append(o.quiet, ps...) }
+}
+
+// activeQuietPeriod returns the quiet period containing t, if any.
+func (o options) activeQuietPeriod(t time.Time) *QuietPeriod {
+	for i, p := range o.quiet {
+		if !t.Before(p.Start) && t.Before(p.End) {
+			return &o.quiet[i]
+		}
+	}
+	return nil
+}
+
+// quietClient suppresses comments and, optionally, labels during quiet periods.
+type quietClient struct {
+	client
+	periods []QuietPeriod
+	store   storage.Store
+	prefix  string
+}
+
+func (c *quietClient) active() *QuietPeriod {
+	return options{quiet: c.periods}.activeQuietPeriod(time.Now())
+}
+
+func (c *quietClient) createIssueComment(ctx context.Context, owner, repo string, number int, body string) error {
+	if p := c.active(); p != nil {
+		logrus.Infof("quiet period, holding comment on %s/%s#%d", owner, repo, number)
+		return c.hold(ctx, storage.Key(c.prefix, owner, repo, number), *p, body)
+	}
+	return c.client.createIssueComment(ctx, owner, repo, number, body)
+}
+
+func (c *quietClient) removeIssueLabel(ctx context.Context, owner, repo string, number int, label string) error {
+	if p := c.active(); p != nil && p.Labels {
+		return nil
+	}
+	return c.client.removeIssueLabel(ctx, owner, repo, number, label)
+}
+
+func (c *quietClient) addIssueLabel(ctx context.Context, owner, repo string, number int, label string) error {
+	if p := c.active(); p != nil && p.Labels {
+		return nil
+	}
+	return c.client.addIssueLabel(ctx, owner, repo, number, label)
+}
+
+func (c *quietClient) replaceIssueLabels(ctx context.Context, owner, repo string, number int, labels []string) error {
+	if p := c.active(); p != nil && p.Labels {
+		return nil
+	}
+	return c.client.replaceIssueLabels(ctx, owner, repo, number, labels)
+}
+
+func (c *quietClient) editLabelColor(ctx context.Context, owner, repo, label, color string) error {
+	if p := c.active(); p != nil && p.Labels {
+		return nil
+	}
+	return c.client.editLabelColor(ctx, owner, repo, label, color)
+}
+
+func (c *quietClient) createLabel(ctx context.Context, owner, repo, label, color string) error {
+	if p := c.active(); p != nil && p.Labels {
+		return nil
+	}
+	return c.client.createLabel(ctx, owner, repo, label, color)
+}
+
+// errQuiet is returned for the writes skipped during a quiet period that are
+// done later instead of dropped.
+var errQuiet = errors.New("skipped during a quiet period")
+
+// minimizeComment keeps the previous reminders visible while the new ones are
+// held, failing so they're minimized once the period is over.
+func (c *quietClient) minimizeComment(ctx context.Context, owner, repo string, id int64) error {
+	if c.active() != nil {
+		return errQuiet
+	}
+	return c.client.minimizeComment(ctx, owner, repo, id)
+}
+
+// heldComments are the comments suppressed for an issue during a quiet period.
+type heldComments struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Bodies []string  `json:"bodies"`
+}
+
+func (c *quietClient) hold(ctx context.Context, key string, p QuietPeriod, body string) error {
+	var held heldComments
+	if err := c.store.Get(ctx, key, &held); err != nil && err != storage.ErrNotFound {
+		return errors.Wrap(err, "could not fetch held comments")
+	}
+	for _, b := range held.Bodies {
+		if b == body {
+			return nil
+		}
+	}
+	held.Start, held.End = p.Start, p.End
+	held.Bodies = append(held.Bodies, body)
+	return errors.Wrap(c.store.Put(ctx, key, held), "could not hold comment")
+}
+
+// catchUp posts the comments held during quiet periods that are already over.
+func (c *InstallationClient) catchUp(ctx context.Context) error {
+	if len(c.opts.quiet) == 0 || c.opts.activeQuietPeriod(time.Now()) != nil {
+		return nil
+	}
+
+	prefix := storage.Key("held", c.appID, c.installationID) + "/"
+	keys, err := c.opts.store.List(ctx, prefix)
+	if err != nil {
+		return errors.Wrap(err, "could not list held comments")
+	}
+	for _, key := range keys {
+		var owner, repo string
+		var number int
+		parts := strings.Split(strings.TrimPrefix(key, prefix), "/")
+		if len(parts) != 3 {
+			logrus.Errorf("bad held comments key %s", key)
+			continue
+		}
+		owner, repo = parts[0], parts[1]
+		if _, err := fmt.Sscan(parts[2], &number); err != nil {
+			logrus.Errorf("bad held comments key %s", key)
+			continue
+		}
+
+		var held heldComments
+		if err := c.opts.store.Get(ctx, key, &held); err != nil {
+			return errors.Wrapf(err, "could not fetch held comments %s", key)
+		}
+		text := fmt.Sprintf("The following reminders were held during the quiet period from %s to %s:\n",
+			held.Start.Format("January 2"), held.End.AddDate(0, 0, -1).Format("January 2"))
+		for _, body := range held.Bodies {
+			text += "\n> " + strings.Replace(body, "\n", "\n> ", -1) + "\n"
+		}
+		if err := c.client.createIssueComment(ctx, owner, repo, number, text); err != nil {
+			return errors.Wrapf(err, "could not post catch-up comment on %s/%s#%d", owner, repo, number)
+		}
+		if err := c.opts.store.Delete(ctx, key); err != nil {
+			return errors.Wrapf(err, "could not delete held comments %s", key)
+		}
+	}
+	return nil
+}
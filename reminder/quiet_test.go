@@ -0,0 +1,64 @@
+package reminder
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseQuietPeriod(t *testing.T) {
+	p, err := ParseQuietPeriod("2018-12-20/2019-01-07")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !p.Start.Equal(time.Date(2018, 12, 20, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected start %v", p.Start)
+	}
+	if !p.End.Equal(time.Date(2019, 1, 8, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected end %v", p.End)
+	}
+
+	for _, s := range []string{"2018-12-20", "2019-01-07/2018-12-20", "foo/bar"} {
+		if _, err := ParseQuietPeriod(s); err == nil {
+			t.Errorf("expected error parsing %q", s)
+		}
+	}
+}
+
+func TestQuietPeriodCatchUp(t *testing.T) {
+	var comments []string
+	fake := &fakeClient{
+		_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error {
+			comments = append(comments, body)
+			return nil
+		},
+	}
+
+	now := time.Now()
+	period := QuietPeriod{Start: now.Add(-time.Hour), End: now.Add(time.Hour)}
+	o := newOptions([]Option{WithQuietPeriods(period)})
+	ic := newInstallationClient(42, 43, fake, o)
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if err := ic.client.createIssueComment(ctx, "foo", "bar", 1, "hi @francesc, it's reminder day!"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(comments) != 0 {
+		t.Fatalf("expected comments to be held; got %v", comments)
+	}
+
+	// the period is over.
+	ic.opts.quiet[0].End = now.Add(-time.Minute)
+	if err := ic.catchUp(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(comments) != 1 {
+		t.Fatalf("expected a single catch-up comment; got %v", comments)
+	}
+	if strings.Count(comments[0], "reminder day") != 1 {
+		t.Errorf("expected the held comment once in the catch-up comment; got %s", comments[0])
+	}
+}
@@ -0,0 +1,132 @@
+package reminder
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/github"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// A RateLimitPolicy is how the calls to the GitHub API wait when they hit its
+// rate limits: the primary ones, until they reset, and the secondary ones, for
+// as long as GitHub asks.
+type RateLimitPolicy struct {
+	// MaxWait is the longest a call waits for a rate limit. Calls that would
+	// wait longer fail right away, as do those that would wait beyond the
+	// deadline of their context. Zero never waits.
+	MaxWait time.Duration
+}
+
+// DefaultRateLimitPolicy waits up to a minute, as long as the secondary rate
+// limits usually take, so an installation out of its quota fails right away
+// instead of holding up the rest until it resets.
+var DefaultRateLimitPolicy = RateLimitPolicy{MaxWait: time.Minute}
+
+// WithRateLimitPolicy sets how the calls wait for the rate limits of GitHub.
+// DefaultRateLimitPolicy is used by default.
+func WithRateLimitPolicy(p RateLimitPolicy) Option {
+	return func(o *options) { o.rateLimits = &p }
+}
+
+// secondaryRateLimitWait is how long the calls are paused by a secondary rate
+// limit that doesn't say for how long, as recommended by GitHub.
+const secondaryRateLimitWait = time.Minute
+
+// maxPauses is the number of times a call is paused by rate limits before
+// giving up on it.
+const maxPauses = 5
+
+// rateLimit checks whether err is GitHub refusing a request because of its
+// rate limits, returning how long to wait before the next one: until the
+// primary ones reset, or as long as asked by the secondary, formerly abuse,
+// ones. The client library only knows of the primary ones of github.com and
+// the former secondary ones.
+func rateLimit(err error) (time.Duration, bool) {
+	switch e := errors.Cause(err).(type) {
+	case *github.RateLimitError:
+		return untilReset(e.Rate.Reset.Time), true
+	case *github.AbuseRateLimitError:
+		if e.RetryAfter != nil {
+			return *e.RetryAfter, true
+		}
+		return secondaryRateLimitWait, true
+	case *github.ErrorResponse:
+		if e.Response == nil || (e.Response.StatusCode != http.StatusForbidden && e.Response.StatusCode != http.StatusTooManyRequests) {
+			return 0, false
+		}
+		if d, ok := retryAfter(e.Response.Header); ok {
+			return d, true
+		}
+		h := e.Response.Header
+		if reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil && h.Get("X-RateLimit-Remaining") == "0" {
+			return untilReset(time.Unix(reset, 0)), true
+		}
+		if strings.Contains(strings.ToLower(e.Message), "secondary rate limit") || strings.Contains(e.DocumentationURL, "secondary-rate-limits") {
+			return secondaryRateLimitWait, true
+		}
+	}
+	return 0, false
+}
+
+// untilReset returns the wait until a rate limit resets at reset, with a
+// second of margin for the clock skew.
+func untilReset(reset time.Time) time.Duration {
+	if d := time.Until(reset) + time.Second; d > 0 {
+		return d
+	}
+	return 0
+}
+
+// retryAfter returns the wait in the Retry-After header, if any, either in
+// seconds or until a date.
+func retryAfter(h http.Header) (time.Duration, bool) {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t), true
+	}
+	return 0, false
+}
+
+// pause pauses every call of the client for d, unless they're paused longer.
+func (c *retryClient) pause(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if resume := time.Now().Add(d); resume.After(c.resume) {
+		c.resume = resume
+	}
+}
+
+// waitResume waits until the calls of the client are resumed, failing right
+// away if they're paused longer than the policy allows or beyond the deadline
+// of ctx.
+func (c *retryClient) waitResume(ctx context.Context) error {
+	c.mu.Lock()
+	resume := c.resume
+	c.mu.Unlock()
+
+	d := time.Until(resume)
+	if d <= 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); d > c.limits.MaxWait || (ok && deadline.Before(resume)) {
+		return errors.Errorf("calls to GitHub are paused by a rate limit until %s", resume.Format(time.RFC3339))
+	}
+	logrus.Debugf("waiting %v for calls to GitHub to resume", d)
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,220 @@
+package reminder
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/github"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+// maxObserved is the number of intended mutations kept for a read-only installation.
+const maxObserved = 100
+
+// A ReadOnlyInstallation is an installation that lost its write permissions.
+// Its mutations are recorded instead of applied until a write succeeds again.
+type ReadOnlyInstallation struct {
+	AppID          int        `json:"app_id"`
+	InstallationID int        `json:"installation_id"`
+	Since          time.Time  `json:"since"`
+	Mutations      []Mutation `json:"mutations"`
+}
+
+func readOnlyKey(appID, installationID int) string {
+	return storage.Key("readonly", appID, installationID)
+}
+
+// ReadOnlyInstallations lists the installations currently in read-only mode in the given store.
+func ReadOnlyInstallations(ctx context.Context, store storage.Store) ([]ReadOnlyInstallation, error) {
+	keys, err := store.List(ctx, "readonly/")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list read-only installations")
+	}
+
+	var res []ReadOnlyInstallation
+	for _, key := range keys {
+		var ro ReadOnlyInstallation
+		if err := store.Get(ctx, key, &ro); err != nil {
+			return nil, errors.Wrapf(err, "could not fetch read-only installation %s", key)
+		}
+		res = append(res, ro)
+	}
+	return res, nil
+}
+
+// isForbidden checks whether err is GitHub refusing a request for lack of permissions.
+// Rate limits are also reported with 403, but with different error types or
+// headers.
+func isForbidden(err error) bool {
+	if _, ok := rateLimit(err); ok {
+		return false
+	}
+	res, ok := errors.Cause(err).(*github.ErrorResponse)
+	return ok && res.Response != nil && res.Response.StatusCode == http.StatusForbidden
+}
+
+// readOnlyClient switches the installation to read-only mode when writes are
+// forbidden, recording the mutations instead of failing. Every client tries a
+// single write while in read-only mode, leaving it if the write succeeds.
+type readOnlyClient struct {
+	client
+	store          storage.Store
+	appID          int
+	installationID int
+	probed         bool
+}
+
+// write performs the mutation with do, unless the installation is read-only.
+func (c *readOnlyClient) write(ctx context.Context, m Mutation, do func() error) error {
+	var ro ReadOnlyInstallation
+	key := readOnlyKey(c.appID, c.installationID)
+	err := c.store.Get(ctx, key, &ro)
+	if err != nil && err != storage.ErrNotFound {
+		return errors.Wrap(err, "could not check read-only mode")
+	}
+	readOnly := err == nil
+
+	if readOnly && c.probed {
+		return c.observe(ctx, ro, m)
+	}
+	c.probed = true
+
+	err = do()
+	if err == nil && readOnly {
+		logrus.Infof("write permissions are back for installation %d/%d", c.appID, c.installationID)
+		return errors.Wrap(c.store.Delete(ctx, key), "could not leave read-only mode")
+	}
+	if !isForbidden(err) {
+		return err
+	}
+
+	if !readOnly {
+		logrus.Warnf("writes are forbidden for installation %d/%d, switching to read-only mode", c.appID, c.installationID)
+		ro = ReadOnlyInstallation{AppID: c.appID, InstallationID: c.installationID, Since: time.Now()}
+	}
+	return c.observe(ctx, ro, m)
+}
+
+// observe records the intended mutation, keeping only the latest ones.
+func (c *readOnlyClient) observe(ctx context.Context, ro ReadOnlyInstallation, m Mutation) error {
+	ro.Mutations = append(ro.Mutations, m)
+	if len(ro.Mutations) > maxObserved {
+		ro.Mutations = ro.Mutations[len(ro.Mutations)-maxObserved:]
+	}
+	err := c.store.Put(ctx, readOnlyKey(c.appID, c.installationID), ro)
+	return errors.Wrap(err, "could not record read-only mutation")
+}
+
+func (c *readOnlyClient) createIssueComment(ctx context.Context, owner, repo string, number int, body string) error {
+	return c.write(ctx, Mutation{CommentMutation, owner, repo, number, body}, func() error {
+		return c.client.createIssueComment(ctx, owner, repo, number, body)
+	})
+}
+
+// createIssue returns 0 if the issue couldn't be opened in read-only mode.
+func (c *readOnlyClient) createIssue(ctx context.Context, owner, repo, title, body string) (int, error) {
+	var number int
+	m := argsMutation(CreateIssueMutation, owner, repo, 0, mutationArgs{Title: title, Body: body})
+	err := c.write(ctx, m, func() error {
+		var err error
+		number, err = c.client.createIssue(ctx, owner, repo, title, body)
+		return err
+	})
+	return number, err
+}
+
+func (c *readOnlyClient) editIssueBody(ctx context.Context, owner, repo string, number int, body string) error {
+	return c.write(ctx, Mutation{EditIssueMutation, owner, repo, number, body}, func() error {
+		return c.client.editIssueBody(ctx, owner, repo, number, body)
+	})
+}
+
+func (c *readOnlyClient) pinIssue(ctx context.Context, owner, repo string, number int) error {
+	return c.write(ctx, Mutation{PinIssueMutation, owner, repo, number, ""}, func() error {
+		return c.client.pinIssue(ctx, owner, repo, number)
+	})
+}
+
+func (c *readOnlyClient) createCheckRun(ctx context.Context, owner, repo string, run checkRun) error {
+	m := checkRunMutation(owner, repo, run)
+	return c.write(ctx, m, func() error {
+		return c.client.createCheckRun(ctx, owner, repo, run)
+	})
+}
+
+func (c *readOnlyClient) createStatus(ctx context.Context, owner, repo string, status commitStatus) error {
+	m := statusMutation(owner, repo, status)
+	return c.write(ctx, m, func() error {
+		return c.client.createStatus(ctx, owner, repo, status)
+	})
+}
+
+func (c *readOnlyClient) setProjectDate(ctx context.Context, owner, repo string, number int, field string, date time.Time) error {
+	m := projectDateMutation(owner, repo, number, field, date)
+	return c.write(ctx, m, func() error {
+		return c.client.setProjectDate(ctx, owner, repo, number, field, date)
+	})
+}
+
+func (c *readOnlyClient) addToProject(ctx context.Context, owner, repo string, number int, p Project) error {
+	m := argsMutation(AddToProjectMutation, owner, repo, number, mutationArgs{Project: &p})
+	return c.write(ctx, m, func() error {
+		return c.client.addToProject(ctx, owner, repo, number, p)
+	})
+}
+
+func (c *readOnlyClient) moveProjectItem(ctx context.Context, owner, repo string, number int, p Project, field, column string) (bool, error) {
+	var found bool
+	m := moveProjectMutation(owner, repo, number, p, field, column)
+	err := c.write(ctx, m, func() error {
+		var err error
+		found, err = c.client.moveProjectItem(ctx, owner, repo, number, p, field, column)
+		return err
+	})
+	return found, err
+}
+
+func (c *readOnlyClient) removeIssueLabel(ctx context.Context, owner, repo string, number int, label string) error {
+	return c.write(ctx, Mutation{RemoveLabelMutation, owner, repo, number, label}, func() error {
+		return c.client.removeIssueLabel(ctx, owner, repo, number, label)
+	})
+}
+
+func (c *readOnlyClient) addIssueLabel(ctx context.Context, owner, repo string, number int, label string) error {
+	return c.write(ctx, Mutation{AddLabelMutation, owner, repo, number, label}, func() error {
+		return c.client.addIssueLabel(ctx, owner, repo, number, label)
+	})
+}
+
+func (c *readOnlyClient) replaceIssueLabels(ctx context.Context, owner, repo string, number int, labels []string) error {
+	m := Mutation{ReplaceLabelsMutation, owner, repo, number, strings.Join(labels, "\n")}
+	return c.write(ctx, m, func() error {
+		return c.client.replaceIssueLabels(ctx, owner, repo, number, labels)
+	})
+}
+
+func (c *readOnlyClient) editLabelColor(ctx context.Context, owner, repo, label, color string) error {
+	m := labelMutation(LabelColorMutation, owner, repo, label, color)
+	return c.write(ctx, m, func() error {
+		return c.client.editLabelColor(ctx, owner, repo, label, color)
+	})
+}
+
+func (c *readOnlyClient) createLabel(ctx context.Context, owner, repo, label, color string) error {
+	m := labelMutation(CreateLabelMutation, owner, repo, label, color)
+	return c.write(ctx, m, func() error {
+		return c.client.createLabel(ctx, owner, repo, label, color)
+	})
+}
+
+func (c *readOnlyClient) minimizeComment(ctx context.Context, owner, repo string, id int64) error {
+	return c.write(ctx, Mutation{MinimizeMutation, owner, repo, 0, strconv.FormatInt(id, 10)}, func() error {
+		return c.client.minimizeComment(ctx, owner, repo, id)
+	})
+}
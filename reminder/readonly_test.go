@@ -0,0 +1,70 @@
+package reminder
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/github"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+func TestReadOnlyMode(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemory()
+	forbidden := true
+	var added []string
+	fc := &fakeClient{
+		_addIssueLabel: func(ctx context.Context, owner, repo string, number int, label string) error {
+			if forbidden {
+				return &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusForbidden}}
+			}
+			added = append(added, label)
+			return nil
+		},
+	}
+	newClient := func() *InstallationClient {
+		return newInstallationClient(42, 43, fc, newOptions([]Option{WithStore(store)}))
+	}
+
+	c := newClient()
+	for _, l := range []string{"a", "b"} {
+		if err := c.client.addIssueLabel(ctx, "foo", "bar", 1, l); err != nil {
+			t.Fatalf("expected forbidden writes to be observed; got %v", err)
+		}
+	}
+	ros, err := ReadOnlyInstallations(ctx, store)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ros) != 1 || len(ros[0].Mutations) != 2 || ros[0].Mutations[1].Value != "b" {
+		t.Fatalf("expected installation to be read-only with 2 mutations; got %+v", ros)
+	}
+
+	fc._createLabel = func(ctx context.Context, owner, repo, label, color string) error {
+		return &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusForbidden}}
+	}
+	if err := c.client.createLabel(ctx, "foo", "bar", "deadline < 1", "ff0000"); err != nil {
+		t.Fatalf("expected forbidden writes to be observed; got %v", err)
+	}
+	ros, _ = ReadOnlyInstallations(ctx, store)
+	var args mutationArgs
+	m := ros[0].Mutations[2]
+	if json.Unmarshal([]byte(m.Value), &args); m.Kind != CreateLabelMutation || args.Label != "deadline < 1" || args.Color != "ff0000" {
+		t.Errorf("expected the label creation to be observed; got %+v", m)
+	}
+
+	forbidden = false
+	c = newClient()
+	if err := c.client.addIssueLabel(ctx, "foo", "bar", 1, "c"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ros, _ := ReadOnlyInstallations(ctx, store); len(ros) != 0 {
+		t.Errorf("expected read-only mode to be left; got %+v", ros)
+	}
+	if len(added) != 1 || added[0] != "c" {
+		t.Errorf("expected only label c to be added; got %v", added)
+	}
+}
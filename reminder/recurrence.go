@@ -0,0 +1,158 @@
+package reminder
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A recurrence is a reminder repeated periodically, like "every monday" or
+// "every 2 weeks". Occurrences are at the start of the day, in the timezone of
+// the bot, from the first one after the day the reminder was written.
+type recurrence struct {
+	first time.Time
+	// either days or months between occurrences is set.
+	days   int
+	months int
+}
+
+// parseRecurrence parses a recurrence written at the given time.
+func parseRecurrence(s string, created time.Time, d dates) (recurrence, bool) {
+	s = strings.Join(strings.Fields(strings.Trim(strings.TrimSpace(s), ":")), " ")
+	if !strings.HasPrefix(s, "every ") || created.IsZero() {
+		return recurrence{}, false
+	}
+	fields := strings.Fields(strings.TrimPrefix(s, "every "))
+
+	n := 1
+	if len(fields) == 2 {
+		var err error
+		if fields[0] == "other" {
+			n = 2
+		} else if n, err = strconv.Atoi(fields[0]); err != nil || n <= 0 {
+			return recurrence{}, false
+		}
+		fields = fields[1:]
+	}
+	if len(fields) != 1 {
+		return recurrence{}, false
+	}
+
+	loc := d.loc
+	if loc == nil {
+		loc = time.UTC
+	}
+	created = created.In(loc)
+	day := time.Date(created.Year(), created.Month(), created.Day(), 0, 0, 0, 0, loc)
+
+	var r recurrence
+	switch strings.TrimSuffix(fields[0], "s") {
+	case "day":
+		r.days = n
+	case "week":
+		r.days = 7 * n
+	case "month":
+		r.months = n
+	default:
+		wd, ok := weekdays[fields[0]]
+		if !ok || n != 1 {
+			return recurrence{}, false
+		}
+		days := (int(wd) - int(day.Weekday()) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+		return recurrence{first: day.AddDate(0, 0, days), days: 7}, true
+	}
+	r.first = day.AddDate(0, r.months, r.days)
+	return r, true
+}
+
+// occurrence returns the k-th occurrence after the first one.
+func (r recurrence) occurrence(k int) time.Time {
+	return r.first.AddDate(0, k*r.months, k*r.days)
+}
+
+// occurrences returns the last occurrence up to now, zero if there's none
+// yet, and the next one.
+func (r recurrence) occurrences(now time.Time) (last, next time.Time) {
+	if now.Before(r.first) {
+		return time.Time{}, r.first
+	}
+
+	k := 0
+	if r.days > 0 {
+		// estimate it in days, correcting it for daylight saving changes.
+		k = int(now.Sub(r.first).Hours()/24) / r.days
+	}
+	for !r.occurrence(k + 1).After(now) {
+		k++
+	}
+	for k > 0 && r.occurrence(k).After(now) {
+		k--
+	}
+	return r.occurrence(k), r.occurrence(k + 1)
+}
+
+// A dueReminder is a time when the given users, or the author of the reminder
+// if there are none, must be reminded.
+type dueReminder struct {
+	at    time.Time
+	users []string
+}
+
+// findReminders returns the reminders in a body written at the given time.
+// For recurring reminders those are their last occurrence up to now, if any,
+// and the next one. Those relative to the deadline, like "3 days before
+// deadline", are ignored if it's zero.
+func (c *InstallationClient) findReminders(issue *issue, body string, created, now, deadline time.Time) []dueReminder {
+	d := c.dates(issue)
+	var reminders []dueReminder
+	for _, v := range issue.grammar.findValues("reminder", issue.synonyms.replace(body)) {
+		users, v := parseAddressees(v)
+		add := func(t time.Time) { reminders = append(reminders, dueReminder{t, users}) }
+		if before, ok := parseBefore(v); ok {
+			if !deadline.IsZero() {
+				add(before(deadline))
+			}
+		} else if r, ok := parseRecurrence(v, created, d); ok {
+			last, next := r.occurrences(now)
+			if !last.IsZero() {
+				add(last.UTC())
+			}
+			add(next.UTC())
+		} else if t := d.parse(v, created); !t.IsZero() {
+			add(t)
+		}
+	}
+	return reminders
+}
+
+// parseAddressees parses the users a reminder is for, as in "for @alice:" or
+// "for @alice and @bob:", returning them and the rest of the value. There are
+// no users if the value isn't addressed to anyone.
+func parseAddressees(v string) ([]string, string) {
+	s := strings.TrimSpace(v)
+	if !strings.HasPrefix(s, "for ") {
+		return nil, v
+	}
+	i := strings.Index(s, ":")
+	if i < 0 {
+		return nil, v
+	}
+
+	var users []string
+	for _, f := range strings.FieldsFunc(s[len("for "):i], func(r rune) bool { return r == ',' || r == ' ' }) {
+		if f == "and" {
+			continue
+		}
+		if !strings.HasPrefix(f, "@") || len(f) == 1 {
+			return nil, v
+		}
+		users = append(users, f[1:])
+	}
+	if len(users) == 0 {
+		return nil, v
+	}
+	return users, s[i:]
+}
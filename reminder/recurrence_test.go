@@ -0,0 +1,81 @@
+package reminder
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRecurrences(t *testing.T) {
+	// a Wednesday.
+	created := time.Date(2018, 8, 1, 15, 30, 0, 0, time.UTC)
+	now := time.Date(2018, 8, 20, 10, 0, 0, 0, time.UTC)
+	day := func(month time.Month, d int) time.Time { return time.Date(2018, month, d, 0, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		s          string
+		last, next time.Time
+	}{
+		{"every monday", day(8, 20), day(8, 27)},
+		{"every friday", day(8, 17), day(8, 24)},
+		{"every day", day(8, 20), day(8, 21)},
+		{"every 2 weeks", day(8, 15), day(8, 29)},
+		{"every other week", day(8, 15), day(8, 29)},
+		{"every month", time.Time{}, day(9, 1)},
+		{": every 3 days", day(8, 19), day(8, 22)},
+	}
+	for _, tt := range tests {
+		r, ok := parseRecurrence(tt.s, created, dates{})
+		if !ok {
+			t.Errorf("%q: expected a recurrence", tt.s)
+			continue
+		}
+		last, next := r.occurrences(now)
+		if !last.Equal(tt.last) || !next.Equal(tt.next) {
+			t.Errorf("%q: expected %v and %v; got %v and %v", tt.s, tt.last, tt.next, last, next)
+		}
+	}
+
+	for _, s := range []string{"every", "every 2 mondays", "every 0 days", "every now and then", "monday"} {
+		if _, ok := parseRecurrence(s, created, dates{}); ok {
+			t.Errorf("%q: expected no recurrence", s)
+		}
+	}
+}
+
+func TestRecurringReminder(t *testing.T) {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	var comments []comment
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil), client: &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) { return nil, nil },
+		_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+			return &issue{
+				repo: repository{owner, repo}, number: number, state: "open", author: "francesc",
+				body:     "reminder: every day",
+				created:  today.AddDate(0, 0, -3),
+				comments: comments,
+			}, nil
+		},
+		_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error {
+			comments = append(comments, comment{author: botLogin, body: body, created: time.Now()})
+			return nil
+		},
+	}}
+
+	for i := 0; i < 2; i++ {
+		if err := ic.UpdateIssue(context.Background(), "foo", "bar", 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(comments) != 1 {
+		t.Fatalf("expected today's occurrence to be reminded once; got %d comments", len(comments))
+	}
+
+	var s ScheduledReminder
+	if err := ic.opts.store.Get(context.Background(), scheduleKey(42, 43, "foo", "bar", 1), &s); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := today.AddDate(0, 0, 1); !s.At.Equal(expected) {
+		t.Errorf("expected next occurrence to be scheduled at %v; got %v", expected, s.At)
+	}
+}
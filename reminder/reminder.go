@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 	"sort"
 	"strconv"
 	"strings"
@@ -14,33 +15,92 @@ import (
 	"github.com/google/go-github/github"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/notify"
+	"github.com/src-d/github-reminder/storage"
 )
 
+// botLogin is the login of the app's bot user, author of all of its comments.
+const botLogin = "deadline-reminder[bot]"
+
+// login returns the login of the author of the comments of the bot.
+func (c *InstallationClient) login() string {
+	if c.opts.login != "" {
+		return c.opts.login
+	}
+	return botLogin
+}
+
 // newClient can be replaced by test cases.
-var newClient = func(client *http.Client) client {
-	return &githubClient{github.NewClient(client)}
+var newClient = func(client *http.Client, baseURL *url.URL) client {
+	return &githubClient{client: newGitHubClient(client, baseURL)}
+}
+
+func newGitHubClient(client *http.Client, baseURL *url.URL) *github.Client {
+	c := github.NewClient(client)
+	if baseURL != nil {
+		c.BaseURL = baseURL
+		c.UploadURL = uploadURL(baseURL)
+	}
+	return c
+}
+
+// uploadURL returns the URL of the uploads of the API at baseURL, which is
+// /api/uploads/ next to the /api/v3/ of GitHub Enterprise Server.
+func uploadURL(baseURL *url.URL) *url.URL {
+	u := *baseURL
+	if strings.HasSuffix(u.Path, "/api/v3/") {
+		u.Path = strings.TrimSuffix(u.Path, "v3/") + "uploads/"
+	}
+	return &u
+}
+
+// apiURL parses the base URL in the options, returning nil if none was given.
+func (o options) apiURL() (*url.URL, error) {
+	if o.baseURL == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(o.baseURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not parse base url %s", o.baseURL)
+	}
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	return u, nil
 }
 
 // An ApplicationClient provides the methods that do not depend on an installation.
 type ApplicationClient struct {
 	appID  int
 	client client
+	opts   options
 }
 
 // NewApplicationClient returns a new ApplicationClient.
 // If the given transport is nil, http.DefaultTransport will be used instead.
-func NewApplicationClient(appID int, key []byte, transport http.RoundTripper) (*ApplicationClient, error) {
+func NewApplicationClient(appID int, key []byte, transport http.RoundTripper, opts ...Option) (*ApplicationClient, error) {
 	if transport == nil {
 		transport = http.DefaultTransport
 	}
 
+	o := newOptions(opts)
+	baseURL, err := o.apiURL()
+	if err != nil {
+		return nil, err
+	}
+
 	itr, err := ghinstallation.NewAppsTransport(transport, appID, key)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not create authenticated application client")
 	}
+	if baseURL != nil {
+		itr.BaseURL = strings.TrimSuffix(baseURL.String(), "/")
+	}
 	return &ApplicationClient{
 		appID:  appID,
-		client: newClient(&http.Client{Transport: itr}),
+		client: &retryClient{client: newClient(&http.Client{Transport: itr}, baseURL), retry: o.retry, limits: *o.rateLimits},
+		opts:   o,
 	}, nil
 }
 
@@ -49,36 +109,102 @@ func (c *ApplicationClient) Installations(ctx context.Context) ([]int, error) {
 	return c.client.installations(ctx)
 }
 
+// RepoInstallation returns the id of the installation of the application in
+// the repository owner/repo.
+func (c *ApplicationClient) RepoInstallation(ctx context.Context, owner, repo string) (int, error) {
+	return c.client.repoInstallation(ctx, owner, repo)
+}
+
 // An InstallationClient provides all of the features depending on a specific installation.
 type InstallationClient struct {
 	appID          int
 	installationID int
 	client         client
+	opts           options
 }
 
 // NewInstallationClient returns a new InstallationClient.
 // If transport is nil http.DefaultTransport will be used.
-func NewInstallationClient(appID, installationID int, key []byte, transport http.RoundTripper) (*InstallationClient, error) {
+func NewInstallationClient(appID, installationID int, key []byte, transport http.RoundTripper, opts ...Option) (*InstallationClient, error) {
+	o := newOptions(opts)
+	baseURL, err := o.apiURL()
+	if err != nil {
+		return nil, err
+	}
+
 	itr, err := ghinstallation.New(transport, appID, installationID, key)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not created authenticated installation client")
 	}
+	if baseURL != nil {
+		itr.BaseURL = strings.TrimSuffix(baseURL.String(), "/")
+	}
+	return newInstallationClient(appID, installationID, newClient(&http.Client{Transport: itr}, baseURL), o), nil
+}
+
+// newInstallationClient wraps the given client according to the options.
+func newInstallationClient(appID, installationID int, cl client, o options) *InstallationClient {
+	cl = &retryClient{client: cl, retry: o.retry, limits: *o.rateLimits}
+	cl = &readOnlyClient{client: cl, store: o.store, appID: appID, installationID: installationID}
+	if len(o.quiet) > 0 {
+		cl = &quietClient{cl, o.quiet, o.store, storage.Key("held", appID, installationID)}
+	}
 	return &InstallationClient{
 		appID:          appID,
 		installationID: installationID,
-		client:         newClient(&http.Client{Transport: itr}),
-	}, nil
+		client:         cl,
+		opts:           o,
+	}
 }
 
 // UpdateInstallation iterates over all of the repositories in the installation updating all deadline labels.
 func (c *InstallationClient) UpdateInstallation(ctx context.Context) error {
 	logrus.Infof("updating all repos for installation %d/%d", c.appID, c.installationID)
 
+	if err := c.catchUp(ctx); err != nil {
+		return err
+	}
+	if err := c.deliverDeferred(ctx); err != nil {
+		return err
+	}
+	if c.opts.backfill > 0 {
+		if _, err := c.Backfill(ctx, c.opts.backfill); err != nil {
+			logrus.Warnf("could not backfill history of installation %d/%d: %v", c.appID, c.installationID, err)
+		}
+	}
+
 	repos, err := c.client.repos(ctx)
 	if err != nil {
 		return errors.Wrap(err, "could not list repositories")
 	}
 
+	if c.opts.anomaly == nil {
+		if err := c.updateRepos(ctx, repos); err != nil {
+			return err
+		}
+		return c.sendWeeklySummary(ctx, time.Now())
+	}
+
+	// compute all of the changes before applying them to detect anomalies.
+	scan, rec := c.recording()
+	if err := scan.updateRepos(ctx, repos); err != nil {
+		return err
+	}
+	ok, err := c.checkAnomaly(ctx, len(rec.mutations))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		logrus.Warnf("discarded %d mutations for installation %d/%d", len(rec.mutations), c.appID, c.installationID)
+		return nil
+	}
+	if err := c.flush(ctx, rec); err != nil {
+		return err
+	}
+	return c.sendWeeklySummary(ctx, time.Now())
+}
+
+func (c *InstallationClient) updateRepos(ctx context.Context, repos []repository) error {
 	for _, repo := range repos {
 		if err := c.UpdateRepo(ctx, repo.owner, repo.name); err != nil {
 			return errors.Wrapf(err, "could not handle repository %s/%s", repo.owner, repo.name)
@@ -88,14 +214,23 @@ func (c *InstallationClient) UpdateInstallation(ctx context.Context) error {
 }
 
 // UpdateRepo iterates over all of the issues and PRs in a repository updating all deadline labels.
+// The configuration file of the repository, if any, replaces the defaults.
 func (c *InstallationClient) UpdateRepo(ctx context.Context, owner, repo string) error {
 	logrus.Debugf("handling repository %s/%s", owner, repo)
+	c, err := c.forRepo(ctx, owner, repo)
+	if err != nil {
+		return err
+	}
+	if disabled, err := c.Disabled(ctx, owner, repo); err != nil || disabled {
+		return err
+	}
 
 	labels, err := c.LabelsInRepo(ctx, owner, repo)
 	if err != nil {
 		return err
 	}
-	if len(labels) == 0 {
+	if len(labels) == 0 && c.opts.overdueLabel == "" && c.opts.required == nil && c.opts.escalation == nil &&
+		c.opts.digestIssue == nil {
 		return nil
 	}
 
@@ -107,23 +242,75 @@ func (c *InstallationClient) UpdateRepo(ctx context.Context, owner, repo string)
 	if err != nil {
 		return errors.Wrap(err, "could not list issues")
 	}
+	if err := c.pruneDeadlines(ctx, owner, repo, numbers); err != nil {
+		return err
+	}
+
+	if err := c.scanRepo(ctx, owner, repo, numbers, labels); err != nil {
+		return err
+	}
+	// the digest issue is only a view of the deadlines, so a repository
+	// where it can't be kept is still labeled.
+	if err := c.updateDigestIssue(ctx, owner, repo); err != nil {
+		logrus.Warnf("could not update digest issue of %s/%s: %v", owner, repo, err)
+	}
+	if err := c.updateLabelColors(ctx, owner, repo, labels); err != nil {
+		return err
+	}
+	return c.updateLabelGradient(ctx, owner, repo, labels)
+}
+
+// scanRepo updates the given issues, holding the changes for approval if there are too many.
+func (c *InstallationClient) scanRepo(ctx context.Context, owner, repo string, numbers []int, labels []Label) error {
+	if c.opts.approvalThreshold <= 0 {
+		return c.updateIssues(ctx, owner, repo, numbers, labels)
+	}
+
+	scan, rec := c.recording()
+	if err := scan.updateIssues(ctx, owner, repo, numbers, labels); err != nil {
+		return err
+	}
+	if len(rec.mutations) > c.opts.approvalThreshold {
+		return c.hold(ctx, owner, repo, rec)
+	}
+	return c.flush(ctx, rec)
+}
 
+func (c *InstallationClient) updateIssues(ctx context.Context, owner, repo string, numbers []int, labels []Label) error {
 	for _, number := range numbers {
-		if err := c.updateIssue(ctx, owner, repo, number, labels); err != nil {
+		if err := c.updateIssue(ctx, owner, repo, number, "", labels); err != nil {
 			return errors.Wrapf(err, "could not handle issue %d", number)
 		}
 	}
 	return nil
 }
 
-// A Label has simply a name and the corresponding number of days.
+// A Label has simply a name and the corresponding number of days, or of
+// hours for labels like "deadline < 12h", whose Days are zero. Labels counting
+// weeks or months, like "deadline < 2w", have their number of days.
 type Label struct {
-	Name string
-	Days int
+	Name  string
+	Days  int
+	Hours int
 }
 
-// LabelsInRepo lists all of the deadline related labels in a repository.
+// Duration returns the time left until the deadline below which the label applies.
+func (l Label) Duration() time.Duration {
+	if l.Hours > 0 {
+		return time.Duration(l.Hours) * time.Hour
+	}
+	return time.Duration(l.Days) * 24 * time.Hour
+}
+
+// LabelsInRepo lists all of the deadline related labels in a repository, those
+// with its label prefix followed by a number of days, or of hours, weeks, or
+// months like "12h", "2w", or "1m", or with that number in the place of its
+// label template, sorted by increasing duration.
 func (c *InstallationClient) LabelsInRepo(ctx context.Context, owner, repo string) ([]Label, error) {
+	prefix, err := c.LabelPrefix(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
 	labels, err := c.client.repoLabels(ctx, owner, repo)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not list labels")
@@ -131,166 +318,490 @@ func (c *InstallationClient) LabelsInRepo(ctx context.Context, owner, repo strin
 
 	var list []Label
 
-	const prefix = "deadline < "
 	for _, label := range labels {
-		if !strings.HasPrefix(label, prefix) {
+		n, ok := matchLabel(prefix, label)
+		if !ok {
 			continue
 		}
-		days, err := strconv.Atoi(strings.TrimPrefix(label, prefix))
-		if err != nil {
+		l, ok := parseLabel(label, n)
+		if !ok {
 			logrus.Errorf("could not parse days in %s", label)
 			continue
 		}
-		list = append(list, Label{label, days})
+		list = append(list, l)
 	}
 
-	sort.Slice(list, func(i, j int) bool { return list[i].Days < list[j].Days })
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Duration() < list[j].Duration() })
 	return list, nil
 }
 
+// labelUnits are the days of the units of the labels, besides hours.
+var labelUnits = map[string]int{"": 1, "d": 1, "w": 7, "m": 30, "mo": 30}
+
+// parseLabel parses the number of days of a label, like "5", or of hours,
+// weeks, or months followed by their unit, like "12h", "2w", or "1m". A month
+// is 30 days.
+func parseLabel(name, n string) (Label, bool) {
+	n = strings.ToLower(n)
+	i := strings.IndexFunc(n, func(r rune) bool { return r < '0' || r > '9' })
+	if i < 0 {
+		i = len(n)
+	}
+	v, err := strconv.Atoi(n[:i])
+	if err != nil || v <= 0 {
+		return Label{}, false
+	}
+	unit := strings.TrimSpace(n[i:])
+	if unit == "h" {
+		return Label{Name: name, Hours: v}, true
+	}
+	days, ok := labelUnits[unit]
+	return Label{Name: name, Days: v * days}, ok
+}
+
 // UpdateIssue finds a deadline in the issue and updates its labels accordingly.
+// The configuration file of the repository, if any, replaces the defaults.
 func (c *InstallationClient) UpdateIssue(ctx context.Context, owner, repo string, number int) error {
+	return c.update(ctx, owner, repo, number, "")
+}
+
+// UpdatePullRequest is UpdateIssue for a pull request whose head commit is
+// known, like when it's opened or pushed to, so its deadline check run and
+// commit status are reported on the new commit right away.
+func (c *InstallationClient) UpdatePullRequest(ctx context.Context, owner, repo string, number int, head string) error {
+	return c.update(ctx, owner, repo, number, head)
+}
+
+func (c *InstallationClient) update(ctx context.Context, owner, repo string, number int, head string) error {
+	c, err := c.forRepo(ctx, owner, repo)
+	if err != nil {
+		return err
+	}
+	if disabled, err := c.Disabled(ctx, owner, repo); err != nil || disabled {
+		return err
+	}
+
 	labels, err := c.LabelsInRepo(ctx, owner, repo)
 	if err != nil {
 		return err
 	}
 
-	return c.updateIssue(ctx, owner, repo, number, labels)
+	return c.updateIssue(ctx, owner, repo, number, head, labels)
 }
 
-func (c *InstallationClient) updateIssue(ctx context.Context, owner, repo string, number int, labels []Label) error {
+func (c *InstallationClient) updateIssue(ctx context.Context, owner, repo string, number int, head string, labels []Label) error {
 	logrus.Debugf("handling issue %s/%s#%d", owner, repo, number)
 	issue, err := c.client.issue(ctx, owner, repo, number)
 	if err != nil {
 		return err
 	}
-	if issue.state != "open" {
+	issue.head = head
+	if isDigestIssue(issue) || isWelcomeIssue(issue) {
 		return nil
 	}
+	if err := c.readSettings(ctx, issue); err != nil {
+		return err
+	}
+	if issue.state != "open" {
+		c.recordDeadline(ctx, issue, time.Time{}, "")
+		if err := c.recordOutcome(ctx, issue); err != nil {
+			return err
+		}
+		if err := c.checkOverdue(ctx, issue, time.Time{}); err != nil {
+			return err
+		}
+		if err := c.checkEscalation(ctx, issue, time.Time{}); err != nil {
+			return err
+		}
+		// the focus label is the bot's own, so it's removed from closed
+		// issues even if their deadline labels are kept.
+		if err := c.checkFocus(ctx, issue, time.Time{}); err != nil {
+			return err
+		}
+		if err := c.stripDeadlineLabels(ctx, issue, labels); err != nil {
+			return err
+		}
+		return c.schedule(ctx, issue, time.Time{})
+	}
 
-	if err = c.checkReminders(ctx, issue); err != nil {
+	if issue.policy, err = c.pathPolicy(ctx, issue); err != nil {
+		return err
+	}
+	if err := c.addFallbacks(ctx, issue); err != nil {
 		return err
 	}
 
-	bodies := []string{issue.body}
+	deadline, err := c.deadline(ctx, issue)
+	if err != nil {
+		return err
+	}
+	issue.deadline = deadline
+	if err = c.syncProjectDate(ctx, issue, deadline); err != nil {
+		return err
+	}
+	if err = c.checkReminders(ctx, issue, deadline); err != nil {
+		return err
+	}
+	if err = c.checkTasks(ctx, issue); err != nil {
+		return err
+	}
+	if err = c.clarifyDates(ctx, issue); err != nil {
+		return err
+	}
+	if err = c.explainDates(ctx, issue); err != nil {
+		return err
+	}
+	deadline = c.endOfDay(deadline)
+	if deadline.IsZero() {
+		c.recordDeadline(ctx, issue, time.Time{}, "")
+		if err := c.clearDeadlineLabels(ctx, issue, labels); err != nil {
+			return err
+		}
+		if err := c.checkOverdue(ctx, issue, time.Time{}); err != nil {
+			return err
+		}
+		if err := c.checkEscalation(ctx, issue, time.Time{}); err != nil {
+			return err
+		}
+		if err := c.checkMissingDeadline(ctx, issue, time.Time{}); err != nil {
+			return err
+		}
+		if err := c.checkDeadlineRun(ctx, issue, time.Time{}); err != nil {
+			return err
+		}
+		if err := c.checkDeadlineStatus(ctx, issue, time.Time{}); err != nil {
+			return err
+		}
+		if err := c.checkBoardColumn(ctx, issue, time.Time{}, ""); err != nil {
+			return err
+		}
+		return c.checkFocus(ctx, issue, time.Time{})
+	}
+	if err := c.checkCadence(ctx, issue, deadline); err != nil {
+		return err
+	}
+	if err := c.checkFocus(ctx, issue, deadline); err != nil {
+		return err
+	}
+	label, err := c.checkDeadlines(ctx, issue, deadline, labels)
+	if err != nil {
+		return err
+	}
+	if err := c.checkProjectBoard(ctx, issue, deadline); err != nil {
+		return err
+	}
+	if err := c.checkBoardColumn(ctx, issue, deadline, label); err != nil {
+		return err
+	}
+	if err := c.checkOverdue(ctx, issue, deadline); err != nil {
+		return err
+	}
+	if err := c.checkEscalation(ctx, issue, deadline); err != nil {
+		return err
+	}
+	if err := c.checkOverdueComment(ctx, issue, deadline); err != nil {
+		return err
+	}
+	if err := c.checkFollowUps(ctx, issue, deadline); err != nil {
+		return err
+	}
+	if err := c.checkMissingDeadline(ctx, issue, deadline); err != nil {
+		return err
+	}
+	if err := c.checkDeadlineRun(ctx, issue, deadline); err != nil {
+		return err
+	}
+	if err := c.checkDeadlineStatus(ctx, issue, deadline); err != nil {
+		return err
+	}
+	c.recordDeadline(ctx, issue, deadline, label)
+	return nil
+}
+
+// titleBrackets separates the text between brackets in titles, like
+// "[deadline: 2018-08-01] Ship it", so it's read as a line of its own.
+var titleBrackets = strings.NewReplacer("[", "\n", "]", "\n", "(", "\n", ")", "\n")
+
+// deadline returns the last deadline written in the title, body, and comments
+// of the issue, in that order, or, if there's none, the one in its projects,
+// the due date of its milestone, or the one imported for it, if any.
+// A deadline set with /deadline replaces the ones written before the command.
+// Unchecked tasks in task lists with their own deadlines are checkpoints named
+// after them.
+// If the issue has several named checkpoints, it's the nearest one that hasn't
+// passed, and its name is kept in the issue. It's zero if the deadline was
+// cleared with "deadline: none", which is also kept in the issue.
+func (c *InstallationClient) deadline(ctx context.Context, issue *issue) (time.Time, error) {
+	cmd, err := c.commandDeadline(ctx, issue)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	d := c.dates(issue)
+	find := func(body string, created time.Time) []checkpoint {
+		body, tasks := splitTasks(issue.synonyms.replace(body), created, d)
+		return append(issue.grammar.findCheckpoints("deadline", body, created, d), taskCheckpoints(tasks)...)
+	}
+	cps := issue.grammar.findCheckpoints("deadline", titleBrackets.Replace(issue.synonyms.replace(issue.title)), issue.created, d)
+	cps = append(cps, find(issue.body, issue.created)...)
 	for _, comment := range issue.comments {
-		bodies = append(bodies, comment.body)
+		if cmd != nil && comment.created.After(cmd.Time) {
+			cps = append(cps, checkpoint{time: cmd.Deadline})
+			cmd = nil
+		}
+		cps = append(cps, find(comment.body, comment.created)...)
 	}
-	deadlines := findTimes("deadline", bodies...)
-	if len(deadlines) == 0 {
-		return nil
+	if cmd != nil {
+		cps = append(cps, checkpoint{time: cmd.Deadline})
+	}
+	if len(cps) > 0 {
+		cp := c.nextCheckpoint(cps, time.Now())
+		issue.checkpoint, issue.cleared, issue.written = cp.name, cp.time.IsZero(), true
+		return cp.time, nil
+	}
+	if t, err := c.projectDeadline(ctx, issue); err != nil || !t.IsZero() {
+		return t, err
+	}
+	if t := c.milestoneDeadline(issue); !t.IsZero() {
+		return t, nil
 	}
-	deadline := deadlines[len(deadlines)-1]
-	return c.checkDeadlines(ctx, owner, repo, number, deadline, labels)
+	return c.importedDeadline(ctx, issue)
 }
 
-func (c *InstallationClient) checkReminders(ctx context.Context, issue *issue) error {
+// checkReminders sends the reminders of the issue due today, and schedules the
+// next one. Reminders can be relative to the deadline, zero if there's none.
+func (c *InstallationClient) checkReminders(ctx context.Context, issue *issue, deadline time.Time) error {
 	var reminded []time.Time
 	for _, comment := range issue.comments {
-		if comment.author == "deadline-reminder[bot]" {
+		if c.isDueReminder(comment) {
 			date := comment.created
 			date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
 			reminded = append(reminded, date)
 		}
 	}
 
-	check := func(author, body string) error {
-		for _, reminder := range findTimes("reminder", body) {
-			now := time.Now().In(time.UTC)
-			// not the same day
-			if reminder.Day() != now.Day() || reminder.Month() != now.Month() || reminder.Year() != now.Year() {
+	now := time.Now().In(time.UTC)
+	var next time.Time
+	check := func(author, body string, created time.Time) error {
+		for _, due := range c.findReminders(issue, body, created, now, deadline) {
+			reminder := due.at
+			if reminder.After(now) && (next.IsZero() || reminder.Before(next)) {
+				next = reminder
+			}
+			// not the same day, or later today
+			if reminder.Day() != now.Day() || reminder.Month() != now.Month() || reminder.Year() != now.Year() || reminder.After(now) {
 				continue
 			}
 
+			day := time.Date(reminder.Year(), reminder.Month(), reminder.Day(), 0, 0, 0, 0, time.UTC)
 			done := false
 			for _, r := range reminded {
-				done = done || r.Equal(reminder)
+				done = done || r.Equal(day)
 			}
 			if done {
 				continue
 			}
 
-			text := fmt.Sprintf("hi @%s, it's reminder day!", author)
-			err := c.client.createIssueComment(ctx, issue.repo.owner, issue.repo.name, issue.number, text)
-			if err != nil {
-				return errors.Wrapf(err, "could not comment on %s/%s#%d", issue.repo.owner, issue.repo.name, issue.number)
+			for _, user := range c.reminded(issue, author, due.users) {
+				if err := c.remind(ctx, issue, user, reminder); err != nil {
+					return err
+				}
 			}
 		}
 		return nil
 	}
 
-	if err := check(issue.author, issue.body); err != nil {
+	if err := check(issue.author, issue.body, issue.created); err != nil {
 		return err
 	}
 	for _, comment := range issue.comments {
-		if err := check(comment.author, comment.body); err != nil {
+		if err := check(comment.author, comment.body, comment.created); err != nil {
 			return err
 		}
 	}
+	return c.schedule(ctx, issue, next)
+}
+
+// clearDeadlineLabels removes the deadline labels of an issue whose deadline was cleared.
+func (c *InstallationClient) clearDeadlineLabels(ctx context.Context, issue *issue, labels []Label) error {
+	if !issue.cleared {
+		return nil
+	}
+	for _, l := range labels {
+		for _, name := range issue.labels {
+			if name != l.Name {
+				continue
+			}
+			logrus.Debugf("removing %s from issue %s/%s#%d, its deadline was cleared", l.Name, issue.repo.owner, issue.repo.name, issue.number)
+			if err := c.client.removeIssueLabel(ctx, issue.repo.owner, issue.repo.name, issue.number, l.Name); err != nil {
+				return errors.Wrapf(err, "could not remove label %s", l.Name)
+			}
+		}
+	}
 	return nil
 }
 
-func (c *InstallationClient) checkDeadlines(ctx context.Context, owner, repo string, number int, deadline time.Time, labels []Label) error {
-	days := time.Until(deadline).Hours() / 24
+// checkDeadlines applies the deadline label corresponding to the issue, returning its name.
+func (c *InstallationClient) checkDeadlines(ctx context.Context, issue *issue, deadline time.Time, labels []Label) (string, error) {
+	owner, repo, number := issue.repo.owner, issue.repo.name, issue.number
 
-	logrus.Debugf("issue #%d deadline in %v days", number, days)
-	labelIdx := -1
-	if days > -1 {
-		for labelIdx = 0; labelIdx < len(labels); labelIdx++ {
-			if labels[labelIdx].Days > int(days) {
-				break
+	if manual, err := c.manualOverride(ctx, issue, deadline, labels); err != nil || manual {
+		for _, l := range labels {
+			if hasLabel(issue.labels, l.Name) {
+				return l.Name, err
 			}
 		}
+		return "", err
 	}
 
-	for i, l := range labels {
-		if i == labelIdx {
-			continue
+	scorer, info := c.opts.scorer, issue.info(deadline)
+	business, err := c.BusinessDays(ctx, owner, repo)
+	if err != nil {
+		return "", err
+	}
+	info.AllDay = c.allDay(deadline)
+	if info.BusinessDays = business; business && c.opts.location != nil {
+		// weekends are those of the bot's timezone.
+		info.Deadline = info.Deadline.In(c.opts.location)
+	}
+	if p := issue.policy; p != nil {
+		info.Deadline = info.Deadline.Add(-p.Margin)
+		if p.Scorer != nil {
+			scorer = p.Scorer
 		}
-		c.client.removeIssueLabel(ctx, owner, repo, number, l.Name)
 	}
 
-	// new deadline is too large for labels.
+	labelIdx := scorer.Score(ctx, info, labels)
 	if labelIdx >= len(labels) {
-		return nil
+		labelIdx = -1
+	}
+
+	// the labels of the issue are replaced at once, only if they change, so
+	// it's never seen without a deadline label while it's being changed.
+	want := withoutDeadlineLabels(issue.labels, labels)
+	if labelIdx >= 0 {
+		want = append(want, labels[labelIdx].Name)
 	}
-	// deadline is in the past.
+	previous := issue.labels
+	if !sameLabels(want, issue.labels) {
+		// the labels are read again, so those added while the issue was
+		// being checked, by anyone, aren't replaced.
+		current, err := c.client.issueLabels(ctx, owner, repo, number)
+		if err != nil {
+			return "", err
+		}
+		want = withoutDeadlineLabels(current, labels)
+		if labelIdx >= 0 {
+			want = append(want, labels[labelIdx].Name)
+		}
+		if !sameLabels(want, current) {
+			logrus.Debugf("setting labels %v of issue %s/%s#%d", want, owner, repo, number)
+			if err := c.client.replaceIssueLabels(ctx, owner, repo, number, want); err != nil {
+				return "", errors.Wrapf(err, "could not replace labels of issue %s/%s#%d", owner, repo, number)
+			}
+		}
+	}
+	issue.labels = want
+
+	// no label applies, e.g. the deadline is too far or in the past.
 	if labelIdx < 0 {
-		return nil
+		return "", nil
 	}
 
 	newLabel := labels[labelIdx]
-	logrus.Debugf("applying %s to issue %s/%s#%d", newLabel.Name, owner, repo, number)
-	err := c.client.addIssueLabel(ctx, owner, repo, number, newLabel.Name)
-	return errors.Wrapf(err, "could not apply label %s", newLabel.Name)
-}
-
-func findTimes(word string, bodies ...string) []time.Time {
-	var times []time.Time
-	for _, body := range bodies {
-		body = strings.ToLower(body)
-		for {
-			i := strings.Index(body, word)
-			if i < 0 {
-				break
-			}
-			body = body[i+len(word):]
+	if hasLabel(previous, newLabel.Name) {
+		return newLabel.Name, nil
+	}
+	e := issue.event(notify.Label, fmt.Sprintf("%s is now labeled %s", issue.title, newLabel.Name))
+	e.User, e.Label, e.Deadline = issue.author, newLabel.Name, deadline
+	c.notify(ctx, e, issue.policy)
+	return newLabel.Name, nil
+}
 
-			lb := strings.Index(body, "\n")
-			if lb < 0 {
-				lb = len(body)
-			}
+// withoutDeadlineLabels returns the labels of an issue that are not deadline labels.
+func withoutDeadlineLabels(names []string, labels []Label) []string {
+	var res []string
+	for _, name := range names {
+		deadlineLabel := false
+		for _, l := range labels {
+			deadlineLabel = deadlineLabel || strings.EqualFold(name, l.Name)
+		}
+		if !deadlineLabel {
+			res = append(res, name)
+		}
+	}
+	return res
+}
 
-			if d := parseDate(body[:lb]); !d.IsZero() {
-				times = append(times, d)
-			}
+// hasLabel reports whether the label is in the list, regardless of its case as GitHub does.
+func hasLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if strings.EqualFold(l, label) {
+			return true
 		}
 	}
+	return false
+}
 
-	return times
+// sameLabels reports whether both lists have the same labels, in any order.
+func sameLabels(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, l := range a {
+		if !hasLabel(b, l) {
+			return false
+		}
+	}
+	return true
+}
+
+// findValues returns the text following the given word in a body, up to the end of the line.
+func findValues(word, body string) []string {
+	var values []string
+	body = strings.ToLower(body)
+	for {
+		i := strings.Index(body, word)
+		if i < 0 {
+			break
+		}
+		body = body[i+len(word):]
+
+		lb := strings.Index(body, "\n")
+		if lb < 0 {
+			lb = len(body)
+		}
+
+		values = append(values, body[:lb])
+	}
+
+	return values
+}
+
+// parseUnixTime parses a Unix time in seconds. It must have at least nine
+// digits, so it's not mistaken for a year or a number, which is after 1973.
+func parseUnixTime(s string) time.Time {
+	if len(s) < 9 {
+		return time.Time{}
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return time.Time{}
+		}
+	}
+	sec, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0).UTC()
 }
 
 var dateLayouts = []string{
+	"2006/01/02 15:04",
+	"2006-01-02 15:04",
+	"2006-01-02t15:04",
+	"2006-01-02 3pm",
+	"2006-01-02 3:04pm",
 	"2006/01/02",
 	"2006-01-02",
 	"2006 January 2",
@@ -301,11 +812,29 @@ var dateLayouts = []string{
 	"Jan 2, 2006",
 }
 
-func parseDate(s string) time.Time {
+// parseDate parses a date, optionally followed by a timezone like CET or
+// Europe/Madrid, in the given location if it has none. The given layouts are
+// tried after the default ones. RFC 3339 timestamps and Unix times in seconds,
+// as written by other tools, are understood too.
+func parseDate(s string, loc *time.Location, layouts []string) time.Time {
 	s = strings.TrimSpace(strings.Trim(strings.TrimSpace(s), ":"))
-	for _, l := range dateLayouts {
-		if t, err := time.Parse(l, s); err == nil {
-			return t
+	if t, err := time.Parse(time.RFC3339Nano, strings.ToUpper(s)); err == nil {
+		return t.UTC()
+	}
+	if t := parseUnixTime(s); !t.IsZero() {
+		return t
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	if rest, zone := splitZone(s); zone != nil {
+		s, loc = rest, zone
+	}
+	for _, ls := range [][]string{dateLayouts, layouts} {
+		for _, l := range ls {
+			if t, err := time.ParseInLocation(l, s, loc); err == nil {
+				return t.UTC()
+			}
 		}
 	}
 	return time.Time{}
@@ -3,6 +3,7 @@ package reminder
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -11,18 +12,44 @@ import (
 // fakeClient satisfies the client interface.
 type fakeClient struct {
 	_installations      func(ctx context.Context) ([]int, error)
+	_repoInstallation   func(ctx context.Context, owner, repo string) (int, error)
 	_repos              func(ctx context.Context) ([]repository, error)
 	_repoLabels         func(ctx context.Context, owner, repo string) ([]string, error)
 	_issues             func(ctx context.Context, owner, repo string) ([]int, error)
+	_closedIssues       func(ctx context.Context, owner, repo string, page int) ([]int, int, error)
 	_issue              func(ctx context.Context, owner, repo string, number int) (*issue, error)
 	_createIssueComment func(ctx context.Context, owner, repo string, number int, body string) error
 	_removeIssueLabel   func(ctx context.Context, owner, repo string, number int, label string) error
 	_addIssueLabel      func(ctx context.Context, owner, repo string, number int, label string) error
+	_replaceIssueLabels func(ctx context.Context, owner, repo string, number int, labels []string) error
+	_editLabelColor     func(ctx context.Context, owner, repo, label, color string) error
+	_createLabel        func(ctx context.Context, owner, repo, label, color string) error
+	_minimizeComment    func(ctx context.Context, owner, repo string, id int64) error
+	_projectDate        func(ctx context.Context, owner, repo string, number int, field string) (time.Time, error)
+	_permission         func(ctx context.Context, owner, repo, user string) (string, error)
+	_files              func(ctx context.Context, owner, repo string, number int) ([]string, error)
+	_file               func(ctx context.Context, owner, repo, path string) ([]byte, error)
+	_labelEvents        func(ctx context.Context, owner, repo string, number int) ([]labelEvent, error)
+	_issueLabels        func(ctx context.Context, owner, repo string, number int) ([]string, error)
+	_labelColors        func(ctx context.Context, owner, repo string) (map[string]string, error)
+	_createIssue        func(ctx context.Context, owner, repo, title, body string) (int, error)
+	_editIssueBody      func(ctx context.Context, owner, repo string, number int, body string) error
+	_pinIssue           func(ctx context.Context, owner, repo string, number int) error
+	_headSHA            func(ctx context.Context, owner, repo string, number int) (string, error)
+	_createCheckRun     func(ctx context.Context, owner, repo string, run checkRun) error
+	_createStatus       func(ctx context.Context, owner, repo string, status commitStatus) error
+	_setProjectDate     func(ctx context.Context, owner, repo string, number int, field string, date time.Time) error
+	_projectItems       func(ctx context.Context, owner, repo string, number int) ([]string, error)
+	_addToProject       func(ctx context.Context, owner, repo string, number int, p Project) error
+	_moveProjectItem    func(ctx context.Context, owner, repo string, number int, p Project, field, column string) (bool, error)
 }
 
 func (f *fakeClient) installations(ctx context.Context) ([]int, error) {
 	return f._installations(ctx)
 }
+func (f *fakeClient) repoInstallation(ctx context.Context, owner, repo string) (int, error) {
+	return f._repoInstallation(ctx, owner, repo)
+}
 func (f *fakeClient) repos(ctx context.Context) ([]repository, error) {
 	return f._repos(ctx)
 }
@@ -32,6 +59,9 @@ func (f *fakeClient) repoLabels(ctx context.Context, owner, repo string) ([]stri
 func (f *fakeClient) issues(ctx context.Context, owner, repo string) ([]int, error) {
 	return f._issues(ctx, owner, repo)
 }
+func (f *fakeClient) closedIssues(ctx context.Context, owner, repo string, page int) ([]int, int, error) {
+	return f._closedIssues(ctx, owner, repo, page)
+}
 func (f *fakeClient) issue(ctx context.Context, owner, repo string, number int) (*issue, error) {
 	return f._issue(ctx, owner, repo, number)
 }
@@ -45,8 +75,155 @@ func (f *fakeClient) addIssueLabel(ctx context.Context, owner, repo string, numb
 	return f._addIssueLabel(ctx, owner, repo, number, label)
 }
 
+// replaceIssueLabels removes and adds the labels that change, unless replacing
+// them is faked, so tests can check the labels removed and added.
+func (f *fakeClient) replaceIssueLabels(ctx context.Context, owner, repo string, number int, labels []string) error {
+	if f._replaceIssueLabels != nil {
+		return f._replaceIssueLabels(ctx, owner, repo, number, labels)
+	}
+	i, err := f._issue(ctx, owner, repo, number)
+	if err != nil {
+		return err
+	}
+	for _, l := range i.labels {
+		if !hasLabel(labels, l) {
+			if err := f._removeIssueLabel(ctx, owner, repo, number, l); err != nil {
+				return err
+			}
+		}
+	}
+	for _, l := range labels {
+		if !hasLabel(i.labels, l) {
+			if err := f._addIssueLabel(ctx, owner, repo, number, l); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+func (f *fakeClient) editLabelColor(ctx context.Context, owner, repo, label, color string) error {
+	return f._editLabelColor(ctx, owner, repo, label, color)
+}
+func (f *fakeClient) createLabel(ctx context.Context, owner, repo, label, color string) error {
+	return f._createLabel(ctx, owner, repo, label, color)
+}
+func (f *fakeClient) minimizeComment(ctx context.Context, owner, repo string, id int64) error {
+	return f._minimizeComment(ctx, owner, repo, id)
+}
+func (f *fakeClient) projectDate(ctx context.Context, owner, repo string, number int, field string) (time.Time, error) {
+	return f._projectDate(ctx, owner, repo, number, field)
+}
+func (f *fakeClient) permission(ctx context.Context, owner, repo, user string) (string, error) {
+	return f._permission(ctx, owner, repo, user)
+}
+func (f *fakeClient) files(ctx context.Context, owner, repo string, number int) ([]string, error) {
+	return f._files(ctx, owner, repo, number)
+}
+
+func (f *fakeClient) file(ctx context.Context, owner, repo, path string) ([]byte, error) {
+	if f._file == nil {
+		return nil, nil
+	}
+	return f._file(ctx, owner, repo, path)
+}
+
+// issueLabels returns the labels of the issue unless faked.
+func (f *fakeClient) issueLabels(ctx context.Context, owner, repo string, number int) ([]string, error) {
+	if f._issueLabels != nil {
+		return f._issueLabels(ctx, owner, repo, number)
+	}
+	i, err := f._issue(ctx, owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	return i.labels, nil
+}
+
+func (f *fakeClient) labelEvents(ctx context.Context, owner, repo string, number int) ([]labelEvent, error) {
+	if f._labelEvents == nil {
+		return nil, nil
+	}
+	return f._labelEvents(ctx, owner, repo, number)
+}
+
+func (f *fakeClient) labelColors(ctx context.Context, owner, repo string) (map[string]string, error) {
+	if f._labelColors == nil {
+		return nil, nil
+	}
+	return f._labelColors(ctx, owner, repo)
+}
+
+func (f *fakeClient) createIssue(ctx context.Context, owner, repo, title, body string) (int, error) {
+	if f._createIssue == nil {
+		return 0, fmt.Errorf("unexpected issue %q", title)
+	}
+	return f._createIssue(ctx, owner, repo, title, body)
+}
+
+func (f *fakeClient) editIssueBody(ctx context.Context, owner, repo string, number int, body string) error {
+	if f._editIssueBody == nil {
+		return fmt.Errorf("unexpected edit of %s/%s#%d", owner, repo, number)
+	}
+	return f._editIssueBody(ctx, owner, repo, number, body)
+}
+
+func (f *fakeClient) pinIssue(ctx context.Context, owner, repo string, number int) error {
+	if f._pinIssue == nil {
+		return nil
+	}
+	return f._pinIssue(ctx, owner, repo, number)
+}
+
+func (f *fakeClient) headSHA(ctx context.Context, owner, repo string, number int) (string, error) {
+	if f._headSHA == nil {
+		return "", fmt.Errorf("unexpected pull request %s/%s#%d", owner, repo, number)
+	}
+	return f._headSHA(ctx, owner, repo, number)
+}
+
+func (f *fakeClient) createCheckRun(ctx context.Context, owner, repo string, run checkRun) error {
+	if f._createCheckRun == nil {
+		return fmt.Errorf("unexpected check run on %s/%s@%s", owner, repo, run.sha)
+	}
+	return f._createCheckRun(ctx, owner, repo, run)
+}
+
+func (f *fakeClient) createStatus(ctx context.Context, owner, repo string, status commitStatus) error {
+	if f._createStatus == nil {
+		return fmt.Errorf("unexpected status on %s/%s@%s", owner, repo, status.sha)
+	}
+	return f._createStatus(ctx, owner, repo, status)
+}
+
+func (f *fakeClient) projectItems(ctx context.Context, owner, repo string, number int) ([]string, error) {
+	if f._projectItems == nil {
+		return nil, nil
+	}
+	return f._projectItems(ctx, owner, repo, number)
+}
+func (f *fakeClient) setProjectDate(ctx context.Context, owner, repo string, number int, field string, date time.Time) error {
+	if f._setProjectDate == nil {
+		return fmt.Errorf("unexpected project date of %s/%s#%d", owner, repo, number)
+	}
+	return f._setProjectDate(ctx, owner, repo, number, field, date)
+}
+
+func (f *fakeClient) addToProject(ctx context.Context, owner, repo string, number int, p Project) error {
+	if f._addToProject == nil {
+		return fmt.Errorf("unexpected project %s for %s/%s#%d", p, owner, repo, number)
+	}
+	return f._addToProject(ctx, owner, repo, number, p)
+}
+
+func (f *fakeClient) moveProjectItem(ctx context.Context, owner, repo string, number int, p Project, field, column string) (bool, error) {
+	if f._moveProjectItem == nil {
+		return false, fmt.Errorf("unexpected move of %s/%s#%d to %s", owner, repo, number, column)
+	}
+	return f._moveProjectItem(ctx, owner, repo, number, p, field, column)
+}
+
 func TestInstallations(t *testing.T) {
-	ac := ApplicationClient{42, &fakeClient{
+	ac := ApplicationClient{appID: 42, client: &fakeClient{
 		_installations: func(context.Context) ([]int, error) { return []int{100}, nil },
 	}}
 	ids, err := ac.Installations(context.Background())
@@ -60,7 +237,7 @@ func TestInstallations(t *testing.T) {
 
 func TestAddFirstReminderComment(t *testing.T) {
 	called := 0
-	ic := InstallationClient{42, 43, &fakeClient{
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil), client: &fakeClient{
 		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) { return nil, nil },
 		_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
 			return &issue{
@@ -97,7 +274,7 @@ func TestAddFirstReminderComment(t *testing.T) {
 }
 
 func TestAvoidAddingSecondReminderComment(t *testing.T) {
-	ic := InstallationClient{42, 43, &fakeClient{
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil), client: &fakeClient{
 		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) { return nil, nil },
 		_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
 			now := time.Now()
@@ -114,7 +291,7 @@ func TestAvoidAddingSecondReminderComment(t *testing.T) {
 					created: now.Add(-96 * time.Hour),
 				}, {
 					author: "deadline-reminder[bot]",
-					body:   "hi @francesc, it's reminder time!",
+					body:   "hi @francesc, it's reminder day!",
 					// 1 am today
 					created: time.Date(now.Year(), now.Month(), now.Day(), 1, 0, 0, 0, now.Location()),
 				}},
@@ -129,3 +306,204 @@ func TestAvoidAddingSecondReminderComment(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestReminderAfterOtherBotComments(t *testing.T) {
+	called := 0
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil), client: &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) { return nil, nil },
+		_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+			now := time.Now()
+			return &issue{
+				repo:   repository{owner, repo},
+				number: number,
+				title:  "Test",
+				body:   "Nothing to see here",
+				author: "francesc",
+				state:  "open",
+				comments: []comment{{
+					author:  "francesc",
+					body:    fmt.Sprintf("reminder: %s\n", now.Format("2006-01-02")),
+					created: now.Add(-96 * time.Hour),
+				}, {
+					author:  "deadline-reminder[bot]",
+					body:    "The deadline changed from August 3 to August 10.",
+					created: time.Date(now.Year(), now.Month(), now.Day(), 0, 1, 0, 0, now.Location()),
+				}},
+			}, nil
+		},
+		_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error {
+			called++
+			return nil
+		},
+	}}
+
+	if err := ic.UpdateIssue(context.Background(), "foo", "bar", 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if called != 1 {
+		t.Fatalf("expected the reminder despite the other comments of the bot; got %d comments", called)
+	}
+}
+
+func TestReminderForOtherUsers(t *testing.T) {
+	var bodies []string
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil), client: &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) { return nil, nil },
+		_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+			return &issue{
+				repo: repository{owner, repo}, number: number, state: "open", author: "francesc",
+				comments: []comment{{
+					author: "francesc",
+					body:   fmt.Sprintf("reminder for @alice and @bob: %s\n", time.Now().Format("2006-01-02")),
+				}},
+			}, nil
+		},
+		_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error {
+			bodies = append(bodies, body)
+			return nil
+		},
+	}}
+
+	if err := ic.UpdateIssue(context.Background(), "foo", "bar", 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(bodies) != 2 || !strings.Contains(bodies[0], "@alice") || !strings.Contains(bodies[1], "@bob") {
+		t.Fatalf("expected reminders for @alice and @bob; got %q", bodies)
+	}
+	for _, b := range bodies {
+		if strings.Contains(b, "francesc") {
+			t.Errorf("expected the author not to be reminded; got %q", b)
+		}
+	}
+
+	for _, v := range []string{": 2018-08-01", " for: 2018-08-01", " for alice: 2018-08-01", " for @: 2018-08-01"} {
+		if users, rest := parseAddressees(v); users != nil || rest != v {
+			t.Errorf("%q: expected no addressees; got %v and %q", v, users, rest)
+		}
+	}
+}
+
+func TestParseRelativeTime(t *testing.T) {
+	created := time.Date(2018, 8, 1, 15, 30, 0, 0, time.UTC)
+	tests := map[string]time.Time{
+		"2018-08-20":       time.Date(2018, 8, 20, 0, 0, 0, 0, time.UTC),
+		"today":            time.Date(2018, 8, 1, 0, 0, 0, 0, time.UTC),
+		": tomorrow":       time.Date(2018, 8, 2, 0, 0, 0, 0, time.UTC),
+		"in 5 days":        time.Date(2018, 8, 6, 0, 0, 0, 0, time.UTC),
+		"2 weeks":          time.Date(2018, 8, 15, 0, 0, 0, 0, time.UTC),
+		"in a month":       time.Date(2018, 9, 1, 0, 0, 0, 0, time.UTC),
+		"in 1 day":         time.Date(2018, 8, 2, 0, 0, 0, 0, time.UTC),
+		"in 5 fortnights":  {},
+		"was two days ago": {},
+		"in -1 days":       {},
+		"2w":               time.Date(2018, 8, 15, 0, 0, 0, 0, time.UTC),
+		"10d":              time.Date(2018, 8, 11, 0, 0, 0, 0, time.UTC),
+		"72h":              time.Date(2018, 8, 4, 15, 30, 0, 0, time.UTC),
+		"1h30m":            time.Date(2018, 8, 1, 17, 0, 0, 0, time.UTC),
+		"0d":               {},
+		"-3h":              {},
+	}
+	for s, expected := range tests {
+		if got := (dates{parsers: DefaultDateParsers}).parse(s, created); !got.Equal(expected) {
+			t.Errorf("%q: expected %v; got %v", s, expected, got)
+		}
+	}
+
+	if times := GrammarV1.findTimes("deadline", "deadline: in 5 days", created, dates{parsers: DefaultDateParsers}); len(times) != 1 || !times[0].Equal(tests["in 5 days"]) {
+		t.Errorf("expected relative deadline to be found; got %v", times)
+	}
+}
+
+func TestParseMachineDates(t *testing.T) {
+	tests := map[string]time.Time{
+		"1717200000":                time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		": 1533139200":              time.Date(2018, 8, 1, 16, 0, 0, 0, time.UTC),
+		"2018-08-01t18:00:00z":      time.Date(2018, 8, 1, 18, 0, 0, 0, time.UTC),
+		"2018-08-01T18:00:00+02:00": time.Date(2018, 8, 1, 16, 0, 0, 0, time.UTC),
+		"2018-08-01t18:00:00.5z":    time.Date(2018, 8, 1, 18, 0, 0, 5e8, time.UTC),
+		"12345":                     {},
+		"1717200000s":               {},
+	}
+	for s, expected := range tests {
+		if got := parseDate(s, nil, nil); !got.Equal(expected) {
+			t.Errorf("%q: expected %v; got %v", s, expected, got)
+		}
+	}
+}
+
+func TestDeadlineInTitle(t *testing.T) {
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil)}
+	date := func(d int) time.Time { return time.Date(2018, 8, d, 0, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		issue    issue
+		expected time.Time
+	}{
+		{issue{title: "[deadline: 2018-08-01] Ship it"}, date(1)},
+		{issue{title: "Ship it (deadline 2018-08-01)", grammar: GrammarV2}, date(1)},
+		{issue{title: "[deadline: 2018-08-01] Ship it", body: "deadline: 2018-08-02"}, date(2)},
+		{issue{title: "[deadline: 2018-08-03] Ship it", comments: []comment{{body: "deadline: 2018-08-02"}}}, date(2)},
+		{issue{title: "Ship the deadline fix"}, time.Time{}},
+	}
+	for _, tt := range tests {
+		got, err := ic.deadline(context.Background(), &tt.issue)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !got.Equal(tt.expected) {
+			t.Errorf("%q: expected %v; got %v", tt.issue.title, tt.expected, got)
+		}
+	}
+}
+
+func TestReplaceDeadlineLabels(t *testing.T) {
+	var replaced [][]string
+	is := &issue{
+		repo:   repository{"foo", "bar"},
+		number: 1,
+		state:  "open",
+		labels: []string{"bug", "deadline < 30"},
+		body:   "deadline: " + time.Now().AddDate(0, 0, 3).Format("2006-01-02"),
+	}
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil), client: &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) {
+			return []string{"deadline < 5", "deadline < 30", "bug"}, nil
+		},
+		_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+			cp := *is
+			return &cp, nil
+		},
+		_replaceIssueLabels: func(ctx context.Context, owner, repo string, number int, labels []string) error {
+			replaced = append(replaced, labels)
+			return nil
+		},
+	}}
+
+	if err := ic.UpdateIssue(context.Background(), "foo", "bar", 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := [][]string{{"bug", "deadline < 5"}}; !reflect.DeepEqual(replaced, expected) {
+		t.Errorf("expected the labels to be replaced with %v at once; got %v", expected, replaced)
+	}
+
+	replaced = nil
+	is.labels = []string{"Deadline < 5", "bug"}
+	if err := ic.UpdateIssue(context.Background(), "foo", "bar", 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(replaced) != 0 {
+		t.Errorf("expected the labels not to be replaced when they don't change; got %v", replaced)
+	}
+
+	// labels added while the issue is checked are kept.
+	replaced = nil
+	is.labels = []string{"bug", "deadline < 30"}
+	ic.client.(*fakeClient)._issueLabels = func(ctx context.Context, owner, repo string, number int) ([]string, error) {
+		return []string{"bug", "deadline < 30", "focus"}, nil
+	}
+	if err := ic.UpdateIssue(context.Background(), "foo", "bar", 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := [][]string{{"bug", "focus", "deadline < 5"}}; !reflect.DeepEqual(replaced, expected) {
+		t.Errorf("expected the labels added meanwhile to be kept in %v; got %v", expected, replaced)
+	}
+}
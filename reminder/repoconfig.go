@@ -0,0 +1,289 @@
+package reminder
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+
+	"github.com/src-d/github-reminder/notify"
+)
+
+// repoConfigPath is the file in the default branch of a repository with its
+// settings for the bot.
+const repoConfigPath = ".github/github-reminder.yml"
+
+// repoConfig is the configuration of a repository read from repoConfigPath,
+// replacing the defaults of the bot in that repository. The settings chosen
+// with /reminder still take precedence over it.
+// The same file in the .github repository of an organization sets the
+// defaults of all of its repositories.
+//
+//	disabled: false
+//	label_prefix: "due-in:"
+//	overdue_label: overdue
+//	cleanup_closed: true
+//	manual_labels: true
+//	follow_ups: ["72h:alice", "168h:org/leads"]
+//	label_gradient: true
+//	timezone: Europe/Madrid
+//	end_of_day: true
+//	language: es
+//	grammar: 2
+//	days: business
+//	keywords:
+//	  deadline: [due, eta]
+//	  reminder: [ping]
+//	notifications:
+//	  events: false
+//	  minimize: true
+//	  date_feedback: true
+//	  mention: assignees
+//	  digest_issue: true
+//	  digest_issue_only: false
+//	  discord: https://discord.com/api/webhooks/...
+//	templates:
+//	  reminder: "{{mention .Assignees}}, {{.Title}} is due in {{.DaysLeft}} days"
+//	  overdue: "{{mention .Assignees}}, {{.Title}} is overdue"
+//	  nag: "@{{.User}}, this {{.Label}} issue is due on {{date .Deadline}}"
+type repoConfig struct {
+	Disabled      bool   `yaml:"disabled"`
+	LabelPrefix   string `yaml:"label_prefix"`
+	OverdueLabel  string `yaml:"overdue_label"`
+	CleanupClosed *bool  `yaml:"cleanup_closed"`
+	ManualLabels  *bool  `yaml:"manual_labels"`
+	// FollowUps are the escalation chain of the overdue issues, written as
+	// in ParseFollowUp.
+	FollowUps     []string `yaml:"follow_ups"`
+	LabelGradient *bool    `yaml:"label_gradient"`
+	Timezone      string   `yaml:"timezone"`
+	EndOfDay      *bool    `yaml:"end_of_day"`
+	Language      string   `yaml:"language"`
+	Grammar       Grammar  `yaml:"grammar"`
+	Days          string   `yaml:"days"`
+	Keywords      struct {
+		Deadline []string `yaml:"deadline"`
+		Reminder []string `yaml:"reminder"`
+	} `yaml:"keywords"`
+	Notifications struct {
+		// Events can be set to false to stop sending the events of the
+		// repository to the notifier of the bot.
+		Events       *bool `yaml:"events"`
+		Minimize     *bool `yaml:"minimize"`
+		DateFeedback *bool `yaml:"date_feedback"`
+		// Mention is who the reminders written without users mention,
+		// assignees or author.
+		Mention string `yaml:"mention"`
+		// DigestIssue keeps a digest issue in the repository, and
+		// DigestIssueOnly stops the cadence reminders besides.
+		DigestIssue     *bool `yaml:"digest_issue"`
+		DigestIssueOnly *bool `yaml:"digest_issue_only"`
+		// Discord is a Discord webhook receiving the reminder and overdue
+		// events of the repository besides the notifiers of the bot.
+		Discord string `yaml:"discord"`
+	} `yaml:"notifications"`
+	Templates struct {
+		Reminder string `yaml:"reminder"`
+		Overdue  string `yaml:"overdue"`
+		Nag      string `yaml:"nag"`
+	} `yaml:"templates"`
+}
+
+// parseRepoConfig parses and checks the configuration of a repository.
+func parseRepoConfig(data []byte) (*repoConfig, error) {
+	var rc repoConfig
+	if err := yaml.UnmarshalStrict(data, &rc); err != nil {
+		return nil, errors.Wrap(err, "could not parse configuration")
+	}
+	if rc.Timezone != "" {
+		if _, err := ParseTimezone(rc.Timezone); err != nil {
+			return nil, err
+		}
+	}
+	if rc.Language != "" {
+		if _, err := ParseLanguage(rc.Language); err != nil {
+			return nil, err
+		}
+	}
+	if rc.Grammar < 0 || rc.Grammar > LatestGrammar {
+		return nil, errors.Errorf("unknown grammar version %d", rc.Grammar)
+	}
+	if rc.Days != "" && rc.Days != businessDays && rc.Days != calendarDays {
+		return nil, errors.Errorf("unknown days %q, expected business or calendar", rc.Days)
+	}
+	if _, err := parseSynonyms("deadline", rc.Keywords.Deadline); err != nil {
+		return nil, err
+	}
+	if _, err := parseSynonyms("reminder", rc.Keywords.Reminder); err != nil {
+		return nil, err
+	}
+	for _, f := range rc.FollowUps {
+		if _, err := ParseFollowUp(f); err != nil {
+			return nil, err
+		}
+	}
+	if m := rc.Notifications.Mention; m != "" && m != mentionAssignees && m != mentionAuthor {
+		return nil, errors.Errorf("unknown mention %q, expected assignees or author", m)
+	}
+	if rc.Notifications.Discord != "" {
+		if _, err := notify.NewDiscord(rc.Notifications.Discord); err != nil {
+			return nil, err
+		}
+	}
+	if err := ParseTemplates(Templates(rc.Templates)); err != nil {
+		return nil, err
+	}
+	return &rc, nil
+}
+
+// apply replaces the options with the ones set in the configuration.
+func (rc *repoConfig) apply(o *options) {
+	o.disabled = rc.Disabled
+	if rc.LabelPrefix != "" {
+		WithLabelPrefix(rc.LabelPrefix)(o)
+	}
+	if rc.OverdueLabel != "" {
+		WithOverdueLabel(rc.OverdueLabel)(o)
+	}
+	if rc.CleanupClosed != nil {
+		o.cleanupClosed = *rc.CleanupClosed
+	}
+	if rc.ManualLabels != nil {
+		o.manualLabels = *rc.ManualLabels
+	}
+	if len(rc.FollowUps) > 0 {
+		fs := make([]FollowUp, len(rc.FollowUps))
+		for i, f := range rc.FollowUps {
+			fs[i], _ = ParseFollowUp(f)
+		}
+		WithFollowUps(fs...)(o)
+	}
+	if rc.LabelGradient != nil {
+		switch {
+		case !*rc.LabelGradient:
+			o.gradient = nil
+		case o.gradient == nil:
+			WithLabelGradient(DefaultUrgencyColors)(o)
+		}
+	}
+	if t := rc.Templates; t.Reminder != "" {
+		o.templates.Reminder = t.Reminder
+	}
+	if t := rc.Templates; t.Overdue != "" {
+		o.templates.Overdue = t.Overdue
+	}
+	if t := rc.Templates; t.Nag != "" {
+		o.templates.Nag = t.Nag
+	}
+	if rc.Timezone != "" {
+		o.location, _ = ParseTimezone(rc.Timezone)
+	}
+	if rc.EndOfDay != nil {
+		o.endOfDay = *rc.EndOfDay
+	}
+	if rc.Language != "" {
+		o.language, _ = ParseLanguage(rc.Language)
+	}
+	if rc.Grammar != 0 {
+		o.grammar = rc.Grammar
+	}
+	if rc.Days != "" {
+		o.businessDays = rc.Days == businessDays
+	}
+	if len(rc.Keywords.Deadline) > 0 {
+		o.deadlineSynonyms, _ = parseSynonyms("deadline", rc.Keywords.Deadline)
+	}
+	if len(rc.Keywords.Reminder) > 0 {
+		o.reminderSynonyms, _ = parseSynonyms("reminder", rc.Keywords.Reminder)
+	}
+	if n := rc.Notifications; n.Events != nil && !*n.Events {
+		o.notifier, o.deadlineNotifier = nil, nil
+	}
+	if n := rc.Notifications; n.Discord != "" {
+		d, _ := notify.NewDiscord(n.Discord)
+		if o.notifier == nil {
+			o.notifier = d
+		} else {
+			o.notifier = notify.Multi{o.notifier, d}
+		}
+	}
+	if n := rc.Notifications; n.Minimize != nil {
+		o.minimize = *n.Minimize
+	}
+	if n := rc.Notifications; n.DateFeedback != nil {
+		o.dateFeedback = *n.DateFeedback
+	}
+	if n := rc.Notifications; n.DigestIssue != nil || n.DigestIssueOnly != nil {
+		var d DigestIssue
+		if o.digestIssue != nil {
+			d = *o.digestIssue
+		}
+		if n.DigestIssueOnly != nil {
+			d.Only = *n.DigestIssueOnly
+		}
+		switch {
+		case n.DigestIssue != nil && !*n.DigestIssue:
+			o.digestIssue = nil
+		case n.DigestIssue != nil || o.digestIssue != nil:
+			WithDigestIssue(d)(o)
+		}
+	}
+	if n := rc.Notifications; n.Mention != "" {
+		o.mentionAssignees = n.Mention == mentionAssignees
+	}
+}
+
+// orgConfigRepo is the repository of an organization with the defaults of all
+// of its repositories, which their own configuration files can override.
+const orgConfigRepo = ".github"
+
+// forRepo returns a client for the given repository, with the options changed
+// by the templates and rules imported into the installation, by the
+// configuration file of its organization, in its .github repository, and then
+// by its own, if any. A configuration that can't be read is reported and
+// ignored, so it doesn't stop the bot from handling the rest of the
+// installation.
+func (c *InstallationClient) forRepo(ctx context.Context, owner, repo string) (*InstallationClient, error) {
+	c, err := c.withInstallationConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var rc repoConfig
+	found, err := c.readConfig(ctx, owner, orgConfigRepo, &rc)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(repo, orgConfigRepo) {
+		ok, err := c.readConfig(ctx, owner, repo, &rc)
+		if err != nil {
+			return nil, err
+		}
+		found = found || ok
+	}
+	if !found {
+		return c, nil
+	}
+	rcc := *c
+	rc.apply(&rcc.opts)
+	return &rcc, nil
+}
+
+// readConfig reads the configuration file of a repository into rc, replacing
+// only the settings it has, and reports whether there was a valid one.
+func (c *InstallationClient) readConfig(ctx context.Context, owner, repo string, rc *repoConfig) (bool, error) {
+	data, err := c.client.file(ctx, owner, repo, repoConfigPath)
+	if err != nil {
+		return false, errors.Wrapf(err, "could not fetch configuration of %s/%s", owner, repo)
+	}
+	if data == nil {
+		return false, nil
+	}
+	if _, err := parseRepoConfig(data); err != nil {
+		logrus.Warnf("ignoring %s in %s/%s: %v", repoConfigPath, owner, repo, err)
+		return false, nil
+	}
+	return true, errors.Wrap(yaml.Unmarshal(data, rc), "could not parse configuration")
+}
@@ -0,0 +1,153 @@
+package reminder
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/src-d/github-reminder/notify"
+)
+
+func TestRepoConfig(t *testing.T) {
+	ctx := context.Background()
+	config := `
+label_prefix: "due-in:"
+timezone: Europe/Madrid
+keywords:
+  deadline: [due]
+notifications:
+  events: false
+`
+	fc := &fakeClient{
+		_file: func(ctx context.Context, owner, repo, path string) ([]byte, error) {
+			if repo != "bar" || path != ".github/github-reminder.yml" {
+				return nil, nil
+			}
+			return []byte(config), nil
+		},
+		_permission: func(ctx context.Context, owner, repo, user string) (string, error) { return "admin", nil },
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) {
+			return []string{"deadline < 30", "due-in: 5"}, nil
+		},
+		_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+			return &issue{repo: repository{owner, repo}, number: number, state: "open", body: "due: 2030-08-01"}, nil
+		},
+		_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error { return nil },
+	}
+	ic := &InstallationClient{appID: 42, installationID: 43, client: fc,
+		opts: newOptions([]Option{WithNotifier(notify.Multi{})})}
+
+	if rc, err := ic.forRepo(ctx, "foo", "other"); err != nil || rc != ic {
+		t.Errorf("expected the same client without a configuration file; got %v (%v)", rc, err)
+	}
+
+	rc, err := ic.forRepo(ctx, "foo", "bar")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ls, err := rc.LabelsInRepo(ctx, "foo", "bar"); err != nil || !reflect.DeepEqual(ls, []Label{{Name: "due-in: 5", Days: 5}}) {
+		t.Errorf("expected the labels with the prefix of the configuration; got %v (%v)", ls, err)
+	}
+	if rc.opts.notifier != nil || ic.opts.notifier == nil {
+		t.Errorf("expected the notifier to be removed only for the repository")
+	}
+
+	is, _ := fc.issue(ctx, "foo", "bar", 1)
+	if err := rc.readSettings(ctx, is); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	madrid, _ := time.LoadLocation("Europe/Madrid")
+	if d, err := rc.deadline(ctx, is); err != nil || !d.Equal(time.Date(2030, 8, 1, 0, 0, 0, 0, madrid)) {
+		t.Errorf("expected the deadline to be read with the keywords and timezone of the configuration; got %v (%v)", d, err)
+	}
+
+	// the settings chosen with /reminder take precedence over the file.
+	if _, err := ic.HandleComment(ctx, "foo", "bar", 1, "admin", "/reminder labels deadline <"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ls, err := rc.LabelsInRepo(ctx, "foo", "bar"); err != nil || !reflect.DeepEqual(ls, []Label{{Name: "deadline < 30", Days: 30}}) {
+		t.Errorf("expected the labels with the prefix chosen with /reminder; got %v (%v)", ls, err)
+	}
+
+	config = "disabled: true"
+	fc._issue = func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+		t.Fatalf("unexpected issue fetched in a disabled repository")
+		return nil, nil
+	}
+	if err := ic.UpdateIssue(ctx, "foo", "bar", 1); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	for _, bad := range []string{"timezone: Mars/Olympus", "days: weekly", "unknown: true", "keywords: {deadline: [due!]}",
+		"notifications: {discord: \"http://localhost/hook\"}", "templates: {reminder: \"{{.User\"}", "notifications: {mention: everyone}"} {
+		config = bad
+		if rc, err := ic.forRepo(ctx, "foo", "bar"); err != nil || rc != ic {
+			t.Errorf("expected %q to be ignored; got %v (%v)", bad, rc, err)
+		}
+	}
+}
+
+func TestOrgConfig(t *testing.T) {
+	ctx := context.Background()
+	files := map[string]string{
+		".github": "disabled: true\nlabel_prefix: \"due-in:\"\ntimezone: Europe/Madrid\nnotifications: {events: false, minimize: true}\n",
+		"bar":     "disabled: false\ntimezone: America/New_York\nnotifications: {events: true}\n",
+	}
+	fc := &fakeClient{
+		_file: func(ctx context.Context, owner, repo, path string) ([]byte, error) {
+			if data, ok := files[repo]; ok && owner == "foo" && path == ".github/github-reminder.yml" {
+				return []byte(data), nil
+			}
+			return nil, nil
+		},
+	}
+	ic := &InstallationClient{appID: 42, installationID: 43, client: fc,
+		opts: newOptions([]Option{WithNotifier(notify.Multi{})})}
+
+	rc, err := ic.forRepo(ctx, "foo", "other")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !rc.opts.disabled || rc.opts.labelPrefix != "due-in:" || rc.opts.location.String() != "Europe/Madrid" ||
+		rc.opts.notifier != nil || !rc.opts.minimize {
+		t.Errorf("expected the settings of the organization; got %+v", rc.opts)
+	}
+
+	rc, err = ic.forRepo(ctx, "foo", "bar")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rc.opts.disabled || rc.opts.labelPrefix != "due-in:" || rc.opts.location.String() != "America/New_York" ||
+		rc.opts.notifier == nil || !rc.opts.minimize {
+		t.Errorf("expected the settings of the repository over the ones of the organization; got %+v", rc.opts)
+	}
+
+	files[".github"] = "timezone: Mars/Olympus"
+	rc, err = ic.forRepo(ctx, "foo", "bar")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rc.opts.location.String() != "America/New_York" {
+		t.Errorf("expected a bad configuration of the organization to be ignored; got %v", rc.opts.location)
+	}
+}
+
+func TestRepoDiscord(t *testing.T) {
+	rc, err := parseRepoConfig([]byte("notifications: {discord: \"https://discord.com/api/webhooks/1/abc\"}"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	o := newOptions(nil)
+	rc.apply(&o)
+	if d, ok := o.notifier.(*notify.Discord); !ok || d.WebhookURL != "https://discord.com/api/webhooks/1/abc" {
+		t.Errorf("expected the Discord webhook of the repository; got %#v", o.notifier)
+	}
+
+	o = newOptions([]Option{WithNotifier(notify.Log)})
+	rc.apply(&o)
+	if m, ok := o.notifier.(notify.Multi); !ok || len(m) != 2 {
+		t.Errorf("expected the Discord webhook besides the notifier of the bot; got %#v", o.notifier)
+	}
+}
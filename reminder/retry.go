@@ -0,0 +1,347 @@
+package reminder
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/github"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Retry is how the calls to the GitHub API failing with transient errors,
+// like server errors or secondary rate limits, are retried.
+type Retry struct {
+	// Attempts is the number of times a call is tried, 1 to never retry it.
+	Attempts int
+	// Backoff is the wait before the first retry, doubled before each of the
+	// rest, with a random jitter of up to half of it either way.
+	Backoff time.Duration
+}
+
+// DefaultRetry tries the calls three times, waiting about a second before the
+// first retry and two before the second one.
+var DefaultRetry = Retry{Attempts: 3, Backoff: time.Second}
+
+// WithRetry sets how the calls failing with transient errors are retried.
+// DefaultRetry is used by default.
+func WithRetry(r Retry) Option {
+	return func(o *options) { o.retry = r }
+}
+
+// alreadyExists checks whether err is GitHub refusing to create something
+// because it already exists.
+func alreadyExists(err error) bool {
+	e, ok := errors.Cause(err).(*github.ErrorResponse)
+	if !ok || e.Response == nil || e.Response.StatusCode != http.StatusUnprocessableEntity {
+		return false
+	}
+	for _, e := range e.Errors {
+		if e.Code == "already_exists" {
+			return true
+		}
+	}
+	return false
+}
+
+// serverError checks whether err is GitHub failing to process a request with a
+// server error, returning its response.
+func serverError(err error) (*http.Response, bool) {
+	e, ok := errors.Cause(err).(*github.ErrorResponse)
+	if !ok || e.Response == nil || e.Response.StatusCode < http.StatusInternalServerError {
+		return nil, false
+	}
+	return e.Response, true
+}
+
+// retryClient retries the calls failing with server errors, so a single flaky
+// call doesn't abort a whole scan. The comments, issues, check runs, and
+// project cards are only retried when GitHub refused them, so they're never
+// posted twice, and the labels created by a failed attempt count as created
+// by the retries. Rate
+// limits pause every call of the client for as long as GitHub asks, without
+// counting as attempts.
+type retryClient struct {
+	client
+	retry  Retry
+	limits RateLimitPolicy
+
+	mu     sync.Mutex
+	resume time.Time
+}
+
+// do calls f until it succeeds, fails with an error that is not transient, or
+// runs out of attempts, waiting between the attempts as long as asked by
+// GitHub, if it did, or backing off otherwise.
+func (c *retryClient) do(ctx context.Context, idempotent bool, f func() error) error {
+	wait, pauses := c.retry.Backoff, 0
+	for attempt := 1; ; attempt++ {
+		if err := c.waitResume(ctx); err != nil {
+			return err
+		}
+		err := f()
+		if err == nil {
+			return nil
+		}
+		if d, ok := rateLimit(err); ok {
+			// the pause is kept even if the call gives up on it, so the
+			// rest of the calls of the client give up too without trying.
+			c.pause(d)
+			if pauses >= maxPauses || d > c.limits.MaxWait {
+				return err
+			}
+			logrus.Warnf("rate limited, pausing calls to GitHub for %v: %v", d, err)
+			pauses++
+			attempt--
+			continue
+		}
+		res, ok := serverError(err)
+		if !ok || !idempotent || attempt >= c.retry.Attempts {
+			return err
+		}
+
+		d, asked := retryAfter(res.Header)
+		if asked && d > c.limits.MaxWait {
+			return err
+		}
+		if !asked {
+			d = wait
+			if wait > 0 {
+				d = wait/2 + time.Duration(rand.Int63n(int64(wait)))
+			}
+		}
+		logrus.Warnf("retrying call to GitHub in %v: %v", d, err)
+		select {
+		case <-time.After(d):
+		case <-ctx.Done():
+			return err
+		}
+		wait *= 2
+	}
+}
+
+func (c *retryClient) installations(ctx context.Context) ([]int, error) {
+	var res []int
+	err := c.do(ctx, true, func() (err error) {
+		res, err = c.client.installations(ctx)
+		return err
+	})
+	return res, err
+}
+
+func (c *retryClient) repoInstallation(ctx context.Context, owner, repo string) (int, error) {
+	var res int
+	err := c.do(ctx, true, func() (err error) {
+		res, err = c.client.repoInstallation(ctx, owner, repo)
+		return err
+	})
+	return res, err
+}
+
+func (c *retryClient) repos(ctx context.Context) ([]repository, error) {
+	var res []repository
+	err := c.do(ctx, true, func() (err error) {
+		res, err = c.client.repos(ctx)
+		return err
+	})
+	return res, err
+}
+
+func (c *retryClient) repoLabels(ctx context.Context, owner, repo string) ([]string, error) {
+	var res []string
+	err := c.do(ctx, true, func() (err error) {
+		res, err = c.client.repoLabels(ctx, owner, repo)
+		return err
+	})
+	return res, err
+}
+
+func (c *retryClient) labelColors(ctx context.Context, owner, repo string) (map[string]string, error) {
+	var res map[string]string
+	err := c.do(ctx, true, func() (err error) {
+		res, err = c.client.labelColors(ctx, owner, repo)
+		return err
+	})
+	return res, err
+}
+
+func (c *retryClient) issues(ctx context.Context, owner, repo string) ([]int, error) {
+	var res []int
+	err := c.do(ctx, true, func() (err error) {
+		res, err = c.client.issues(ctx, owner, repo)
+		return err
+	})
+	return res, err
+}
+
+func (c *retryClient) closedIssues(ctx context.Context, owner, repo string, page int) ([]int, int, error) {
+	var numbers []int
+	var next int
+	err := c.do(ctx, true, func() (err error) {
+		numbers, next, err = c.client.closedIssues(ctx, owner, repo, page)
+		return err
+	})
+	return numbers, next, err
+}
+
+func (c *retryClient) issue(ctx context.Context, owner, repo string, number int) (*issue, error) {
+	var res *issue
+	err := c.do(ctx, true, func() (err error) {
+		res, err = c.client.issue(ctx, owner, repo, number)
+		return err
+	})
+	return res, err
+}
+
+func (c *retryClient) createIssueComment(ctx context.Context, owner, repo string, number int, body string) error {
+	return c.do(ctx, false, func() error { return c.client.createIssueComment(ctx, owner, repo, number, body) })
+}
+
+func (c *retryClient) createIssue(ctx context.Context, owner, repo, title, body string) (int, error) {
+	var res int
+	err := c.do(ctx, false, func() (err error) {
+		res, err = c.client.createIssue(ctx, owner, repo, title, body)
+		return err
+	})
+	return res, err
+}
+
+func (c *retryClient) editIssueBody(ctx context.Context, owner, repo string, number int, body string) error {
+	return c.do(ctx, true, func() error { return c.client.editIssueBody(ctx, owner, repo, number, body) })
+}
+
+func (c *retryClient) pinIssue(ctx context.Context, owner, repo string, number int) error {
+	return c.do(ctx, true, func() error { return c.client.pinIssue(ctx, owner, repo, number) })
+}
+
+func (c *retryClient) headSHA(ctx context.Context, owner, repo string, number int) (string, error) {
+	var res string
+	err := c.do(ctx, true, func() (err error) {
+		res, err = c.client.headSHA(ctx, owner, repo, number)
+		return err
+	})
+	return res, err
+}
+
+func (c *retryClient) createCheckRun(ctx context.Context, owner, repo string, run checkRun) error {
+	return c.do(ctx, false, func() error { return c.client.createCheckRun(ctx, owner, repo, run) })
+}
+
+func (c *retryClient) createStatus(ctx context.Context, owner, repo string, status commitStatus) error {
+	return c.do(ctx, true, func() error { return c.client.createStatus(ctx, owner, repo, status) })
+}
+
+func (c *retryClient) removeIssueLabel(ctx context.Context, owner, repo string, number int, label string) error {
+	return c.do(ctx, true, func() error { return c.client.removeIssueLabel(ctx, owner, repo, number, label) })
+}
+
+func (c *retryClient) addIssueLabel(ctx context.Context, owner, repo string, number int, label string) error {
+	return c.do(ctx, true, func() error { return c.client.addIssueLabel(ctx, owner, repo, number, label) })
+}
+
+func (c *retryClient) replaceIssueLabels(ctx context.Context, owner, repo string, number int, labels []string) error {
+	return c.do(ctx, true, func() error { return c.client.replaceIssueLabels(ctx, owner, repo, number, labels) })
+}
+
+func (c *retryClient) editLabelColor(ctx context.Context, owner, repo, label, color string) error {
+	return c.do(ctx, true, func() error { return c.client.editLabelColor(ctx, owner, repo, label, color) })
+}
+
+func (c *retryClient) createLabel(ctx context.Context, owner, repo, label, color string) error {
+	retried := false
+	return c.do(ctx, true, func() error {
+		err := c.client.createLabel(ctx, owner, repo, label, color)
+		if retried && alreadyExists(err) {
+			return nil
+		}
+		retried = true
+		return err
+	})
+}
+
+func (c *retryClient) minimizeComment(ctx context.Context, owner, repo string, id int64) error {
+	return c.do(ctx, true, func() error { return c.client.minimizeComment(ctx, owner, repo, id) })
+}
+
+func (c *retryClient) projectDate(ctx context.Context, owner, repo string, number int, field string) (time.Time, error) {
+	var res time.Time
+	err := c.do(ctx, true, func() (err error) {
+		res, err = c.client.projectDate(ctx, owner, repo, number, field)
+		return err
+	})
+	return res, err
+}
+
+func (c *retryClient) setProjectDate(ctx context.Context, owner, repo string, number int, field string, date time.Time) error {
+	return c.do(ctx, true, func() error { return c.client.setProjectDate(ctx, owner, repo, number, field, date) })
+}
+
+func (c *retryClient) projectItems(ctx context.Context, owner, repo string, number int) ([]string, error) {
+	var res []string
+	err := c.do(ctx, true, func() (err error) {
+		res, err = c.client.projectItems(ctx, owner, repo, number)
+		return err
+	})
+	return res, err
+}
+
+func (c *retryClient) addToProject(ctx context.Context, owner, repo string, number int, p Project) error {
+	return c.do(ctx, false, func() error { return c.client.addToProject(ctx, owner, repo, number, p) })
+}
+
+func (c *retryClient) moveProjectItem(ctx context.Context, owner, repo string, number int, p Project, field, column string) (bool, error) {
+	var res bool
+	err := c.do(ctx, true, func() (err error) {
+		res, err = c.client.moveProjectItem(ctx, owner, repo, number, p, field, column)
+		return err
+	})
+	return res, err
+}
+
+func (c *retryClient) permission(ctx context.Context, owner, repo, user string) (string, error) {
+	var res string
+	err := c.do(ctx, true, func() (err error) {
+		res, err = c.client.permission(ctx, owner, repo, user)
+		return err
+	})
+	return res, err
+}
+
+func (c *retryClient) files(ctx context.Context, owner, repo string, number int) ([]string, error) {
+	var res []string
+	err := c.do(ctx, true, func() (err error) {
+		res, err = c.client.files(ctx, owner, repo, number)
+		return err
+	})
+	return res, err
+}
+
+func (c *retryClient) file(ctx context.Context, owner, repo, path string) ([]byte, error) {
+	var res []byte
+	err := c.do(ctx, true, func() (err error) {
+		res, err = c.client.file(ctx, owner, repo, path)
+		return err
+	})
+	return res, err
+}
+
+func (c *retryClient) issueLabels(ctx context.Context, owner, repo string, number int) ([]string, error) {
+	var res []string
+	err := c.do(ctx, true, func() (err error) {
+		res, err = c.client.issueLabels(ctx, owner, repo, number)
+		return err
+	})
+	return res, err
+}
+
+func (c *retryClient) labelEvents(ctx context.Context, owner, repo string, number int) ([]labelEvent, error) {
+	var res []labelEvent
+	err := c.do(ctx, true, func() (err error) {
+		res, err = c.client.labelEvents(ctx, owner, repo, number)
+		return err
+	})
+	return res, err
+}
@@ -0,0 +1,208 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/github"
+)
+
+func githubError(code int) error {
+	return &github.ErrorResponse{Response: &http.Response{StatusCode: code, Request: &http.Request{}}}
+}
+
+func TestRetry(t *testing.T) {
+	var errs []error
+	calls := 0
+	next := func() error {
+		calls++
+		if len(errs) == 0 {
+			return nil
+		}
+		err := errs[0]
+		errs = errs[1:]
+		return err
+	}
+	c := &retryClient{retry: Retry{Attempts: 3}, client: &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) {
+			return []string{"deadline < 3"}, next()
+		},
+		_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error {
+			return next()
+		},
+		_createLabel: func(ctx context.Context, owner, repo, label, color string) error {
+			return next()
+		},
+		_addToProject: func(ctx context.Context, owner, repo string, number int, p Project) error {
+			return next()
+		},
+	}}
+	ctx := context.Background()
+	exists := &github.ErrorResponse{
+		Response: &http.Response{StatusCode: http.StatusUnprocessableEntity, Request: &http.Request{}},
+		Errors:   []github.Error{{Resource: "Label", Code: "already_exists"}},
+	}
+
+	tests := []struct {
+		name  string
+		errs  []error
+		call  func() error
+		calls int
+		fails bool
+	}{
+		{"server errors", []error{githubError(502), githubError(500)}, func() error {
+			_, err := c.repoLabels(ctx, "foo", "bar")
+			return err
+		}, 3, false},
+		{"too many server errors", []error{githubError(502), githubError(502), githubError(502)}, func() error {
+			_, err := c.repoLabels(ctx, "foo", "bar")
+			return err
+		}, 3, true},
+		{"not found", []error{githubError(404)}, func() error {
+			_, err := c.repoLabels(ctx, "foo", "bar")
+			return err
+		}, 1, true},
+		{"other errors", []error{errors.New("boom")}, func() error {
+			_, err := c.repoLabels(ctx, "foo", "bar")
+			return err
+		}, 1, true},
+		{"comment on server error", []error{githubError(502)}, func() error {
+			return c.createIssueComment(ctx, "foo", "bar", 1, "hi")
+		}, 1, true},
+		{"label created by a failed attempt", []error{githubError(502), exists}, func() error {
+			return c.createLabel(ctx, "foo", "bar", "deadline < 3", "ff0000")
+		}, 2, false},
+		{"existing label", []error{exists}, func() error {
+			return c.createLabel(ctx, "foo", "bar", "deadline < 3", "ff0000")
+		}, 1, true},
+		{"project card on server error", []error{githubError(502)}, func() error {
+			return c.addToProject(ctx, "foo", "bar", 1, Project{})
+		}, 1, true},
+		{"comment on secondary rate limit", []error{&github.AbuseRateLimitError{Response: &http.Response{Request: &http.Request{}}, RetryAfter: new(time.Duration)}}, func() error {
+			return c.createIssueComment(ctx, "foo", "bar", 1, "hi")
+		}, 2, false},
+	}
+	for _, tt := range tests {
+		errs, calls = tt.errs, 0
+		err := tt.call()
+		if (err != nil) != tt.fails || calls != tt.calls {
+			t.Errorf("%s: expected %d calls and failure %v; got %d calls and %v", tt.name, tt.calls, tt.fails, calls, err)
+		}
+	}
+}
+
+func TestSecondaryRateLimit(t *testing.T) {
+	res := &http.Response{StatusCode: http.StatusForbidden, Header: http.Header{"Retry-After": {"30"}}, Request: &http.Request{}}
+	err := &github.ErrorResponse{Response: res, Message: "You have exceeded a secondary rate limit. Please wait a few minutes before you try again.",
+		DocumentationURL: "https://docs.github.com/rest/overview/rate-limits-for-the-rest-api#about-secondary-rate-limits"}
+	if d, ok := rateLimit(err); !ok || d != 30*time.Second {
+		t.Errorf("expected a secondary rate limit of 30s; got %v (%v)", d, ok)
+	}
+	if isForbidden(err) {
+		t.Errorf("expected secondary rate limits not to be read as forbidden")
+	}
+	if _, ok := rateLimit(githubError(http.StatusForbidden)); ok {
+		t.Errorf("expected other forbidden errors not to be secondary rate limits")
+	}
+
+	calls := 0
+	c := &retryClient{retry: Retry{Attempts: 1}, limits: DefaultRateLimitPolicy, client: &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) {
+			calls++
+			if calls == 1 {
+				return nil, err
+			}
+			return nil, nil
+		},
+	}}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := c.repoLabels(ctx, "foo", "bar"); err == nil || calls != 1 {
+		t.Errorf("expected the call to fail right away when paused beyond the deadline; got %d calls (%v)", calls, err)
+	}
+	if _, err := c.repoLabels(ctx, "foo", "bar"); err == nil || calls != 1 {
+		t.Errorf("expected the rest of the calls to be paused too; got %d calls (%v)", calls, err)
+	}
+
+	c.resume = time.Now().Add(10 * time.Millisecond)
+	if _, err := c.repoLabels(context.Background(), "foo", "bar"); err != nil || calls != 2 {
+		t.Errorf("expected the calls to resume; got %d calls (%v)", calls, err)
+	}
+}
+
+func TestRateLimits(t *testing.T) {
+	reset := time.Now().Add(10 * time.Minute)
+	primary := &http.Response{StatusCode: http.StatusForbidden, Request: &http.Request{}, Header: http.Header{
+		"X-Ratelimit-Remaining": {"0"},
+		"X-Ratelimit-Reset":     {strconv.FormatInt(reset.Unix(), 10)},
+	}}
+	tests := []struct {
+		name string
+		err  error
+		min  time.Duration
+		ok   bool
+	}{
+		{"primary", &github.RateLimitError{Rate: github.Rate{Reset: github.Timestamp{Time: reset}}, Response: primary}, 9 * time.Minute, true},
+		{"primary headers", &github.ErrorResponse{Response: primary, Message: "rate limit exceeded"}, 9 * time.Minute, true},
+		{"retry after", &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusTooManyRequests,
+			Header: http.Header{"Retry-After": {"120"}}, Request: &http.Request{}}}, 2 * time.Minute, true},
+		{"secondary", &github.AbuseRateLimitError{}, time.Minute, true},
+		{"forbidden", githubError(http.StatusForbidden), 0, false},
+		{"server error", githubError(http.StatusBadGateway), 0, false},
+	}
+	for _, tt := range tests {
+		d, ok := rateLimit(tt.err)
+		if ok != tt.ok || d < tt.min || d > tt.min+2*time.Minute {
+			t.Errorf("%s: expected a wait of about %v (%v); got %v (%v)", tt.name, tt.min, tt.ok, d, ok)
+		}
+	}
+
+	calls := 0
+	c := &retryClient{retry: Retry{Attempts: 3}, limits: RateLimitPolicy{MaxWait: time.Minute}, client: &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) {
+			calls++
+			return nil, tests[0].err
+		},
+	}}
+	start := time.Now()
+	if _, err := c.repoLabels(context.Background(), "foo", "bar"); err == nil || calls != 1 || time.Since(start) > time.Second {
+		t.Errorf("expected waits longer than the policy allows to fail right away; got %d calls (%v)", calls, err)
+	}
+	if _, err := c.repoLabels(context.Background(), "foo", "bar"); err == nil || calls != 1 || time.Since(start) > time.Second {
+		t.Errorf("expected the next calls to fail right away without trying; got %d calls (%v)", calls, err)
+	}
+	c.resume = time.Time{}
+
+	calls = 0
+	c.client = &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) {
+			calls++
+			if calls == 1 {
+				res := &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{"Retry-After": {"0"}}, Request: &http.Request{}}
+				return nil, &github.ErrorResponse{Response: res}
+			}
+			return nil, nil
+		},
+	}
+	c.retry.Backoff = time.Hour
+	if _, err := c.repoLabels(context.Background(), "foo", "bar"); err != nil || calls != 2 {
+		t.Errorf("expected the server error to be retried as soon as asked; got %d calls (%v)", calls, err)
+	}
+
+	calls = 0
+	c.client = &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) {
+			calls++
+			res := &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{"Retry-After": {"3600"}}, Request: &http.Request{}}
+			return nil, &github.ErrorResponse{Response: res}
+		},
+	}
+	start = time.Now()
+	if _, err := c.repoLabels(context.Background(), "foo", "bar"); err == nil || calls != 1 || time.Since(start) > time.Second {
+		t.Errorf("expected server errors asking to wait longer than the policy allows to fail right away; got %d calls (%v)", calls, err)
+	}
+}
@@ -0,0 +1,127 @@
+package reminder
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+// A ScheduledReminder is the next reminder found in an issue, so it can be
+// sent at the right time instead of waiting for the next scan.
+type ScheduledReminder struct {
+	AppID          int       `json:"app_id"`
+	InstallationID int       `json:"installation_id"`
+	Owner          string    `json:"owner"`
+	Repo           string    `json:"repo"`
+	Number         int       `json:"number"`
+	At             time.Time `json:"at"`
+}
+
+func scheduleKey(appID, installationID int, owner, repo string, number int) string {
+	return storage.Key("schedule", appID, installationID, owner, repo, number)
+}
+
+// dueLayout formats the due times in the keys of the reminders by due time,
+// so their lexicographical order is the order of the times.
+const dueLayout = "20060102T150405Z"
+
+// dueKey returns the key of a scheduled reminder in the index of the
+// reminders of an app by due time.
+func dueKey(r ScheduledReminder) string {
+	return storage.Key("scheduledue", r.AppID, r.At.UTC().Format(dueLayout), r.InstallationID, r.Owner, r.Repo, r.Number)
+}
+
+// schedule stores the next reminder of an issue, or forgets it if zero.
+func (c *InstallationClient) schedule(ctx context.Context, issue *issue, at time.Time) error {
+	key := scheduleKey(c.appID, c.installationID, issue.repo.owner, issue.repo.name, issue.number)
+	var old ScheduledReminder
+	err := c.opts.store.Get(ctx, key, &old)
+	if err != nil && err != storage.ErrNotFound {
+		return errors.Wrap(err, "could not fetch scheduled reminder")
+	}
+	if err == nil && !old.At.Equal(at) {
+		if err := c.opts.store.Delete(ctx, dueKey(old)); err != nil {
+			return errors.Wrap(err, "could not unschedule reminder")
+		}
+	}
+	if at.IsZero() {
+		return errors.Wrap(c.opts.store.Delete(ctx, key), "could not unschedule reminder")
+	}
+
+	r := ScheduledReminder{
+		AppID:          c.appID,
+		InstallationID: c.installationID,
+		Owner:          issue.repo.owner,
+		Repo:           issue.repo.name,
+		Number:         issue.number,
+		At:             at,
+	}
+	// the index is written even if the time didn't change, since the state
+	// of moved issues is moved without it.
+	if err := c.opts.store.Put(ctx, dueKey(r), r); err != nil {
+		return errors.Wrap(err, "could not schedule reminder")
+	}
+	return errors.Wrap(c.opts.store.Put(ctx, key, r), "could not schedule reminder")
+}
+
+// DueReminders lists the scheduled reminders of an app due at the given time,
+// sorted by time. Only the due ones are fetched, from the index by due time.
+func DueReminders(ctx context.Context, store storage.Store, appID int, now time.Time) ([]ScheduledReminder, error) {
+	prefix := storage.Key("scheduledue", appID) + "/"
+	keys, err := store.List(ctx, prefix)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list scheduled reminders")
+	}
+
+	var res []ScheduledReminder
+	for _, key := range keys {
+		at, err := time.Parse(dueLayout, strings.SplitN(strings.TrimPrefix(key, prefix), "/", 2)[0])
+		if err != nil {
+			logrus.Warnf("skipping scheduled reminder %s: %v", key, err)
+			continue
+		}
+		if at.After(now) {
+			break
+		}
+		var r ScheduledReminder
+		if err := store.Get(ctx, key, &r); err != nil {
+			return nil, errors.Wrapf(err, "could not fetch scheduled reminder %s", key)
+		}
+		res = append(res, r)
+	}
+	return res, nil
+}
+
+// FireReminder sends a scheduled reminder by updating its issue, which
+// also schedules the following reminder of the issue if any. The reminder is
+// only unscheduled once the issue is updated, so a failed one is sent later.
+func (c *InstallationClient) FireReminder(ctx context.Context, r ScheduledReminder) error {
+	var current ScheduledReminder
+	err := c.opts.store.Get(ctx, scheduleKey(c.appID, c.installationID, r.Owner, r.Repo, r.Number), &current)
+	if err != nil && err != storage.ErrNotFound {
+		return errors.Wrap(err, "could not fetch scheduled reminder")
+	}
+	if err == storage.ErrNotFound || !current.At.Equal(r.At) {
+		// the issue was rescheduled or moved since it was indexed.
+		return errors.Wrap(c.opts.store.Delete(ctx, dueKey(r)), "could not unschedule reminder")
+	}
+
+	if err := c.UpdateIssue(ctx, r.Owner, r.Repo, r.Number); err != nil {
+		return err
+	}
+	// the update reschedules the issue, unless it was skipped.
+	err = c.opts.store.Get(ctx, scheduleKey(c.appID, c.installationID, r.Owner, r.Repo, r.Number), &current)
+	if err == nil && current.At.Equal(r.At) {
+		issue := &issue{repo: repository{r.Owner, r.Repo}, number: r.Number}
+		return c.schedule(ctx, issue, time.Time{})
+	}
+	if err != nil && err != storage.ErrNotFound {
+		return errors.Wrap(err, "could not fetch scheduled reminder")
+	}
+	return nil
+}
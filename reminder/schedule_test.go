@@ -0,0 +1,70 @@
+package reminder
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestScheduleReminders(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	soon := now.Add(2 * time.Minute).Truncate(time.Minute)
+	later := now.AddDate(0, 0, 3)
+
+	var comments []string
+	i := &issue{repo: repository{"foo", "bar"}, number: 1, author: "francesc", state: "open",
+		body: "reminder: " + later.Format("2006-01-02") + "\nreminder: " + soon.Format("2006-01-02 15:04")}
+	fc := &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) { return nil, nil },
+		_issue:      func(ctx context.Context, owner, repo string, number int) (*issue, error) { return i, nil },
+		_files:      func(ctx context.Context, owner, repo string, number int) ([]string, error) { return nil, nil },
+		_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error {
+			comments = append(comments, body)
+			return nil
+		},
+	}
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil), client: fc}
+
+	if err := ic.UpdateIssue(ctx, "foo", "bar", 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(comments) != 0 {
+		t.Fatalf("expected no reminder yet; got %v", comments)
+	}
+	if due, _ := DueReminders(ctx, ic.opts.store, 42, now); len(due) != 0 {
+		t.Fatalf("expected no due reminders yet; got %v", due)
+	}
+
+	due, err := DueReminders(ctx, ic.opts.store, 42, soon)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(due) != 1 || !due[0].At.Equal(soon) || due[0].InstallationID != 43 {
+		t.Fatalf("expected reminder due at %v; got %+v", soon, due)
+	}
+	if due, _ := DueReminders(ctx, ic.opts.store, 7, soon); len(due) != 0 {
+		t.Errorf("expected no reminders for other apps; got %v", due)
+	}
+
+	// a reminder that can't be sent stays scheduled.
+	fc._issue = func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+		return nil, errors.New("server error")
+	}
+	if err := ic.FireReminder(ctx, due[0]); err == nil {
+		t.Fatalf("expected the failed update to be reported")
+	}
+	if due, _ := DueReminders(ctx, ic.opts.store, 42, soon); len(due) != 1 {
+		t.Fatalf("expected the failed reminder to stay scheduled; got %v", due)
+	}
+
+	fc._issue = func(ctx context.Context, owner, repo string, number int) (*issue, error) { return i, nil }
+	if err := ic.FireReminder(ctx, due[0]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if due, _ := DueReminders(ctx, ic.opts.store, 42, soon); len(due) != 0 {
+		t.Errorf("expected the sent reminder to be unscheduled; got %v", due)
+	}
+}
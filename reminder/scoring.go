@@ -0,0 +1,83 @@
+package reminder
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// An Issue describes an issue or pull request with a deadline.
+type Issue struct {
+	Owner     string
+	Repo      string
+	Number    int
+	Title     string
+	Body      string
+	Author    string
+	Labels    []string
+	Reactions int
+	Deadline  time.Time
+	// AllDay is set when the deadline was written without a time of day, so
+	// it lasts until the end of that day.
+	AllDay bool
+	// BusinessDays is set when the days left until the deadline are counted
+	// skipping weekends, as chosen with WithBusinessDays or /reminder days.
+	BusinessDays bool
+}
+
+func (i *issue) info(deadline time.Time) Issue {
+	return Issue{
+		Owner:     i.repo.owner,
+		Repo:      i.repo.name,
+		Number:    i.number,
+		Title:     i.title,
+		Body:      i.body,
+		Author:    i.author,
+		Labels:    i.labels,
+		Reactions: i.reactions,
+		Deadline:  deadline,
+	}
+}
+
+// A Scorer decides the urgency of an issue by choosing which of the deadline
+// labels of the repository, sorted by increasing duration, applies to it.
+type Scorer interface {
+	// Score returns the index of the label to apply, or -1 if none applies.
+	Score(ctx context.Context, issue Issue, labels []Label) int
+}
+
+// ScorerFunc allows using ordinary functions as a Scorer.
+type ScorerFunc func(ctx context.Context, issue Issue, labels []Label) int
+
+// Score calls f.
+func (f ScorerFunc) Score(ctx context.Context, issue Issue, labels []Label) int {
+	return f(ctx, issue, labels)
+}
+
+// DaysScorer is the default Scorer. It chooses the label with the smallest
+// duration larger than the time left until the deadline, and no label
+// once the deadline has passed, at the exact time given or at the end of the
+// day for deadlines without one. Only business days are counted if the issue
+// asks for them.
+var DaysScorer Scorer = ScorerFunc(daysScore)
+
+func daysScore(ctx context.Context, issue Issue, labels []Label) int {
+	days := daysUntil(time.Now(), issue.Deadline, issue.BusinessDays)
+	logrus.Debugf("issue #%d deadline in %v days", issue.Number, days)
+	if days < 0 && (!issue.AllDay || days <= -1) {
+		return -1
+	}
+
+	for i, l := range labels {
+		if l.Duration().Hours()/24 > days {
+			return i
+		}
+	}
+	return -1
+}
+
+// WithScorer replaces DaysScorer as the way to choose the label applied to an issue.
+func WithScorer(s Scorer) Option {
+	return func(o *options) { o.scorer = s }
+}
@@ -0,0 +1,139 @@
+package reminder
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDaysScorer(t *testing.T) {
+	labels := []Label{{Name: "deadline < 1", Days: 1}, {Name: "deadline < 5", Days: 5}, {Name: "deadline < 30", Days: 30}}
+	day := 24 * time.Hour
+	tests := []struct {
+		in       time.Duration
+		allDay   bool
+		expected int
+	}{
+		{-2 * day, false, -1},
+		{-2 * day, true, -1},
+		{-time.Hour, false, -1},
+		{-time.Hour, true, 0},
+		{3 * time.Hour, false, 0},
+		{day + time.Minute, false, 1},
+		{4*day + time.Hour, false, 1},
+		{5*day - time.Minute, false, 1},
+		{5*day + time.Minute, false, 2},
+		{10 * day, false, 2},
+		{40 * day, false, -1},
+	}
+
+	for _, tt := range tests {
+		got := DaysScorer.Score(context.Background(), Issue{Deadline: time.Now().Add(tt.in), AllDay: tt.allDay}, labels)
+		if got != tt.expected {
+			t.Errorf("deadline in %v (all day %v): expected label %d; got %d", tt.in, tt.allDay, tt.expected, got)
+		}
+	}
+}
+
+func TestCustomScorer(t *testing.T) {
+	var added []string
+	urgent := ScorerFunc(func(ctx context.Context, issue Issue, labels []Label) int {
+		if issue.Reactions > 10 {
+			return 0
+		}
+		return DaysScorer.Score(ctx, issue, labels)
+	})
+
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions([]Option{WithScorer(urgent)}), client: &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) {
+			return []string{"deadline < 5", "deadline < 30"}, nil
+		},
+		_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+			return &issue{
+				repo:      repository{owner, repo},
+				number:    number,
+				state:     "open",
+				reactions: 20,
+				body:      "deadline: " + time.Now().AddDate(0, 0, 20).Format("2006-01-02"),
+			}, nil
+		},
+		_removeIssueLabel: func(ctx context.Context, owner, repo string, number int, label string) error { return nil },
+		_addIssueLabel: func(ctx context.Context, owner, repo string, number int, label string) error {
+			added = append(added, label)
+			return nil
+		},
+	}}
+
+	if err := ic.UpdateIssue(context.Background(), "foo", "bar", 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(added) != 1 || added[0] != "deadline < 5" {
+		t.Errorf("expected popular issue to be labeled as urgent; got %v", added)
+	}
+}
+
+func TestHourLabels(t *testing.T) {
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil), client: &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) {
+			return []string{"deadline < 5", "deadline < 48H", "deadline < 1", "deadline < 12h", "deadline < 0h", "deadline < h"}, nil
+		},
+	}}
+	labels, err := ic.LabelsInRepo(context.Background(), "foo", "bar")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []Label{
+		{Name: "deadline < 12h", Hours: 12},
+		{Name: "deadline < 1", Days: 1},
+		{Name: "deadline < 48H", Hours: 48},
+		{Name: "deadline < 5", Days: 5},
+	}
+	if !reflect.DeepEqual(labels, expected) {
+		t.Fatalf("expected labels %v; got %v", expected, labels)
+	}
+
+	tests := []struct {
+		in       time.Duration
+		expected int
+	}{
+		{3 * time.Hour, 0},
+		{20 * time.Hour, 1},
+		{30 * time.Hour, 2},
+		{47 * time.Hour, 2},
+		{72 * time.Hour, 3},
+		{6 * 24 * time.Hour, -1},
+	}
+	for _, tt := range tests {
+		got := DaysScorer.Score(context.Background(), Issue{Deadline: time.Now().Add(tt.in)}, labels)
+		if got != tt.expected {
+			t.Errorf("deadline in %v: expected label %d; got %d", tt.in, tt.expected, got)
+		}
+	}
+}
+
+func TestLabelUnits(t *testing.T) {
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil), client: &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) {
+			return []string{"deadline < 1m", "deadline < 2w", "deadline < 10", "deadline < 3M", "deadline < 1y", "deadline < w", "deadline < 1 w"}, nil
+		},
+	}}
+	labels, err := ic.LabelsInRepo(context.Background(), "foo", "bar")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []Label{
+		{Name: "deadline < 1 w", Days: 7},
+		{Name: "deadline < 10", Days: 10},
+		{Name: "deadline < 2w", Days: 14},
+		{Name: "deadline < 1m", Days: 30},
+		{Name: "deadline < 3M", Days: 90},
+	}
+	if !reflect.DeepEqual(labels, expected) {
+		t.Errorf("expected labels %v; got %v", expected, labels)
+	}
+	deadline := time.Now().Add(20 * 24 * time.Hour)
+	if got := DaysScorer.Score(context.Background(), Issue{Deadline: deadline}, labels); got != 3 {
+		t.Errorf("expected deadline in 20 days to be labeled %v; got %d", labels[3], got)
+	}
+}
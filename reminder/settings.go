@@ -0,0 +1,394 @@
+package reminder
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+// Settings are the settings of an installation, exported to replicate them
+// into the installations of other organizations.
+type Settings struct {
+	// Labels are the days of the deadline labels, like 30 for "deadline < 30".
+	Labels []int `json:"labels" yaml:"labels"`
+	// HourLabels are the hours of the deadline labels like "deadline < 12h".
+	HourLabels []int `json:"hour_labels,omitempty" yaml:"hour_labels,omitempty"`
+	// Templates are the texts of the comments of the bot.
+	Templates Templates `json:"templates,omitempty" yaml:"templates,omitempty"`
+	// Rules are the reminders and escalations of the installation.
+	Rules Rules `json:"rules,omitempty" yaml:"rules,omitempty"`
+	// Repos are the settings of the repositories that changed any, by name.
+	Repos map[string]RepoSettings `json:"repos,omitempty" yaml:"repos,omitempty"`
+}
+
+// Rules are the cadences and follow-ups of an installation, written as parsed
+// by ParseCadence and ParseFollowUp.
+type Rules struct {
+	Cadences  []string `json:"cadences,omitempty" yaml:"cadences,omitempty"`
+	FollowUps []string `json:"follow_ups,omitempty" yaml:"follow_ups,omitempty"`
+}
+
+// installationConfig are the templates and rules imported into an
+// installation, replacing the defaults of the bot in all of its repositories
+// below their configuration files.
+type installationConfig struct {
+	Templates Templates `json:"templates"`
+	Rules     Rules     `json:"rules"`
+}
+
+func installationConfigKey(appID, installationID int) string {
+	return storage.Key("config", appID, installationID)
+}
+
+// apply replaces the options with the ones set in the configuration.
+func (ic installationConfig) apply(o *options) {
+	if t := ic.Templates; t.Reminder != "" {
+		o.templates.Reminder = t.Reminder
+	}
+	if t := ic.Templates; t.Overdue != "" {
+		o.templates.Overdue = t.Overdue
+	}
+	if t := ic.Templates; t.Nag != "" {
+		o.templates.Nag = t.Nag
+	}
+	if len(ic.Rules.Cadences) > 0 {
+		o.cadences = nil
+		for _, s := range ic.Rules.Cadences {
+			cd, _ := ParseCadence(s)
+			o.cadences = append(o.cadences, cd)
+		}
+	}
+	if len(ic.Rules.FollowUps) > 0 {
+		fs := make([]FollowUp, len(ic.Rules.FollowUps))
+		for i, s := range ic.Rules.FollowUps {
+			fs[i], _ = ParseFollowUp(s)
+		}
+		WithFollowUps(fs...)(o)
+	}
+}
+
+// withInstallationConfig returns a client with the options changed by the
+// templates and rules imported into the installation, if any.
+func (c *InstallationClient) withInstallationConfig(ctx context.Context) (*InstallationClient, error) {
+	var ic installationConfig
+	err := c.opts.store.Get(ctx, installationConfigKey(c.appID, c.installationID), &ic)
+	if err == storage.ErrNotFound {
+		return c, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch installation configuration")
+	}
+	icc := *c
+	ic.apply(&icc.opts)
+	return &icc, nil
+}
+
+// RepoSettings are the settings changed in a repository with /reminder.
+type RepoSettings struct {
+	Disabled bool    `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+	Grammar  Grammar `json:"grammar,omitempty" yaml:"grammar,omitempty"`
+	// Days is either "business" or "calendar" if chosen with /reminder days.
+	Days string `json:"days,omitempty" yaml:"days,omitempty"`
+	// Language is the code of the language chosen with /reminder language.
+	Language string `json:"language,omitempty" yaml:"language,omitempty"`
+	// DeadlineSynonyms and ReminderSynonyms are the synonyms of the keywords
+	// chosen with /reminder synonyms.
+	DeadlineSynonyms []string `json:"deadline_synonyms,omitempty" yaml:"deadline_synonyms,omitempty"`
+	ReminderSynonyms []string `json:"reminder_synonyms,omitempty" yaml:"reminder_synonyms,omitempty"`
+	// LabelPrefix is the prefix of the deadline labels chosen with /reminder labels.
+	LabelPrefix string `json:"label_prefix,omitempty" yaml:"label_prefix,omitempty"`
+}
+
+// Validate checks the settings can be imported.
+func (s Settings) Validate() error {
+	for _, d := range s.Labels {
+		if d <= 0 {
+			return errors.Errorf("bad number of days %d", d)
+		}
+	}
+	for _, h := range s.HourLabels {
+		if h <= 0 {
+			return errors.Errorf("bad number of hours %d", h)
+		}
+	}
+	for name, rs := range s.Repos {
+		if rs.Grammar < 0 || rs.Grammar > LatestGrammar {
+			return errors.Errorf("unknown grammar version %d for %s", rs.Grammar, name)
+		}
+		if rs.Days != "" && rs.Days != businessDays && rs.Days != calendarDays {
+			return errors.Errorf("unknown days %q for %s, expected business or calendar", rs.Days, name)
+		}
+		if rs.Language != "" {
+			if _, err := ParseLanguage(rs.Language); err != nil {
+				return errors.Wrapf(err, "bad settings for %s", name)
+			}
+		}
+		if _, err := parseSynonyms("deadline", rs.DeadlineSynonyms); err != nil {
+			return errors.Wrapf(err, "bad settings for %s", name)
+		}
+		if _, err := parseSynonyms("reminder", rs.ReminderSynonyms); err != nil {
+			return errors.Wrapf(err, "bad settings for %s", name)
+		}
+	}
+	if err := ParseTemplates(s.Templates); err != nil {
+		return err
+	}
+	for _, cd := range s.Rules.Cadences {
+		if _, err := ParseCadence(cd); err != nil {
+			return err
+		}
+	}
+	for _, f := range s.Rules.FollowUps {
+		if _, err := ParseFollowUp(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExportSettings returns the deadline labels found in any of the repositories
+// of the installation, its templates and rules, and the settings of each
+// repository.
+func (c *InstallationClient) ExportSettings(ctx context.Context) (Settings, error) {
+	repos, err := c.client.repos(ctx)
+	if err != nil {
+		return Settings{}, err
+	}
+	ic, err := c.withInstallationConfig(ctx)
+	if err != nil {
+		return Settings{}, err
+	}
+
+	s := Settings{Templates: ic.opts.templates, Repos: make(map[string]RepoSettings)}
+	for _, cd := range ic.opts.cadences {
+		s.Rules.Cadences = append(s.Rules.Cadences, cd.String())
+	}
+	for _, f := range ic.opts.followUps {
+		s.Rules.FollowUps = append(s.Rules.FollowUps, f.String())
+	}
+	days, hours := make(map[int]bool), make(map[int]bool)
+	for _, repo := range repos {
+		labels, err := c.LabelsInRepo(ctx, repo.owner, repo.name)
+		if err != nil {
+			return Settings{}, err
+		}
+		for _, l := range labels {
+			if l.Hours > 0 {
+				hours[l.Hours] = true
+			} else {
+				days[l.Days] = true
+			}
+		}
+
+		rs, err := c.repoSettings(ctx, repo.owner, repo.name)
+		if err != nil {
+			return Settings{}, err
+		}
+		if !reflect.DeepEqual(rs, RepoSettings{}) {
+			s.Repos[repo.name] = rs
+		}
+	}
+	for d := range days {
+		s.Labels = append(s.Labels, d)
+	}
+	for h := range hours {
+		s.HourLabels = append(s.HourLabels, h)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(s.Labels)))
+	sort.Sort(sort.Reverse(sort.IntSlice(s.HourLabels)))
+	return s, nil
+}
+
+func (c *InstallationClient) repoSettings(ctx context.Context, owner, repo string) (RepoSettings, error) {
+	var rs RepoSettings
+	disabled, err := c.Disabled(ctx, owner, repo)
+	if err != nil {
+		return rs, err
+	}
+	rs.Disabled = disabled
+
+	var rg repoGrammar
+	err = c.opts.store.Get(ctx, grammarKey(c.appID, c.installationID, owner, repo), &rg)
+	if err != nil && err != storage.ErrNotFound {
+		return rs, errors.Wrapf(err, "could not fetch grammar of %s/%s", owner, repo)
+	}
+	rs.Grammar = rg.Version
+
+	var rd repoDays
+	err = c.opts.store.Get(ctx, daysKey(c.appID, c.installationID, owner, repo), &rd)
+	if err == nil {
+		rs.Days = calendarDays
+		if rd.Business {
+			rs.Days = businessDays
+		}
+	} else if err != storage.ErrNotFound {
+		return rs, errors.Wrapf(err, "could not fetch days of %s/%s", owner, repo)
+	}
+
+	var rl repoLanguage
+	err = c.opts.store.Get(ctx, languageKey(c.appID, c.installationID, owner, repo), &rl)
+	if err != nil && err != storage.ErrNotFound {
+		return rs, errors.Wrapf(err, "could not fetch language of %s/%s", owner, repo)
+	}
+	rs.Language = rl.Language
+
+	syn, err := c.repoSynonyms(ctx, owner, repo)
+	if err != nil {
+		return rs, err
+	}
+	rs.DeadlineSynonyms, rs.ReminderSynonyms = syn.Deadline, syn.Reminder
+
+	var rp repoLabelPrefix
+	err = c.opts.store.Get(ctx, labelPrefixKey(c.appID, c.installationID, owner, repo), &rp)
+	if err != nil && err != storage.ErrNotFound {
+		return rs, errors.Wrapf(err, "could not fetch label prefix of %s/%s", owner, repo)
+	}
+	rs.LabelPrefix = rp.Prefix
+	return rs, nil
+}
+
+// ImportSettings creates the deadline labels missing in each repository of the
+// installation, replaces its templates and rules, and applies the repository
+// settings to the repositories with the same names. Repositories not in the
+// settings are left as they are.
+func (c *InstallationClient) ImportSettings(ctx context.Context, s Settings) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+
+	key := installationConfigKey(c.appID, c.installationID)
+	ic := installationConfig{Templates: s.Templates, Rules: s.Rules}
+	if reflect.DeepEqual(ic, installationConfig{}) {
+		if err := c.opts.store.Delete(ctx, key); err != nil {
+			return errors.Wrap(err, "could not reset installation configuration")
+		}
+	} else if err := c.opts.store.Put(ctx, key, ic); err != nil {
+		return errors.Wrap(err, "could not store installation configuration")
+	}
+
+	repos, err := c.client.repos(ctx)
+	if err != nil {
+		return err
+	}
+
+	found := make(map[string]bool)
+	for _, repo := range repos {
+		// the settings go first, so the labels are created with their prefix.
+		for name, rs := range s.Repos {
+			if strings.EqualFold(name, repo.name) {
+				found[name] = true
+				if err := c.importRepoSettings(ctx, repo, rs); err != nil {
+					return err
+				}
+			}
+		}
+		if err := c.importLabels(ctx, repo, s.Labels, s.HourLabels); err != nil {
+			return err
+		}
+	}
+	for name := range s.Repos {
+		if !found[name] {
+			logrus.Warnf("repository %s not found in installation %d, skipping its settings", name, c.installationID)
+		}
+	}
+	return nil
+}
+
+func (c *InstallationClient) importLabels(ctx context.Context, repo repository, days, hours []int) error {
+	existing, err := c.LabelsInRepo(ctx, repo.owner, repo.name)
+	if err != nil {
+		return err
+	}
+	prefix, err := c.LabelPrefix(ctx, repo.owner, repo.name)
+	if err != nil {
+		return err
+	}
+	has := make(map[Label]bool)
+	for _, l := range existing {
+		has[Label{Days: l.Days, Hours: l.Hours}] = true
+	}
+	var labels []Label
+	for _, d := range days {
+		labels = append(labels, Label{Name: LabelName(prefix, d), Days: d})
+	}
+	for _, h := range hours {
+		labels = append(labels, Label{Name: HourLabelName(prefix, h), Hours: h})
+	}
+	for _, l := range labels {
+		if has[Label{Days: l.Days, Hours: l.Hours}] {
+			continue
+		}
+		if err := c.client.createLabel(ctx, repo.owner, repo.name, l.Name, DefaultUrgencyColors.Calm); err != nil {
+			return errors.Wrapf(err, "could not create label %s in %s/%s", l.Name, repo.owner, repo.name)
+		}
+	}
+	return nil
+}
+
+func (c *InstallationClient) importRepoSettings(ctx context.Context, repo repository, rs RepoSettings) error {
+	now := time.Now()
+	key := disabledKey(c.appID, c.installationID, repo.owner, repo.name)
+	if rs.Disabled {
+		err := c.opts.store.Put(ctx, key, disabledRepo{By: "import", Time: now})
+		if err != nil {
+			return errors.Wrapf(err, "could not disable %s/%s", repo.owner, repo.name)
+		}
+	} else if err := c.opts.store.Delete(ctx, key); err != nil {
+		return errors.Wrapf(err, "could not enable %s/%s", repo.owner, repo.name)
+	}
+
+	key = grammarKey(c.appID, c.installationID, repo.owner, repo.name)
+	if rs.Grammar == 0 {
+		if err := c.opts.store.Delete(ctx, key); err != nil {
+			return errors.Wrapf(err, "could not reset grammar of %s/%s", repo.owner, repo.name)
+		}
+	} else if err := c.opts.store.Put(ctx, key, repoGrammar{Version: rs.Grammar, By: "import", Time: now}); err != nil {
+		return errors.Wrapf(err, "could not set grammar of %s/%s", repo.owner, repo.name)
+	}
+
+	key = daysKey(c.appID, c.installationID, repo.owner, repo.name)
+	if rs.Days == "" {
+		if err := c.opts.store.Delete(ctx, key); err != nil {
+			return errors.Wrapf(err, "could not reset days of %s/%s", repo.owner, repo.name)
+		}
+	} else if err := c.opts.store.Put(ctx, key, repoDays{Business: rs.Days == businessDays, By: "import", Time: now}); err != nil {
+		return errors.Wrapf(err, "could not set days of %s/%s", repo.owner, repo.name)
+	}
+
+	key = languageKey(c.appID, c.installationID, repo.owner, repo.name)
+	if rs.Language == "" {
+		if err := c.opts.store.Delete(ctx, key); err != nil {
+			return errors.Wrapf(err, "could not reset language of %s/%s", repo.owner, repo.name)
+		}
+	} else if err := c.opts.store.Put(ctx, key, repoLanguage{Language: strings.ToLower(rs.Language), By: "import", Time: now}); err != nil {
+		return errors.Wrapf(err, "could not set language of %s/%s", repo.owner, repo.name)
+	}
+
+	key = synonymsKey(c.appID, c.installationID, repo.owner, repo.name)
+	if len(rs.DeadlineSynonyms) == 0 && len(rs.ReminderSynonyms) == 0 {
+		if err := c.opts.store.Delete(ctx, key); err != nil {
+			return errors.Wrapf(err, "could not reset synonyms of %s/%s", repo.owner, repo.name)
+		}
+	} else {
+		deadline, _ := parseSynonyms("deadline", rs.DeadlineSynonyms)
+		reminder, _ := parseSynonyms("reminder", rs.ReminderSynonyms)
+		rs := repoSynonyms{Deadline: deadline, Reminder: reminder, By: "import", Time: now}
+		if err := c.opts.store.Put(ctx, key, rs); err != nil {
+			return errors.Wrapf(err, "could not set synonyms of %s/%s", repo.owner, repo.name)
+		}
+	}
+
+	key = labelPrefixKey(c.appID, c.installationID, repo.owner, repo.name)
+	if strings.TrimSpace(rs.LabelPrefix) == "" {
+		return errors.Wrapf(c.opts.store.Delete(ctx, key), "could not reset label prefix of %s/%s", repo.owner, repo.name)
+	}
+	err := c.opts.store.Put(ctx, key, repoLabelPrefix{Prefix: strings.TrimSpace(rs.LabelPrefix), By: "import", Time: now})
+	return errors.Wrapf(err, "could not set label prefix of %s/%s", repo.owner, repo.name)
+}
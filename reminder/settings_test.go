@@ -0,0 +1,79 @@
+package reminder
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExportImportSettings(t *testing.T) {
+	ctx := context.Background()
+	labels := map[string][]string{
+		"api": {"deadline < 30", "bug"},
+		"web": {"deadline < 5", "deadline < 12h"},
+	}
+	var created []string
+	fc := &fakeClient{
+		_repos: func(ctx context.Context) ([]repository, error) {
+			return []repository{{"foo", "api"}, {"foo", "web"}}, nil
+		},
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) { return labels[repo], nil },
+		_createLabel: func(ctx context.Context, owner, repo, label, color string) error {
+			created = append(created, repo+":"+label)
+			return nil
+		},
+	}
+	src := InstallationClient{appID: 42, installationID: 1, client: fc, opts: newOptions([]Option{
+		WithTemplates(Templates{Reminder: "{{.Title}} is due"}),
+		WithCadences(Cadence{Label: "sev1", Before: 168 * time.Hour, Every: 24 * time.Hour}),
+		WithFollowUps(FollowUp{After: 72 * time.Hour, Users: []string{"alice", "org/leads"}}),
+	})}
+	src.opts.store.Put(ctx, disabledKey(42, 1, "foo", "web"), disabledRepo{By: "admin"})
+	src.opts.store.Put(ctx, grammarKey(42, 1, "foo", "api"), repoGrammar{Version: GrammarV2})
+
+	s, err := src.ExportSettings(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := Settings{
+		Labels:     []int{30, 5},
+		HourLabels: []int{12},
+		Templates:  Templates{Reminder: "{{.Title}} is due"},
+		Rules:      Rules{Cadences: []string{"sev1:168h0m0s:24h0m0s"}, FollowUps: []string{"72h0m0s:alice:org/leads"}},
+		Repos:      map[string]RepoSettings{"api": {Grammar: GrammarV2}, "web": {Disabled: true}},
+	}
+	if !reflect.DeepEqual(s, expected) {
+		t.Fatalf("expected settings %+v; got %+v", expected, s)
+	}
+
+	dst := InstallationClient{appID: 42, installationID: 2, opts: newOptions([]Option{WithStore(src.opts.store)}), client: fc}
+	if err := dst.ImportSettings(ctx, s); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, err := dst.ExportSettings(ctx); err != nil || !reflect.DeepEqual(got, s) {
+		t.Errorf("expected the imported settings %+v to be exported; got %+v (%v)", s, got, err)
+	}
+	if rc, _ := dst.forRepo(ctx, "foo", "api"); rc.opts.templates.Reminder != s.Templates.Reminder || len(rc.opts.cadences) != 1 {
+		t.Errorf("expected the imported templates and rules to apply; got %+v and %+v", rc.opts.templates, rc.opts.cadences)
+	}
+	if expected := []string{"api:deadline < 5", "api:deadline < 12h", "web:deadline < 30"}; !reflect.DeepEqual(created, expected) {
+		t.Errorf("expected labels %v to be created; got %v", expected, created)
+	}
+	if disabled, _ := dst.Disabled(ctx, "foo", "web"); !disabled {
+		t.Errorf("expected web to be disabled")
+	}
+	if g, _ := dst.Grammar(ctx, "foo", "api"); g != GrammarV2 {
+		t.Errorf("expected api to use grammar v2; got %v", g)
+	}
+
+	for _, bad := range []Settings{
+		{Labels: []int{0}},
+		{Templates: Templates{Nag: "{{.Title"}},
+		{Rules: Rules{Cadences: []string{"sev1"}}},
+	} {
+		if err := dst.ImportSettings(ctx, bad); err == nil {
+			t.Errorf("expected bad settings %+v not to be imported", bad)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+// snoozedIssue records who snoozed the reminders of an issue, and until when.
+type snoozedIssue struct {
+	Until time.Time `json:"until"`
+	By    string    `json:"by"`
+	Time  time.Time `json:"time"`
+}
+
+func snoozeKey(appID, installationID int, owner, repo string, number int) string {
+	return storage.Key("snooze", appID, installationID, strings.ToLower(owner), strings.ToLower(repo), number)
+}
+
+// parseSnooze parses how long to snooze an issue for, either a duration in
+// hours, days, or weeks, like "3d", or a date, relative to the given time.
+func parseSnooze(s string, now time.Time, d dates) time.Time {
+	if t, ok := addShortDuration(s, now); ok {
+		return t
+	}
+	return d.parse(s, now)
+}
+
+// snoozedUntil returns the time until which the reminders of the issue are
+// snoozed, either with /snooze or with a line like "snooze: 2018-08-01",
+// taking the latest of them. It's zero if the issue was never snoozed.
+func (c *InstallationClient) snoozedUntil(ctx context.Context, issue *issue) (time.Time, error) {
+	var s snoozedIssue
+	err := c.opts.store.Get(ctx, snoozeKey(c.appID, c.installationID, issue.repo.owner, issue.repo.name, issue.number), &s)
+	if err != nil && err != storage.ErrNotFound {
+		return time.Time{}, errors.Wrap(err, "could not fetch snooze")
+	}
+
+	until := s.Until
+	d := c.dates(issue)
+	find := func(body string, created time.Time) {
+		for _, v := range issue.grammar.findValues("snooze:", body) {
+			if t := d.parse(v, created); t.After(until) {
+				until = t
+			}
+		}
+	}
+	find(issue.body, issue.created)
+	for _, cm := range issue.comments {
+		if cm.author != c.login() {
+			find(cm.body, cm.created)
+		}
+	}
+	return until, nil
+}
+
+// snoozed reports whether the reminders of the issue are snoozed right now.
+func (c *InstallationClient) snoozed(ctx context.Context, issue *issue) (bool, error) {
+	until, err := c.snoozedUntil(ctx, issue)
+	return time.Now().Before(until), err
+}
+
+// snoozeCommand suppresses the reminders and deadline comments on an issue
+// for a while, or until a given date.
+//
+//	/snooze 3d
+//	/snooze 2018-08-01
+//	/snooze clear
+func snoozeCommand(ctx context.Context, c *InstallationClient, cmd Command) error {
+	reply := func(text string) error {
+		return c.client.createIssueComment(ctx, cmd.Owner, cmd.Repo, cmd.Number, fmt.Sprintf("@%s %s", cmd.Author, text))
+	}
+	key := snoozeKey(c.appID, c.installationID, cmd.Owner, cmd.Repo, cmd.Number)
+
+	if len(cmd.Args) == 1 && strings.EqualFold(cmd.Args[0], "clear") {
+		if err := c.opts.store.Delete(ctx, key); err != nil {
+			return errors.Wrap(err, "could not delete snooze")
+		}
+		return reply("reminders on this issue are back on.")
+	}
+
+	const usage = "usage: `/snooze 3d`, `/snooze 2018-08-01`, or `/snooze clear`."
+	if len(cmd.Args) == 0 {
+		return reply(usage)
+	}
+	lang, err := c.Language(ctx, cmd.Owner, cmd.Repo)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	until := parseSnooze(strings.Join(cmd.Args, " "), now, c.dates(&issue{language: lang}))
+	if !until.After(now) {
+		return reply(usage)
+	}
+
+	if err := c.opts.store.Put(ctx, key, snoozedIssue{Until: until, By: cmd.Author, Time: now}); err != nil {
+		return errors.Wrap(err, "could not store snooze")
+	}
+	return reply(fmt.Sprintf("reminders on this issue are silenced until %s.", until.UTC().Format("January 2 15:04 MST")))
+}
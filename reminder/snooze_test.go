@@ -0,0 +1,83 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSnooze(t *testing.T) {
+	now := time.Date(2018, 8, 1, 15, 30, 0, 0, time.UTC)
+	tests := []struct {
+		s        string
+		expected time.Time
+	}{
+		{"3d", now.AddDate(0, 0, 3)},
+		{"2W", now.AddDate(0, 0, 14)},
+		{"12h", now.Add(12 * time.Hour)},
+		{"2018-08-15", time.Date(2018, 8, 15, 0, 0, 0, 0, time.UTC)},
+		{"tomorrow", time.Date(2018, 8, 2, 0, 0, 0, 0, time.UTC)},
+		{"0d", time.Time{}},
+		{"3y", time.Time{}},
+		{"later", time.Time{}},
+	}
+	for _, tt := range tests {
+		if got := parseSnooze(tt.s, now, dates{parsers: DefaultDateParsers}); !got.Equal(tt.expected) {
+			t.Errorf("%q: expected %v; got %v", tt.s, tt.expected, got)
+		}
+	}
+}
+
+func TestSnooze(t *testing.T) {
+	var comments []string
+	body := fmt.Sprintf("reminder: %s\n", time.Now().Format("2006-01-02"))
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil), client: &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) { return nil, nil },
+		_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+			return &issue{repo: repository{owner, repo}, number: number, author: "francesc", state: "open", body: body}, nil
+		},
+		_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error {
+			comments = append(comments, body)
+			return nil
+		},
+	}}
+
+	ctx := context.Background()
+	if _, err := ic.HandleComment(ctx, "foo", "bar", 1, "alice", "/snooze 3d"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(comments) != 1 || !strings.Contains(comments[0], "@alice") || !strings.Contains(comments[0], "silenced until") {
+		t.Fatalf("expected the snooze to be acknowledged; got %v", comments)
+	}
+
+	// the snooze is kept across updates.
+	for i := 0; i < 2; i++ {
+		if err := ic.UpdateIssue(ctx, "foo", "bar", 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(comments) != 1 {
+		t.Fatalf("expected no reminders while snoozed; got %v", comments[1:])
+	}
+
+	if _, err := ic.HandleComment(ctx, "foo", "bar", 1, "alice", "/snooze clear"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body += fmt.Sprintf("snooze: %s\n", time.Now().AddDate(0, 0, 2).Format("2006-01-02"))
+	if err := ic.UpdateIssue(ctx, "foo", "bar", 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(comments) != 2 {
+		t.Fatalf("expected no reminders while snoozed in the body; got %v", comments[2:])
+	}
+
+	body = fmt.Sprintf("reminder: %s\n", time.Now().Format("2006-01-02"))
+	if err := ic.UpdateIssue(ctx, "foo", "bar", 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(comments) != 3 || !strings.Contains(comments[2], reminderText) {
+		t.Fatalf("expected a reminder once the snooze is lifted; got %v", comments[2:])
+	}
+}
@@ -0,0 +1,100 @@
+package reminder
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+// statusContext tells the commit statuses of the deadlines apart from the
+// statuses of other systems.
+const statusContext = "github-reminder/deadline"
+
+// A commitStatus is the status of the head commit of a pull request.
+type commitStatus struct {
+	sha         string
+	state       string
+	description string
+}
+
+// WithDeadlineStatuses makes the bot set a commit status on the head commit
+// of every pull request with a deadline, pending once less than pending is
+// left and failing once it's overdue, so branch protection and dashboards can
+// surface the pull requests at risk. It's a lighter alternative to the check
+// runs of WithDeadlineChecks, also available to personal access tokens.
+func WithDeadlineStatuses(pending time.Duration) Option {
+	return func(o *options) { o.statuses = &pending }
+}
+
+// commitStatusState is the last commit status set for a pull request.
+type commitStatusState struct {
+	SHA         string `json:"sha"`
+	State       string `json:"state"`
+	Description string `json:"description"`
+}
+
+// checkDeadlineStatus sets the commit status of the pull request for its
+// deadline, zero if it has none, unless it's already set. Pull requests that
+// never had a deadline get none. As with the check runs, the head commit is
+// only fetched when the webhook didn't tell it and the status changed.
+func (c *InstallationClient) checkDeadlineStatus(ctx context.Context, issue *issue, deadline time.Time) error {
+	if c.opts.statuses == nil || !issue.pullRequest {
+		return nil
+	}
+	owner, repo, number := issue.repo.owner, issue.repo.name, issue.number
+	key := storage.Key("commitstatus", c.appID, c.installationID, strings.ToLower(owner), strings.ToLower(repo), number)
+	var state commitStatusState
+	err := c.opts.store.Get(ctx, key, &state)
+	if err != nil && err != storage.ErrNotFound {
+		return errors.Wrap(err, "could not fetch commit status")
+	}
+	if deadline.IsZero() && err == storage.ErrNotFound {
+		return nil
+	}
+
+	status := c.deadlineStatus(deadline, time.Now())
+	status.sha = issue.head
+	if status.sha == "" {
+		if state.State == status.state && state.Description == status.description {
+			return nil
+		}
+		if status.sha, err = c.client.headSHA(ctx, owner, repo, number); err != nil {
+			return err
+		}
+	}
+	if state == (commitStatusState{SHA: status.sha, State: status.state, Description: status.description}) {
+		return nil
+	}
+	logrus.Debugf("setting status of %s/%s#%d to %s", owner, repo, number, status.state)
+	if err := c.client.createStatus(ctx, owner, repo, status); err != nil {
+		return errors.Wrapf(err, "could not set status of %s/%s#%d", owner, repo, number)
+	}
+	state = commitStatusState{SHA: status.sha, State: status.state, Description: status.description}
+	return errors.Wrap(c.opts.store.Put(ctx, key, state), "could not store commit status")
+}
+
+// deadlineStatus returns the commit status for the deadline at now. The
+// deadlines without a time of day last all of it.
+func (c *InstallationClient) deadlineStatus(deadline time.Time, now time.Time) commitStatus {
+	if deadline.IsZero() {
+		return commitStatus{state: "success", description: "No deadline"}
+	}
+	due := deadline
+	if c.allDay(due) {
+		due = due.AddDate(0, 0, 1)
+	}
+	s := commitStatus{state: "success", description: daysLeft(due, now)}
+	switch {
+	case now.After(due):
+		s.state = "failure"
+	case due.Sub(now) < *c.opts.statuses:
+		s.state = "pending"
+	}
+	s.description = strings.ToUpper(s.description[:1]) + s.description[1:]
+	return s
+}
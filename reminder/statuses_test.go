@@ -0,0 +1,54 @@
+package reminder
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDeadlineStatus(t *testing.T) {
+	sha := "abc"
+	var statuses []commitStatus
+	ic := InstallationClient{appID: 42, installationID: 43,
+		opts: newOptions([]Option{WithDeadlineStatuses(48 * time.Hour)}),
+		client: &fakeClient{
+			_headSHA: func(ctx context.Context, owner, repo string, number int) (string, error) { return sha, nil },
+			_createStatus: func(ctx context.Context, owner, repo string, status commitStatus) error {
+				statuses = append(statuses, status)
+				return nil
+			},
+		}}
+
+	i := &issue{repo: repository{"foo", "bar"}, number: 1, pullRequest: true}
+	check := func(deadline time.Time, state, description string) {
+		n := len(statuses)
+		if err := ic.checkDeadlineStatus(context.Background(), i, deadline); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if state == "" {
+			if len(statuses) != n {
+				t.Errorf("expected no new status; got %+v", statuses[n:])
+			}
+			return
+		}
+		if len(statuses) != n+1 {
+			t.Fatalf("expected a new status; got %+v", statuses)
+		}
+		if s := statuses[n]; s.sha != sha || s.state != state || s.description != description {
+			t.Errorf("expected status %s %q on %s; got %+v", state, description, sha, s)
+		}
+	}
+
+	check(time.Time{}, "", "")
+	far := time.Now().Add(5*24*time.Hour + time.Hour)
+	check(far, "success", "5 days left")
+	check(far, "", "")
+	// the webhooks of the pushes tell the new commits.
+	sha, i.head = "def", "def"
+	check(far, "success", "5 days left")
+	check(far, "", "")
+	i.head = ""
+	check(time.Now().Add(25*time.Hour), "pending", "1 day left")
+	check(time.Now().Add(-time.Hour), "failure", "Overdue")
+	check(time.Time{}, "success", "No deadline")
+}
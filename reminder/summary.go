@@ -0,0 +1,221 @@
+package reminder
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/notify"
+	"github.com/src-d/github-reminder/storage"
+)
+
+// A Team is a group of repositories summarized together.
+type Team struct {
+	Name  string
+	Repos []string
+}
+
+// ParseTeam parses a team written as name:owner/repo[+owner/repo...], like
+// web:acme/site+acme/api.
+func ParseTeam(s string) (Team, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return Team{}, errors.Errorf("bad team %q, expected name:owner/repo[+owner/repo...]", s)
+	}
+	t := Team{Name: parts[0]}
+	for _, r := range strings.Split(parts[1], "+") {
+		if strings.Count(r, "/") != 1 || strings.HasPrefix(r, "/") || strings.HasSuffix(r, "/") {
+			return Team{}, errors.Errorf("bad repository %q in team %s", r, t.Name)
+		}
+		t.Repos = append(t.Repos, r)
+	}
+	return t, nil
+}
+
+// A WeeklySummary rolls up the status of the deadlines of an installation
+// every Monday, by team: the issues due in the week, the overdue ones, and the
+// ones closed in the previous week. It's delivered to the notifiers of the
+// bot as an event of kind summary, and commented on Issue if set by the
+// installation of its repository.
+type WeeklySummary struct {
+	// Teams group the repositories. Repositories of no team are summarized
+	// on their own.
+	Teams []Team
+	// Issue, like "acme/planning#12", gets the summaries of the installation
+	// of its repository as comments.
+	Issue string
+	// Hour is the hour of Monday, in UTC, when the summaries are sent.
+	Hour int
+}
+
+// ParseIssueRef parses a reference to an issue like owner/repo#12.
+func ParseIssueRef(s string) (owner, repo string, number int, err error) {
+	i, j := strings.Index(s, "/"), strings.LastIndex(s, "#")
+	if i <= 0 || j < i+2 {
+		return "", "", 0, errors.Errorf("bad issue %q, expected owner/repo#number", s)
+	}
+	number, err = strconv.Atoi(s[j+1:])
+	if err != nil || number <= 0 {
+		return "", "", 0, errors.Errorf("bad issue %q, expected owner/repo#number", s)
+	}
+	return s[:i], s[i+1 : j], number, nil
+}
+
+// WithWeeklySummary makes the bot send a weekly summary of the deadlines of
+// every installation, checked at the end of each update.
+func WithWeeklySummary(s WeeklySummary) Option {
+	return func(o *options) { o.summary = &s }
+}
+
+// sendWeeklySummary sends the summary of the installation if it's due at
+// now, and records it was sent.
+func (c *InstallationClient) sendWeeklySummary(ctx context.Context, now time.Time) error {
+	s := c.opts.summary
+	if s == nil {
+		return nil
+	}
+	key := storage.Key("weeklysummary", c.appID, c.installationID)
+	var last time.Time
+	if err := c.opts.store.Get(ctx, key, &last); err != nil && err != storage.ErrNotFound {
+		return errors.Wrap(err, "could not fetch last summary")
+	}
+	now = now.UTC()
+	scheduled := time.Date(now.Year(), now.Month(), now.Day(), s.Hour, 0, 0, 0, time.UTC)
+	// Mondays are 1, Sundays 0.
+	scheduled = scheduled.AddDate(0, 0, -(int(scheduled.Weekday())+6)%7)
+	if scheduled.After(now) {
+		scheduled = scheduled.AddDate(0, 0, -7)
+	}
+	if !last.Before(scheduled) {
+		return nil
+	}
+
+	ds, err := c.Deadlines(ctx)
+	if err != nil {
+		return err
+	}
+	outcomes, err := c.History(ctx)
+	if err != nil {
+		return err
+	}
+	text := c.summary(ds, outcomes, now)
+	if text != "" {
+		logrus.Infof("sending weekly summary of installation %d/%d", c.appID, c.installationID)
+		c.notify(ctx, notify.Event{Kind: notify.Summary, Title: "Weekly summary", Message: text, Time: now}, nil)
+		if s.Issue != "" {
+			if err := c.commentSummary(ctx, s.Issue, text); err != nil {
+				logrus.Warnf("could not post summary of installation %d/%d on %s: %v", c.appID, c.installationID, s.Issue, err)
+			}
+		}
+	}
+	return errors.Wrap(c.opts.store.Put(ctx, key, now), "could not record summary")
+}
+
+// commentSummary comments the summary on the issue, if it's in one of the
+// repositories of the installation, since the others can't reach it.
+func (c *InstallationClient) commentSummary(ctx context.Context, ref, text string) error {
+	owner, repo, number, err := ParseIssueRef(ref)
+	if err != nil {
+		return err
+	}
+	repos, err := c.client.repos(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not list repositories")
+	}
+	for _, r := range repos {
+		if strings.EqualFold(r.owner, owner) && strings.EqualFold(r.name, repo) {
+			return errors.Wrap(c.client.createIssueComment(ctx, owner, repo, number, text), "could not comment")
+		}
+	}
+	return nil
+}
+
+// teamSummary is the status of the deadlines of a team.
+type teamSummary struct {
+	name                    string
+	overdue, due, completed []string
+}
+
+// summary returns the text of the weekly summary of the deadlines and the
+// outcomes at now, or nothing if there's nothing to tell. The titles of the
+// issues are written as code so they're never read as deadlines.
+func (c *InstallationClient) summary(ds []Deadline, outcomes []Outcome, now time.Time) string {
+	var teams []*teamSummary
+	byRepo := make(map[string]*teamSummary)
+	for _, t := range c.opts.summary.Teams {
+		ts := &teamSummary{name: t.Name}
+		teams = append(teams, ts)
+		for _, r := range t.Repos {
+			byRepo[strings.ToLower(r)] = ts
+		}
+	}
+	team := func(owner, repo string) *teamSummary {
+		name := owner + "/" + repo
+		ts, ok := byRepo[strings.ToLower(name)]
+		if !ok {
+			ts = &teamSummary{name: name}
+			teams = append(teams, ts)
+			byRepo[strings.ToLower(name)] = ts
+		}
+		return ts
+	}
+	item := func(owner, repo string, number int, title, detail string) string {
+		return fmt.Sprintf("%s/%s#%d `%s`, %s", owner, repo, number, strings.Replace(title, "`", "'", -1), detail)
+	}
+
+	week := now.AddDate(0, 0, 7)
+	for _, d := range ds {
+		switch {
+		case c.overdue(d.Deadline):
+			ts := team(d.Owner, d.Repo)
+			ts.overdue = append(ts.overdue, item(d.Owner, d.Repo, d.Number, d.Title, "since "+d.Deadline.Format("January 2")))
+		case d.Deadline.Before(week):
+			ts := team(d.Owner, d.Repo)
+			ts.due = append(ts.due, item(d.Owner, d.Repo, d.Number, d.Title, "on "+d.Deadline.Format("Monday, January 2")))
+		}
+	}
+	for _, o := range outcomes {
+		if o.Closed.Before(now.AddDate(0, 0, -7)) {
+			continue
+		}
+		detail := "on time"
+		switch days := int(o.Slip().Hours() / 24); {
+		case o.Met:
+		case days < 1:
+			detail = "less than a day late"
+		case days == 1:
+			detail = "1 day late"
+		default:
+			detail = fmt.Sprintf("%d days late", days)
+		}
+		ts := team(o.Owner, o.Repo)
+		ts.completed = append(ts.completed, item(o.Owner, o.Repo, o.Number, o.Title, detail))
+	}
+
+	var buf bytes.Buffer
+	for _, ts := range teams {
+		if len(ts.overdue)+len(ts.due)+len(ts.completed) == 0 {
+			continue
+		}
+		fmt.Fprintf(&buf, "\n**%s**: %d overdue, %d due this week, %d completed\n",
+			ts.name, len(ts.overdue), len(ts.due), len(ts.completed))
+		for _, section := range []struct {
+			name  string
+			items []string
+		}{{"Overdue", ts.overdue}, {"This week", ts.due}, {"Completed", ts.completed}} {
+			for _, it := range section.items {
+				fmt.Fprintf(&buf, "- %s: %s\n", section.name, it)
+			}
+		}
+	}
+	if buf.Len() == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Weekly summary of the deadlines, week of %s:\n%s", now.Format("January 2"), buf.String())
+}
@@ -0,0 +1,141 @@
+package reminder
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/src-d/github-reminder/notify"
+)
+
+func TestParseTeam(t *testing.T) {
+	team, err := ParseTeam("web:acme/site+acme/api")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := (Team{Name: "web", Repos: []string{"acme/site", "acme/api"}}); !reflect.DeepEqual(team, expected) {
+		t.Errorf("expected %v; got %v", expected, team)
+	}
+	for _, bad := range []string{"web", ":acme/site", "web:acme", "web:acme/site+", "web:acme/site/x"} {
+		if _, err := ParseTeam(bad); err == nil {
+			t.Errorf("expected error parsing %q", bad)
+		}
+	}
+}
+
+func TestParseIssueRef(t *testing.T) {
+	owner, repo, number, err := ParseIssueRef("acme/planning#12")
+	if err != nil || owner != "acme" || repo != "planning" || number != 12 {
+		t.Errorf("expected acme planning 12; got %s %s %d (%v)", owner, repo, number, err)
+	}
+	for _, bad := range []string{"acme/planning", "acme#12", "/planning#12", "acme/planning#x", "acme/#12"} {
+		if _, _, _, err := ParseIssueRef(bad); err == nil {
+			t.Errorf("expected error parsing %q", bad)
+		}
+	}
+}
+
+func TestWeeklySummary(t *testing.T) {
+	ctx := context.Background()
+	var comments []string
+	var events []notify.Event
+	ic := InstallationClient{appID: 42, installationID: 43,
+		opts: newOptions([]Option{
+			WithWeeklySummary(WeeklySummary{
+				Teams: []Team{{Name: "web", Repos: []string{"acme/site", "acme/api"}}},
+				Issue: "acme/planning#12",
+				Hour:  9,
+			}),
+			WithNotifier(notify.NotifierFunc(func(ctx context.Context, e notify.Event) error {
+				events = append(events, e)
+				return nil
+			})),
+		}),
+		client: &fakeClient{
+			_repos: func(ctx context.Context) ([]repository, error) {
+				return []repository{{"acme", "site"}, {"Acme", "Planning"}}, nil
+			},
+			_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error {
+				if owner != "acme" || repo != "planning" || number != 12 {
+					t.Errorf("unexpected comment on %s/%s#%d", owner, repo, number)
+				}
+				comments = append(comments, body)
+				return nil
+			},
+		}}
+
+	now := time.Now()
+	for _, d := range []Deadline{
+		{Owner: "acme", Repo: "site", Number: 1, Title: "[deadline: soon] Ship", Deadline: now.AddDate(0, 0, -2)},
+		{Owner: "acme", Repo: "api", Number: 2, Title: "Docs", Deadline: now.AddDate(0, 0, 3)},
+		{Owner: "acme", Repo: "etl", Number: 3, Title: "Backfill", Deadline: now.AddDate(0, 0, 5)},
+		{Owner: "acme", Repo: "etl", Number: 4, Title: "Later", Deadline: now.AddDate(0, 0, 30)},
+	} {
+		if err := ic.opts.store.Put(ctx, deadlineKey(42, 43, d.Owner, d.Repo, d.Number), d); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	for _, o := range []Outcome{
+		{Owner: "acme", Repo: "api", Number: 5, Title: "Login", Closed: now.AddDate(0, 0, -1), Met: true},
+		{Owner: "acme", Repo: "api", Number: 6, Title: "Old", Closed: now.AddDate(0, 0, -20), Met: true},
+	} {
+		if err := ic.opts.store.Put(ctx, historyKey(42, 43, o.Owner, o.Repo, o.Number), o); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if err := ic.sendWeeklySummary(ctx, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(comments) != 1 || len(events) != 1 || events[0].Kind != notify.Summary || events[0].Message != comments[0] {
+		t.Fatalf("expected a summary comment and event; got %q and %v", comments, events)
+	}
+	for _, s := range []string{
+		"**web**: 1 overdue, 1 due this week, 1 completed",
+		"- Overdue: acme/site#1 `[deadline: soon] Ship`",
+		"- This week: acme/api#2 `Docs`",
+		"- Completed: acme/api#5 `Login`, on time",
+		"**acme/etl**: 0 overdue, 1 due this week, 0 completed",
+	} {
+		if !strings.Contains(comments[0], s) {
+			t.Errorf("expected %q in the summary; got %q", s, comments[0])
+		}
+	}
+	if strings.Contains(comments[0], "#4") || strings.Contains(comments[0], "#6") {
+		t.Errorf("expected only this week's deadlines in the summary; got %q", comments[0])
+	}
+	if ic.summary(nil, nil, now) != "" {
+		t.Errorf("expected no summary without deadlines")
+	}
+
+	if err := ic.sendWeeklySummary(ctx, now.Add(time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(comments) != 1 {
+		t.Errorf("expected a single summary a week; got %d", len(comments))
+	}
+	if err := ic.sendWeeklySummary(ctx, now.AddDate(0, 0, 7)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(comments) != 2 {
+		t.Errorf("expected a new summary the next week; got %d", len(comments))
+	}
+
+	// other installations can't reach the issue, they only notify.
+	ic.installationID = 44
+	ic.client.(*fakeClient)._repos = func(ctx context.Context) ([]repository, error) {
+		return []repository{{"other", "site"}}, nil
+	}
+	if err := ic.opts.store.Put(ctx, deadlineKey(42, 44, "other", "site", 1), Deadline{Owner: "other", Repo: "site", Number: 1, Deadline: now}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	events = nil
+	if err := ic.sendWeeklySummary(ctx, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(comments) != 2 || len(events) != 1 {
+		t.Errorf("expected only an event from another installation; got %d comments and %v", len(comments), events)
+	}
+}
@@ -0,0 +1,136 @@
+package reminder
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+// repoSynonyms records the synonyms of the keywords chosen for a repository,
+// like "due" or "eta" for "deadline", and who chose them.
+type repoSynonyms struct {
+	Deadline []string  `json:"deadline,omitempty"`
+	Reminder []string  `json:"reminder,omitempty"`
+	By       string    `json:"by"`
+	Time     time.Time `json:"time"`
+}
+
+func synonymsKey(appID, installationID int, owner, repo string) string {
+	return storage.Key("synonyms", appID, installationID, strings.ToLower(owner), strings.ToLower(repo))
+}
+
+// synonymWord matches the synonyms, made of letters in any language, digits,
+// and dashes, and possibly of several words, like "fecha límite".
+var synonymWord = regexp.MustCompile(`^\pL[\pL\pN_-]*( [\pL\pN_-]+)*$`)
+
+// parseSynonyms checks the synonyms of a keyword, returning them in lower case.
+func parseSynonyms(keyword string, words []string) ([]string, error) {
+	var res []string
+	for _, w := range words {
+		w = strings.Join(strings.Fields(strings.ToLower(strings.TrimSuffix(strings.TrimSpace(w), ":"))), " ")
+		if !synonymWord.MatchString(w) || w == "deadline" || w == "reminder" {
+			return nil, errors.Errorf("bad synonym %q of %s", w, keyword)
+		}
+		res = append(res, w)
+	}
+	return res, nil
+}
+
+// splitSynonyms splits the synonyms given to /reminder synonyms, separated
+// by commas if there's any, to allow synonyms of several words, or by spaces.
+func splitSynonyms(args []string) []string {
+	s := strings.Join(args, " ")
+	if !strings.Contains(s, ",") {
+		return args
+	}
+	var words []string
+	for _, w := range strings.Split(s, ",") {
+		if w = strings.TrimSpace(w); w != "" {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+// synonyms replaces the synonyms of the keywords followed by a colon, like
+// "ETA: 2018-08-01", with the keyword they stand for, so issues are read the
+// same way as if they used it.
+type synonyms struct {
+	deadline, reminder *regexp.Regexp
+}
+
+// newSynonyms returns the synonyms chosen for a repository, along with the
+// words for the keywords in its language, if any.
+func newSynonyms(rs repoSynonyms, code string) synonyms {
+	compile := func(words []string) *regexp.Regexp {
+		if len(words) == 0 {
+			return nil
+		}
+		quoted := make([]string, len(words))
+		for i, w := range words {
+			quoted[i] = strings.Replace(regexp.QuoteMeta(w), " ", `\s+`, -1)
+		}
+		// \b only knows about ASCII letters, so the start of the word is matched by hand.
+		return regexp.MustCompile(`(?i)(^|[^\pL\pN_])(?:` + strings.Join(quoted, "|") + `)\s*:`)
+	}
+	lang := languages[code]
+	return synonyms{
+		deadline: compile(append(append([]string{}, rs.Deadline...), lang.deadline...)),
+		reminder: compile(append(append([]string{}, rs.Reminder...), lang.reminder...)),
+	}
+}
+
+// replace returns the text with the synonyms replaced by their keywords.
+func (s synonyms) replace(text string) string {
+	if s.deadline != nil {
+		text = s.deadline.ReplaceAllString(text, "${1}deadline:")
+	}
+	if s.reminder != nil {
+		text = s.reminder.ReplaceAllString(text, "${1}reminder:")
+	}
+	return text
+}
+
+// repoSynonyms returns the synonyms of the keywords chosen for a repository,
+// or the ones in its configuration file if none were chosen.
+func (c *InstallationClient) repoSynonyms(ctx context.Context, owner, repo string) (repoSynonyms, error) {
+	var rs repoSynonyms
+	err := c.opts.store.Get(ctx, synonymsKey(c.appID, c.installationID, owner, repo), &rs)
+	if err == storage.ErrNotFound {
+		return repoSynonyms{Deadline: c.opts.deadlineSynonyms, Reminder: c.opts.reminderSynonyms}, nil
+	}
+	if err != nil {
+		return rs, errors.Wrapf(err, "could not fetch synonyms of %s/%s", owner, repo)
+	}
+	return rs, nil
+}
+
+// Synonyms returns the synonyms of the deadline and reminder keywords chosen
+// for a repository with /reminder synonyms.
+func (c *InstallationClient) Synonyms(ctx context.Context, owner, repo string) (deadline, reminder []string, err error) {
+	rs, err := c.repoSynonyms(ctx, owner, repo)
+	return rs.Deadline, rs.Reminder, err
+}
+
+// readSettings sets the settings of the repository used to read the issue.
+func (c *InstallationClient) readSettings(ctx context.Context, issue *issue) error {
+	owner, repo := issue.repo.owner, issue.repo.name
+	var err error
+	if issue.grammar, err = c.Grammar(ctx, owner, repo); err != nil {
+		return err
+	}
+	if issue.language, err = c.Language(ctx, owner, repo); err != nil {
+		return err
+	}
+	rs, err := c.repoSynonyms(ctx, owner, repo)
+	if err != nil {
+		return err
+	}
+	issue.synonyms = newSynonyms(rs, issue.language)
+	return nil
+}
@@ -0,0 +1,100 @@
+package reminder
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSynonyms(t *testing.T) {
+	s := newSynonyms(repoSynonyms{Deadline: []string{"due", "eta"}, Reminder: []string{"ping"}}, "")
+	tests := map[string]string{
+		"ETA: 2018-08-01":         "deadline: 2018-08-01",
+		"- due : next friday":     "- deadline: next friday",
+		"ping: tomorrow":          "reminder: tomorrow",
+		"this is due to a bug":    "this is due to a bug",
+		"beta: 2018-08-01":        "beta: 2018-08-01",
+		"deadline: 2018-08-01":    "deadline: 2018-08-01",
+		"[eta: 2018-08-01] Title": "[deadline: 2018-08-01] Title",
+	}
+	for text, expected := range tests {
+		if got := s.replace(text); got != expected {
+			t.Errorf("%q: expected %q; got %q", text, expected, got)
+		}
+	}
+	if got := (synonyms{}).replace("ETA: 2018-08-01"); got != "ETA: 2018-08-01" {
+		t.Errorf("expected no replacements without synonyms; got %q", got)
+	}
+	for _, words := range [][]string{{"deadline"}, {"due!"}, {"@due"}, {"1st"}} {
+		if _, err := parseSynonyms("deadline", words); err == nil {
+			t.Errorf("%q: expected an error", words)
+		}
+	}
+}
+
+func TestLocalizedKeywords(t *testing.T) {
+	s := newSynonyms(repoSynonyms{Deadline: []string{"fecha de entrega"}}, "es")
+	tests := map[string]string{
+		"Fecha límite: 3 de junio de 2018": "deadline: 3 de junio de 2018",
+		"- plazo: mañana":                  "- deadline: mañana",
+		"fecha  de entrega: 2018-08-01":    "deadline: 2018-08-01",
+		"Recordatorio: 2018-08-01":         "reminder: 2018-08-01",
+		"échéance: 2018-08-01":             "échéance: 2018-08-01",
+	}
+	for text, expected := range tests {
+		if got := s.replace(text); got != expected {
+			t.Errorf("%q: expected %q; got %q", text, expected, got)
+		}
+	}
+	if got := newSynonyms(repoSynonyms{}, "fr").replace("Échéance : 1 août 2018"); got != "deadline: 1 août 2018" {
+		t.Errorf("expected the French keyword to be read; got %q", got)
+	}
+
+	words := splitSynonyms(strings.Fields("fecha de entrega, entrega"))
+	if expected := []string{"fecha de entrega", "entrega"}; !reflect.DeepEqual(words, expected) {
+		t.Errorf("expected synonyms %q; got %q", expected, words)
+	}
+}
+
+func TestSynonymsCommand(t *testing.T) {
+	ctx := context.Background()
+	var comments []string
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil), client: &fakeClient{
+		_permission: func(ctx context.Context, owner, repo, user string) (string, error) { return "admin", nil },
+		_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+			return &issue{repo: repository{owner, repo}, number: number, state: "open", body: "Target: 2018-08-01"}, nil
+		},
+		_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error {
+			comments = append(comments, body)
+			return nil
+		},
+	}}
+
+	if _, err := ic.HandleComment(ctx, "foo", "bar", 1, "admin", "/reminder synonyms deadline due ETA target"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(comments) != 1 || !strings.Contains(comments[0], "due:, eta:, target:") {
+		t.Fatalf("unexpected replies %q", comments)
+	}
+	deadline, _, err := ic.Synonyms(ctx, "Foo", "Bar")
+	if err != nil || !reflect.DeepEqual(deadline, []string{"due", "eta", "target"}) {
+		t.Fatalf("expected synonyms to be stored; got %v (%v)", deadline, err)
+	}
+
+	is, _ := ic.client.issue(ctx, "foo", "bar", 1)
+	if err := ic.readSettings(ctx, is); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d, err := ic.deadline(ctx, is); err != nil || !d.Equal(time.Date(2018, 8, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the deadline to be read from the synonym; got %v (%v)", d, err)
+	}
+
+	if _, err := ic.HandleComment(ctx, "foo", "bar", 1, "admin", "/reminder synonyms deadline"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deadline, _, _ := ic.Synonyms(ctx, "foo", "bar"); len(deadline) != 0 {
+		t.Errorf("expected synonyms to be cleared; got %v", deadline)
+	}
+}
@@ -0,0 +1,114 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/src-d/github-reminder/notify"
+)
+
+// A task is an item of a Markdown task list with its own deadline, like
+// "- [ ] write docs — deadline: 2018-08-01".
+type task struct {
+	name     string
+	done     bool
+	deadline time.Time
+}
+
+var taskItem = regexp.MustCompile(`^\s*[-*+]\s+\[([ xX])\]\s+(.*)$`)
+
+// splitTasks returns the tasks with deadlines in a body written at the given
+// time, and the body without them, so their deadlines are not taken as the
+// deadline of the whole issue.
+func splitTasks(body string, created time.Time, d dates) (string, []task) {
+	var tasks []task
+	var rest []string
+	for _, line := range strings.Split(body, "\n") {
+		m := taskItem.FindStringSubmatch(line)
+		if m == nil {
+			rest = append(rest, line)
+			continue
+		}
+		i := strings.Index(strings.ToLower(m[2]), "deadline:")
+		if i < 0 {
+			rest = append(rest, line)
+			continue
+		}
+		t := d.parse(m[2][i+len("deadline:"):], created)
+		name := strings.TrimRight(m[2][:i], " \t—–-,:([")
+		if t.IsZero() || name == "" {
+			rest = append(rest, line)
+			continue
+		}
+		tasks = append(tasks, task{name: name, done: m[1] != " ", deadline: t})
+	}
+	return strings.Join(rest, "\n"), tasks
+}
+
+// taskCheckpoints returns the unchecked tasks as checkpoints named after them.
+func taskCheckpoints(tasks []task) []checkpoint {
+	var cps []checkpoint
+	for _, t := range tasks {
+		if !t.done {
+			cps = append(cps, checkpoint{t.name, t.deadline})
+		}
+	}
+	return cps
+}
+
+// issueTasks returns the tasks with deadlines in the body and comments of the issue.
+func (c *InstallationClient) issueTasks(issue *issue) []task {
+	d := c.dates(issue)
+	_, tasks := splitTasks(issue.synonyms.replace(issue.body), issue.created, d)
+	for _, cm := range issue.comments {
+		if cm.author == c.login() {
+			continue
+		}
+		_, ts := splitTasks(issue.synonyms.replace(cm.body), cm.created, d)
+		tasks = append(tasks, ts...)
+	}
+	return tasks
+}
+
+// checkTasks reminds the author of the issue of the unchecked tasks due today,
+// once their deadline is reached, naming each of them.
+func (c *InstallationClient) checkTasks(ctx context.Context, issue *issue) error {
+	loc := c.opts.location
+	if loc == nil {
+		loc = time.UTC
+	}
+	now := time.Now().In(loc)
+	sameDay := func(a, b time.Time) bool {
+		a, b = a.In(loc), b.In(loc)
+		return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+	}
+
+	for _, t := range c.issueTasks(issue) {
+		if t.done || now.Before(t.deadline) || !sameDay(now, t.deadline) {
+			continue
+		}
+		quoted := fmt.Sprintf("“%s”", t.name)
+		done := false
+		for _, cm := range issue.comments {
+			done = done || (cm.author == c.login() && sameDay(cm.created, now) && strings.Contains(cm.body, quoted))
+		}
+		if done {
+			continue
+		}
+		if snoozed, err := c.snoozed(ctx, issue); err != nil || snoozed {
+			return err
+		}
+
+		text := fmt.Sprintf("hi @%s, %s the task %s is due today.", issue.author, reminderText, quoted)
+		if err := c.postReminder(ctx, issue, issue.author, text); err != nil {
+			return err
+		}
+		e := issue.event(notify.Reminder, text)
+		e.User, e.Deadline = issue.author, t.deadline
+		c.notify(ctx, e, issue.policy)
+	}
+	return nil
+}
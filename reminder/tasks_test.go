@@ -0,0 +1,74 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSplitTasks(t *testing.T) {
+	body := "Release plan\n" +
+		"- [ ] write docs — deadline: 2018-08-20\n" +
+		"- [x] fix tests, deadline: 2018-08-10\n" +
+		"* [ ] announce it\n" +
+		"- [ ] deadline: 2018-08-30\n" +
+		"deadline: 2018-09-01"
+	rest, tasks := splitTasks(body, time.Time{}, dates{})
+	expected := []task{
+		{"write docs", false, time.Date(2018, 8, 20, 0, 0, 0, 0, time.UTC)},
+		{"fix tests", true, time.Date(2018, 8, 10, 0, 0, 0, 0, time.UTC)},
+	}
+	if len(tasks) != len(expected) {
+		t.Fatalf("expected %v; got %v", expected, tasks)
+	}
+	for i, tk := range tasks {
+		if tk.name != expected[i].name || tk.done != expected[i].done || !tk.deadline.Equal(expected[i].deadline) {
+			t.Errorf("expected %v; got %v", expected[i], tk)
+		}
+	}
+	if expected := "Release plan\n* [ ] announce it\n- [ ] deadline: 2018-08-30\ndeadline: 2018-09-01"; rest != expected {
+		t.Errorf("expected the rest of the body to be %q; got %q", expected, rest)
+	}
+}
+
+func TestTaskDeadlines(t *testing.T) {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	var comments []comment
+	body := fmt.Sprintf("- [ ] write docs — deadline: %s\n- [ ] ship it — deadline: %s\n- [x] fix tests — deadline: %s",
+		today.Format("2006-01-02"), today.AddDate(0, 0, 3).Format("2006-01-02"), today.AddDate(0, 0, -1).Format("2006-01-02"))
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil), client: &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) { return nil, nil },
+		_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+			return &issue{
+				repo: repository{owner, repo}, number: number, state: "open", author: "francesc",
+				body: body, comments: comments,
+			}, nil
+		},
+		_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error {
+			comments = append(comments, comment{author: botLogin, body: body, created: time.Now()})
+			return nil
+		},
+	}}
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if err := ic.UpdateIssue(ctx, "foo", "bar", 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(comments) != 1 || !strings.Contains(comments[0].body, "@francesc") || !strings.Contains(comments[0].body, "“write docs”") {
+		t.Fatalf("expected a single reminder naming the task due today; got %v", comments)
+	}
+
+	is, _ := ic.client.issue(ctx, "foo", "bar", 1)
+	d, err := ic.deadline(ctx, is)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !d.Equal(today) || is.checkpoint != "write docs" {
+		t.Errorf("expected the deadline of the first unchecked task; got %v for %q", d, is.checkpoint)
+	}
+}
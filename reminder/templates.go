@@ -0,0 +1,164 @@
+package reminder
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+// Templates replace the texts of the comments posted by the bot, written as
+// text/template templates executed with a TemplateData. Empty templates keep
+// the default texts.
+//
+// The bot reads its own comments too, so a template writing a keyword followed
+// by a date, like "deadline: {{date .Deadline}}", would set the deadline or a
+// reminder of the issue. Comments read that way are replaced by the default
+// texts.
+type Templates struct {
+	// Reminder is posted when a reminder is due.
+	Reminder string `json:"reminder,omitempty" yaml:"reminder,omitempty"`
+	// Overdue is posted once when the deadline of an issue passes, and only
+	// if given, since there's no default text.
+	Overdue string `json:"overdue,omitempty" yaml:"overdue,omitempty"`
+	// Nag is posted by the cadences as the deadline approaches.
+	Nag string `json:"nag,omitempty" yaml:"nag,omitempty"`
+}
+
+// TemplateData are the variables of the comment templates.
+type TemplateData struct {
+	// User is the login of the user the comment is for.
+	User string
+	// Title, URL, Number and Repo, as in "owner/repo", identify the issue.
+	Title  string
+	URL    string
+	Number int
+	Repo   string
+	// Assignees are the logins of the users the issue is assigned to.
+	Assignees []string
+	// Deadline of the issue, zero if it has none, and the whole days left
+	// until it, negative once it has passed.
+	Deadline time.Time
+	DaysLeft int
+	// Checkpoint is the name of the checkpoint due, if any.
+	Checkpoint string
+	// Label is the label of the cadence of a nag.
+	Label string
+}
+
+// templateFuncs are the functions available in the comment templates.
+var templateFuncs = template.FuncMap{
+	// date formats a time like "January 2".
+	"date": func(t time.Time) string { return t.Format("January 2") },
+	// mention mentions a list of users, like "@alice, @bob".
+	"mention": func(users []string) string {
+		ms := make([]string, len(users))
+		for i, u := range users {
+			ms[i] = "@" + u
+		}
+		return strings.Join(ms, ", ")
+	},
+}
+
+// ParseTemplates checks that all of the templates can be parsed.
+func ParseTemplates(t Templates) error {
+	for name, text := range map[string]string{"reminder": t.Reminder, "overdue": t.Overdue, "nag": t.Nag} {
+		if _, err := template.New(name).Funcs(templateFuncs).Parse(text); err != nil {
+			return errors.Wrapf(err, "bad %s template", name)
+		}
+	}
+	return nil
+}
+
+// WithTemplates replaces the texts of the comments posted by the bot with
+// the given templates.
+func WithTemplates(t Templates) Option {
+	return func(o *options) { o.templates = t }
+}
+
+// templateData returns the variables of the templates of the issue for the user.
+func (i *issue) templateData(user string) TemplateData {
+	d := TemplateData{
+		User:       user,
+		Title:      i.title,
+		URL:        i.url,
+		Number:     i.number,
+		Repo:       i.repo.owner + "/" + i.repo.name,
+		Assignees:  i.assignees,
+		Deadline:   i.deadline,
+		Checkpoint: i.checkpoint,
+	}
+	if !i.deadline.IsZero() {
+		d.DaysLeft = int(time.Until(i.deadline).Hours() / 24)
+	}
+	return d
+}
+
+// render returns the text of the template for the issue, or the default text
+// if there's no template, it fails, or its text would be read as a deadline or
+// a reminder.
+func (c *InstallationClient) render(issue *issue, name, text string, data TemplateData, def string) string {
+	if text == "" {
+		return def
+	}
+	var buf bytes.Buffer
+	t, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err == nil {
+		err = t.Execute(&buf, data)
+	}
+	if err != nil {
+		logrus.Warnf("could not render %s template for %s/%s#%d: %v", name, issue.repo.owner, issue.repo.name, issue.number, err)
+		return def
+	}
+	if c.readsAsDates(issue, buf.String()) {
+		logrus.Warnf("ignoring %s template for %s/%s#%d, it would be read as a deadline or a reminder",
+			name, issue.repo.owner, issue.repo.name, issue.number)
+		return def
+	}
+	return buf.String()
+}
+
+// readsAsDates reports whether a comment with the text would be read as a
+// deadline or a reminder of the issue.
+func (c *InstallationClient) readsAsDates(issue *issue, text string) bool {
+	now, d := time.Now(), c.dates(issue)
+	body, tasks := splitTasks(issue.synonyms.replace(text), now, d)
+	return len(tasks) > 0 || len(issue.grammar.findCheckpoints("deadline", body, now, d)) > 0 ||
+		len(c.findReminders(issue, text, now, now, issue.deadline)) > 0
+}
+
+// overdueMarker is hidden in the overdue comments to find them later.
+const overdueMarker = "<!-- github-reminder:overdue -->"
+
+// checkOverdueComment posts the overdue template on the issue once its
+// deadline passes, once for each deadline.
+func (c *InstallationClient) checkOverdueComment(ctx context.Context, issue *issue, deadline time.Time) error {
+	if c.opts.templates.Overdue == "" || !c.overdue(deadline) {
+		return nil
+	}
+	owner, repo, number := issue.repo.owner, issue.repo.name, issue.number
+	key := storage.Key("overduecomment", c.appID, c.installationID, strings.ToLower(owner), strings.ToLower(repo), number)
+	var last time.Time
+	if err := c.opts.store.Get(ctx, key, &last); err != nil && err != storage.ErrNotFound {
+		return errors.Wrap(err, "could not fetch overdue comment")
+	}
+	if last.Equal(deadline) {
+		return nil
+	}
+
+	text := c.render(issue, "overdue", c.opts.templates.Overdue, issue.templateData(issue.author), "")
+	if text == "" {
+		return nil
+	}
+	if err := c.Comment(ctx, owner, repo, number, fmt.Sprintf("%s\n%s", text, overdueMarker)); err != nil {
+		return err
+	}
+	return c.whenApplied(ctx, deferredPut(key, deadline))
+}
@@ -0,0 +1,153 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestReminderTemplate(t *testing.T) {
+	deadline := time.Now().AddDate(0, 0, 10).Format("2006-01-02")
+	tests := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{"default", "", "hi @francesc, " + reminderText},
+		{"template", "{{mention .Assignees}}, {{.Title}} is due on {{date .Deadline}}",
+			"@alice, @bob, Ship it is due on " + time.Now().AddDate(0, 0, 10).Format("January 2") + "\n" + reminderMarker},
+		{"bad template", "{{.Nope}}", "hi @francesc, " + reminderText},
+		{"read as a reminder", `reminder: {{.Deadline.Format "2006-01-02"}}`, "hi @francesc, " + reminderText},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var bodies []string
+			ic := InstallationClient{appID: 42, installationID: 43,
+				opts: newOptions([]Option{WithTemplates(Templates{Reminder: tt.template})}),
+				client: &fakeClient{
+					_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) { return nil, nil },
+					_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+						return &issue{
+							repo: repository{owner, repo}, number: number, state: "open", author: "francesc",
+							title: "Ship it", body: "deadline: " + deadline, assignees: []string{"alice", "bob"},
+							comments: []comment{{
+								author: "francesc",
+								body:   fmt.Sprintf("reminder: %s\n", time.Now().Format("2006-01-02")),
+							}},
+						}, nil
+					},
+					_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error {
+						bodies = append(bodies, body)
+						return nil
+					},
+				}}
+
+			if err := ic.UpdateIssue(context.Background(), "foo", "bar", 1); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(bodies) != 1 || bodies[0] != tt.expected {
+				t.Errorf("expected comment %q; got %q", tt.expected, bodies)
+			}
+		})
+	}
+}
+
+func TestOverdueComment(t *testing.T) {
+	var bodies []string
+	ic := InstallationClient{appID: 42, installationID: 43,
+		opts: newOptions([]Option{WithTemplates(Templates{Overdue: "@{{.User}}, {{.Title}} is overdue"})}),
+		client: &fakeClient{
+			_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error {
+				bodies = append(bodies, body)
+				return nil
+			},
+		}}
+
+	i := &issue{repo: repository{"foo", "bar"}, number: 1, author: "francesc", title: "Ship it"}
+	check := func(deadline time.Time, expected int) {
+		i.deadline = deadline
+		if err := ic.checkOverdueComment(context.Background(), i, deadline); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(bodies) != expected {
+			t.Fatalf("expected %d comments; got %q", expected, bodies)
+		}
+	}
+
+	check(time.Now().AddDate(0, 0, 2), 0)
+	check(time.Now().AddDate(0, 0, -2).Truncate(time.Hour), 1)
+	if expected := "@francesc, Ship it is overdue\n" + overdueMarker; bodies[0] != expected {
+		t.Errorf("expected comment %q; got %q", expected, bodies[0])
+	}
+	check(i.deadline, 1)
+	// a new deadline that is missed too gets its own comment.
+	check(time.Now().AddDate(0, 0, -1).Truncate(time.Hour), 2)
+}
+
+func TestOverdueCommentRecorded(t *testing.T) {
+	ctx := context.Background()
+	var bodies []string
+	ic := &InstallationClient{appID: 42, installationID: 43,
+		opts: newOptions([]Option{WithTemplates(Templates{Overdue: "{{.Title}} is overdue"})}),
+		client: &fakeClient{
+			_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error {
+				bodies = append(bodies, body)
+				return nil
+			},
+		}}
+	deadline := time.Now().AddDate(0, 0, -2).Truncate(time.Hour)
+	i := &issue{repo: repository{"foo", "bar"}, number: 1, title: "Ship it", deadline: deadline}
+
+	// a comment that is only recorded is not remembered, so that discarding
+	// it posts it again on the next scan.
+	for n := 1; n <= 2; n++ {
+		scan, rec := ic.recording()
+		if err := scan.checkOverdueComment(ctx, i, deadline); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(rec.mutations) != 1 || len(bodies) != 0 {
+			t.Fatalf("expected the comment to be recorded on scan %d; got %v and %q", n, rec.mutations, bodies)
+		}
+		if n == 2 {
+			if err := ic.flush(ctx, rec); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+	}
+	if len(bodies) != 1 {
+		t.Fatalf("expected the comment to be posted once applied; got %q", bodies)
+	}
+	scan, rec := ic.recording()
+	if err := scan.checkOverdueComment(ctx, i, deadline); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rec.mutations) != 0 {
+		t.Errorf("expected the applied comment to be remembered; got %v", rec.mutations)
+	}
+}
+
+func TestNagTemplate(t *testing.T) {
+	var body string
+	ic := InstallationClient{appID: 42, installationID: 43,
+		opts: newOptions([]Option{
+			WithCadences(Cadence{Label: "sev1", Before: 7 * 24 * time.Hour}),
+			WithTemplates(Templates{Nag: "@{{.User}}, this {{.Label}} issue is due in {{.DaysLeft}} days"}),
+		}),
+		client: &fakeClient{
+			_createIssueComment: func(ctx context.Context, owner, repo string, number int, b string) error {
+				body = b
+				return nil
+			},
+		}}
+
+	deadline := time.Now().AddDate(0, 0, 5).Add(time.Hour)
+	i := &issue{repo: repository{"foo", "bar"}, number: 1, author: "francesc", labels: []string{"sev1"}, deadline: deadline}
+	if err := ic.checkCadence(context.Background(), i, deadline); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := "@francesc, this sev1 issue is due in 5 days\n" + cadenceMarker; body != expected {
+		t.Errorf("expected comment %q; got %q", expected, body)
+	}
+}
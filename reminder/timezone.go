@@ -0,0 +1,122 @@
+package reminder
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// WithTimezone sets the timezone of the dates written without one, UTC by default.
+func WithTimezone(loc *time.Location) Option {
+	return func(o *options) { o.location = loc }
+}
+
+// WithEndOfDay makes the deadlines written without a time of day due at 23:59
+// of that day, in the timezone of the bot, instead of at its start.
+func WithEndOfDay() Option {
+	return func(o *options) { o.endOfDay = true }
+}
+
+// zoneAbbreviations are the offsets in minutes of common timezone
+// abbreviations, which unlike the zone names don't change with daylight saving.
+var zoneAbbreviations = map[string]int{
+	"utc": 0, "gmt": 0, "z": 0,
+	"wet": 0, "west": 60, "bst": 60,
+	"cet": 60, "cest": 120,
+	"eet": 120, "eest": 180,
+	"msk": 180, "ist": 330, "jst": 540, "aest": 600,
+	"est": -300, "edt": -240,
+	"cst": -360, "cdt": -300,
+	"mst": -420, "mdt": -360,
+	"pst": -480, "pdt": -420,
+}
+
+// allDay reports whether t is the start of a day in the bot's timezone, as the
+// dates written without a time of day are.
+func (c *InstallationClient) allDay(t time.Time) bool {
+	loc := c.opts.location
+	if loc == nil {
+		loc = time.UTC
+	}
+	t = t.In(loc)
+	return t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0
+}
+
+// endOfDay moves an all-day deadline to 23:59 of its day if the deadlines are
+// due at the end of the day.
+func (c *InstallationClient) endOfDay(deadline time.Time) time.Time {
+	if !c.opts.endOfDay || deadline.IsZero() || !c.allDay(deadline) {
+		return deadline
+	}
+	loc := c.opts.location
+	if loc == nil {
+		loc = time.UTC
+	}
+	return deadline.In(loc).AddDate(0, 0, 1).Add(-time.Minute).UTC()
+}
+
+// ParseTimezone parses a timezone given as an abbreviation like CET, a name
+// like Europe/Madrid, or an offset like +02:00, regardless of its case.
+func ParseTimezone(s string) (*time.Location, error) {
+	if loc := parseZone(strings.ToLower(strings.TrimSpace(s))); loc != nil {
+		return loc, nil
+	}
+	return nil, errors.Errorf("unknown timezone %q", s)
+}
+
+// parseZone parses a lower case timezone, returning nil if it's not one.
+func parseZone(s string) *time.Location {
+	if offset, ok := zoneAbbreviations[s]; ok {
+		return time.FixedZone(strings.ToUpper(s), offset*60)
+	}
+
+	if len(s) > 1 && (s[0] == '+' || s[0] == '-') {
+		digits := strings.Replace(s[1:], ":", "", 1)
+		if len(digits) == 2 {
+			digits += "00"
+		}
+		if len(digits) != 4 {
+			return nil
+		}
+		hours, herr := strconv.Atoi(digits[:2])
+		mins, merr := strconv.Atoi(digits[2:])
+		if herr != nil || merr != nil || hours > 14 || mins > 59 {
+			return nil
+		}
+		offset := hours*60*60 + mins*60
+		if s[0] == '-' {
+			offset = -offset
+		}
+		return time.FixedZone(strings.ToUpper(s), offset)
+	}
+
+	if !strings.Contains(s, "/") {
+		return nil
+	}
+	// zone names are case sensitive, like America/New_York, but bodies are read in lower case.
+	name := []byte(s)
+	for i := range name {
+		if i == 0 || strings.IndexByte("/_-", name[i-1]) >= 0 {
+			name[i] = strings.ToUpper(string(name[i]))[0]
+		}
+	}
+	loc, err := time.LoadLocation(string(name))
+	if err != nil {
+		return nil
+	}
+	return loc
+}
+
+// splitZone separates the timezone at the end of s, if any.
+func splitZone(s string) (string, *time.Location) {
+	i := strings.LastIndex(s, " ")
+	if i < 0 {
+		return s, nil
+	}
+	if loc := parseZone(s[i+1:]); loc != nil {
+		return strings.TrimSpace(s[:i]), loc
+	}
+	return s, nil
+}
@@ -0,0 +1,69 @@
+package reminder
+
+import (
+	"testing"
+	"time"
+)
+
+func TestZonedDates(t *testing.T) {
+	madrid, err := ParseTimezone("Europe/Madrid")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		s        string
+		loc      *time.Location
+		expected time.Time
+	}{
+		{"2018-08-01", nil, time.Date(2018, 8, 1, 0, 0, 0, 0, time.UTC)},
+		{"2018-08-01 18:00", nil, time.Date(2018, 8, 1, 18, 0, 0, 0, time.UTC)},
+		{"2018-08-01 18:00 cet", nil, time.Date(2018, 8, 1, 17, 0, 0, 0, time.UTC)},
+		{"2018-08-01 18:00 +02:00", nil, time.Date(2018, 8, 1, 16, 0, 0, 0, time.UTC)},
+		{"2018-08-01 18:00 -0530", nil, time.Date(2018, 8, 1, 23, 30, 0, 0, time.UTC)},
+		{"2018-08-01 18:00 america/new_york", nil, time.Date(2018, 8, 1, 22, 0, 0, 0, time.UTC)},
+		{"2018-08-01 18:00", madrid, time.Date(2018, 8, 1, 16, 0, 0, 0, time.UTC)},
+		{"2018-08-01 18:00 utc", madrid, time.Date(2018, 8, 1, 18, 0, 0, 0, time.UTC)},
+		{"2018-08-01 18:00 mars/olympus", nil, time.Time{}},
+	}
+	for _, tt := range tests {
+		if got := parseDate(tt.s, tt.loc, nil); !got.Equal(tt.expected) {
+			t.Errorf("%q: expected %v; got %v", tt.s, tt.expected, got)
+		}
+	}
+
+	created := time.Date(2018, 8, 1, 23, 30, 0, 0, time.UTC)
+	if got, expected := (dates{loc: madrid, parsers: DefaultDateParsers}).parse("tomorrow", created), time.Date(2018, 8, 2, 22, 0, 0, 0, time.UTC); !got.Equal(expected) {
+		t.Errorf("expected tomorrow in Madrid to be %v; got %v", expected, got)
+	}
+
+	if _, err := ParseTimezone("nowhere"); err == nil {
+		t.Errorf("expected unknown timezone to fail")
+	}
+}
+
+func TestEndOfDay(t *testing.T) {
+	madrid, err := ParseTimezone("Europe/Madrid")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	day := time.Date(2018, 8, 1, 0, 0, 0, 0, madrid)
+	noon := time.Date(2018, 8, 1, 12, 0, 0, 0, madrid)
+
+	ic := InstallationClient{opts: newOptions([]Option{WithTimezone(madrid)})}
+	if got := ic.endOfDay(day); !got.Equal(day) {
+		t.Errorf("expected the start of the day by default; got %v", got)
+	}
+
+	ic.opts = newOptions([]Option{WithTimezone(madrid), WithEndOfDay()})
+	tests := []struct{ deadline, expected time.Time }{
+		{day, time.Date(2018, 8, 1, 23, 59, 0, 0, madrid)},
+		{noon, noon},
+		{time.Time{}, time.Time{}},
+	}
+	for _, tt := range tests {
+		if got := ic.endOfDay(tt.deadline); !got.Equal(tt.expected) {
+			t.Errorf("%v: expected %v; got %v", tt.deadline, tt.expected, got)
+		}
+	}
+}
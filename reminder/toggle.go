@@ -0,0 +1,167 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+// disabledRepo records who disabled the bot in a repository.
+type disabledRepo struct {
+	By   string    `json:"by"`
+	Time time.Time `json:"time"`
+}
+
+func disabledKey(appID, installationID int, owner, repo string) string {
+	return storage.Key("disabled", appID, installationID, strings.ToLower(owner), strings.ToLower(repo))
+}
+
+// Disabled checks whether the bot was disabled in the repository with /reminder
+// disable, or in its configuration file.
+func (c *InstallationClient) Disabled(ctx context.Context, owner, repo string) (bool, error) {
+	var d disabledRepo
+	err := c.opts.store.Get(ctx, disabledKey(c.appID, c.installationID, owner, repo), &d)
+	if err == storage.ErrNotFound {
+		return c.opts.disabled, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, "could not check whether the repository is disabled")
+	}
+	return true, nil
+}
+
+// reminderCommand lets repository admins turn the bot on and off, choose
+// the grammar it reads the repository with, the language of its dates, the
+// synonyms of its keywords, the prefix of its labels, and how days are counted.
+//
+//	/reminder enable
+//	/reminder disable
+//	/reminder grammar 2
+//	/reminder language es
+//	/reminder synonyms deadline due eta
+//	/reminder labels due-in:
+//	/reminder days business
+func reminderCommand(ctx context.Context, c *InstallationClient, cmd Command) error {
+	reply := func(text string) error {
+		return c.client.createIssueComment(ctx, cmd.Owner, cmd.Repo, cmd.Number, fmt.Sprintf("@%s %s", cmd.Author, text))
+	}
+	const usage = "usage: `/reminder enable`, `/reminder disable`, `/reminder grammar <version>`, " +
+		"`/reminder language <code>`, `/reminder synonyms deadline|reminder [<word>...]`, " +
+		"`/reminder labels <prefix>`, or `/reminder days business|calendar`."
+
+	if len(cmd.Args) == 0 {
+		return reply(usage)
+	}
+	action := strings.ToLower(cmd.Args[0])
+	switch {
+	case (action == "enable" || action == "disable") && len(cmd.Args) == 1:
+	case action == "grammar" && len(cmd.Args) == 2:
+	case action == "days" && len(cmd.Args) == 2:
+	case action == "language" && len(cmd.Args) == 2:
+	case action == "synonyms" && len(cmd.Args) >= 2:
+	case action == "labels" && len(cmd.Args) >= 2:
+	default:
+		return reply(usage)
+	}
+
+	perm, err := c.client.permission(ctx, cmd.Owner, cmd.Repo, cmd.Author)
+	if err != nil {
+		return errors.Wrapf(err, "could not check permissions for %s", cmd.Author)
+	}
+	if !strings.EqualFold(perm, "admin") {
+		logrus.Warnf("%s can not run /reminder %s in %s/%s", cmd.Author, action, cmd.Owner, cmd.Repo)
+		return reply("only repository admins can configure the reminders.")
+	}
+
+	if action == "grammar" {
+		g, err := ParseGrammar(cmd.Args[1])
+		if err != nil {
+			return reply(fmt.Sprintf("%v.", err))
+		}
+		rg := repoGrammar{Version: g, By: cmd.Author, Time: time.Now()}
+		if err := c.opts.store.Put(ctx, grammarKey(c.appID, c.installationID, cmd.Owner, cmd.Repo), rg); err != nil {
+			return errors.Wrap(err, "could not set grammar")
+		}
+		return reply(fmt.Sprintf("this repository now uses grammar %s.", g))
+	}
+
+	if action == "language" {
+		lang, err := ParseLanguage(cmd.Args[1])
+		if err != nil {
+			return reply(fmt.Sprintf("%v.", err))
+		}
+		rl := repoLanguage{Language: lang, By: cmd.Author, Time: time.Now()}
+		if err := c.opts.store.Put(ctx, languageKey(c.appID, c.installationID, cmd.Owner, cmd.Repo), rl); err != nil {
+			return errors.Wrap(err, "could not set language")
+		}
+		return reply(fmt.Sprintf("dates in this repository can now be written in %s.", lang))
+	}
+
+	if action == "synonyms" {
+		keyword := strings.ToLower(cmd.Args[1])
+		if keyword != "deadline" && keyword != "reminder" {
+			return reply(usage)
+		}
+		words, err := parseSynonyms(keyword, splitSynonyms(cmd.Args[2:]))
+		if err != nil {
+			return reply(fmt.Sprintf("%v.", err))
+		}
+		rs, err := c.repoSynonyms(ctx, cmd.Owner, cmd.Repo)
+		if err != nil {
+			return err
+		}
+		if keyword == "deadline" {
+			rs.Deadline = words
+		} else {
+			rs.Reminder = words
+		}
+		rs.By, rs.Time = cmd.Author, time.Now()
+		if err := c.opts.store.Put(ctx, synonymsKey(c.appID, c.installationID, cmd.Owner, cmd.Repo), rs); err != nil {
+			return errors.Wrap(err, "could not set synonyms")
+		}
+		if len(words) == 0 {
+			return reply(fmt.Sprintf("%s has no synonyms in this repository anymore.", keyword))
+		}
+		return reply(fmt.Sprintf("%s can now be written as %s: in this repository.", keyword, strings.Join(words, ":, ")))
+	}
+
+	if action == "labels" {
+		prefix := strings.Join(cmd.Args[1:], " ")
+		rp := repoLabelPrefix{Prefix: prefix, By: cmd.Author, Time: time.Now()}
+		if err := c.opts.store.Put(ctx, labelPrefixKey(c.appID, c.installationID, cmd.Owner, cmd.Repo), rp); err != nil {
+			return errors.Wrap(err, "could not set label prefix")
+		}
+		return reply(fmt.Sprintf("deadline labels in this repository are now named like `%s`.", LabelName(prefix, 5)))
+	}
+
+	if action == "days" {
+		days := strings.ToLower(cmd.Args[1])
+		if days != businessDays && days != calendarDays {
+			return reply(usage)
+		}
+		rd := repoDays{Business: days == businessDays, By: cmd.Author, Time: time.Now()}
+		if err := c.opts.store.Put(ctx, daysKey(c.appID, c.installationID, cmd.Owner, cmd.Repo), rd); err != nil {
+			return errors.Wrap(err, "could not set days")
+		}
+		return reply(fmt.Sprintf("deadline labels now count %s days in this repository.", days))
+	}
+
+	key := disabledKey(c.appID, c.installationID, cmd.Owner, cmd.Repo)
+	if action == "enable" {
+		if err := c.opts.store.Delete(ctx, key); err != nil {
+			return errors.Wrap(err, "could not enable repository")
+		}
+		return reply("reminders are enabled for this repository.")
+	}
+
+	if err := c.opts.store.Put(ctx, key, disabledRepo{By: cmd.Author, Time: time.Now()}); err != nil {
+		return errors.Wrap(err, "could not disable repository")
+	}
+	return reply("reminders are disabled for this repository, use `/reminder enable` to turn them back on.")
+}
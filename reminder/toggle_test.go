@@ -0,0 +1,53 @@
+package reminder
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestReminderCommand(t *testing.T) {
+	ctx := context.Background()
+	var comments []string
+	perms := map[string]string{"admin": "admin", "dev": "write"}
+	scanned := 0
+	ic := InstallationClient{appID: 42, installationID: 43, opts: newOptions(nil), client: &fakeClient{
+		_permission: func(ctx context.Context, owner, repo, user string) (string, error) { return perms[user], nil },
+		_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error {
+			comments = append(comments, body)
+			return nil
+		},
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) {
+			scanned++
+			return nil, nil
+		},
+	}}
+
+	run := func(author, body string) {
+		if ok, err := ic.HandleComment(ctx, "foo", "bar", 1, author, body); !ok || err != nil {
+			t.Fatalf("expected command to run; got %v, %v", ok, err)
+		}
+	}
+
+	run("dev", "/reminder disable")
+	if disabled, _ := ic.Disabled(ctx, "foo", "bar"); disabled {
+		t.Fatalf("expected non admins not to be able to disable the bot")
+	}
+
+	run("admin", "/reminder disable")
+	if disabled, _ := ic.Disabled(ctx, "Foo", "Bar"); !disabled {
+		t.Fatalf("expected repository to be disabled")
+	}
+	if err := ic.UpdateRepo(ctx, "foo", "bar"); err != nil || scanned != 0 {
+		t.Errorf("expected disabled repository not to be scanned; got %d scans (%v)", scanned, err)
+	}
+
+	run("admin", "/reminder enable")
+	if err := ic.UpdateRepo(ctx, "foo", "bar"); err != nil || scanned != 1 {
+		t.Errorf("expected enabled repository to be scanned; got %d scans (%v)", scanned, err)
+	}
+
+	if len(comments) != 3 || !strings.Contains(comments[0], "only repository admins") {
+		t.Errorf("unexpected replies %q", comments)
+	}
+}
@@ -0,0 +1,97 @@
+package reminder
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// newUserClient can be replaced by test cases.
+var newUserClient = func(client *http.Client, baseURL *url.URL) client {
+	return &githubClient{client: newGitHubClient(client, baseURL), user: true}
+}
+
+// NewTokenClient returns an InstallationClient authenticated with a token rather
+// than as a GitHub App installation. Fine-grained and classic personal access tokens
+// are supported, as well as OAuth tokens.
+// The client operates on all of the repositories the token has access to.
+// Its state is kept as that of an installation with the id of the user of the
+// token, fetched here, so the clients of different users don't share it, and
+// the comments of that user are the ones of the bot.
+// If transport is nil http.DefaultTransport will be used.
+func NewTokenClient(ctx context.Context, token string, transport http.RoundTripper, opts ...Option) (*InstallationClient, error) {
+	if token == "" {
+		return nil, errors.New("missing token")
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	o := newOptions(opts)
+	baseURL, err := o.apiURL()
+	if err != nil {
+		return nil, err
+	}
+
+	hc := &http.Client{Transport: &tokenTransport{token: token, base: transport}}
+	user, _, err := newGitHubClient(hc, baseURL).Users.Get(ctx, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch the user of the token")
+	}
+	o.login = user.GetLogin()
+	return newInstallationClient(0, int(user.GetID()), newUserClient(hc, baseURL), o), nil
+}
+
+// requiredScopes are the classic OAuth scopes, any of which grants access to issues and labels.
+var requiredScopes = []string{"repo", "public_repo"}
+
+// tokenTransport authenticates requests with a token, warning about missing
+// scopes once the first response is received.
+type tokenTransport struct {
+	token string
+	base  http.RoundTripper
+	once  sync.Once
+}
+
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := new(http.Request)
+	*r = *req
+	r.Header = make(http.Header, len(req.Header)+1)
+	for k, v := range req.Header {
+		r.Header[k] = append([]string(nil), v...)
+	}
+	r.Header.Set("Authorization", "token "+t.token)
+
+	res, err := t.base.RoundTrip(r)
+	if err == nil {
+		t.once.Do(func() { t.checkScopes(res) })
+	}
+	return res, err
+}
+
+func (t *tokenTransport) checkScopes(res *http.Response) {
+	if strings.HasPrefix(t.token, "github_pat_") {
+		logrus.Infof("using a fine-grained token, make sure it has read and write access to issues and pull requests")
+		return
+	}
+
+	header, ok := res.Header["X-Oauth-Scopes"]
+	if !ok {
+		logrus.Warnf("could not introspect token scopes")
+		return
+	}
+	for _, scope := range strings.Split(strings.Join(header, ","), ",") {
+		for _, req := range requiredScopes {
+			if strings.TrimSpace(scope) == req {
+				return
+			}
+		}
+	}
+	logrus.Warnf("token scopes %q do not include any of %v, labels and comments will fail",
+		strings.Join(header, ","), requiredScopes)
+}
@@ -0,0 +1,84 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTokenTransport(t *testing.T) {
+	var got string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("Authorization")
+		w.Header().Set("X-OAuth-Scopes", "repo, read:org")
+	}))
+	defer ts.Close()
+
+	client := &http.Client{Transport: &tokenTransport{token: "secret", base: http.DefaultTransport}}
+	req, _ := http.NewRequest("GET", ts.URL, nil)
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	res.Body.Close()
+
+	if got != "token secret" {
+		t.Errorf("expected authorization header %q; got %q", "token secret", got)
+	}
+	if req.Header.Get("Authorization") != "" {
+		t.Errorf("original request should not be modified")
+	}
+}
+
+func TestTokenClient(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/user" || r.Header.Get("Authorization") != "token secret" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"login": "francesc", "id": 7}`)
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	c, err := NewTokenClient(ctx, "secret", nil, WithBaseURL(ts.URL+"/api/v3/"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.appID != 0 || c.installationID != 7 || c.login() != "francesc" {
+		t.Errorf("expected the state and comments of the user 7; got %d/%d by %s", c.appID, c.installationID, c.login())
+	}
+	if _, err := NewTokenClient(ctx, "wrong", nil, WithBaseURL(ts.URL+"/api/v3/")); err == nil {
+		t.Errorf("expected a client with a bad token to fail")
+	}
+}
+
+func TestTokenClientReminders(t *testing.T) {
+	comments := []comment{{author: "alice", body: "reminder: " + time.Now().Format("2006-01-02")}}
+	posted := 0
+	c := newInstallationClient(0, 7, &fakeClient{
+		_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) { return nil, nil },
+		_issue: func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+			return &issue{repo: repository{owner, repo}, number: number, author: "alice", state: "open", comments: comments}, nil
+		},
+		_createIssueComment: func(ctx context.Context, owner, repo string, number int, body string) error {
+			posted++
+			// the comments of a token client are authored by its user.
+			comments = append(comments, comment{author: "francesc", body: body, created: time.Now()})
+			return nil
+		},
+	}, newOptions(nil))
+	c.opts.login = "francesc"
+
+	for i := 0; i < 2; i++ {
+		if err := c.UpdateIssue(context.Background(), "foo", "bar", 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if posted != 1 {
+		t.Errorf("expected one reminder after two scans; got %d", posted)
+	}
+}
@@ -0,0 +1,94 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/src-d/github-reminder/storage"
+)
+
+// welcomeMarker is hidden in the body of the getting started issues, so the
+// bot doesn't read their examples, which older grammars would.
+const welcomeMarker = "<!-- github-reminder:welcome -->"
+
+// WithWelcomeIssue makes the bot open a getting started issue in every
+// repository of the new installations, explaining how to write deadlines,
+// the deadline labels, and the configuration file.
+func WithWelcomeIssue() Option {
+	return func(o *options) { o.welcome = true }
+}
+
+// Welcome greets a new installation: it opens the getting started issues, if
+// enabled, and scans the installation right away, so its users see the bot
+// working without waiting for the next scheduled scan. A repository whose
+// getting started issue can't be opened doesn't stop the others.
+func (c *InstallationClient) Welcome(ctx context.Context) error {
+	logrus.Infof("welcoming installation %d/%d", c.appID, c.installationID)
+	if c.opts.welcome {
+		repos, err := c.client.repos(ctx)
+		if err != nil {
+			return errors.Wrap(err, "could not list repositories")
+		}
+		for _, r := range repos {
+			if err := c.openWelcomeIssue(ctx, r.owner, r.name); err != nil {
+				logrus.Warnf("could not welcome %s/%s: %v", r.owner, r.name, err)
+			}
+		}
+	}
+	return c.UpdateInstallation(ctx)
+}
+
+// openWelcomeIssue opens the getting started issue of the repository, unless
+// it was opened already or the bot is disabled in it.
+func (c *InstallationClient) openWelcomeIssue(ctx context.Context, owner, repo string) error {
+	key := storage.Key("welcome", c.appID, c.installationID, strings.ToLower(owner), strings.ToLower(repo))
+	var number int
+	err := c.opts.store.Get(ctx, key, &number)
+	if err != storage.ErrNotFound {
+		return errors.Wrap(err, "could not fetch welcome issue")
+	}
+	rc, err := c.forRepo(ctx, owner, repo)
+	if err != nil {
+		return err
+	}
+	if disabled, err := rc.Disabled(ctx, owner, repo); err != nil || disabled {
+		return err
+	}
+	prefix, err := rc.LabelPrefix(ctx, owner, repo)
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("opening welcome issue in %s/%s", owner, repo)
+	err = c.openIssue(ctx, owner, repo, "Getting started with deadlines", welcomeBody(prefix), key, false)
+	return errors.Wrapf(err, "could not open welcome issue in %s/%s", owner, repo)
+}
+
+// isWelcomeIssue reports whether the issue is a getting started issue of the
+// bot.
+func isWelcomeIssue(issue *issue) bool {
+	return strings.Contains(issue.body, welcomeMarker)
+}
+
+// welcomeBody returns the body of the getting started issues.
+func welcomeBody(prefix string) string {
+	return fmt.Sprintf(welcomeMarker+`
+This repository now has a bot keeping track of the deadlines of its issues and pull requests.
+
+To give an issue a deadline, write a line like this in its title, body, or any comment:
+
+`+"```"+`
+deadline: 2018-06-20
+`+"```"+`
+
+Every day the issues with a deadline get the label of this repository closest to it, so create labels like `+"`%s`, `%s`, and `%s`"+`, applied when less than 1, 3, or 7 days are left. Lines like `+"`reminder: 2018-06-18`"+` get you a comment on that day.
+
+The bot can be configured with a `+"`.github/github-reminder.yml`"+` file in the default branch, and turned off by commenting `+"`/reminder disable`"+`.
+
+This issue can be closed.
+`, LabelName(prefix, 1), LabelName(prefix, 3), LabelName(prefix, 7))
+}
@@ -0,0 +1,73 @@
+package reminder
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestWelcomeIssue(t *testing.T) {
+	var bodies []string
+	ic := InstallationClient{appID: 42, installationID: 43,
+		opts: newOptions([]Option{WithWelcomeIssue(), WithLabelPrefix("due-in:")}),
+		client: &fakeClient{
+			_file: func(ctx context.Context, owner, repo, path string) ([]byte, error) { return nil, nil },
+			_createIssue: func(ctx context.Context, owner, repo, title, body string) (int, error) {
+				bodies = append(bodies, body)
+				return 7, nil
+			},
+		}}
+
+	for i := 0; i < 2; i++ {
+		if err := ic.openWelcomeIssue(context.Background(), "foo", "bar"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(bodies) != 1 {
+		t.Fatalf("expected a single welcome issue; got %d", len(bodies))
+	}
+	if !strings.Contains(bodies[0], "`due-in: 3`") {
+		t.Errorf("expected the labels of the repository in the welcome issue; got %q", bodies[0])
+	}
+
+	// the examples aren't read, as the issue is skipped.
+	ic.client.(*fakeClient)._issue = func(ctx context.Context, owner, repo string, number int) (*issue, error) {
+		return &issue{repo: repository{owner, repo}, number: number, state: "open", body: bodies[0]}, nil
+	}
+	if err := ic.updateIssue(context.Background(), "foo", "bar", 7, "", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ds, err := ic.Deadlines(context.Background()); err != nil || len(ds) != 0 {
+		t.Errorf("expected the welcome issue to be skipped; got deadlines %v (%v)", ds, err)
+	}
+}
+
+func TestWelcomeContinues(t *testing.T) {
+	var opened []string
+	ic := InstallationClient{appID: 42, installationID: 43,
+		opts: newOptions([]Option{WithWelcomeIssue()}),
+		client: &fakeClient{
+			_repos: func(ctx context.Context) ([]repository, error) {
+				return []repository{{"foo", "bar"}, {"foo", "baz"}}, nil
+			},
+			_issues:     func(ctx context.Context, owner, repo string) ([]int, error) { return nil, nil },
+			_repoLabels: func(ctx context.Context, owner, repo string) ([]string, error) { return nil, nil },
+			_file:       func(ctx context.Context, owner, repo, path string) ([]byte, error) { return nil, nil },
+			_createIssue: func(ctx context.Context, owner, repo, title, body string) (int, error) {
+				if repo == "bar" {
+					return 0, errors.New("issues are disabled")
+				}
+				opened = append(opened, repo)
+				return 7, nil
+			},
+		}}
+
+	if err := ic.Welcome(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(opened) != 1 || opened[0] != "baz" {
+		t.Errorf("expected the rest of the repositories to be welcomed; got %v", opened)
+	}
+}
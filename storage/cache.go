@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+type cacheEntry struct {
+	key string
+	// data is nil if the key was not found.
+	data    []byte
+	expires time.Time
+}
+
+type cache struct {
+	Store
+	size int
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+// NewCache returns a Store keeping up to size of the values read from s in
+// memory for the given time, missing keys included. Since scans read the same
+// per-issue state over and over this saves most of the round trips to remote
+// stores. Writes go through to s, so only values changed by other processes
+// can be seen up to ttl late. Listings are never cached.
+func NewCache(s Store, size int, ttl time.Duration) Store {
+	return &cache{Store: s, size: size, ttl: ttl, entries: make(map[string]*list.Element), lru: list.New()}
+}
+
+func (c *cache) lookup(key string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*cacheEntry)
+	if time.Now().After(e.expires) {
+		c.lru.Remove(el)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	return e, true
+}
+
+func (c *cache) add(key string, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e := &cacheEntry{key: key, data: data, expires: time.Now().Add(c.ttl)}
+	if el, ok := c.entries[key]; ok {
+		el.Value = e
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(e)
+	for c.lru.Len() > c.size {
+		el := c.lru.Back()
+		c.lru.Remove(el)
+		delete(c.entries, el.Value.(*cacheEntry).key)
+	}
+}
+
+func (c *cache) evict(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		c.lru.Remove(el)
+		delete(c.entries, key)
+	}
+}
+
+func (c *cache) Get(ctx context.Context, key string, v interface{}) error {
+	if e, ok := c.lookup(key); ok {
+		if e.data == nil {
+			return ErrNotFound
+		}
+		return decode(key, e.data, v)
+	}
+
+	var raw json.RawMessage
+	err := c.Store.Get(ctx, key, &raw)
+	if err == ErrNotFound {
+		c.add(key, nil)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	c.add(key, raw)
+	return decode(key, raw, v)
+}
+
+func (c *cache) Put(ctx context.Context, key string, v interface{}) error {
+	data, err := encode(key, v)
+	if err != nil {
+		return err
+	}
+	if err := c.Store.Put(ctx, key, json.RawMessage(data)); err != nil {
+		c.evict(key)
+		return err
+	}
+	c.add(key, data)
+	return nil
+}
+
+func (c *cache) Delete(ctx context.Context, key string) error {
+	if err := c.Store.Delete(ctx, key); err != nil {
+		c.evict(key)
+		return err
+	}
+	c.add(key, nil)
+	return nil
+}
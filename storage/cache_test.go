@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// countingStore counts the reads reaching the underlying store.
+type countingStore struct {
+	Store
+	gets int
+}
+
+func (s *countingStore) Get(ctx context.Context, key string, v interface{}) error {
+	s.gets++
+	return s.Store.Get(ctx, key, v)
+}
+
+func TestCache(t *testing.T) {
+	ctx := context.Background()
+	backend := &countingStore{Store: NewMemory()}
+	if err := backend.Put(ctx, "a", 1); err != nil {
+		t.Fatal(err)
+	}
+	c := NewCache(backend, 2, time.Hour)
+
+	var v int
+	for i := 0; i < 3; i++ {
+		if err := c.Get(ctx, "a", &v); err != nil || v != 1 {
+			t.Fatalf("expected 1; got %d (%v)", v, err)
+		}
+		if err := c.Get(ctx, "missing", &v); err != ErrNotFound {
+			t.Fatalf("expected ErrNotFound; got %v", err)
+		}
+	}
+	if backend.gets != 2 {
+		t.Errorf("expected 2 reads to reach the store; got %d", backend.gets)
+	}
+
+	if err := c.Put(ctx, "missing", 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, "missing", &v); err != nil || v != 2 {
+		t.Errorf("expected written value 2; got %d (%v)", v, err)
+	}
+	if err := backend.Get(ctx, "missing", &v); err != nil || v != 2 {
+		t.Errorf("expected write to reach the store; got %d (%v)", v, err)
+	}
+
+	if err := c.Delete(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, "a", &v); err != ErrNotFound {
+		t.Errorf("expected deleted key not to be found; got %v", err)
+	}
+
+	backend.gets = 0
+	c.Put(ctx, "b", 3)
+	c.Put(ctx, "c", 4)
+	if err := c.Get(ctx, "missing", &v); err != nil || backend.gets != 1 {
+		t.Errorf("expected least recently used key to be evicted; got %d reads (%v)", backend.gets, err)
+	}
+}
+
+func TestCacheExpiration(t *testing.T) {
+	ctx := context.Background()
+	backend := &countingStore{Store: NewMemory()}
+	c := NewCache(backend, 10, -time.Second)
+
+	var v int
+	c.Get(ctx, "a", &v)
+	c.Get(ctx, "a", &v)
+	if backend.gets != 2 {
+		t.Errorf("expected expired entries to be read again; got %d reads", backend.gets)
+	}
+}
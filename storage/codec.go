@@ -0,0 +1,16 @@
+package storage
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+func encode(key string, v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	return data, errors.Wrapf(err, "could not encode %s", key)
+}
+
+func decode(key string, data []byte, v interface{}) error {
+	return errors.Wrapf(json.Unmarshal(data, v), "could not decode %s", key)
+}
@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type memory struct {
+	mu     sync.RWMutex
+	values map[string][]byte
+}
+
+// NewMemory returns a Store keeping all of its values in memory.
+func NewMemory() Store {
+	return &memory{values: make(map[string][]byte)}
+}
+
+func (m *memory) Get(ctx context.Context, key string, v interface{}) error {
+	m.mu.RLock()
+	data, ok := m.values[key]
+	m.mu.RUnlock()
+	if !ok {
+		return ErrNotFound
+	}
+	return decode(key, data, v)
+}
+
+func (m *memory) Put(ctx context.Context, key string, v interface{}) error {
+	data, err := encode(key, v)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	m.values[key] = data
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *memory) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	delete(m.values, key)
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *memory) List(ctx context.Context, prefix string) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var keys []string
+	for k := range m.values {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// A PostgresOption modifies the default behavior of a Postgres store.
+type PostgresOption func(*postgres)
+
+// WithReplica sends reads to a read replica of the database, keeping the
+// primary for writes. Keys written recently, as given by WithReplicaLag, are
+// still read from the primary so they're never seen out of date.
+func WithReplica(db *sql.DB) PostgresOption {
+	return func(p *postgres) { p.replica = db }
+}
+
+// WithReplicaLag sets how long the replica can take to catch up with the
+// primary, 5 seconds by default.
+func WithReplicaLag(d time.Duration) PostgresOption {
+	return func(p *postgres) { p.lag = d }
+}
+
+type postgres struct {
+	primary *sql.DB
+	replica *sql.DB
+	lag     time.Duration
+
+	mu sync.Mutex
+	// written holds when the keys recently written to the primary were written.
+	written map[string]time.Time
+}
+
+// NewPostgres returns a Store keeping its values in the github_reminder table
+// of a Postgres database, which is created if needed.
+func NewPostgres(ctx context.Context, db *sql.DB, opts ...PostgresOption) (Store, error) {
+	p := &postgres{primary: db, lag: 5 * time.Second, written: make(map[string]time.Time)}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS github_reminder (
+		key TEXT PRIMARY KEY,
+		value TEXT NOT NULL
+	)`)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create table")
+	}
+	return p, nil
+}
+
+// reader returns the database to read from, the primary if any of the keys
+// matching the given one, or starting with it if it's a prefix, were recently written.
+func (p *postgres) reader(key string, prefix bool) *sql.DB {
+	if p.replica == nil {
+		return p.primary
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	for k, t := range p.written {
+		if now.Sub(t) > p.lag {
+			delete(p.written, k)
+			continue
+		}
+		if k == key || (prefix && strings.HasPrefix(k, key)) {
+			return p.primary
+		}
+	}
+	return p.replica
+}
+
+func (p *postgres) wrote(key string) {
+	if p.replica == nil {
+		return
+	}
+	p.mu.Lock()
+	p.written[key] = time.Now()
+	p.mu.Unlock()
+}
+
+func (p *postgres) Get(ctx context.Context, key string, v interface{}) error {
+	var data string
+	err := p.reader(key, false).QueryRowContext(ctx, `SELECT value FROM github_reminder WHERE key = $1`, key).Scan(&data)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return errors.Wrapf(err, "could not fetch %s", key)
+	}
+	return decode(key, []byte(data), v)
+}
+
+func (p *postgres) Put(ctx context.Context, key string, v interface{}) error {
+	data, err := encode(key, v)
+	if err != nil {
+		return err
+	}
+	_, err = p.primary.ExecContext(ctx, `INSERT INTO github_reminder (key, value) VALUES ($1, $2)
+		ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value`, key, string(data))
+	if err != nil {
+		return errors.Wrapf(err, "could not store %s", key)
+	}
+	p.wrote(key)
+	return nil
+}
+
+func (p *postgres) Delete(ctx context.Context, key string) error {
+	_, err := p.primary.ExecContext(ctx, `DELETE FROM github_reminder WHERE key = $1`, key)
+	if err != nil {
+		return errors.Wrapf(err, "could not delete %s", key)
+	}
+	p.wrote(key)
+	return nil
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (p *postgres) List(ctx context.Context, prefix string) ([]string, error) {
+	rows, err := p.reader(prefix, true).QueryContext(ctx,
+		`SELECT key FROM github_reminder WHERE key LIKE $1 ORDER BY key COLLATE "C"`, likeEscaper.Replace(prefix)+"%")
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not list %s", prefix)
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, errors.Wrapf(err, "could not list %s", prefix)
+		}
+		keys = append(keys, key)
+	}
+	return keys, errors.Wrapf(rows.Err(), "could not list %s", prefix)
+}
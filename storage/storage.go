@@ -0,0 +1,34 @@
+// Package storage provides the persistence layer used by the github-reminder app.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNotFound is returned by Get when the given key does not exist.
+var ErrNotFound = errors.New("key not found")
+
+// A Store is a key-value store where values are encoded as JSON.
+type Store interface {
+	// Get decodes the value stored under key into v.
+	// If no value is found ErrNotFound is returned.
+	Get(ctx context.Context, key string, v interface{}) error
+	// Put stores v under the given key, replacing any previous value.
+	Put(ctx context.Context, key string, v interface{}) error
+	// Delete removes the given key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+	// List returns all of the keys with the given prefix in lexicographical order.
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// Key joins the given parts into a key separated by slashes.
+func Key(parts ...interface{}) string {
+	ss := make([]string, len(parts))
+	for i, p := range parts {
+		ss[i] = fmt.Sprint(p)
+	}
+	return strings.Join(ss, "/")
+}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/src-d/github-reminder/reminder"
+)
+
+// wizard asks the questions of the interactive setup.
+type wizard struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// ask prints the question and returns the answer, or def if it is empty.
+func (w *wizard) ask(question, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(w.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(w.out, "%s: ", question)
+	}
+	line, err := w.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", errors.Wrap(err, "could not read answer")
+	}
+	if line = strings.TrimSpace(line); line == "" {
+		return def, nil
+	}
+	return line, nil
+}
+
+// choose asks until the answer is one of the given choices, the first being the default.
+func (w *wizard) choose(question string, choices ...string) (string, error) {
+	for {
+		answer, err := w.ask(fmt.Sprintf("%s (%s)", question, strings.Join(choices, "/")), choices[0])
+		if err != nil {
+			return "", err
+		}
+		for _, c := range choices {
+			if strings.EqualFold(answer, c) {
+				return c, nil
+			}
+		}
+		fmt.Fprintf(w.out, "please answer one of %s\n", strings.Join(choices, ", "))
+	}
+}
+
+// setup is the configuration gathered by the wizard.
+type setup struct {
+	AppID      int
+	PrivateKey string
+	Secret     string
+	Labels     []int
+}
+
+// runInit walks the user through the registration and configuration of the bot.
+func runInit(in io.Reader, out io.Writer) error {
+	w := &wizard{bufio.NewReader(in), out}
+	fmt.Fprintln(out, "This wizard sets up github-reminder. Press enter to accept the default answers.")
+	fmt.Fprintln(out)
+
+	var s setup
+	register, err := w.choose("Register a new GitHub App now?", "yes", "no")
+	if err != nil {
+		return err
+	}
+	if register == "yes" {
+		err = w.register(&s)
+	} else {
+		err = w.existing(&s)
+	}
+	if err != nil {
+		return err
+	}
+
+	scheme, err := w.ask("Days of the deadline labels, comma separated", "30,5")
+	if err != nil {
+		return err
+	}
+	for _, d := range strings.Split(scheme, ",") {
+		days, err := strconv.Atoi(strings.TrimSpace(d))
+		if err != nil || days <= 0 {
+			return errors.Errorf("bad number of days %q", d)
+		}
+		s.Labels = append(s.Labels, days)
+	}
+
+	format, err := w.choose("Where should the credentials be stored?", "env", "kubernetes")
+	if err != nil {
+		return err
+	}
+	if format == "env" {
+		return w.writeEnv(s)
+	}
+	return w.writeKubernetes(s)
+}
+
+// existing asks for the credentials of an already registered app.
+func (w *wizard) existing(s *setup) error {
+	id, err := w.ask("GitHub App id", "")
+	if err != nil {
+		return err
+	}
+	if s.AppID, err = strconv.Atoi(id); err != nil {
+		return errors.Errorf("bad app id %q", id)
+	}
+
+	path, err := w.ask("Path to the private key (.pem) of the app", "")
+	if err != nil {
+		return err
+	}
+	key, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "could not read private key")
+	}
+	s.PrivateKey = string(key)
+
+	s.Secret, err = w.ask("Webhook secret", "")
+	return err
+}
+
+// appManifest is the GitHub App manifest used to register the bot.
+type appManifest struct {
+	Name           string            `json:"name"`
+	URL            string            `json:"url"`
+	HookAttributes map[string]string `json:"hook_attributes"`
+	RedirectURL    string            `json:"redirect_url"`
+	Public         bool              `json:"public"`
+	Permissions    map[string]string `json:"default_permissions"`
+	Events         []string          `json:"default_events"`
+}
+
+var manifestForm = template.Must(template.New("manifest").Parse(`<!DOCTYPE html>
+<html>
+<body onload="document.forms[0].submit()">
+<form method="post" action="{{.Action}}">
+<input type="hidden" name="manifest" value="{{.Manifest}}">
+<input type="submit" value="Register github-reminder">
+</form>
+</body>
+</html>
+`))
+
+// register creates a new GitHub App through the manifest flow, using a local
+// server to submit the manifest and receive the temporary code from GitHub.
+func (w *wizard) register(s *setup) error {
+	web, err := w.ask("GitHub URL", "https://github.com")
+	if err != nil {
+		return err
+	}
+	web = strings.TrimSuffix(web, "/")
+	api := "https://api.github.com"
+	if web != "https://github.com" {
+		api = web + "/api/v3"
+	}
+
+	name, err := w.ask("App name", "github-reminder")
+	if err != nil {
+		return err
+	}
+	hook, err := w.ask("Public URL of the bot's /hook endpoint", "")
+	if err != nil {
+		return err
+	}
+	org, err := w.ask("Organization owning the app, empty for your user", "")
+	if err != nil {
+		return err
+	}
+	action := web + "/settings/apps/new"
+	if org != "" {
+		action = fmt.Sprintf("%s/organizations/%s/settings/apps/new", web, org)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return errors.Wrap(err, "could not start local server")
+	}
+	defer l.Close()
+	local := "http://" + l.Addr().String()
+
+	manifest, err := json.Marshal(appManifest{
+		Name:           name,
+		URL:            "https://github.com/src-d/github-reminder",
+		HookAttributes: map[string]string{"url": hook},
+		RedirectURL:    local + "/callback",
+		Permissions: map[string]string{
+			"issues":        "write",
+			"pull_requests": "write",
+			"checks":        "write",
+			"statuses":      "write",
+			"contents":      "read",
+			"metadata":      "read",
+		},
+		Events: []string{"issues", "issue_comment", "pull_request", "pull_request_review_comment", "label", "milestone", "repository"},
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not encode manifest")
+	}
+
+	codes := make(chan string, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(rw http.ResponseWriter, r *http.Request) {
+		manifestForm.Execute(rw, map[string]string{"Action": action, "Manifest": string(manifest)})
+	})
+	mux.HandleFunc("/callback", func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(rw, "github-reminder was registered, you can go back to the terminal.")
+		select {
+		case codes <- r.URL.Query().Get("code"):
+		default:
+		}
+	})
+	go http.Serve(l, mux)
+
+	fmt.Fprintf(w.out, "\nOpen %s in your browser and confirm the creation of the app.\n", local)
+	code := <-codes
+	if code == "" {
+		return errors.New("GitHub did not return a registration code")
+	}
+
+	res, err := http.Post(fmt.Sprintf("%s/app-manifests/%s/conversions", api, code), "application/json", nil)
+	if err != nil {
+		return errors.Wrap(err, "could not complete the app registration")
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return errors.Errorf("could not complete the app registration: %s", res.Status)
+	}
+
+	var app struct {
+		ID            int    `json:"id"`
+		HTMLURL       string `json:"html_url"`
+		PEM           string `json:"pem"`
+		WebhookSecret string `json:"webhook_secret"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&app); err != nil {
+		return errors.Wrap(err, "could not decode the registered app")
+	}
+	s.AppID, s.PrivateKey, s.Secret = app.ID, app.PEM, app.WebhookSecret
+	fmt.Fprintf(w.out, "Registered app %d, install it from %s/installations/new\n\n", app.ID, app.HTMLURL)
+	return nil
+}
+
+// labelsHelp explains which labels need to exist in the repositories.
+func (s setup) labelsHelp() string {
+	var names []string
+	for _, d := range s.Labels {
+		names = append(names, fmt.Sprintf("%q", reminder.LabelName(reminder.DefaultLabelPrefix, d)))
+	}
+	return "create the labels " + strings.Join(names, ", ") + " in the repositories to track"
+}
+
+// writeEnv writes the private key and an env file referencing it.
+func (w *wizard) writeEnv(s setup) error {
+	path, err := w.ask("Env file to write", "github-reminder.env")
+	if err != nil {
+		return err
+	}
+	keyPath, err := filepath.Abs(strings.TrimSuffix(path, filepath.Ext(path)) + ".pem")
+	if err != nil {
+		return errors.Wrap(err, "could not resolve private key path")
+	}
+	if err := ioutil.WriteFile(keyPath, []byte(s.PrivateKey), 0600); err != nil {
+		return errors.Wrap(err, "could not write private key")
+	}
+
+	env := fmt.Sprintf("# %s\nGITHUB_REMINDER_APP_ID=%d\nGITHUB_REMINDER_PRIVATE_KEY_FILE=%s\nGITHUB_REMINDER_SECRET=%s\n",
+		s.labelsHelp(), s.AppID, keyPath, s.Secret)
+	if err := ioutil.WriteFile(path, []byte(env), 0600); err != nil {
+		return errors.Wrap(err, "could not write env file")
+	}
+	fmt.Fprintf(w.out, "Wrote %s and %s, %s.\n", path, keyPath, s.labelsHelp())
+	return nil
+}
+
+// writeKubernetes writes a Kubernetes Secret holding the credentials.
+func (w *wizard) writeKubernetes(s setup) error {
+	path, err := w.ask("Manifest file to write", "github-reminder-secret.yaml")
+	if err != nil {
+		return err
+	}
+
+	var key strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(s.PrivateKey), "\n") {
+		key.WriteString("    " + line + "\n")
+	}
+	manifest := fmt.Sprintf(`# %s
+apiVersion: v1
+kind: Secret
+metadata:
+  name: github-reminder
+type: Opaque
+stringData:
+  GITHUB_REMINDER_APP_ID: "%d"
+  GITHUB_REMINDER_SECRET: %q
+  GITHUB_REMINDER_PRIVATE_KEY: |
+%s`, s.labelsHelp(), s.AppID, s.Secret, key.String())
+	if err := ioutil.WriteFile(path, []byte(manifest), 0600); err != nil {
+		return errors.Wrap(err, "could not write manifest")
+	}
+	fmt.Fprintf(w.out, "Wrote %s, apply it with kubectl and use it with envFrom, %s.\n", path, s.labelsHelp())
+	return nil
+}